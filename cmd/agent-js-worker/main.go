@@ -70,6 +70,7 @@ import (
 
 	"github.com/trustbloc/agent-sdk/pkg/auth/zcapld"
 	agentctrl "github.com/trustbloc/agent-sdk/pkg/controller"
+	didclientcmd "github.com/trustbloc/agent-sdk/pkg/controller/command/didclient"
 	"github.com/trustbloc/agent-sdk/pkg/storage/jsindexeddbcache"
 )
 
@@ -155,6 +156,8 @@ type agentStartOpts struct {
 	SidetreeToken            string      `json:"sidetreeToken"`
 	ContextProviderURLs      []string    `json:"context-provider-url"`
 	UnanchoredDIDMaxLifeTime int         `json:"unanchoredDIDMaxLifeTime"`
+	DIDCacheTTL              int         `json:"didCacheTTL"`
+	UnanchoredDIDCacheTTL    int         `json:"unanchoredDIDCacheTTL"`
 	KeyType                  string      `json:"key-type"`
 	KeyAgreementType         string      `json:"key-agreement-type"`
 	MediaTypeProfiles        []string    `json:"media-type-profiles"`
@@ -164,6 +167,9 @@ type agentStartOpts struct {
 	GNAPSigningJWK           string      `json:"gnap-signing-jwk"`
 	GNAPAccessToken          string      `json:"gnap-access-token"`
 	GNAPUserSubject          string      `json:"gnap-user-subject"`
+
+	// Orb domains DID operations fail over to when blocDomain is not reachable
+	OrbDomains []didclientcmd.OrbDomain `json:"orbDomains"`
 }
 
 type userConfig struct {
@@ -422,7 +428,9 @@ func getAgentHandlers(ctx *context.Provider,
 	handlers, err := agentctrl.GetCommandHandlers(ctx, agentctrl.WithBlocDomain(opts.BlocDomain),
 		agentctrl.WithDidAnchorOrigin(opts.DidAnchorOrigin), agentctrl.WithSidetreeToken(opts.SidetreeToken),
 		agentctrl.WithUnanchoredDIDMaxLifeTime(opts.UnanchoredDIDMaxLifeTime), agentctrl.WithMessageHandler(r),
-		agentctrl.WithNotifier(&jsNotifier{}))
+		agentctrl.WithNotifier(&jsNotifier{}),
+		agentctrl.WithDIDResolutionCacheTTL(opts.DIDCacheTTL, opts.UnanchoredDIDCacheTTL),
+		agentctrl.WithOrbDomains(opts.OrbDomains...))
 	if err != nil {
 		return nil, err
 	}
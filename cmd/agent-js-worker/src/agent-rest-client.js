@@ -564,6 +564,98 @@ const pkgs = {
         CreatePeerDID: {
             path: "/didclient/create-peer-did",
             method: "POST",
+        },
+        UpdateOrbDID: {
+            path: "/didclient/update-orb-did",
+            method: "POST",
+        },
+        RecoverOrbDID: {
+            path: "/didclient/recover-orb-did",
+            method: "POST",
+        },
+        DeactivateOrbDID: {
+            path: "/didclient/deactivate-orb-did",
+            method: "POST",
+        },
+        ResolveDID: {
+            path: "/didclient/resolve-did",
+            method: "POST",
+        },
+        GetOrbDIDStatus: {
+            path: "/didclient/get-orb-did-status",
+            method: "POST",
+        },
+        CreateDID: {
+            path: "/didclient/create-did",
+            method: "POST",
+        },
+        InvalidateDIDCache: {
+            path: "/didclient/invalidate-did-cache",
+            method: "POST",
+        },
+        ListCachedDIDs: {
+            path: "/didclient/list-cached-dids",
+            method: "POST",
+        },
+        RotatePeerDIDKeys: {
+            path: "/didclient/rotate-peer-did-keys",
+            method: "POST",
+        },
+        createDIDConfiguration: {
+            path: "/didclient/create-did-configuration",
+            method: "POST",
+        },
+        verifyDIDConfiguration: {
+            path: "/didclient/verify-did-configuration",
+            method: "POST",
+        },
+        getOrbDIDHistory: {
+            path: "/didclient/get-orb-did-history",
+            method: "POST",
+        },
+        listPendingDIDOperations: {
+            path: "/didclient/list-pending-did-operations",
+            method: "POST",
+        },
+        cancelPendingDIDOperation: {
+            path: "/didclient/cancel-pending-did-operation",
+            method: "POST",
+        },
+        createDIDBatch: {
+            path: "/didclient/create-did-batch",
+            method: "POST",
+        },
+        validateOrbDIDRequest: {
+            path: "/didclient/validate-orb-did-request",
+            method: "POST",
+        },
+        signJWT: {
+            path: "/didclient/sign-jwt",
+            method: "POST",
+        },
+        verifyJWT: {
+            path: "/didclient/verify-jwt",
+            method: "POST",
+        },
+        signJWS: {
+            path: "/didclient/sign-jws",
+            method: "POST",
+        },
+        verifyJWS: {
+            path: "/didclient/verify-jws",
+            method: "POST",
+        },
+        createDIDAuthChallenge: {
+            path: "/didclient/create-did-auth-challenge",
+            method: "POST",
+        },
+        respondDIDAuthChallenge: {
+            path: "/didclient/respond-did-auth-challenge",
+            method: "POST",
+        },
+        verifyDIDAuthResponse: {
+            path: "/didclient/verify-did-auth-response",
+            method: "POST",
         }
     },
     mediatorclient: {
@@ -578,6 +670,54 @@ const pkgs = {
         SendCreateConnectionRequest: {
             path: "/mediatorclient/send-connection-request",
             method: "POST",
+        },
+        GetConnections: {
+            path: "/mediatorclient/connections",
+            method: "POST",
+        },
+        Disconnect: {
+            path: "/mediatorclient/disconnect",
+            method: "POST",
+        },
+        CheckHealth: {
+            path: "/mediatorclient/check-health",
+            method: "POST",
+        },
+        StatusRequest: {
+            path: "/mediatorclient/pickup/status-request",
+            method: "POST",
+        },
+        BatchPickup: {
+            path: "/mediatorclient/pickup/batch-pickup",
+            method: "POST",
+        },
+        DeliveryRequest: {
+            path: "/mediatorclient/pickup/delivery-request",
+            method: "POST",
+        },
+        MessagesReceived: {
+            path: "/mediatorclient/pickup/messages-received",
+            method: "POST",
+        },
+        LiveMode: {
+            path: "/mediatorclient/pickup/live-mode",
+            method: "POST",
+        },
+        GetKeylist: {
+            path: "/mediatorclient/keylist",
+            method: "POST",
+        },
+        ReconcileKeylist: {
+            path: "/mediatorclient/keylist/reconcile",
+            method: "POST",
+        },
+        ParseInvitationURL: {
+            path: "/mediatorclient/parse-invitation-url",
+            method: "POST",
+        },
+        CancelPending: {
+            path: "/mediatorclient/cancel-pending",
+            method: "POST",
         }
     },
     blindedrouting: {
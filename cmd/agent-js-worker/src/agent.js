@@ -1502,6 +1502,236 @@ const Agent = function (opts) {
             createPeerDID: async function (req) {
                 return invoke(aw, pending, this.pkgname, "CreatePeerDID", req, "timeout while creating did")
             },
+
+            /**
+             * Updates keys and services of an Orb DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            updateOrbDID: async function (req) {
+                return invoke(aw, pending, this.pkgname, "UpdateOrbDID", req, "timeout while updating orb did")
+            },
+
+            /**
+             * Recovers an Orb DID using its recovery key.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            recoverOrbDID: async function (req) {
+                return invoke(aw, pending, this.pkgname, "RecoverOrbDID", req, "timeout while recovering orb did")
+            },
+
+            /**
+             * Deactivates an Orb DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            deactivateOrbDID: async function (req) {
+                return invoke(aw, pending, this.pkgname, "DeactivateOrbDID", req, "timeout while deactivating orb did")
+            },
+
+            /**
+             * Resolves DID of any method supported by the agent.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            resolveDID: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ResolveDID", req, "timeout while resolving did")
+            },
+
+            /**
+             * Returns publication status of orb DID along with its canonical and equivalent IDs once anchored.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            getOrbDIDStatus: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetOrbDIDStatus", req, "timeout while getting orb did status")
+            },
+
+            /**
+             * Creates a new did:key, did:jwk or did:web DID with keys created in the agent KMS.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            createDID: async function (req) {
+                return invoke(aw, pending, this.pkgname, "CreateDID", req, "timeout while creating did")
+            },
+
+            /**
+             * Removes cached resolutions of DID or the whole DID resolution cache if no DID is given.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            invalidateDIDCache: async function (req) {
+                return invoke(aw, pending, this.pkgname, "InvalidateDIDCache", req, "timeout while invalidating did cache")
+            },
+
+            /**
+             * Lists DIDs whose resolutions are cached.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            listCachedDIDs: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ListCachedDIDs", req, "timeout while listing cached dids")
+            },
+
+            /**
+             * Rotates keys of peer DID, connections using the old DID are rotated to the new one.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            rotatePeerDIDKeys: async function (req) {
+                return invoke(aw, pending, this.pkgname, "RotatePeerDIDKeys", req, "timeout while rotating peer did keys")
+            },
+
+            /**
+             * Creates DID configuration (/.well-known/did-configuration.json) linking DID to origin.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            createDIDConfiguration: async function (req) {
+                return invoke(aw, pending, this.pkgname, "createDIDConfiguration", req, "timeout while creating DID configuration")
+            },
+
+            /**
+             * Verifies DID configuration of origin against DID and its LinkedDomains service.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyDIDConfiguration: async function (req) {
+                return invoke(aw, pending, this.pkgname, "verifyDIDConfiguration", req, "timeout while verifying DID configuration")
+            },
+
+            /**
+             * Returns Sidetree operations of orb DID along with anchor times and the document after each of them.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            getOrbDIDHistory: async function (req) {
+                return invoke(aw, pending, this.pkgname, "getOrbDIDHistory", req, "timeout while getting orb DID history")
+            },
+
+            /**
+             * Lists orb DID operations waiting to be submitted.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            listPendingDIDOperations: async function (req) {
+                return invoke(aw, pending, this.pkgname, "listPendingDIDOperations", req, "timeout while listing pending did operations")
+            },
+
+            /**
+             * Removes orb DID operation from the queue of operations waiting to be submitted.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            cancelPendingDIDOperation: async function (req) {
+                return invoke(aw, pending, this.pkgname, "cancelPendingDIDOperation", req, "timeout while cancelling pending did operation")
+            },
+
+            /**
+             * Creates orb and peer DIDs in batch and reports the result of each of them.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            createDIDBatch: async function (req) {
+                return invoke(aw, pending, this.pkgname, "createDIDBatch", req, "timeout while creating did batch")
+            },
+
+            /**
+             * Validates orb DID create request without creating the DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            validateOrbDIDRequest: async function (req) {
+                return invoke(aw, pending, this.pkgname, "validateOrbDIDRequest", req, "timeout while validating orb DID request")
+            },
+
+            /**
+             * Signs JWT claims with a key of DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            signJWT: async function (req) {
+                return invoke(aw, pending, this.pkgname, "signJWT", req, "timeout while signing JWT")
+            },
+
+            /**
+             * Verifies JWT signed by a key of DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyJWT: async function (req) {
+                return invoke(aw, pending, this.pkgname, "verifyJWT", req, "timeout while verifying JWT")
+            },
+
+            /**
+             * Signs payload with a key of DID into compact JWS.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            signJWS: async function (req) {
+                return invoke(aw, pending, this.pkgname, "signJWS", req, "timeout while signing JWS")
+            },
+
+            /**
+             * Verifies compact JWS signed by a key of DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyJWS: async function (req) {
+                return invoke(aw, pending, this.pkgname, "verifyJWS", req, "timeout while verifying JWS")
+            },
+
+            /**
+             * Creates DID Auth challenge for the domain of relying party.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            createDIDAuthChallenge: async function (req) {
+                return invoke(aw, pending, this.pkgname, "createDIDAuthChallenge", req, "timeout while creating DID Auth challenge")
+            },
+
+            /**
+             * Answers DID Auth challenge with Verifiable Presentation signed by an authentication key of DID.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            respondDIDAuthChallenge: async function (req) {
+                return invoke(aw, pending, this.pkgname, "respondDIDAuthChallenge", req, "timeout while responding to DID Auth challenge")
+            },
+
+            /**
+             * Verifies DID Auth response to a challenge created by the agent.
+             *
+             * @param req - json document
+             * @returns {Promise<Object>}
+             */
+            verifyDIDAuthResponse: async function (req) {
+                return invoke(aw, pending, this.pkgname, "verifyDIDAuthResponse", req, "timeout while verifying DID Auth response")
+            },
         },
 
         /**
@@ -1514,7 +1744,8 @@ const Agent = function (opts) {
             /**
              * connects an agent with the router.
              *
-             * @param req - json document containing invitation and label
+             * @param req - json document containing invitation and label, optional timeout (in milliseconds) and
+             * request ID for cancelling pending connect.
              * @returns {Promise<Object>}
              */
             connect: async function (req) {
@@ -1524,7 +1755,8 @@ const Agent = function (opts) {
             /**
              * createInvitation creates out-of-band invitation from one of the mediator connection.
              *
-             * @param req - json document containing label, goal, goal code, service & protocols.
+             * @param req - json document containing label, goal, goal code, service, protocols, optional attachments
+             * and base URL for encoding invitation as URL.
              * @returns {Promise<Object>}
              */
             createInvitation: async function (req) {
@@ -1534,13 +1766,136 @@ const Agent = function (opts) {
             /**
              * sendCreateConnectionRequest sends create connection request to mediator.
              *
-             * @param req - json document containing raw DID Document.
+             * @param req - json document containing raw DID Document, optional timeout (in milliseconds) and
+             * request ID for cancelling pending request.
              * @returns {Promise<Object>}
              */
             sendCreateConnectionRequest: async function (req) {
                 return invoke(aw, pending, this.pkgname, "SendCreateConnectionRequest", req, "timeout while sending create connection request")
             },
 
+            /**
+             * getConnections returns router connections of the agent along with their endpoints and routing keys.
+             *
+             * @returns {Promise<Object>}
+             */
+            getConnections: async function () {
+                return invoke(aw, pending, this.pkgname, "GetConnections", {}, "timeout while getting mediator connections")
+            },
+
+            /**
+             * disconnect removes recipient keys from the router and unregisters the agent from the router.
+             *
+             * @param req - json document containing router connection ID and optional recipient keys.
+             * @returns {Promise<Object>}
+             */
+            disconnect: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Disconnect", req, "timeout while disconnecting from mediator")
+            },
+
+            /**
+             * checkHealth sends trust ping to the router, or to all routers if connection ID is not provided,
+             * and reports whether the router responded along with round-trip latency.
+             *
+             * @param req - json document containing optional router connection ID.
+             * @returns {Promise<Object>}
+             */
+            checkHealth: async function (req) {
+                return invoke(aw, pending, this.pkgname, "CheckHealth", req, "timeout while checking mediator health")
+            },
+
+            /**
+             * statusRequest requests status of messages waiting for the agent at the router.
+             * Pickup 2.0 is used for DIDComm V2 routers and message pickup 1.0 for DIDComm V1 routers.
+             *
+             * @param req - json document containing router connection ID and optional recipient key.
+             * @returns {Promise<Object>}
+             */
+            statusRequest: async function (req) {
+                return invoke(aw, pending, this.pkgname, "StatusRequest", req, "timeout while requesting message pickup status")
+            },
+
+            /**
+             * batchPickup picks up a batch of messages from DIDComm V1 router and hands them over to the agent.
+             *
+             * @param req - json document containing router connection ID and optional batch size.
+             * @returns {Promise<Object>}
+             */
+            batchPickup: async function (req) {
+                return invoke(aw, pending, this.pkgname, "BatchPickup", req, "timeout while picking up messages")
+            },
+
+            /**
+             * deliveryRequest requests delivery of messages from DIDComm V2 router and hands them over to the agent.
+             *
+             * @param req - json document containing router connection ID, optional limit and recipient key.
+             * @returns {Promise<Object>}
+             */
+            deliveryRequest: async function (req) {
+                return invoke(aw, pending, this.pkgname, "DeliveryRequest", req, "timeout while requesting message delivery")
+            },
+
+            /**
+             * messagesReceived acknowledges receipt of messages delivered by DIDComm V2 router.
+             *
+             * @param req - json document containing router connection ID and IDs of received messages.
+             * @returns {Promise<Object>}
+             */
+            messagesReceived: async function (req) {
+                return invoke(aw, pending, this.pkgname, "MessagesReceived", req, "timeout while acknowledging received messages")
+            },
+
+            /**
+             * liveMode turns live delivery of messages by DIDComm V2 router on or off.
+             *
+             * @param req - json document containing router connection ID and enabled flag.
+             * @returns {Promise<Object>}
+             */
+            liveMode: async function (req) {
+                return invoke(aw, pending, this.pkgname, "LiveMode", req, "timeout while changing live delivery mode")
+            },
+
+            /**
+             * getKeylist queries a page of recipient keys registered with the router.
+             *
+             * @param req - json document containing router connection ID, optional limit and offset.
+             * @returns {Promise<Object>}
+             */
+            getKeylist: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetKeylist", req, "timeout while querying router keylist")
+            },
+
+            /**
+             * reconcileKeylist adds keys missing from the router keylist and removes stale keys from it.
+             *
+             * @param req - json document containing router connection ID and optional dry run flag.
+             * @returns {Promise<Object>}
+             */
+            reconcileKeylist: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ReconcileKeylist", req, "timeout while reconciling router keylist")
+            },
+
+            /**
+             * parseInvitationURL decodes out-of-band invitation from invitation URL with 'oob' or '_oob' query parameter.
+             *
+             * @param req - json document containing invitation URL.
+             * @returns {Promise<Object>}
+             */
+            parseInvitationURL: async function (req) {
+                return invoke(aw, pending, this.pkgname, "ParseInvitationURL", req, "timeout while parsing invitation URL")
+            },
+
+            /**
+             * cancelPending cancels pending connect or create connection request, for example when page waiting
+             * for it is closed.
+             *
+             * @param req - json document containing request ID of the pending request.
+             * @returns {Promise<Object>}
+             */
+            cancelPending: async function (req) {
+                return invoke(aw, pending, this.pkgname, "CancelPending", req, "timeout while cancelling pending request")
+            },
+
         },
 
         /**
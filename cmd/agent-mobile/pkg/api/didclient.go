@@ -18,4 +18,73 @@ type DIDClient interface {
 
 	// ResolveOrbDID resolve orb DID.
 	ResolveOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// UpdateOrbDID updates keys and services of an orb DID.
+	UpdateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// RecoverOrbDID recovers an orb DID using its recovery key.
+	RecoverOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeactivateOrbDID deactivates an orb DID.
+	DeactivateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ResolveDID resolves DID of any method supported by the agent.
+	ResolveDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetOrbDIDStatus returns publication status of an orb DID.
+	GetOrbDIDStatus(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CreateDID creates a new did:key, did:jwk or did:web DID
+	CreateDID(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// InvalidateDIDCache removes cached resolutions of DID or the whole DID resolution cache
+	InvalidateDIDCache(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ListCachedDIDs lists DIDs whose resolutions are cached
+	ListCachedDIDs(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// RotatePeerDIDKeys rotates keys of peer DID
+	RotatePeerDIDKeys(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CreateDIDConfiguration creates DID configuration linking DID to origin
+	CreateDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyDIDConfiguration verifies DID configuration of origin
+	VerifyDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetOrbDIDHistory returns operation history of orb DID
+	GetOrbDIDHistory(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ListPendingDIDOperations lists orb DID operations waiting to be submitted
+	ListPendingDIDOperations(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CancelPendingDIDOperation removes orb DID operation from the queue
+	CancelPendingDIDOperation(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CreateDIDBatch creates orb and peer DIDs in batch
+	CreateDIDBatch(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ValidateOrbDIDRequest validates orb DID create request without creating the DID.
+	ValidateOrbDIDRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// SignJWT signs JWT claims with a key of DID
+	SignJWT(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyJWT verifies JWT signed by a key of DID
+	VerifyJWT(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// SignJWS signs payload with a key of DID into compact JWS
+	SignJWS(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyJWS verifies compact JWS signed by a key of DID
+	VerifyJWS(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CreateDIDAuthChallenge creates DID Auth challenge for the domain of relying party
+	CreateDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// RespondDIDAuthChallenge answers DID Auth challenge with Verifiable Presentation signed by key of DID
+	RespondDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// VerifyDIDAuthResponse verifies DID Auth response to a challenge created by the agent
+	VerifyDIDAuthResponse(request *models.RequestEnvelope) *models.ResponseEnvelope
 }
@@ -18,4 +18,40 @@ type MediatorClient interface {
 
 	// SendCreateConnectionRequest sends create connection request to mediator.
 	SendCreateConnectionRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetConnections returns router connections of the agent along with their endpoints and routing keys.
+	GetConnections(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Disconnect removes recipient keys from router and unregisters the agent from the router.
+	Disconnect(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CheckHealth sends trust ping to routers and reports round-trip latency.
+	CheckHealth(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// StatusRequest requests status of messages waiting for the agent at the router.
+	StatusRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// BatchPickup picks up a batch of messages from DIDComm V1 router.
+	BatchPickup(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeliveryRequest requests delivery of messages from DIDComm V2 router.
+	DeliveryRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// MessagesReceived acknowledges receipt of messages delivered by DIDComm V2 router.
+	MessagesReceived(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// LiveMode turns live delivery of messages by DIDComm V2 router on or off.
+	LiveMode(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetKeylist queries a page of recipient keys registered with the router.
+	GetKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ReconcileKeylist adds missing keys of agent DIDs to the router keylist and removes stale keys from it.
+	ReconcileKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// ParseInvitationURL decodes out-of-band invitation from invitation URL with 'oob' or '_oob' query parameter.
+	ParseInvitationURL(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// CancelPending cancels pending connect or create connection request with given request ID.
+	CancelPending(request *models.RequestEnvelope) *models.ResponseEnvelope
 }
@@ -93,8 +93,10 @@ func NewAries(opts *config.Options) (*Aries, error) {
 
 	sdkCommandHandlers, err := sdkcontroller.GetCommandHandlers(context,
 		sdkcontroller.WithBlocDomain(opts.TrustblocDomain),
+		sdkcontroller.WithOrbDomains(opts.OrbDomains...),
 		sdkcontroller.WithMessageHandler(msgHandler),
 		sdkcontroller.WithNotifier(notifier.NewNotifier(notifications)),
+		sdkcontroller.WithDIDResolutionCacheTTL(opts.DIDResolutionCacheTTL, opts.UnanchoredDIDResolutionCacheTTL),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sdk command handlers: %w", err)
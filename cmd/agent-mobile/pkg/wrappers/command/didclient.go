@@ -67,3 +67,359 @@ func (de *DIDClient) CreatePeerDID(request *models.RequestEnvelope) *models.Resp
 
 	return &models.ResponseEnvelope{Payload: response}
 }
+
+// UpdateOrbDID updates keys and services of an orb DID.
+func (de *DIDClient) UpdateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.UpdateOrbDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.UpdateOrbDIDCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// RecoverOrbDID recovers an orb DID using its recovery key.
+func (de *DIDClient) RecoverOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.RecoverOrbDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.RecoverOrbDIDCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeactivateOrbDID deactivates an orb DID.
+func (de *DIDClient) DeactivateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.DeactivateOrbDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.DeactivateOrbDIDCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ResolveDID resolves DID of any method supported by the agent.
+func (de *DIDClient) ResolveDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.ResolveDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.ResolveDIDCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// GetOrbDIDStatus returns publication status of an orb DID.
+func (de *DIDClient) GetOrbDIDStatus(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.GetOrbDIDStatusRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.GetOrbDIDStatusCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CreateDID creates a new did:key, did:jwk or did:web DID
+func (de *DIDClient) CreateDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CreateDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.CreateDIDCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// InvalidateDIDCache removes cached resolutions of DID or the whole DID resolution cache
+func (de *DIDClient) InvalidateDIDCache(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.InvalidateDIDCacheRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.InvalidateDIDCacheCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ListCachedDIDs lists DIDs whose resolutions are cached
+func (de *DIDClient) ListCachedDIDs(_ *models.RequestEnvelope) *models.ResponseEnvelope {
+	response, cmdErr := exec(de.handlers[didclient.ListCachedDIDsCommandMethod], nil)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// RotatePeerDIDKeys rotates keys of peer DID
+func (de *DIDClient) RotatePeerDIDKeys(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.RotatePeerDIDKeysRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.RotatePeerDIDKeysCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CreateDIDConfiguration creates DID configuration linking DID to origin
+func (de *DIDClient) CreateDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CreateDIDConfigurationRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.CreateDIDConfigurationCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// VerifyDIDConfiguration verifies DID configuration of origin
+func (de *DIDClient) VerifyDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.VerifyDIDConfigurationRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.VerifyDIDConfigurationCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// GetOrbDIDHistory returns operation history of orb DID
+func (de *DIDClient) GetOrbDIDHistory(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.GetOrbDIDHistoryRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.GetOrbDIDHistoryCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ListPendingDIDOperations lists orb DID operations waiting to be submitted
+func (de *DIDClient) ListPendingDIDOperations(_ *models.RequestEnvelope) *models.ResponseEnvelope {
+	response, cmdErr := exec(de.handlers[didclient.ListPendingDIDOperationsCommandMethod], nil)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CancelPendingDIDOperation removes orb DID operation from the queue
+func (de *DIDClient) CancelPendingDIDOperation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CancelPendingDIDOperationRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.CancelPendingDIDOperationCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CreateDIDBatch creates orb and peer DIDs in batch
+func (de *DIDClient) CreateDIDBatch(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CreateDIDBatchRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.CreateDIDBatchCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ValidateOrbDIDRequest validates orb DID create request without creating the DID.
+func (de *DIDClient) ValidateOrbDIDRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CreateOrbDIDRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.ValidateOrbDIDRequestCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// SignJWT signs JWT claims with a key of DID
+func (de *DIDClient) SignJWT(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.SignJWTRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.SignJWTCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// VerifyJWT verifies JWT signed by a key of DID
+func (de *DIDClient) VerifyJWT(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.VerifyJWTRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.VerifyJWTCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// SignJWS signs payload with a key of DID into compact JWS
+func (de *DIDClient) SignJWS(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.SignJWSRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.SignJWSCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// VerifyJWS verifies compact JWS signed by a key of DID
+func (de *DIDClient) VerifyJWS(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.VerifyJWSRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.VerifyJWSCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CreateDIDAuthChallenge creates DID Auth challenge for the domain of relying party
+func (de *DIDClient) CreateDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.CreateDIDAuthChallengeRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.CreateDIDAuthChallengeCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// RespondDIDAuthChallenge answers DID Auth challenge with Verifiable Presentation signed by key of DID
+func (de *DIDClient) RespondDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.RespondDIDAuthChallengeRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.RespondDIDAuthChallengeCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// VerifyDIDAuthResponse verifies DID Auth response to a challenge created by the agent
+func (de *DIDClient) VerifyDIDAuthResponse(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := didclient.VerifyDIDAuthResponseRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(de.handlers[didclient.VerifyDIDAuthResponseCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
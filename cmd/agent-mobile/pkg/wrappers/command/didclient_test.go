@@ -186,3 +186,558 @@ func TestDIDClient_CreatePeerDID(t *testing.T) {
 		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
 	})
 }
+
+func TestDIDClient_UpdateOrbDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.UpdateOrbDIDCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.UpdateOrbDID(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.UpdateOrbDID(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_RecoverOrbDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.RecoverOrbDIDCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.RecoverOrbDID(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.RecoverOrbDID(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_DeactivateOrbDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.DeactivateOrbDIDCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.DeactivateOrbDID(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.DeactivateOrbDID(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_ResolveDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.ResolveDIDCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.ResolveDID(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.ResolveDID(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_GetOrbDIDStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.GetOrbDIDStatusCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.GetOrbDIDStatus(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.GetOrbDIDStatus(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_CreateDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.CreateDIDCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.CreateDID(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.CreateDID(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_InvalidateDIDCache(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.InvalidateDIDCacheCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.InvalidateDIDCache(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.InvalidateDIDCache(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_ListCachedDIDs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.ListCachedDIDsCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.ListCachedDIDs(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+}
+
+func TestDIDClient_RotatePeerDIDKeys(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.RotatePeerDIDKeysCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.RotatePeerDIDKeys(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.RotatePeerDIDKeys(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_CreateDIDConfiguration(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.CreateDIDConfigurationCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.CreateDIDConfiguration(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.CreateDIDConfiguration(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_VerifyDIDConfiguration(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.VerifyDIDConfigurationCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.VerifyDIDConfiguration(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.VerifyDIDConfiguration(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_GetOrbDIDHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.GetOrbDIDHistoryCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.GetOrbDIDHistory(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.GetOrbDIDHistory(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_ListPendingDIDOperations(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.ListPendingDIDOperationsCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.ListPendingDIDOperations(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+}
+
+func TestDIDClient_CancelPendingDIDOperation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.CancelPendingDIDOperationCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.CancelPendingDIDOperation(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.CancelPendingDIDOperation(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_CreateDIDBatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.CreateDIDBatchCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.CreateDIDBatch(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.CreateDIDBatch(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_ValidateOrbDIDRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.ValidateOrbDIDRequestCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.ValidateOrbDIDRequest(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.ValidateOrbDIDRequest(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_SignJWT(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.SignJWTCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.SignJWT(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.SignJWT(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_VerifyJWT(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.VerifyJWTCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.VerifyJWT(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.VerifyJWT(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_SignJWS(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.SignJWSCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.SignJWS(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.SignJWS(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_VerifyJWS(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.VerifyJWSCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.VerifyJWS(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.VerifyJWS(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_CreateDIDAuthChallenge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.CreateDIDAuthChallengeCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.CreateDIDAuthChallenge(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.CreateDIDAuthChallenge(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_RespondDIDAuthChallenge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.RespondDIDAuthChallengeCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.RespondDIDAuthChallenge(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.RespondDIDAuthChallenge(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
+
+func TestDIDClient_VerifyDIDAuthResponse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		fakeHandler := mockCommandRunner{data: []byte(`{}`)}
+		client.handlers[didclient.VerifyDIDAuthResponseCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := client.VerifyDIDAuthResponse(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, `{}`, string(resp.Payload))
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		client := getDIDClient(t)
+
+		req := &models.RequestEnvelope{Payload: []byte(`{`)}
+		resp := client.VerifyDIDAuthResponse(req)
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+		require.Equal(t, "unexpected end of JSON input", resp.Error.Message)
+	})
+}
@@ -67,3 +67,189 @@ func (mc *MediatorClient) SendCreateConnectionRequest(request *models.RequestEnv
 
 	return &models.ResponseEnvelope{Payload: response}
 }
+
+// GetConnections returns router connections of the agent along with their endpoints and routing keys.
+func (mc *MediatorClient) GetConnections(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	response, cmdErr := exec(mc.handlers[mediatorclient.GetConnections], request.Payload)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// Disconnect removes recipient keys from router and unregisters the agent from the router.
+func (mc *MediatorClient) Disconnect(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.DisconnectRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.Disconnect], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CheckHealth sends trust ping to routers and reports round-trip latency.
+func (mc *MediatorClient) CheckHealth(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.CheckHealthRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.CheckHealth], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// StatusRequest requests status of messages waiting for the agent at the router.
+func (mc *MediatorClient) StatusRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.PickupStatusRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.StatusRequest], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// BatchPickup picks up a batch of messages from DIDComm V1 router.
+func (mc *MediatorClient) BatchPickup(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.BatchPickupRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.BatchPickup], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DeliveryRequest requests delivery of messages from DIDComm V2 router.
+func (mc *MediatorClient) DeliveryRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.PickupDeliveryRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.DeliveryRequest], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// MessagesReceived acknowledges receipt of messages delivered by DIDComm V2 router.
+func (mc *MediatorClient) MessagesReceived(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.MessagesReceivedRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.MessagesReceived], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// LiveMode turns live delivery of messages by DIDComm V2 router on or off.
+func (mc *MediatorClient) LiveMode(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.LiveModeRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.LiveMode], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// GetKeylist queries a page of recipient keys registered with the router.
+func (mc *MediatorClient) GetKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.GetKeylistRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.GetKeylist], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ReconcileKeylist adds missing keys of agent DIDs to the router keylist and removes stale keys from it.
+func (mc *MediatorClient) ReconcileKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.ReconcileKeylistRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.ReconcileKeylist], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// ParseInvitationURL decodes out-of-band invitation from invitation URL with 'oob' or '_oob' query parameter.
+func (mc *MediatorClient) ParseInvitationURL(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.ParseInvitationURLRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.ParseInvitationURL], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// CancelPending cancels pending connect or create connection request with given request ID.
+func (mc *MediatorClient) CancelPending(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := mediatorclient.CancelPendingRequest{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(mc.handlers[mediatorclient.CancelPending], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
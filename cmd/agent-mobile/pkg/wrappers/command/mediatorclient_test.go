@@ -118,3 +118,207 @@ func TestMediatorClient_SendCreateConnectionRequest(t *testing.T) {
 			string(resp.Payload))
 	})
 }
+
+func TestMediatorClient_GetConnections(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"connections":[{"connectionID":"sample-connection"}]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.GetConnections] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := mediatorClientController.GetConnections(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_Disconnect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := ``
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.Disconnect] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := mediatorClientController.Disconnect(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_CheckHealth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"results":[{"connectionID":"sample-connection","healthy":true}]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.CheckHealth] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := mediatorClientController.CheckHealth(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_StatusRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"messageCount":2}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.StatusRequest] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := mediatorClientController.StatusRequest(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_BatchPickup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"messageCount":2}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.BatchPickup] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","batchSize":5}`)}
+		resp := mediatorClientController.BatchPickup(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_DeliveryRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"messageIDs":["msg-1"]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.DeliveryRequest] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","limit":5}`)}
+		resp := mediatorClientController.DeliveryRequest(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_MessagesReceived(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := ``
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.MessagesReceived] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","messageIDs":["msg-1"]}`)}
+		resp := mediatorClientController.MessagesReceived(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_LiveMode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := ``
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.LiveMode] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","enabled":true}`)}
+		resp := mediatorClientController.LiveMode(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_GetKeylist(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"keys":["key-1"],"count":1,"offset":0,"remaining":0}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.GetKeylist] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","limit":10}`)}
+		resp := mediatorClientController.GetKeylist(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_ReconcileKeylist(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"added":["key-1"],"removed":["key-2"],"dryRun":true}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.ReconcileKeylist] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","dryRun":true}`)}
+		resp := mediatorClientController.ReconcileKeylist(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_ParseInvitationURL(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"invitation":{"@id":"inv-1","@type":"https://didcomm.org/out-of-band/1.0/invitation"}}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.ParseInvitationURL] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"url":"https://example.com?oob=e30"}`)}
+		resp := mediatorClientController.ParseInvitationURL(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_CancelPending(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mediatorClientController := getMediatorClientController(t)
+
+		mockResponse := `{"cancelled":true}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+
+		mediatorClientController.handlers[mediatorclient.CancelPending] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"requestID":"request-1"}`)}
+		resp := mediatorClientController.CancelPending(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
@@ -9,6 +9,7 @@ package config
 
 import (
 	"github.com/trustbloc/agent-sdk/cmd/agent-mobile/pkg/api"
+	didclientcmd "github.com/trustbloc/agent-sdk/pkg/controller/command/didclient"
 
 	"github.com/piprate/json-gold/ld"
 )
@@ -28,10 +29,15 @@ type Options struct {
 	Logger               api.LoggerProvider
 	Storage              api.Provider
 	DocumentLoader       ld.DocumentLoader
+	// DID resolution cache TTLs in seconds, zero keeps the default and negative disables caching
+	DIDResolutionCacheTTL           int
+	UnanchoredDIDResolutionCacheTTL int
 	// expected to be ignored by gomobile
 	// not intended to be used by golang code
 	HTTPResolvers     []string
 	OutboundTransport []string
+	// Orb domains DID operations fail over to when TrustblocDomain is not reachable
+	OrbDomains []didclientcmd.OrbDomain
 }
 
 // New returns an instance of Options which can be used to configure an aries controller instance.
@@ -44,6 +50,18 @@ func (o *Options) AddHTTPResolver(resolverURL string) {
 	o.HTTPResolvers = append(o.HTTPResolvers, resolverURL)
 }
 
+// AddOrbDomain appends an Orb domain which DID operations fail over to when the trustbloc domain is not reachable.
+// Domains with lower priority are tried first, anchor origin and unpublished DID label are optional.
+func (o *Options) AddOrbDomain(url, token, anchorOrigin, unpublishedDIDLabel string, priority int) {
+	o.OrbDomains = append(o.OrbDomains, didclientcmd.OrbDomain{
+		URL:                 url,
+		Token:               token,
+		AnchorOrigin:        anchorOrigin,
+		UnpublishedDIDLabel: unpublishedDIDLabel,
+		Priority:            priority,
+	})
+}
+
 // AddOutboundTransport appends a transport type to the options e.g. http or ws.
 func (o *Options) AddOutboundTransport(transportType string) {
 	o.OutboundTransport = append(o.OutboundTransport, transportType)
@@ -35,6 +35,121 @@ func (dc *DIDClient) CreatePeerDID(request *models.RequestEnvelope) *models.Resp
 	return dc.createRespEnvelope(request, didclient.CreatePeerDIDCommandMethod)
 }
 
+// UpdateOrbDID updates keys and services of an orb DID.
+func (dc *DIDClient) UpdateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.UpdateOrbDIDCommandMethod)
+}
+
+// RecoverOrbDID recovers an orb DID using its recovery key.
+func (dc *DIDClient) RecoverOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.RecoverOrbDIDCommandMethod)
+}
+
+// DeactivateOrbDID deactivates an orb DID.
+func (dc *DIDClient) DeactivateOrbDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.DeactivateOrbDIDCommandMethod)
+}
+
+// ResolveDID resolves DID of any method supported by the agent.
+func (dc *DIDClient) ResolveDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.ResolveDIDCommandMethod)
+}
+
+// GetOrbDIDStatus returns publication status of an orb DID.
+func (dc *DIDClient) GetOrbDIDStatus(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.GetOrbDIDStatusCommandMethod)
+}
+
+// CreateDID creates a new did:key, did:jwk or did:web DID
+func (dc *DIDClient) CreateDID(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.CreateDIDCommandMethod)
+}
+
+// InvalidateDIDCache removes cached resolutions of DID or the whole DID resolution cache
+func (dc *DIDClient) InvalidateDIDCache(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.InvalidateDIDCacheCommandMethod)
+}
+
+// ListCachedDIDs lists DIDs whose resolutions are cached
+func (dc *DIDClient) ListCachedDIDs(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.ListCachedDIDsCommandMethod)
+}
+
+// RotatePeerDIDKeys rotates keys of peer DID
+func (dc *DIDClient) RotatePeerDIDKeys(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.RotatePeerDIDKeysCommandMethod)
+}
+
+// CreateDIDConfiguration creates DID configuration linking DID to origin
+func (dc *DIDClient) CreateDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.CreateDIDConfigurationCommandMethod)
+}
+
+// VerifyDIDConfiguration verifies DID configuration of origin
+func (dc *DIDClient) VerifyDIDConfiguration(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.VerifyDIDConfigurationCommandMethod)
+}
+
+// GetOrbDIDHistory returns operation history of orb DID
+func (dc *DIDClient) GetOrbDIDHistory(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.GetOrbDIDHistoryCommandMethod)
+}
+
+// ListPendingDIDOperations lists orb DID operations waiting to be submitted
+func (dc *DIDClient) ListPendingDIDOperations(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.ListPendingDIDOperationsCommandMethod)
+}
+
+// CancelPendingDIDOperation removes orb DID operation from the queue
+func (dc *DIDClient) CancelPendingDIDOperation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.CancelPendingDIDOperationCommandMethod)
+}
+
+// CreateDIDBatch creates orb and peer DIDs in batch
+func (dc *DIDClient) CreateDIDBatch(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.CreateDIDBatchCommandMethod)
+}
+
+// ValidateOrbDIDRequest validates orb DID create request without creating the DID.
+func (dc *DIDClient) ValidateOrbDIDRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.ValidateOrbDIDRequestCommandMethod)
+}
+
+// SignJWT signs JWT claims with a key of DID
+func (dc *DIDClient) SignJWT(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.SignJWTCommandMethod)
+}
+
+// VerifyJWT verifies JWT signed by a key of DID
+func (dc *DIDClient) VerifyJWT(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.VerifyJWTCommandMethod)
+}
+
+// SignJWS signs payload with a key of DID into compact JWS
+func (dc *DIDClient) SignJWS(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.SignJWSCommandMethod)
+}
+
+// VerifyJWS verifies compact JWS signed by a key of DID
+func (dc *DIDClient) VerifyJWS(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.VerifyJWSCommandMethod)
+}
+
+// CreateDIDAuthChallenge creates DID Auth challenge for the domain of relying party
+func (dc *DIDClient) CreateDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.CreateDIDAuthChallengeCommandMethod)
+}
+
+// RespondDIDAuthChallenge answers DID Auth challenge with Verifiable Presentation signed by key of DID
+func (dc *DIDClient) RespondDIDAuthChallenge(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.RespondDIDAuthChallengeCommandMethod)
+}
+
+// VerifyDIDAuthResponse verifies DID Auth response to a challenge created by the agent
+func (dc *DIDClient) VerifyDIDAuthResponse(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return dc.createRespEnvelope(request, didclient.VerifyDIDAuthResponseCommandMethod)
+}
+
 func (dc *DIDClient) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
 	return exec(&restOperation{
 		url:        dc.URL,
@@ -98,3 +98,348 @@ func TestDIDClient_ResolveOrbDID(t *testing.T) {
 	require.Nil(t, resp.Error)
 	require.Equal(t, string(response), string(resp.Payload))
 }
+
+func TestDIDClient_UpdateOrbDID(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.UpdateOrbDIDPath,
+	}
+
+	resp := client.UpdateOrbDID(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_RecoverOrbDID(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.RecoverOrbDIDPath,
+	}
+
+	resp := client.RecoverOrbDID(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_DeactivateOrbDID(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.DeactivateOrbDIDPath,
+	}
+
+	resp := client.DeactivateOrbDID(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_ResolveDID(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.ResolveDIDPath,
+	}
+
+	resp := client.ResolveDID(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_GetOrbDIDStatus(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.GetOrbDIDStatusPath,
+	}
+
+	resp := client.GetOrbDIDStatus(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_CreateDID(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.CreateDIDPath,
+	}
+
+	resp := client.CreateDID(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_InvalidateDIDCache(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.InvalidateDIDCachePath,
+	}
+
+	resp := client.InvalidateDIDCache(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_ListCachedDIDs(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.ListCachedDIDsPath,
+	}
+
+	resp := client.ListCachedDIDs(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_RotatePeerDIDKeys(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.RotatePeerDIDKeysPath,
+	}
+
+	resp := client.RotatePeerDIDKeys(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_CreateDIDConfiguration(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.CreateDIDConfigurationPath,
+	}
+
+	resp := client.CreateDIDConfiguration(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_VerifyDIDConfiguration(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.VerifyDIDConfigurationPath,
+	}
+
+	resp := client.VerifyDIDConfiguration(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_GetOrbDIDHistory(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.GetOrbDIDHistoryPath,
+	}
+
+	resp := client.GetOrbDIDHistory(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_ListPendingDIDOperations(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.ListPendingDIDOperationsPath,
+	}
+
+	resp := client.ListPendingDIDOperations(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_CancelPendingDIDOperation(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.CancelPendingDIDOperationPath,
+	}
+
+	resp := client.CancelPendingDIDOperation(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_CreateDIDBatch(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.CreateDIDBatchPath,
+	}
+
+	resp := client.CreateDIDBatch(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_ValidateOrbDIDRequest(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.ValidateOrbDIDRequestPath,
+	}
+
+	resp := client.ValidateOrbDIDRequest(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_SignJWT(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.SignJWTPath,
+	}
+
+	resp := client.SignJWT(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_VerifyJWT(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.VerifyJWTPath,
+	}
+
+	resp := client.VerifyJWT(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_SignJWS(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.SignJWSPath,
+	}
+
+	resp := client.SignJWS(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_VerifyJWS(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.VerifyJWSPath,
+	}
+
+	resp := client.VerifyJWS(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_CreateDIDAuthChallenge(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.CreateDIDAuthChallengePath,
+	}
+
+	resp := client.CreateDIDAuthChallenge(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_RespondDIDAuthChallenge(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.RespondDIDAuthChallengePath,
+	}
+
+	resp := client.RespondDIDAuthChallenge(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
+
+func TestDIDClient_VerifyDIDAuthResponse(t *testing.T) {
+	client := getDIDClient(t)
+
+	client.httpClient = &mockHTTPClient{
+		data:   `{}`,
+		method: http.MethodPost, url: mockAgentURL + restdidclient.VerifyDIDAuthResponsePath,
+	}
+
+	resp := client.VerifyDIDAuthResponse(&models.RequestEnvelope{Payload: []byte(`{}`)})
+
+	require.NotNil(t, resp)
+	require.Nil(t, resp.Error)
+	require.Equal(t, `{}`, string(resp.Payload))
+}
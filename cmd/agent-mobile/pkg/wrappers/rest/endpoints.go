@@ -192,6 +192,98 @@ func getDIDClientEndpoints() map[string]*endpoint {
 			Path:   opdidclient.CreatePeerDIDPath,
 			Method: http.MethodPost,
 		},
+		cmddidclient.UpdateOrbDIDCommandMethod: {
+			Path:   opdidclient.UpdateOrbDIDPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.RecoverOrbDIDCommandMethod: {
+			Path:   opdidclient.RecoverOrbDIDPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.DeactivateOrbDIDCommandMethod: {
+			Path:   opdidclient.DeactivateOrbDIDPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.ResolveDIDCommandMethod: {
+			Path:   opdidclient.ResolveDIDPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.GetOrbDIDStatusCommandMethod: {
+			Path:   opdidclient.GetOrbDIDStatusPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.CreateDIDCommandMethod: {
+			Path:   opdidclient.CreateDIDPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.InvalidateDIDCacheCommandMethod: {
+			Path:   opdidclient.InvalidateDIDCachePath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.ListCachedDIDsCommandMethod: {
+			Path:   opdidclient.ListCachedDIDsPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.RotatePeerDIDKeysCommandMethod: {
+			Path:   opdidclient.RotatePeerDIDKeysPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.CreateDIDConfigurationCommandMethod: {
+			Path:   opdidclient.CreateDIDConfigurationPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.VerifyDIDConfigurationCommandMethod: {
+			Path:   opdidclient.VerifyDIDConfigurationPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.GetOrbDIDHistoryCommandMethod: {
+			Path:   opdidclient.GetOrbDIDHistoryPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.ListPendingDIDOperationsCommandMethod: {
+			Path:   opdidclient.ListPendingDIDOperationsPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.CancelPendingDIDOperationCommandMethod: {
+			Path:   opdidclient.CancelPendingDIDOperationPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.CreateDIDBatchCommandMethod: {
+			Path:   opdidclient.CreateDIDBatchPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.ValidateOrbDIDRequestCommandMethod: {
+			Path:   opdidclient.ValidateOrbDIDRequestPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.SignJWTCommandMethod: {
+			Path:   opdidclient.SignJWTPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.VerifyJWTCommandMethod: {
+			Path:   opdidclient.VerifyJWTPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.SignJWSCommandMethod: {
+			Path:   opdidclient.SignJWSPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.VerifyJWSCommandMethod: {
+			Path:   opdidclient.VerifyJWSPath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.CreateDIDAuthChallengeCommandMethod: {
+			Path:   opdidclient.CreateDIDAuthChallengePath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.RespondDIDAuthChallengeCommandMethod: {
+			Path:   opdidclient.RespondDIDAuthChallengePath,
+			Method: http.MethodPost,
+		},
+		cmddidclient.VerifyDIDAuthResponseCommandMethod: {
+			Path:   opdidclient.VerifyDIDAuthResponsePath,
+			Method: http.MethodPost,
+		},
 	}
 }
 
@@ -494,6 +586,54 @@ func getMediatorClientEndpoints() map[string]*endpoint {
 			Path:   opmediatorclient.SendCreateConnectionRequest,
 			Method: http.MethodPost,
 		},
+		cmdmediatorclient.GetConnections: {
+			Path:   opmediatorclient.GetConnectionsPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.Disconnect: {
+			Path:   opmediatorclient.DisconnectPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.CheckHealth: {
+			Path:   opmediatorclient.CheckHealthPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.StatusRequest: {
+			Path:   opmediatorclient.StatusRequestPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.BatchPickup: {
+			Path:   opmediatorclient.BatchPickupPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.DeliveryRequest: {
+			Path:   opmediatorclient.DeliveryRequestPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.MessagesReceived: {
+			Path:   opmediatorclient.MessagesReceivedPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.LiveMode: {
+			Path:   opmediatorclient.LiveModePath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.GetKeylist: {
+			Path:   opmediatorclient.GetKeylistPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.ReconcileKeylist: {
+			Path:   opmediatorclient.ReconcileKeylistPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.ParseInvitationURL: {
+			Path:   opmediatorclient.ParseInvitationURLPath,
+			Method: http.MethodPost,
+		},
+		cmdmediatorclient.CancelPending: {
+			Path:   opmediatorclient.CancelPendingPath,
+			Method: http.MethodPost,
+		},
 	}
 }
 
@@ -35,6 +35,66 @@ func (mc *MediatorClient) SendCreateConnectionRequest(request *models.RequestEnv
 	return mc.createRespEnvelope(request, mediatorclient.SendCreateConnectionRequest)
 }
 
+// GetConnections returns router connections of the agent along with their endpoints and routing keys.
+func (mc *MediatorClient) GetConnections(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.GetConnections)
+}
+
+// Disconnect removes recipient keys from router and unregisters the agent from the router.
+func (mc *MediatorClient) Disconnect(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.Disconnect)
+}
+
+// CheckHealth sends trust ping to routers and reports round-trip latency.
+func (mc *MediatorClient) CheckHealth(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.CheckHealth)
+}
+
+// StatusRequest requests status of messages waiting for the agent at the router.
+func (mc *MediatorClient) StatusRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.StatusRequest)
+}
+
+// BatchPickup picks up a batch of messages from DIDComm V1 router.
+func (mc *MediatorClient) BatchPickup(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.BatchPickup)
+}
+
+// DeliveryRequest requests delivery of messages from DIDComm V2 router.
+func (mc *MediatorClient) DeliveryRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.DeliveryRequest)
+}
+
+// MessagesReceived acknowledges receipt of messages delivered by DIDComm V2 router.
+func (mc *MediatorClient) MessagesReceived(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.MessagesReceived)
+}
+
+// LiveMode turns live delivery of messages by DIDComm V2 router on or off.
+func (mc *MediatorClient) LiveMode(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.LiveMode)
+}
+
+// GetKeylist queries a page of recipient keys registered with the router.
+func (mc *MediatorClient) GetKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.GetKeylist)
+}
+
+// ReconcileKeylist adds missing keys of agent DIDs to the router keylist and removes stale keys from it.
+func (mc *MediatorClient) ReconcileKeylist(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.ReconcileKeylist)
+}
+
+// ParseInvitationURL decodes out-of-band invitation from invitation URL with 'oob' or '_oob' query parameter.
+func (mc *MediatorClient) ParseInvitationURL(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.ParseInvitationURL)
+}
+
+// CancelPending cancels pending connect or create connection request with given request ID.
+func (mc *MediatorClient) CancelPending(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return mc.createRespEnvelope(request, mediatorclient.CancelPending)
+}
+
 func (mc *MediatorClient) createRespEnvelope(request *models.RequestEnvelope,
 	endpoint string) *models.ResponseEnvelope {
 	return exec(&restOperation{
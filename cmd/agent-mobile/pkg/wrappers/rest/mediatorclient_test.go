@@ -119,3 +119,243 @@ func TestMediatorClient_SendCreateConnectionRequest(t *testing.T) {
 		require.Equal(t, mockResponse, string(resp.Payload))
 	})
 }
+
+func TestMediatorClient_GetConnections(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"connections":[{"connectionID":"sample-connection"}]}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.GetConnectionsPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{}`)}
+		resp := controller.GetConnections(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_Disconnect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := ``
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.DisconnectPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := controller.Disconnect(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_CheckHealth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"results":[{"connectionID":"sample-connection","healthy":true}]}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.CheckHealthPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := controller.CheckHealth(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_StatusRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"messageCount":2}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.StatusRequestPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection"}`)}
+		resp := controller.StatusRequest(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_BatchPickup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"messageCount":2}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.BatchPickupPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","batchSize":5}`)}
+		resp := controller.BatchPickup(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_DeliveryRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"messageIDs":["msg-1"]}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.DeliveryRequestPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","limit":5}`)}
+		resp := controller.DeliveryRequest(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_MessagesReceived(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := ``
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.MessagesReceivedPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","messageIDs":["msg-1"]}`)}
+		resp := controller.MessagesReceived(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_LiveMode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := ``
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.LiveModePath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","enabled":true}`)}
+		resp := controller.LiveMode(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_GetKeylist(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"keys":["key-1"],"count":1,"offset":0,"remaining":0}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.GetKeylistPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","limit":10}`)}
+		resp := controller.GetKeylist(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_ReconcileKeylist(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"added":["key-1"],"removed":["key-2"],"dryRun":true}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.ReconcileKeylistPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"connectionID":"sample-connection","dryRun":true}`)}
+		resp := controller.ReconcileKeylist(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_ParseInvitationURL(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"invitation":{"@id":"inv-1","@type":"https://didcomm.org/out-of-band/1.0/invitation"}}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.ParseInvitationURLPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"url":"https://example.com?oob=e30"}`)}
+		resp := controller.ParseInvitationURL(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestMediatorClient_CancelPending(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getMediatorClientController(t)
+
+		mockResponse := `{"cancelled":true}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + mediatorclient.CancelPendingPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(`{"requestID":"request-1"}`)}
+		resp := controller.CancelPending(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
@@ -8,6 +8,7 @@ package startcmd
 
 import (
 	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -42,6 +43,7 @@ import (
 	"github.com/spf13/cobra"
 
 	sdkcontroller "github.com/trustbloc/agent-sdk/pkg/controller"
+	didclientcmd "github.com/trustbloc/agent-sdk/pkg/controller/command/didclient"
 )
 
 const (
@@ -125,6 +127,13 @@ const (
 		" Alternatively, this can be set with the following environment variable (in CSV format): " +
 		agentTrustblocDomainEnvKey
 
+	// orb domains flag.
+	agentOrbDomainsFlagName  = "orb-domains"
+	agentOrbDomainsEnvKey    = "ARIESD_ORB_DOMAINS"
+	agentOrbDomainsFlagUsage = "Orb domains which DID operations fail over to when trustbloc domain is not reachable." +
+		" Value should be a JSON array of domains, e.g. [{\"url\":\"https://orb.example.com\",\"priority\":1}]." +
+		" Alternatively, this can be set with the following environment variable: " + agentOrbDomainsEnvKey
+
 	// EDV server URL flag.
 	agentEDVServerURLFlagName  = "edv-server-url"
 	agentEDVServerURLEnvKey    = "ARIESD_EDV_SERVER_URL"
@@ -254,6 +263,7 @@ type agentParameters struct {
 	tlsCertFile, tlsKeyFile                        string
 	token                                          string
 	trustblocDomain                                string
+	orbDomains                                     []didclientcmd.OrbDomain
 	edvServerURL                                   string
 	trustblocResolver                              string
 	webhookURLs, httpResolvers, outboundTransports []string
@@ -388,6 +398,11 @@ func createStartCMD(server server) *cobra.Command { // nolint: funlen, gocyclo,
 				return err
 			}
 
+			orbDomains, err := getOrbDomains(cmd)
+			if err != nil {
+				return err
+			}
+
 			edvServerURL, err := getUserSetVar(cmd, agentEDVServerURLFlagName, agentEDVServerURLEnvKey, true)
 			if err != nil {
 				return err
@@ -456,6 +471,7 @@ func createStartCMD(server server) *cobra.Command { // nolint: funlen, gocyclo,
 				webhookURLs:          webhookURLs,
 				httpResolvers:        httpResolvers,
 				trustblocDomain:      trustblocDomain,
+				orbDomains:           orbDomains,
 				edvServerURL:         edvServerURL,
 				trustblocResolver:    trustblocResolver,
 				outboundTransports:   outboundTransports,
@@ -546,6 +562,24 @@ func getWebSocketReadLimit(cmd *cobra.Command) (int64, error) {
 	return readLimit, nil
 }
 
+func getOrbDomains(cmd *cobra.Command) ([]didclientcmd.OrbDomain, error) {
+	orbDomainsVal, err := getUserSetVar(cmd, agentOrbDomainsFlagName, agentOrbDomainsEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var orbDomains []didclientcmd.OrbDomain
+
+	if orbDomainsVal != "" {
+		err = json.Unmarshal([]byte(orbDomainsVal), &orbDomains)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse orb domains %s: %w", orbDomainsVal, err)
+		}
+	}
+
+	return orbDomains, nil
+}
+
 func createFlags(startCmd *cobra.Command) { // nolint: funlen
 	// agent host flag
 	startCmd.Flags().StringP(agentHostFlagName, agentHostFlagShorthand, "", agentHostFlagUsage)
@@ -584,6 +618,9 @@ func createFlags(startCmd *cobra.Command) { // nolint: funlen
 	startCmd.Flags().StringP(agentTrustblocDomainFlagName, agentTrustblocDomainFlagShorthand, "",
 		agentTrustblocDomainFlagUsage)
 
+	// orb domains flag
+	startCmd.Flags().StringP(agentOrbDomainsFlagName, "", "", agentOrbDomainsFlagUsage)
+
 	// sds server url flag
 	startCmd.Flags().StringP(agentEDVServerURLFlagName, "", "",
 		agentEDVServerURLFlagUsage)
@@ -876,7 +913,7 @@ func startAgent(parameters *agentParameters) error {
 	}
 
 	sdkHandlers, err := sdkcontroller.GetRESTHandlers(ctx, sdkcontroller.WithBlocDomain(parameters.trustblocDomain),
-		sdkcontroller.WithMessageHandler(parameters.msgHandler))
+		sdkcontroller.WithOrbDomains(parameters.orbDomains...), sdkcontroller.WithMessageHandler(parameters.msgHandler))
 	if err != nil {
 		return fmt.Errorf("failed to start sdk agent rest on port [%s], failed to get rest service api:  %w",
 			parameters.host, err)
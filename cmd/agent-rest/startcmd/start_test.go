@@ -556,6 +556,33 @@ func TestStartCmdWithInvalidReadLimit(t *testing.T) {
 	require.Contains(t, err.Error(), "failed to parse web socket read limit")
 }
 
+func TestStartCmdWithInvalidOrbDomains(t *testing.T) {
+	startCmd, err := Cmd(&mockServer{})
+	require.NoError(t, err)
+
+	args := []string{
+		"--" + agentHostFlagName,
+		randomURL(),
+		"--" + agentInboundHostFlagName,
+		httpProtocol + "@" + randomURL(),
+		"--" + agentInboundHostExternalFlagName,
+		httpProtocol + "@" + randomURL(),
+		"--" + agentOrbDomainsFlagName,
+		"invalid",
+		"--" + databaseTypeFlagName,
+		databaseTypeMemOption,
+		"--" + agentDefaultLabelFlagName,
+		"agent",
+		"--" + agentWebhookFlagName,
+		"",
+	}
+	startCmd.SetArgs(args)
+
+	err = startCmd.Execute()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse orb domains")
+}
+
 func TestStartCmdValidArgs(t *testing.T) {
 	startCmd, err := Cmd(&mockServer{})
 	require.NoError(t, err)
@@ -567,6 +594,8 @@ func TestStartCmdValidArgs(t *testing.T) {
 		httpProtocol + "@" + randomURL(),
 		"--" + agentInboundHostExternalFlagName,
 		httpProtocol + "@" + randomURL(),
+		"--" + agentOrbDomainsFlagName,
+		`[{"url":"https://orb.example.com","priority":1}]`,
 		"--" + databaseTypeFlagName,
 		databaseTypeMemOption,
 		"--" + agentDefaultLabelFlagName,
@@ -186,14 +186,23 @@ export class DIDManager {
    *  @param {Object} options
    *  @param {string} options.auth - authorization token for wallet operations.
    *  @param {string} options.collection - (optional, default no collection) collection to which this DID should belong in wallet content store.
+   *  @param {string} options.keyType - (optional, default ed25519) type of the verification key.
+   *  @param {string} options.keyAgreementType - (optional, default x25519ecdhkw for DIDCommMessaging service) type of the key agreement key.
+   *  @param {string} options.serviceType - (optional) type of DIDComm service, either 'did-communication' or 'DIDCommMessaging'.
    *
    * @returns {Promise} - empty promise or an error if operation fails..
    */
-  async createPeerDID(auth, { collection } = {}) {
+  async createPeerDID(
+    auth,
+    { collection, keyType, keyAgreementType, serviceType } = {}
+  ) {
     let content = await this.agent.didclient.createPeerDID({
       routerConnectionID: await getMediatorConnections(this.agent, {
         single: true,
       }),
+      keyType,
+      keyAgreementType,
+      serviceType,
     });
 
     await this.saveDID(auth, { content, collection });
@@ -313,23 +322,18 @@ export class DIDManager {
    * @returns {Promise<Object>} - resolved DID ID - Canonical ID of the new published DID or null if not published.
    */
   async refreshOrbDID(auth, contentID) {
-    let resolvedDID = await this.resolveOrbDID(auth, contentID);
-    if (
-      resolvedDID.didDocumentMetadata &&
-      resolvedDID.didDocumentMetadata.method &&
-      resolvedDID.didDocumentMetadata.method.published
-    ) {
+    let status = await this.agent.didclient.getOrbDIDStatus({
+      did: contentID,
+    });
+    if (status.status === "published") {
       // resolve canonical DID ID to get fresh DID Document.
-      let content = await this.resolveOrbDID(
-        auth,
-        resolvedDID.didDocumentMetadata.canonicalId
-      );
+      let content = await this.resolveOrbDID(auth, status.canonicalId);
       await Promise.all([
         this.saveDID(auth, { content }),
         this.removeDID(auth, contentID),
       ]);
 
-      return resolvedDID.didDocumentMetadata.canonicalId;
+      return status.canonicalId;
     }
 
     return null;
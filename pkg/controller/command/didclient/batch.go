@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	defaultBatchConcurrency = 10
+
+	// DID methods supported in batch.
+	batchMethodOrb  = "orb"
+	batchMethodPeer = "peer"
+)
+
+var errBatchItemSkipped = errors.New("not created as another item of atomic batch failed")
+
+// CreateDIDBatch creates orb and peer DIDs concurrently and reports the result of each of them. Failure of
+// an item doesn't fail the command unless the batch is atomic, in which case the created DIDs are rolled back.
+func (c *Command) CreateDIDBatch(rw io.Writer, req io.Reader) command.Error {
+	var request CreateDIDBatchRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDBatchCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateBatch(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDBatchCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp := c.createDIDBatch(&request)
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, CreateDIDBatchCommandMethod, successString)
+
+	return nil
+}
+
+func validateBatch(request *CreateDIDBatchRequest) error {
+	if len(request.Items) == 0 {
+		return errors.New(errEmptyBatch)
+	}
+
+	for i, item := range request.Items {
+		switch item.Method {
+		case batchMethodPeer:
+		case batchMethodOrb:
+			if !request.Atomic {
+				continue
+			}
+
+			// rollback deactivates orb DIDs, which needs their recovery keys.
+			orbRequest := CreateOrbDIDRequest{}
+
+			err := json.Unmarshal(item.Request, &orbRequest)
+			if err != nil {
+				return fmt.Errorf("invalid request of item %d : %w", i, err)
+			}
+
+			if !orbRequest.ManagedKeys {
+				return errors.New(errAtomicBatchUnmanagedKeys)
+			}
+		default:
+			return fmt.Errorf(errUnsupportedBatchMethod, item.Method)
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) createDIDBatch(request *CreateDIDBatchRequest) *CreateDIDBatchResponse {
+	concurrency := request.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var (
+		results = make([]CreateDIDBatchResult, len(request.Items))
+		created = make([]*did.DocResolution, len(request.Items))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		failed  bool
+	)
+
+	for i := range request.Items {
+		sem <- struct{}{}
+
+		mutex.Lock()
+		skip := request.Atomic && failed
+		mutex.Unlock()
+
+		if skip {
+			<-sem
+
+			results[i] = CreateDIDBatchResult{ErrorCode: CreateDIDBatchErrorCode, Error: errBatchItemSkipped.Error()}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			docResolution, docBytes, cmdErr := c.createBatchItem(&request.Items[i])
+			if cmdErr != nil {
+				results[i] = CreateDIDBatchResult{ErrorCode: cmdErr.Code(), Error: cmdErr.Error()}
+
+				mutex.Lock()
+				failed = true
+				mutex.Unlock()
+
+				return
+			}
+
+			created[i] = docResolution
+			results[i] = CreateDIDBatchResult{DID: docResolution.DIDDocument.ID, DocResolution: docBytes}
+		}(i)
+	}
+
+	wg.Wait()
+
+	resp := &CreateDIDBatchResponse{Results: results}
+
+	if !request.Atomic || !failed {
+		return resp
+	}
+
+	resp.RolledBack = true
+
+	for i := range request.Items {
+		if created[i] == nil {
+			continue
+		}
+
+		err := c.rollbackBatchItem(&request.Items[i], created[i])
+		if err != nil {
+			logger.Warnf("failed to roll back DID %s of batch : %s", created[i].DIDDocument.ID, err)
+
+			results[i].RollbackError = err.Error()
+			resp.RolledBack = false
+
+			continue
+		}
+
+		results[i].RolledBack = true
+	}
+
+	return resp
+}
+
+// createBatchItem creates DID of batch item by the create command of its method.
+func (c *Command) createBatchItem(item *CreateDIDBatchItem) (*did.DocResolution, json.RawMessage, command.Error) {
+	create := c.CreateOrbDID
+	if item.Method == batchMethodPeer {
+		create = c.CreatePeerDID
+	}
+
+	var b bytes.Buffer
+
+	cmdErr := create(&b, bytes.NewReader(item.Request))
+	if cmdErr != nil {
+		return nil, nil, cmdErr
+	}
+
+	docResolution, err := did.ParseDocumentResolution(b.Bytes())
+	if err != nil {
+		return nil, nil, command.NewExecuteError(CreateDIDBatchErrorCode,
+			fmt.Errorf("failed to parse DID resolution : %w", err))
+	}
+
+	return docResolution, b.Bytes(), nil
+}
+
+// rollbackBatchItem deactivates orb DID, or cancels its creation if it is still queued, and removes keys of
+// peer DID from its router.
+func (c *Command) rollbackBatchItem(item *CreateDIDBatchItem, docResolution *did.DocResolution) error {
+	if item.Method == batchMethodPeer {
+		peerRequest := CreatePeerDIDRequest{}
+
+		err := json.Unmarshal(item.Request, &peerRequest)
+		if err != nil {
+			return err
+		}
+
+		return c.removePeerKeysFromRouters(docResolution.DIDDocument, []string{peerRequest.RouterConnectionID})
+	}
+
+	orbRequest := CreateOrbDIDRequest{}
+
+	err := json.Unmarshal(item.Request, &orbRequest)
+	if err != nil {
+		return err
+	}
+
+	if orbRequest.Queued {
+		if docResolution.DocumentMetadata == nil || len(docResolution.DocumentMetadata.EquivalentID) == 0 {
+			return fmt.Errorf("short-form DID missing in metadata of queued DID %s", docResolution.DIDDocument.ID)
+		}
+
+		return c.cancelQueuedOrbDID(docResolution.DocumentMetadata.EquivalentID[0])
+	}
+
+	req, err := json.Marshal(&DeactivateOrbDIDRequest{
+		DID:               docResolution.DIDDocument.ID,
+		RouterConnections: orbRequest.RouterConnections,
+	})
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+
+	if cmdErr := c.DeactivateOrbDID(&b, bytes.NewReader(req)); cmdErr != nil {
+		return cmdErr
+	}
+
+	return nil
+}
+
+// cancelQueuedOrbDID cancels pending create operation of orb DID and deletes its managed keys.
+func (c *Command) cancelQueuedOrbDID(didID string) error {
+	ops, err := c.operationQueue.list()
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		if op.DID != didID || op.Type != didOperationTypeCreate {
+			continue
+		}
+
+		err = c.operationQueue.cancel(op.ID)
+		if err != nil {
+			return err
+		}
+
+		return c.managedKeys.delete(didID)
+	}
+
+	return fmt.Errorf("%w : create operation of %s", errDIDOperationNotFound, didID)
+}
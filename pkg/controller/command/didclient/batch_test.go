@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_CreateDIDBatch(t *testing.T) {
+	newCommand := func(t *testing.T) (*Command, *batchDIDClient) {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager, err = localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		c.crypto, err = tinkcrypto.New()
+		require.NoError(t, err)
+
+		client := &batchDIDClient{}
+		c.didBlocClient = client
+
+		return c, client
+	}
+
+	orbItem := func(t *testing.T, request *CreateOrbDIDRequest) CreateDIDBatchItem {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		return CreateDIDBatchItem{Method: batchMethodOrb, Request: req}
+	}
+
+	createBatch := func(t *testing.T, c *Command, request *CreateDIDBatchRequest) (*CreateDIDBatchResponse,
+		command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDIDBatch(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp := &CreateDIDBatchResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp, nil
+	}
+
+	t.Run("test success with partial failure", func(t *testing.T) {
+		c, client := newCommand(t)
+
+		request := &CreateDIDBatchRequest{Concurrency: 2}
+
+		for i := 0; i < 5; i++ {
+			request.Items = append(request.Items, orbItem(t, &CreateOrbDIDRequest{ServiceID: fmt.Sprintf("svc%d", i)}))
+		}
+
+		request.Items = append(request.Items,
+			orbItem(t, &CreateOrbDIDRequest{ServiceID: "fail"}),
+			CreateDIDBatchItem{Method: batchMethodPeer, Request: []byte(`{}`)},
+		)
+
+		resp, cmdErr := createBatch(t, c, request)
+		require.NoError(t, cmdErr)
+		require.False(t, resp.RolledBack)
+		require.Len(t, resp.Results, 7)
+
+		for i := 0; i < 5; i++ {
+			result := resp.Results[i]
+			require.Empty(t, result.Error)
+
+			docResolution, err := did.ParseDocumentResolution(result.DocResolution)
+			require.NoError(t, err)
+			require.Equal(t, result.DID, docResolution.DIDDocument.ID)
+			require.Equal(t, fmt.Sprintf("svc%d", i), docResolution.DIDDocument.Service[0].ID)
+		}
+
+		require.Equal(t, CreateDIDErrorCode, resp.Results[5].ErrorCode)
+		require.Contains(t, resp.Results[5].Error, "create error")
+		require.Equal(t, InvalidRequestErrorCode, resp.Results[6].ErrorCode)
+		require.Contains(t, resp.Results[6].Error, errInvalidRouterConnectionID)
+
+		require.LessOrEqual(t, client.maxConcurrent, 2)
+		require.Empty(t, client.deactivated)
+	})
+
+	t.Run("test atomic batch rolled back", func(t *testing.T) {
+		c, client := newCommand(t)
+
+		resp, cmdErr := createBatch(t, c, &CreateDIDBatchRequest{
+			Concurrency: 1,
+			Atomic:      true,
+			Items: []CreateDIDBatchItem{
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, ServiceID: "svc0"}),
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, ServiceID: "fail"}),
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, ServiceID: "svc2"}),
+			},
+		})
+		require.NoError(t, cmdErr)
+		require.True(t, resp.RolledBack)
+
+		require.True(t, resp.Results[0].RolledBack)
+		require.Equal(t, []string{resp.Results[0].DID}, client.deactivated)
+
+		_, err := c.managedKeys.get(resp.Results[0].DID)
+		require.True(t, errors.Is(err, errManagedKeysNotFound))
+
+		require.Equal(t, CreateDIDErrorCode, resp.Results[1].ErrorCode)
+		require.Equal(t, CreateDIDBatchErrorCode, resp.Results[2].ErrorCode)
+		require.Equal(t, errBatchItemSkipped.Error(), resp.Results[2].Error)
+	})
+
+	t.Run("test atomic batch rollback error", func(t *testing.T) {
+		c, client := newCommand(t)
+		client.deactivateErr = errors.New("deactivate error")
+
+		resp, cmdErr := createBatch(t, c, &CreateDIDBatchRequest{
+			Concurrency: 1,
+			Atomic:      true,
+			Items: []CreateDIDBatchItem{
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true}),
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, ServiceID: "fail"}),
+			},
+		})
+		require.NoError(t, cmdErr)
+		require.False(t, resp.RolledBack)
+		require.False(t, resp.Results[0].RolledBack)
+		require.Contains(t, resp.Results[0].RollbackError, "deactivate error")
+	})
+
+	t.Run("test atomic batch cancels queued creation", func(t *testing.T) {
+		c, _ := newCommand(t)
+		c.operationQueue.submit = func(context.Context, []byte, string) error {
+			return errors.New("orb is not reachable")
+		}
+		c.operationQueue.initialBackoff = time.Hour
+
+		resp, cmdErr := createBatch(t, c, &CreateDIDBatchRequest{
+			Concurrency: 1,
+			Atomic:      true,
+			Items: []CreateDIDBatchItem{
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, Queued: true}),
+				orbItem(t, &CreateOrbDIDRequest{ManagedKeys: true, ServiceID: "fail"}),
+			},
+		})
+		require.NoError(t, cmdErr)
+		require.True(t, resp.RolledBack)
+		require.True(t, resp.Results[0].RolledBack)
+
+		ops, err := c.operationQueue.list()
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+
+	t.Run("test rollback of queued DID without short-form DID", func(t *testing.T) {
+		c, _ := newCommand(t)
+
+		err := c.rollbackBatchItem(&CreateDIDBatchItem{
+			Method:  batchMethodOrb,
+			Request: json.RawMessage(`{"queued":true}`),
+		}, &did.DocResolution{DIDDocument: &did.Doc{ID: "did:orb:uAAA:suffix"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "short-form DID missing in metadata of queued DID did:orb:uAAA:suffix")
+	})
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, _ := newCommand(t)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDIDBatch(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		_, cmdErr = createBatch(t, c, &CreateDIDBatchRequest{})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errEmptyBatch)
+
+		_, cmdErr = createBatch(t, c, &CreateDIDBatchRequest{Items: []CreateDIDBatchItem{{Method: "web"}}})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "unsupported DID method in batch: web")
+
+		_, cmdErr = createBatch(t, c, &CreateDIDBatchRequest{
+			Atomic: true,
+			Items:  []CreateDIDBatchItem{orbItem(t, &CreateOrbDIDRequest{})},
+		})
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errAtomicBatchUnmanagedKeys)
+
+		_, cmdErr = createBatch(t, c, &CreateDIDBatchRequest{
+			Atomic: true,
+			Items:  []CreateDIDBatchItem{{Method: batchMethodOrb, Request: []byte(`[]`)}},
+		})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "invalid request of item 0")
+	})
+}
+
+// batchDIDClient creates published orb DIDs concurrently, failing documents of 'fail' service.
+type batchDIDClient struct {
+	mockDIDClient
+	mutex         sync.Mutex
+	created       int
+	concurrent    int
+	maxConcurrent int
+	deactivated   []string
+}
+
+func (m *batchDIDClient) Create(didDoc *did.Doc, _ ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	m.mutex.Lock()
+	m.created++
+	m.concurrent++
+	didID := fmt.Sprintf("did:orb:https:orb.domain1.com:EiA%d", m.created)
+
+	if m.concurrent > m.maxConcurrent {
+		m.maxConcurrent = m.concurrent
+	}
+	m.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	m.mutex.Lock()
+	m.concurrent--
+	m.mutex.Unlock()
+
+	if didDoc.Service[0].ID == "fail" {
+		return nil, errors.New("create error")
+	}
+
+	return &did.DocResolution{
+		DIDDocument:      longFormDIDDoc(didDoc, didID),
+		DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{Published: true}},
+	}, nil
+}
+
+func (m *batchDIDClient) Read(didID string, _ ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+}
+
+func (m *batchDIDClient) Deactivate(didID string, _ ...vdr.DIDMethodOption) error {
+	if m.deactivateErr != nil {
+		return m.deactivateErr
+	}
+
+	m.deactivated = append(m.deactivated, didID)
+
+	return nil
+}
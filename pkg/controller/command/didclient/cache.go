@@ -0,0 +1,307 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	didCacheKeyPrefix = "didcache_"
+	didCacheTag       = "didCache"
+
+	defaultAnchoredCacheTTL   = 10 * time.Minute
+	defaultUnanchoredCacheTTL = time.Minute
+
+	orbDIDPrefix = "did:orb:"
+)
+
+// CachedDID cached resolution of DID.
+type CachedDID struct {
+	DID         string    `json:"did"`
+	VersionID   string    `json:"versionId,omitempty"`
+	VersionTime string    `json:"versionTime,omitempty"`
+	Anchored    bool      `json:"anchored"`
+	CachedAt    time.Time `json:"cachedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type cacheEntry struct {
+	CachedDID
+	Resolution json.RawMessage `json:"resolution"`
+}
+
+// resolutionCache persists DID resolutions for TTL, which is longer for anchored DIDs as their documents
+// change only through operations.
+type resolutionCache struct {
+	store         storage.Store
+	anchoredTTL   time.Duration
+	unanchoredTTL time.Duration
+	now           func() time.Time
+}
+
+func newResolutionCache(store storage.Store, anchoredTTL, unanchoredTTL time.Duration) *resolutionCache {
+	return &resolutionCache{
+		store:         store,
+		anchoredTTL:   anchoredTTL,
+		unanchoredTTL: unanchoredTTL,
+		now:           time.Now,
+	}
+}
+
+// get returns cached resolution of DID version or nil if it isn't cached or has expired.
+func (c *resolutionCache) get(didID, versionID, versionTime string) (*did.DocResolution, error) {
+	data, err := c.store.Get(cacheKey(didID, versionID, versionTime))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached DID resolution : %w", err)
+	}
+
+	entry := &cacheEntry{}
+
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached DID resolution : %w", err)
+	}
+
+	if !c.now().Before(entry.ExpiresAt) {
+		return nil, c.delete(cacheKey(didID, versionID, versionTime))
+	}
+
+	docResolution, err := did.ParseDocumentResolution(entry.Resolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cached DID resolution : %w", err)
+	}
+
+	return docResolution, nil
+}
+
+// put caches resolution of DID version unless TTL for it is disabled.
+func (c *resolutionCache) put(didID, versionID, versionTime string, docResolution *did.DocResolution) error {
+	anchored := isAnchored(docResolution)
+
+	ttl := c.unanchoredTTL
+	if anchored {
+		ttl = c.anchoredTTL
+	}
+
+	if ttl <= 0 {
+		return nil
+	}
+
+	resolution, err := docResolution.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("failed to marshal DID resolution : %w", err)
+	}
+
+	now := c.now()
+
+	data, err := json.Marshal(&cacheEntry{
+		CachedDID: CachedDID{
+			DID:         didID,
+			VersionID:   versionID,
+			VersionTime: versionTime,
+			Anchored:    anchored,
+			CachedAt:    now,
+			ExpiresAt:   now.Add(ttl),
+		},
+		Resolution: resolution,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached DID resolution : %w", err)
+	}
+
+	err = c.store.Put(cacheKey(didID, versionID, versionTime), data,
+		storage.Tag{Name: didCacheTag, Value: cacheTagValue(didID)})
+	if err != nil {
+		return fmt.Errorf("failed to save cached DID resolution : %w", err)
+	}
+
+	return nil
+}
+
+// invalidate removes cached resolutions of all versions of DID, or the whole cache if DID is empty.
+// Orb DIDs are matched by suffix so that both unpublished and canonical forms are invalidated.
+func (c *resolutionCache) invalidate(didID string) error {
+	query := didCacheTag
+	if didID != "" {
+		query += ":" + cacheTagValue(didID)
+	}
+
+	entries, err := c.query(query)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = c.delete(cacheKey(entry.DID, entry.VersionID, entry.VersionTime))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// list returns cached DIDs which haven't expired yet.
+func (c *resolutionCache) list() ([]CachedDID, error) {
+	entries, err := c.query(didCacheTag)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := make([]CachedDID, 0, len(entries))
+
+	for _, entry := range entries {
+		if c.now().Before(entry.ExpiresAt) {
+			cached = append(cached, entry.CachedDID)
+		}
+	}
+
+	return cached, nil
+}
+
+func (c *resolutionCache) query(query string) ([]*cacheEntry, error) {
+	iter, err := c.store.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cached DID resolutions : %w", err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("failed to close iterator : %s", e)
+		}
+	}()
+
+	var entries []*cacheEntry
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next cached DID resolution : %w", err)
+		}
+
+		if !ok {
+			return entries, nil
+		}
+
+		data, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cached DID resolution : %w", err)
+		}
+
+		entry := &cacheEntry{}
+
+		err = json.Unmarshal(data, entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cached DID resolution : %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+}
+
+func (c *resolutionCache) delete(key string) error {
+	err := c.store.Delete(key)
+	if err != nil {
+		return fmt.Errorf("failed to delete cached DID resolution : %w", err)
+	}
+
+	return nil
+}
+
+func cacheKey(didID, versionID, versionTime string) string {
+	return didCacheKeyPrefix + didID + "|" + versionID + "|" + versionTime
+}
+
+// cacheTagValue returns tag value of cached resolutions of DID, tag values can't contain ':'.
+func cacheTagValue(didID string) string {
+	if strings.HasPrefix(didID, orbDIDPrefix) {
+		return didSuffix(didID)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(didID))
+}
+
+func isAnchored(docResolution *did.DocResolution) bool {
+	metadata := docResolution.DocumentMetadata
+
+	return metadata != nil && metadata.Method != nil && metadata.Method.Published
+}
+
+// cachedResolve returns cached resolution of DID version or resolves and caches it. Cache isn't read if
+// noCache is set, but the resolution is still cached.
+func (c *Command) cachedResolve(didID, versionID, versionTime string, noCache bool,
+	resolve func() (*did.DocResolution, error)) (*did.DocResolution, error) {
+	if !noCache {
+		docResolution, err := c.resolutionCache.get(didID, versionID, versionTime)
+		if err != nil {
+			logger.Warnf("failed to get cached resolution of DID %s : %s", didID, err)
+		}
+
+		if docResolution != nil {
+			return docResolution, nil
+		}
+	}
+
+	docResolution, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.resolutionCache.put(didID, versionID, versionTime, docResolution)
+	if err != nil {
+		logger.Warnf("failed to cache resolution of DID %s : %s", didID, err)
+	}
+
+	return docResolution, nil
+}
+
+// resolve resolves DID with the VDR registry through the resolution cache.
+func (c *Command) resolve(didID string) (*did.DocResolution, error) {
+	return c.cachedResolve(didID, "", "", false, func() (*did.DocResolution, error) {
+		return c.vdrRegistry.Resolve(didID)
+	})
+}
+
+// cachingVDR returns VDR registry which resolves DIDs through the resolution cache, for verifiers which
+// resolve keys of DIDs themselves.
+func (c *Command) cachingVDR() vdr.Registry {
+	return &cachingRegistry{Registry: c.vdrRegistry, c: c}
+}
+
+type cachingRegistry struct {
+	vdr.Registry
+	c *Command
+}
+
+func (r *cachingRegistry) Resolve(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	if len(opts) > 0 {
+		return r.Registry.Resolve(didID, opts...)
+	}
+
+	return r.c.resolve(didID)
+}
+
+// invalidateDIDCache drops cached resolutions of DID changed by an operation.
+func (c *Command) invalidateDIDCache(didID string) {
+	err := c.resolutionCache.invalidate(didID)
+	if err != nil {
+		logger.Warnf("failed to invalidate cached resolutions of DID %s : %s", didID, err)
+	}
+}
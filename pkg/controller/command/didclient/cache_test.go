@@ -0,0 +1,360 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func unpublishedResolution(didID string) *did.DocResolution {
+	return &did.DocResolution{
+		DIDDocument:      &did.Doc{Context: []string{did.ContextV1}, ID: didID},
+		DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{Published: false}},
+	}
+}
+
+func TestResolutionCache(t *testing.T) {
+	t.Run("test TTL of anchored and unanchored DIDs", func(t *testing.T) {
+		c := newResolutionCache(mockstorage.NewMockStoreProvider().Store, time.Hour, time.Minute)
+
+		now := time.Now()
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.put("did:orb:bafy:EiA123", "", "", publishedResolution("did:orb:bafy:EiA123")))
+		require.NoError(t, c.put("did:orb:uAAA:EiA456", "", "", unpublishedResolution("did:orb:uAAA:EiA456")))
+
+		docResolution, err := c.get("did:orb:bafy:EiA123", "", "")
+		require.NoError(t, err)
+		require.Equal(t, "did:orb:bafy:EiA123", docResolution.DIDDocument.ID)
+		require.True(t, docResolution.DocumentMetadata.Method.Published)
+
+		cached, err := c.list()
+		require.NoError(t, err)
+		require.Len(t, cached, 2)
+
+		now = now.Add(2 * time.Minute)
+
+		docResolution, err = c.get("did:orb:uAAA:EiA456", "", "")
+		require.NoError(t, err)
+		require.Nil(t, docResolution)
+
+		docResolution, err = c.get("did:orb:bafy:EiA123", "", "")
+		require.NoError(t, err)
+		require.NotNil(t, docResolution)
+
+		cached, err = c.list()
+		require.NoError(t, err)
+		require.Len(t, cached, 1)
+		require.Equal(t, "did:orb:bafy:EiA123", cached[0].DID)
+		require.True(t, cached[0].Anchored)
+
+		now = now.Add(time.Hour)
+
+		cached, err = c.list()
+		require.NoError(t, err)
+		require.Empty(t, cached)
+	})
+
+	t.Run("test versions and disabled TTL", func(t *testing.T) {
+		c := newResolutionCache(mockstorage.NewMockStoreProvider().Store, time.Hour, -1)
+
+		require.NoError(t, c.put("did:orb:bafy:EiA123", "v1", "", publishedResolution("did:orb:bafy:EiA123")))
+		require.NoError(t, c.put("did:key:z6Mk", "", "", unpublishedResolution("did:key:z6Mk")))
+
+		docResolution, err := c.get("did:orb:bafy:EiA123", "", "")
+		require.NoError(t, err)
+		require.Nil(t, docResolution)
+
+		docResolution, err = c.get("did:orb:bafy:EiA123", "v1", "")
+		require.NoError(t, err)
+		require.NotNil(t, docResolution)
+
+		docResolution, err = c.get("did:key:z6Mk", "", "")
+		require.NoError(t, err)
+		require.Nil(t, docResolution)
+	})
+
+	t.Run("test invalidate", func(t *testing.T) {
+		c := newResolutionCache(mockstorage.NewMockStoreProvider().Store, time.Hour, time.Hour)
+
+		require.NoError(t, c.put("did:orb:uAAA:EiA123", "", "", unpublishedResolution("did:orb:uAAA:EiA123")))
+		require.NoError(t, c.put("did:orb:bafy:EiA123", "v1", "", publishedResolution("did:orb:bafy:EiA123")))
+		require.NoError(t, c.put("did:web:example.com", "", "", unpublishedResolution("did:web:example.com")))
+		require.NoError(t, c.put("did:key:z6Mk", "", "", unpublishedResolution("did:key:z6Mk")))
+
+		// all forms and versions of orb DID are invalidated
+		require.NoError(t, c.invalidate("did:orb:https:example.com:EiA123"))
+
+		cached, err := c.list()
+		require.NoError(t, err)
+		require.Len(t, cached, 2)
+
+		require.NoError(t, c.invalidate("did:web:example.com"))
+
+		cached, err = c.list()
+		require.NoError(t, err)
+		require.Len(t, cached, 1)
+		require.Equal(t, "did:key:z6Mk", cached[0].DID)
+
+		require.NoError(t, c.put("did:web:example.com", "", "", unpublishedResolution("did:web:example.com")))
+		require.NoError(t, c.invalidate(""))
+
+		cached, err = c.list()
+		require.NoError(t, err)
+		require.Empty(t, cached)
+	})
+
+	t.Run("test store errors", func(t *testing.T) {
+		c := newResolutionCache(&mockstorage.MockStore{
+			Store:     make(map[string]mockstorage.DBEntry),
+			ErrGet:    fmt.Errorf("get error"),
+			ErrPut:    fmt.Errorf("put error"),
+			ErrQuery:  fmt.Errorf("query error"),
+			ErrDelete: fmt.Errorf("delete error"),
+		}, time.Hour, time.Hour)
+
+		_, err := c.get("did:key:z6Mk", "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		err = c.put("did:key:z6Mk", "", "", unpublishedResolution("did:key:z6Mk"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+
+		err = c.invalidate("did:key:z6Mk")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+
+		_, err = c.list()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+
+		c = newResolutionCache(mockstorage.NewMockStoreProvider().Store, time.Hour, time.Hour)
+		require.NoError(t, c.store.Put(cacheKey("did:key:z6Mk", "", ""), []byte("{"),
+			storage.Tag{Name: didCacheTag, Value: cacheTagValue("did:key:z6Mk")}))
+
+		_, err = c.get("did:key:z6Mk", "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal cached DID resolution")
+
+		_, err = c.list()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal cached DID resolution")
+	})
+}
+
+func TestCommand_DIDCache(t *testing.T) {
+	resolveOrbDID := func(t *testing.T, c *Command, didID string) *did.DocResolution {
+		t.Helper()
+
+		req, err := json.Marshal(ResolveOrbDIDRequest{DID: didID})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.ResolveOrbDID(&b, bytes.NewBuffer(req)))
+
+		docResolution, err := did.ParseDocumentResolution(b.Bytes())
+		require.NoError(t, err)
+
+		return docResolution
+	}
+
+	listCached := func(t *testing.T, c *Command) []CachedDID {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.ListCachedDIDs(&b, nil))
+
+		resp := &ListCachedDIDsResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp.DIDs
+	}
+
+	t.Run("test orb DID resolution cached", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		client := &mockDIDClient{resolveDIDValue: publishedResolution("did:orb:bafy:EiA123")}
+		c.didBlocClient = client
+
+		resolveOrbDID(t, c, "did:orb:bafy:EiA123")
+
+		client.resolveDIDValue = publishedResolution("did:orb:bafy:EiA456")
+
+		// cached resolution is returned
+		require.Equal(t, "did:orb:bafy:EiA123", resolveOrbDID(t, c, "did:orb:bafy:EiA123").DIDDocument.ID)
+
+		cached := listCached(t, c)
+		require.Len(t, cached, 1)
+		require.Equal(t, "did:orb:bafy:EiA123", cached[0].DID)
+
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		c.keyManager = &mockkms.KeyManager{ExportPubKeyBytesValue: pubKey, ExportPubKeyTypeValue: kms.ED25519Type}
+
+		// deactivate invalidates cached resolution
+		req, err := json.Marshal(DeactivateOrbDIDRequest{DID: "did:orb:bafy:EiA123", RecoveryKeyID: "recovery"})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.DeactivateOrbDID(&b, bytes.NewBuffer(req)))
+		require.Empty(t, listCached(t, c))
+
+		require.Equal(t, "did:orb:bafy:EiA456", resolveOrbDID(t, c, "did:orb:bafy:EiA123").DIDDocument.ID)
+	})
+
+	t.Run("test resolve DID with no cache", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier(),
+			WithResolutionCacheTTL(time.Hour, time.Hour))
+		require.NoError(t, err)
+
+		registry := &mockvdr.MockVDRegistry{
+			ResolveValue: &did.Doc{Context: []string{did.ContextV1}, ID: "did:web:example.com"},
+		}
+		c.vdrRegistry = registry
+
+		resolveDID := func(request *ResolveDIDRequest) *ResolveDIDResponse {
+			req, e := json.Marshal(request)
+			require.NoError(t, e)
+
+			var b bytes.Buffer
+
+			require.NoError(t, c.ResolveDID(&b, bytes.NewBuffer(req)))
+
+			resp := &ResolveDIDResponse{}
+			require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+			return resp
+		}
+
+		resolveDID(&ResolveDIDRequest{DID: "did:web:example.com"})
+
+		registry.ResolveValue = &did.Doc{
+			Context: []string{did.ContextV1},
+			ID:      "did:web:example.com",
+			Service: []did.Service{{
+				ID:              "#svc1",
+				Type:            "LinkedDomains",
+				ServiceEndpoint: model.NewDIDCommV1Endpoint("https://example.com"),
+			}},
+		}
+
+		resp := resolveDID(&ResolveDIDRequest{DID: "did:web:example.com"})
+		require.NotContains(t, string(resp.DIDDocument), "#svc1")
+
+		resp = resolveDID(&ResolveDIDRequest{DID: "did:web:example.com", NoCache: true})
+		require.Contains(t, string(resp.DIDDocument), "#svc1")
+
+		// fresh resolution replaces cached one
+		resp = resolveDID(&ResolveDIDRequest{DID: "did:web:example.com"})
+		require.Contains(t, string(resp.DIDDocument), "#svc1")
+	})
+
+	t.Run("test verifiers resolve DIDs through cache", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier(),
+			WithResolutionCacheTTL(time.Hour, time.Hour))
+		require.NoError(t, err)
+
+		resolved := 0
+
+		c.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, opts ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				resolved++
+
+				return &did.DocResolution{DIDDocument: &did.Doc{Context: []string{did.ContextV1}, ID: didID}}, nil
+			},
+		}
+
+		_, err = c.resolve("did:web:example.com")
+		require.NoError(t, err)
+
+		docResolution, err := c.cachingVDR().Resolve("did:web:example.com")
+		require.NoError(t, err)
+		require.Equal(t, "did:web:example.com", docResolution.DIDDocument.ID)
+		require.Equal(t, 1, resolved)
+
+		// resolutions with options aren't cached
+		_, err = c.cachingVDR().Resolve("did:web:example.com", vdrapi.WithOption("opt", "value"))
+		require.NoError(t, err)
+		require.Equal(t, 2, resolved)
+	})
+
+	t.Run("test invalidate DID cache", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: publishedResolution("did:orb:bafy:EiA123")}
+
+		resolveOrbDID(t, c, "did:orb:bafy:EiA123")
+		resolveOrbDID(t, c, "did:orb:bafy:EiA456")
+		require.Len(t, listCached(t, c), 2)
+
+		var b bytes.Buffer
+
+		cmdErr := c.InvalidateDIDCache(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		require.NoError(t, c.InvalidateDIDCache(&b, bytes.NewBufferString(`{"did":"did:orb:bafy:EiA123"}`)))
+		require.Len(t, listCached(t, c), 1)
+
+		require.NoError(t, c.InvalidateDIDCache(&b, bytes.NewBufferString(`{}`)))
+		require.Empty(t, listCached(t, c))
+	})
+
+	t.Run("test cache errors", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: publishedResolution("did:orb:bafy:EiA123")}
+		c.resolutionCache.store = &mockstorage.MockStore{
+			Store:    make(map[string]mockstorage.DBEntry),
+			ErrGet:   fmt.Errorf("get error"),
+			ErrPut:   fmt.Errorf("put error"),
+			ErrQuery: fmt.Errorf("query error"),
+		}
+
+		// resolution doesn't fail because of cache
+		require.Equal(t, "did:orb:bafy:EiA123", resolveOrbDID(t, c, "did:orb:bafy:EiA123").DIDDocument.ID)
+
+		var b bytes.Buffer
+
+		cmdErr := c.InvalidateDIDCache(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDCacheErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "query error")
+
+		cmdErr = c.ListCachedDIDs(&b, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDCacheErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "query error")
+	})
+}
@@ -24,24 +24,34 @@ import (
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
 	"github.com/hyperledger/aries-framework-go/pkg/client/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/common/model"
+	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/middleware"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	jwk2 "github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk/jwksupport"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/agent-sdk/pkg/controller/command"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/cmdutil"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
 )
 
 var logger = log.New("agent-sdk-didclient")
 
+// errDIDMethodNotSupported is returned when none of the VDRs of the registry accepts the DID method.
+var errDIDMethodNotSupported = errors.New("DID method not supported")
+
 const (
 	// CommandName package command name.
 	CommandName = "didclient"
@@ -51,12 +61,73 @@ const (
 	ResolveOrbDIDCommandMethod = "ResolveOrbDID"
 	// CreatePeerDIDCommandMethod command method.
 	CreatePeerDIDCommandMethod = "CreatePeerDID"
+	// UpdateOrbDIDCommandMethod command method.
+	UpdateOrbDIDCommandMethod = "UpdateOrbDID"
+	// RecoverOrbDIDCommandMethod command method.
+	RecoverOrbDIDCommandMethod = "RecoverOrbDID"
+	// DeactivateOrbDIDCommandMethod command method.
+	DeactivateOrbDIDCommandMethod = "DeactivateOrbDID"
+	// ResolveDIDCommandMethod command method.
+	ResolveDIDCommandMethod = "ResolveDID"
+	// GetOrbDIDStatusCommandMethod command method.
+	GetOrbDIDStatusCommandMethod = "GetOrbDIDStatus"
+
+	// GetOrbDIDHistoryCommandMethod command method.
+	GetOrbDIDHistoryCommandMethod = "GetOrbDIDHistory"
+
+	// ListPendingDIDOperationsCommandMethod command method.
+	ListPendingDIDOperationsCommandMethod = "ListPendingDIDOperations"
+
+	// CancelPendingDIDOperationCommandMethod command method.
+	CancelPendingDIDOperationCommandMethod = "CancelPendingDIDOperation"
+	// CreateDIDCommandMethod command method.
+	CreateDIDCommandMethod = "CreateDID"
+	// InvalidateDIDCacheCommandMethod command method.
+	InvalidateDIDCacheCommandMethod = "InvalidateDIDCache"
+	// ListCachedDIDsCommandMethod command method.
+	ListCachedDIDsCommandMethod = "ListCachedDIDs"
+	// RotatePeerDIDKeysCommandMethod command method.
+	RotatePeerDIDKeysCommandMethod = "RotatePeerDIDKeys"
+	// CreateDIDConfigurationCommandMethod command method.
+	CreateDIDConfigurationCommandMethod = "CreateDIDConfiguration"
+	// VerifyDIDConfigurationCommandMethod command method.
+	VerifyDIDConfigurationCommandMethod = "VerifyDIDConfiguration"
+	// CreateDIDBatchCommandMethod command method.
+	CreateDIDBatchCommandMethod = "CreateDIDBatch"
+	// ValidateOrbDIDRequestCommandMethod command method.
+	ValidateOrbDIDRequestCommandMethod = "ValidateOrbDIDRequest"
+	// SignJWTCommandMethod command method.
+	SignJWTCommandMethod = "SignJWT"
+	// VerifyJWTCommandMethod command method.
+	VerifyJWTCommandMethod = "VerifyJWT"
+	// SignJWSCommandMethod command method.
+	SignJWSCommandMethod = "SignJWS"
+	// VerifyJWSCommandMethod command method.
+	VerifyJWSCommandMethod = "VerifyJWS"
+	// CreateDIDAuthChallengeCommandMethod command method.
+	CreateDIDAuthChallengeCommandMethod = "CreateDIDAuthChallenge"
+	// RespondDIDAuthChallengeCommandMethod command method.
+	RespondDIDAuthChallengeCommandMethod = "RespondDIDAuthChallenge"
+	// VerifyDIDAuthResponseCommandMethod command method.
+	VerifyDIDAuthResponseCommandMethod = "VerifyDIDAuthResponse"
 	// log constants.
 	successString = "success"
 
 	didCommServiceType   = "did-communication"
 	didCommV2ServiceType = "DIDCommMessaging"
 
+	// DID document representation media types.
+	didLDJSONMediaType = "application/did+ld+json"
+	didJSONMediaType   = "application/did+json"
+
+	// DID resolution metadata error codes.
+	resolutionErrInvalidDID                = "invalidDid"
+	resolutionErrNotFound                  = "notFound"
+	resolutionErrMethodNotSupported        = "methodNotSupported"
+	resolutionErrRepresentationUnsupported = "representationNotSupported"
+	resolutionErrInvalidOptions            = "invalidDidResolutionOptions"
+	resolutionErrInternal                  = "internalError"
+
 	// ed25519KeyType defines ed25119 key type.
 	ed25519KeyType = "ed25519"
 
@@ -88,10 +159,77 @@ const (
 	// ResolveDIDErrorCode is typically a code for resolve did errors.
 	ResolveDIDErrorCode
 
+	// UpdateDIDErrorCode is typically a code for update did errors.
+	UpdateDIDErrorCode
+
+	// RecoverDIDErrorCode is typically a code for recover did errors.
+	RecoverDIDErrorCode
+
+	// DeactivateDIDErrorCode is typically a code for deactivate did errors.
+	DeactivateDIDErrorCode
+
+	// GetOrbDIDStatusErrorCode is typically a code for get orb did status errors.
+	GetOrbDIDStatusErrorCode
+
+	// DIDCacheErrorCode is typically a code for DID resolution cache errors.
+	DIDCacheErrorCode
+
+	// RotatePeerDIDKeysErrorCode is typically a code for rotate peer did keys errors.
+	RotatePeerDIDKeysErrorCode
+
+	// DIDConfigurationErrorCode is typically a code for DID configuration errors.
+	DIDConfigurationErrorCode
+
+	// GetOrbDIDHistoryErrorCode is typically a code for get orb did history errors.
+	GetOrbDIDHistoryErrorCode
+
+	// DIDOperationQueueErrorCode is typically a code for queued did operation errors.
+	DIDOperationQueueErrorCode
+
+	// CreateDIDBatchErrorCode is typically a code for batch did creation errors.
+	CreateDIDBatchErrorCode
+
+	// SignErrorCode is typically a code for JWT and JWS signing errors.
+	SignErrorCode
+
+	// VerifySignatureErrorCode is typically a code for JWT and JWS verification errors.
+	VerifySignatureErrorCode
+
+	// DIDAuthErrorCode is typically a code for DID Auth errors.
+	DIDAuthErrorCode
+
 	// errors.
-	errInvalidRouterConnectionID = "invalid router connection ID"
-	errMissingDIDCommServiceType = "did document missing '%s' service type"
-	errFailedToRegisterDIDRecKey = "failed to register did doc recipient key : %w"
+	errInvalidRouterConnectionID   = "invalid router connection ID"
+	errInvalidDID                  = "invalid DID"
+	errInvalidUpdateKeyID          = "invalid update key ID"
+	errInvalidNextUpdateKey        = "invalid next update key"
+	errInvalidRecoveryKeyID        = "invalid recovery key ID"
+	errInvalidNextRecoveryKey      = "invalid next recovery key"
+	errManagedUpdateRecoveryKeys   = "update and recovery keys can't be provided when keys are managed by the agent"
+	errMissingDIDCommServiceType   = "did document missing '%s' service type"
+	errUnsupportedKeyType          = "unsupported key type: %s"
+	errUnsupportedKeyAgreementType = "unsupported key agreement type: %s"
+	errUnsupportedServiceType      = "unsupported service type: %s"
+	errUnsupportedDIDMethod        = "unsupported DID method: %s"
+	errUnsupportedKeyPurpose       = "unsupported key purpose: %s"
+	errSingleKeyMethod             = "did:%s supports a single key"
+	errInvalidDomain               = "invalid domain"
+	errInvalidOrigin               = "invalid origin: %s"
+	errUnsupportedDIDConfigFormat  = "unsupported DID configuration format: %s"
+	errVersionIDAndTime            = "versionId and versionTime can't be used together"
+	errInvalidVersionTime          = "invalid versionTime: %w"
+	errInvalidOperationID          = "invalid operation ID"
+	errEmptyBatch                  = "batch has no items"
+	errUnsupportedBatchMethod      = "unsupported DID method in batch: %s"
+	errAtomicBatchUnmanagedKeys    = "atomic batch requires orb DIDs with keys managed by the agent"
+	errUnsupportedSignaturePurpose = "unsupported signature purpose: %s"
+	errMissingClaims               = "claims must be a JSON object"
+	errMissingPayload              = "payload is required"
+	errMissingDIDAuthDomain        = "domain is required"
+	errMissingDIDAuthChallenge     = "challenge is required"
+	errUnsupportedDIDAuthFormat    = "unsupported DID Auth format: %s"
+	errExpiredDIDAuthChallenge     = "expiration date of challenge has passed"
+	errFailedToRegisterDIDRecKey   = "failed to register did doc recipient key : %w"
 )
 
 // Provider describes dependencies for the client.
@@ -99,11 +237,25 @@ type Provider interface {
 	VDRegistry() vdr.Registry
 	Service(id string) (interface{}, error)
 	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	MediaTypeProfiles() []string
+	DIDConnectionStore() didstore.ConnectionStore
 }
 
 type didBlocClient interface {
 	Create(did *did.Doc, opts ...vdr.DIDMethodOption) (*did.DocResolution, error)
 	Read(id string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error)
+	Update(did *did.Doc, opts ...vdr.DIDMethodOption) error
+	Deactivate(did string, opts ...vdr.DIDMethodOption) error
+}
+
+// httpClient fetches DID configuration resources and submits queued orb DID operations.
+type httpClient interface {
+	Get(url string) (*http.Response, error)
+	Do(req *http.Request) (*http.Response, error)
 }
 
 // mediatorClient is client interface for mediator.
@@ -112,17 +264,95 @@ type mediatorClient interface {
 	GetConfig(connID string) (*mediatorservice.Config, error)
 }
 
+// options contains optional configuration of DID client command.
+type options struct {
+	anchoredCacheTTL        time.Duration
+	unanchoredCacheTTL      time.Duration
+	operationInitialBackoff time.Duration
+	operationMaxBackoff     time.Duration
+	operationMaxAttempts    int
+	orbDomains              []OrbDomain
+}
+
+// Option configures DID client command.
+type Option func(opts *options)
+
+// WithResolutionCacheTTL sets how long resolutions of anchored and unanchored DIDs are cached. Zero TTL keeps
+// the default one and caching is disabled for negative TTL.
+func WithResolutionCacheTTL(anchored, unanchored time.Duration) Option {
+	return func(opts *options) {
+		if anchored != 0 {
+			opts.anchoredCacheTTL = anchored
+		}
+
+		if unanchored != 0 {
+			opts.unanchoredCacheTTL = unanchored
+		}
+	}
+}
+
+// WithDIDOperationRetry sets backoff between submissions of queued orb DID operations and how many times
+// a submission is attempted before the operation fails. Zero values keep the defaults.
+func WithDIDOperationRetry(initialBackoff, maxBackoff time.Duration, maxAttempts int) Option {
+	return func(opts *options) {
+		if initialBackoff != 0 {
+			opts.operationInitialBackoff = initialBackoff
+		}
+
+		if maxBackoff != 0 {
+			opts.operationMaxBackoff = maxBackoff
+		}
+
+		if maxAttempts != 0 {
+			opts.operationMaxAttempts = maxAttempts
+		}
+	}
+}
+
 // New returns new DID Exchange controller command instance.
-func New(domain, didAnchorOrigin, token string, unanchoredDIDMaxLifeTime int, p Provider) (*Command, error) {
-	orbOpts := make([]orb.Option, 0)
+func New(domain, didAnchorOrigin, token string, unanchoredDIDMaxLifeTime int, p Provider,
+	notifier ariescmd.Notifier, opts ...Option) (*Command, error) {
+	cmdOpts := &options{
+		anchoredCacheTTL:        defaultAnchoredCacheTTL,
+		unanchoredCacheTTL:      defaultUnanchoredCacheTTL,
+		operationInitialBackoff: defaultOperationInitialBackoff,
+		operationMaxBackoff:     defaultOperationMaxBackoff,
+		operationMaxAttempts:    defaultOperationMaxAttempts,
+	}
 
-	if unanchoredDIDMaxLifeTime > 0 {
-		orbOpts = append(orbOpts, orb.WithUnanchoredMaxLifeTime(time.Duration(unanchoredDIDMaxLifeTime)*time.Second))
+	for _, opt := range opts {
+		opt(cmdOpts)
+	}
+
+	keyRetriever := newKeyRetriever()
+
+	// domain given to New comes first among domains of the same priority.
+	domains := make([]OrbDomain, 0, len(cmdOpts.orbDomains)+1)
+	if domain != "" || len(cmdOpts.orbDomains) == 0 {
+		domains = append(domains, OrbDomain{URL: domain, Token: token, AnchorOrigin: didAnchorOrigin})
 	}
 
-	orbOpts = append(orbOpts, orb.WithDomain(domain), orb.WithAuthToken(token), orb.WithHTTPClient(http.DefaultClient))
+	for _, d := range cmdOpts.orbDomains {
+		if d.AnchorOrigin == "" {
+			d.AnchorOrigin = didAnchorOrigin
+		}
+
+		domains = append(domains, d)
+	}
+
+	orbDomains, err := newOrbDomainPool(domains, func(d *OrbDomain) (didBlocClient, error) {
+		orbOpts := make([]orb.Option, 0)
 
-	client, err := orb.New(nil, orbOpts...)
+		if unanchoredDIDMaxLifeTime > 0 {
+			orbOpts = append(orbOpts,
+				orb.WithUnanchoredMaxLifeTime(time.Duration(unanchoredDIDMaxLifeTime)*time.Second))
+		}
+
+		orbOpts = append(orbOpts, orb.WithDomain(d.URL), orb.WithAuthToken(d.Token),
+			orb.WithHTTPClient(http.DefaultClient))
+
+		return orb.New(keyRetriever, orbOpts...)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -144,34 +374,129 @@ func New(domain, didAnchorOrigin, token string, unanchoredDIDMaxLifeTime int, p
 		return nil, errors.New("cast service to route service failed")
 	}
 
-	return &Command{
-		didBlocClient:   client,
-		domain:          domain,
-		vdrRegistry:     p.VDRegistry(),
-		mediatorClient:  mClient,
-		mediatorSvc:     mediatorSvc,
-		keyManager:      p.KMS(),
-		didAnchorOrigin: didAnchorOrigin,
-	}, nil
+	rotator, err := middleware.New(p)
+	if err != nil {
+		return nil, err
+	}
+
+	documentLoader, err := newDocumentLoader()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := p.StorageProvider().OpenStore(CommandName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Command{
+		didBlocClient:     orbDomains,
+		orbDomains:        orbDomains,
+		vdrRegistry:       p.VDRegistry(),
+		mediatorClient:    mClient,
+		mediatorSvc:       mediatorSvc,
+		keyManager:        p.KMS(),
+		crypto:            p.Crypto(),
+		keyRetriever:      keyRetriever,
+		routeProvider:     p,
+		didRotator:        rotator,
+		documentLoader:    documentLoader,
+		httpClient:        http.DefaultClient,
+		managedKeys:       &managedKeyStore{store: store},
+		kmsKeys:           newKMSKeyStore(p.KMS(), store),
+		resolutionCache:   newResolutionCache(store, cmdOpts.anchoredCacheTTL, cmdOpts.unanchoredCacheTTL),
+		didAuthChallenges: &didAuthChallengeStore{store: store},
+	}
+
+	c.publicationTracker = newPublicationTracker(func(didID string) (*did.DocResolution, error) {
+		return c.didBlocClient.Read(didID)
+	}, store, notifier)
+
+	// orb DIDs not anchored within max lifetime of unanchored DIDs aren't expected to get anchored.
+	if unanchoredDIDMaxLifeTime > 0 {
+		c.publicationTracker.maxLifetime = time.Duration(unanchoredDIDMaxLifeTime) * time.Second
+	}
+
+	err = c.publicationTracker.resume()
+	if err != nil {
+		return nil, err
+	}
+
+	c.operationQueue = newDIDOperationQueue(c.submitOrbOperation, func(op *PendingDIDOperation) {
+		if e := c.publicationTracker.track(op.DID); e != nil {
+			logger.Warnf("failed to track publication of orb DID %s : %s", op.DID, e)
+		}
+	}, store, notifier)
+	c.operationQueue.initialBackoff = cmdOpts.operationInitialBackoff
+	c.operationQueue.maxBackoff = cmdOpts.operationMaxBackoff
+	c.operationQueue.maxAttempts = cmdOpts.operationMaxAttempts
+
+	err = c.operationQueue.resume()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
 
 // Command is controller command for DID Exchange.
 type Command struct {
-	didBlocClient   didBlocClient
-	domain          string
-	vdrRegistry     vdr.Registry
-	mediatorClient  mediatorClient
-	mediatorSvc     mediatorservice.ProtocolService
-	keyManager      kms.KeyManager
-	didAnchorOrigin string
+	didBlocClient      didBlocClient
+	orbDomains         *orbDomainPool
+	vdrRegistry        vdr.Registry
+	mediatorClient     mediatorClient
+	mediatorSvc        mediatorservice.ProtocolService
+	keyManager         kms.KeyManager
+	crypto             crypto.Crypto
+	keyRetriever       *keyRetriever
+	routeProvider      routeutil.Provider
+	didRotator         didRotator
+	documentLoader     *ld.DocumentLoader
+	httpClient         httpClient
+	managedKeys        *managedKeyStore
+	kmsKeys            *kmsKeyStore
+	publicationTracker *publicationTracker
+	operationQueue     *didOperationQueue
+	resolutionCache    *resolutionCache
+	didAuthChallenges  *didAuthChallengeStore
+}
+
+// Close stops submitting queued orb DID operations and tracking publication of orb DIDs. Queued operations and
+// pending DIDs are resumed by the next command created on the same store.
+func (c *Command) Close() {
+	c.operationQueue.close()
+	c.publicationTracker.close()
 }
 
 // GetHandlers returns list of all commands supported by this controller command.
 func (c *Command) GetHandlers() []command.Handler {
 	return []command.Handler{
 		cmdutil.NewCommandHandler(CommandName, CreateOrbDIDCommandMethod, c.CreateOrbDID),
+		cmdutil.NewCommandHandler(CommandName, ValidateOrbDIDRequestCommandMethod, c.ValidateOrbDIDRequest),
 		cmdutil.NewCommandHandler(CommandName, CreatePeerDIDCommandMethod, c.CreatePeerDID),
 		cmdutil.NewCommandHandler(CommandName, ResolveOrbDIDCommandMethod, c.ResolveOrbDID),
+		cmdutil.NewCommandHandler(CommandName, UpdateOrbDIDCommandMethod, c.UpdateOrbDID),
+		cmdutil.NewCommandHandler(CommandName, RecoverOrbDIDCommandMethod, c.RecoverOrbDID),
+		cmdutil.NewCommandHandler(CommandName, DeactivateOrbDIDCommandMethod, c.DeactivateOrbDID),
+		cmdutil.NewCommandHandler(CommandName, ResolveDIDCommandMethod, c.ResolveDID),
+		cmdutil.NewCommandHandler(CommandName, GetOrbDIDStatusCommandMethod, c.GetOrbDIDStatus),
+		cmdutil.NewCommandHandler(CommandName, GetOrbDIDHistoryCommandMethod, c.GetOrbDIDHistory),
+		cmdutil.NewCommandHandler(CommandName, ListPendingDIDOperationsCommandMethod, c.ListPendingDIDOperations),
+		cmdutil.NewCommandHandler(CommandName, CancelPendingDIDOperationCommandMethod, c.CancelPendingDIDOperation),
+		cmdutil.NewCommandHandler(CommandName, CreateDIDCommandMethod, c.CreateDID),
+		cmdutil.NewCommandHandler(CommandName, CreateDIDBatchCommandMethod, c.CreateDIDBatch),
+		cmdutil.NewCommandHandler(CommandName, SignJWTCommandMethod, c.SignJWT),
+		cmdutil.NewCommandHandler(CommandName, VerifyJWTCommandMethod, c.VerifyJWT),
+		cmdutil.NewCommandHandler(CommandName, SignJWSCommandMethod, c.SignJWS),
+		cmdutil.NewCommandHandler(CommandName, VerifyJWSCommandMethod, c.VerifyJWS),
+		cmdutil.NewCommandHandler(CommandName, CreateDIDAuthChallengeCommandMethod, c.CreateDIDAuthChallenge),
+		cmdutil.NewCommandHandler(CommandName, RespondDIDAuthChallengeCommandMethod, c.RespondDIDAuthChallenge),
+		cmdutil.NewCommandHandler(CommandName, VerifyDIDAuthResponseCommandMethod, c.VerifyDIDAuthResponse),
+		cmdutil.NewCommandHandler(CommandName, InvalidateDIDCacheCommandMethod, c.InvalidateDIDCache),
+		cmdutil.NewCommandHandler(CommandName, ListCachedDIDsCommandMethod, c.ListCachedDIDs),
+		cmdutil.NewCommandHandler(CommandName, RotatePeerDIDKeysCommandMethod, c.RotatePeerDIDKeys),
+		cmdutil.NewCommandHandler(CommandName, CreateDIDConfigurationCommandMethod, c.CreateDIDConfiguration),
+		cmdutil.NewCommandHandler(CommandName, VerifyDIDConfigurationCommandMethod, c.VerifyDIDConfiguration),
 	}
 }
 
@@ -186,7 +511,17 @@ func (c *Command) ResolveOrbDID(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	docResolution, errRead := c.didBlocClient.Read(request.DID)
+	opts, err := versionOptions(request.VersionID, request.VersionTime)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ResolveOrbDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	docResolution, errRead := c.cachedResolve(request.DID, request.VersionID, request.VersionTime, false,
+		func() (*did.DocResolution, error) {
+			return c.didBlocClient.Read(request.DID, opts...)
+		})
 	if errRead != nil {
 		logutil.LogError(logger, CommandName, ResolveOrbDIDCommandMethod, errRead.Error())
 
@@ -209,298 +544,1154 @@ func (c *Command) ResolveOrbDID(rw io.Writer, req io.Reader) command.Error {
 	return nil
 }
 
-// CreateOrbDID creates a new orb DID.
-func (c *Command) CreateOrbDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen,gocyclo,gocognit
-	var request CreateOrbDIDRequest
+// CreateDID creates a new did:key, did:jwk or did:web DID with keys created in the agent KMS. Document of
+// did:web DID is returned along with the URL at which it has to be published.
+func (c *Command) CreateDID(rw io.Writer, req io.Reader) command.Error {
+	var request CreateDIDRequest
 
 	err := json.NewDecoder(req).Decode(&request)
 	if err != nil {
-		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+		logutil.LogError(logger, CommandName, CreateDIDCommandMethod, err.Error())
 
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	didDoc := did.Doc{}
+	err = validateCreateDIDRequest(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDCommandMethod, err.Error())
 
-	didcommServicetype := didCommV2ServiceType
-	if request.DIDcommServiceType != "" {
-		didcommServicetype = request.DIDcommServiceType
+		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	serviceID := "sidetree"
-	if request.ServiceID != "" {
-		serviceID = request.ServiceID
-	}
+	resp, err := c.createDID(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDCommandMethod, err.Error())
 
-	serviceEndpoint := "https://testnet.orb.local"
-	if request.ServiceEndpoint != "" {
-		serviceEndpoint = request.ServiceEndpoint
+		return command.NewExecuteError(CreateDIDErrorCode, err)
 	}
 
-	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("request.RoutersKeyAgrIDS: %+v",
-		request.RoutersKeyAgrIDS))
+	command.WriteNillableResponse(rw, resp, logger)
 
-	var routerKeys []string
-	routerKeys = append(routerKeys, request.RoutersKeyAgrIDS...)
+	logutil.LogDebug(logger, CommandName, CreateDIDCommandMethod, successString)
 
-	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("routerKeys: %+v", routerKeys))
+	return nil
+}
 
-	didDoc.Service = []did.Service{{
-		ID:   serviceID,
-		Type: didcommServicetype,
-		ServiceEndpoint: model.NewDIDCommV2Endpoint(
-			[]model.DIDCommV2Endpoint{{RoutingKeys: routerKeys, URI: serviceEndpoint}}),
-	}}
+// InvalidateDIDCache removes cached resolutions of all versions of DID, or the whole DID resolution cache
+// if no DID is given.
+func (c *Command) InvalidateDIDCache(rw io.Writer, req io.Reader) command.Error {
+	var request InvalidateDIDCacheRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, InvalidateDIDCacheCommandMethod, err.Error())
 
-	if didDoc.Service[0].Type == didCommServiceType {
-		didDoc.Service[0].ServiceEndpoint = model.NewDIDCommV1Endpoint(serviceEndpoint)
-		didDoc.Service[0].RoutingKeys = routerKeys
+		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	var didMethodOpt []vdr.DIDMethodOption
+	err = c.resolutionCache.invalidate(request.DID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, InvalidateDIDCacheCommandMethod, err.Error())
 
-	for _, v := range request.PublicKeys {
-		value, decodeErr := base64.RawURLEncoding.DecodeString(v.Value)
-		if decodeErr != nil {
-			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, decodeErr.Error())
+		return command.NewExecuteError(DIDCacheErrorCode, err)
+	}
 
-			return command.NewExecuteError(CreateDIDErrorCode, decodeErr)
-		}
+	command.WriteNillableResponse(rw, nil, logger)
 
-		k, errGet := getKey(v.KeyType, value)
-		if errGet != nil {
-			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errGet.Error())
+	logutil.LogDebug(logger, CommandName, InvalidateDIDCacheCommandMethod, successString)
 
-			return command.NewExecuteError(CreateDIDErrorCode, errGet)
-		}
+	return nil
+}
 
-		if v.Recovery {
-			didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.RecoveryPublicKeyOpt, k))
+// ListCachedDIDs lists DIDs whose resolutions are cached.
+func (c *Command) ListCachedDIDs(rw io.Writer, _ io.Reader) command.Error {
+	cached, err := c.resolutionCache.list()
+	if err != nil {
+		logutil.LogError(logger, CommandName, ListCachedDIDsCommandMethod, err.Error())
 
-			continue
-		}
+		return command.NewExecuteError(DIDCacheErrorCode, err)
+	}
 
-		if v.Update {
-			didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.UpdatePublicKeyOpt, k))
+	command.WriteNillableResponse(rw, &ListCachedDIDsResponse{DIDs: cached}, logger)
 
-			continue
-		}
+	logutil.LogDebug(logger, CommandName, ListCachedDIDsCommandMethod, successString)
 
-		var (
-			jwk    *jwk2.JWK
-			errJWK error
-		)
+	return nil
+}
 
-		//nolint:gocritic,nestif
-		if strings.EqualFold(v.KeyType, x25519ECDHKW) {
-			jwk, errJWK = jwksupport.JWKFromX25519Key(k.(*crypto.PublicKey).X)
-			if errJWK != nil {
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errJWK.Error())
+// ResolveDID resolves DID of any method supported by the VDR registry. Resolution failures are reported
+// through the error of the DID resolution metadata.
+func (c *Command) ResolveDID(rw io.Writer, req io.Reader) command.Error {
+	var request ResolveDIDRequest
 
-				return command.NewExecuteError(CreateDIDErrorCode, errJWK)
-			}
-		} else if strings.EqualFold(v.KeyType, p256ecdhkw) || strings.EqualFold(v.KeyType, p384ecdhkw) ||
-			strings.EqualFold(v.KeyType, p521ecdhkw) {
-			pubKey, ok := k.(*crypto.PublicKey)
-			if !ok {
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod,
-					fmt.Sprintf("key '%+v' is not NIST P ECDH KW type", k))
-
-				return command.NewExecuteError(CreateDIDErrorCode, fmt.Errorf("key '%+v' is not NIST P ECDH KW type", k))
-			}
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ResolveDIDCommandMethod, err.Error())
 
-			ecdsaKey := &ecdsa.PublicKey{
-				X:     new(big.Int).SetBytes(pubKey.X),
-				Y:     new(big.Int).SetBytes(pubKey.Y),
-				Curve: getCurve(pubKey.Curve),
-			}
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
 
-			jwk, errJWK = jwksupport.JWKFromKey(ecdsaKey)
-			if errJWK != nil {
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errJWK.Error())
+	if request.DID == "" {
+		logutil.LogError(logger, CommandName, ResolveDIDCommandMethod, errInvalidDID)
 
-				return command.NewExecuteError(CreateDIDErrorCode, fmt.Errorf("JWKFromKey() jwk: %+v, ecdsa key: "+
-					"%+v, error: %w", jwk, ecdsaKey, errJWK))
-			}
-		} else {
-			jwk, errJWK = jwksupport.JWKFromKey(k)
-			if errJWK != nil {
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errJWK.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
 
-				return command.NewExecuteError(CreateDIDErrorCode, errJWK)
-			}
-		}
+	response := c.resolveDID(&request)
 
-		vm, errVM := did.NewVerificationMethodFromJWK(v.ID, v.Type, "", jwk)
-		if errVM != nil {
-			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errVM.Error())
-
-			return command.NewExecuteError(CreateDIDErrorCode, errVM)
-		}
-
-		for _, p := range v.Purposes {
-			switch p {
-			case doc.KeyPurposeAuthentication:
-				didDoc.Authentication = append(didDoc.Authentication,
-					*did.NewReferencedVerification(vm, did.Authentication))
-			case doc.KeyPurposeAssertionMethod:
-				didDoc.AssertionMethod = append(didDoc.AssertionMethod,
-					*did.NewReferencedVerification(vm, did.AssertionMethod))
-			case doc.KeyPurposeKeyAgreement:
-				didDoc.KeyAgreement = append(didDoc.KeyAgreement,
-					*did.NewReferencedVerification(vm, did.KeyAgreement))
-			case doc.KeyPurposeCapabilityDelegation:
-				didDoc.CapabilityDelegation = append(didDoc.CapabilityDelegation,
-					*did.NewReferencedVerification(vm, did.CapabilityDelegation))
-			case doc.KeyPurposeCapabilityInvocation:
-				didDoc.CapabilityInvocation = append(didDoc.CapabilityInvocation,
-					*did.NewReferencedVerification(vm, did.CapabilityInvocation))
-			default:
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod,
-					fmt.Sprintf("public key purpose %s not supported", p))
-
-				return command.NewExecuteError(CreateDIDErrorCode,
-					fmt.Errorf("public key purpose %s not supported", p))
-			}
-		}
+	if response.DIDResolutionMetadata.Error != "" {
+		logutil.LogError(logger, CommandName, ResolveDIDCommandMethod, response.DIDResolutionMetadata.ErrorMessage)
 	}
 
-	didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.AnchorOriginOpt, c.didAnchorOrigin))
+	command.WriteNillableResponse(rw, response, logger)
 
-	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("about to create DID Doc, "+
-		"keyAgreements: %+v", didDoc.KeyAgreement))
+	logutil.LogDebug(logger, CommandName, ResolveDIDCommandMethod, successString)
 
-	docResolution, err := c.didBlocClient.Create(&didDoc, didMethodOpt...)
-	if err != nil {
-		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+	return nil
+}
 
-		return command.NewExecuteError(CreateDIDErrorCode, err)
+func (c *Command) resolveDID(request *ResolveDIDRequest) *ResolveDIDResponse {
+	contentType := request.Accept
+	if contentType == "" {
+		contentType = didLDJSONMediaType
 	}
 
-	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("ORB DID Doc crated: %+v",
-		docResolution.DIDDocument))
+	response := &ResolveDIDResponse{DIDResolutionMetadata: &DIDResolutionMetadata{ContentType: contentType}}
 
-	// add all keyAgreements to router connections
-	for _, val := range docResolution.DIDDocument.KeyAgreement {
-		for _, rConn := range request.RouterConnections {
-			err = mediatorservice.AddKeyToRouter(c.mediatorSvc, rConn, val.VerificationMethod.ID)
-			if err != nil {
-				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+	resolutionError := func(code string, err error) *ResolveDIDResponse {
+		response.DIDResolutionMetadata.Error = code
+		response.DIDResolutionMetadata.ErrorMessage = err.Error()
 
-				return command.NewExecuteError(CreateDIDErrorCode, fmt.Errorf(errFailedToRegisterDIDRecKey+
-					" for KeyAgreement ID %v, connection: %v", err, val.VerificationMethod.ID, rConn))
-			}
+		return response
+	}
 
-			logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("added keyAgreements ID %v"+
-				" to router connection: %+v", val.VerificationMethod.ID, rConn))
-		}
+	if contentType != didLDJSONMediaType && contentType != didJSONMediaType {
+		return resolutionError(resolutionErrRepresentationUnsupported,
+			fmt.Errorf("representation %s not supported", contentType))
 	}
 
-	bytes, err := docResolution.JSONBytes()
+	parsedDID, err := did.Parse(request.DID)
 	if err != nil {
-		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+		return resolutionError(resolutionErrInvalidDID, err)
+	}
 
-		return command.NewExecuteError(CreateDIDErrorCode, err)
+	// only orb DIDs are resolved at a version, versions of other DIDs would be ignored by their VDRs
+	if parsedDID.Method != orb.DIDMethod && (request.VersionID != "" || request.VersionTime != "") {
+		return resolutionError(resolutionErrInvalidOptions,
+			fmt.Errorf("resolution at a version not supported for did method %s", parsedDID.Method))
 	}
 
-	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, successString)
+	opts, err := versionOptions(request.VersionID, request.VersionTime)
+	if err != nil {
+		return resolutionError(resolutionErrInvalidOptions, err)
+	}
 
-	if _, err := rw.Write(bytes); err != nil {
-		logger.Errorf(err.Error())
+	docResolution, err := c.cachedResolve(request.DID, request.VersionID, request.VersionTime, request.NoCache,
+		func() (*did.DocResolution, error) {
+			return c.resolveWithRegistry(request.DID, parsedDID.Method, opts...)
+		})
+
+	switch {
+	case errors.Is(err, vdr.ErrNotFound):
+		return resolutionError(resolutionErrNotFound, err)
+	case errors.Is(err, errDIDMethodNotSupported):
+		return resolutionError(resolutionErrMethodNotSupported, err)
+	case err != nil:
+		return resolutionError(resolutionErrInternal, err)
 	}
 
-	return nil
+	docBytes, err := docRepresentation(docResolution.DIDDocument, contentType)
+	if err != nil {
+		return resolutionError(resolutionErrInternal, err)
+	}
+
+	response.Context = docResolution.Context
+	response.DIDDocument = docBytes
+	response.DIDDocumentMetadata = docResolution.DocumentMetadata
+
+	return response
 }
 
-func getCurve(crv string) elliptic.Curve {
-	c := elliptic.P256()
+// resolveWithRegistry resolves DID with the VDR registry. The registry doesn't export an error for DID methods
+// none of its VDRs accepts, so its error for the method of the DID is reported as errDIDMethodNotSupported.
+func (c *Command) resolveWithRegistry(didID, method string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	docResolution, err := c.vdrRegistry.Resolve(didID, opts...)
+	if err != nil && err.Error() == fmt.Sprintf("did method %s not supported for vdr", method) {
+		return nil, fmt.Errorf("%w : %s", errDIDMethodNotSupported, method)
+	}
 
-	switch crv {
-	case "NIST_P256", "P-256":
-	case "NIST_P384", "P-384":
-		c = elliptic.P384()
-	case "NIST_P521", "P-521":
-		c = elliptic.P521()
+	return docResolution, err
+}
+
+// docRepresentation returns DID document in the representation of given media type.
+func docRepresentation(didDoc *did.Doc, contentType string) (json.RawMessage, error) {
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID document : %w", err)
 	}
 
-	return c
+	if contentType == didLDJSONMediaType {
+		return docBytes, nil
+	}
+
+	// plain JSON representation doesn't carry JSON-LD context
+	rawDoc := map[string]json.RawMessage{}
+
+	err = json.Unmarshal(docBytes, &rawDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DID document : %w", err)
+	}
+
+	delete(rawDoc, "@context")
+
+	return json.Marshal(rawDoc)
 }
 
-func getKey(keyType string, value []byte) (interface{}, error) {
-	switch strings.ToLower(keyType) {
-	case ed25519KeyType:
-		return ed25519.PublicKey(value), nil
-	case p256KeyType:
-		x, y := elliptic.Unmarshal(elliptic.P256(), value)
+// GetOrbDIDStatus returns publication status of orb DID along with its canonical and equivalent IDs
+// once it is anchored.
+func (c *Command) GetOrbDIDStatus(rw io.Writer, req io.Reader) command.Error {
+	var request GetOrbDIDStatusRequest
 
-		return &ecdsa.PublicKey{X: x, Y: y, Curve: elliptic.P256()}, nil
-	case p384KeyType:
-		x, y := elliptic.Unmarshal(elliptic.P384(), value)
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetOrbDIDStatusCommandMethod, err.Error())
 
-		return &ecdsa.PublicKey{X: x, Y: y, Curve: elliptic.P384()}, nil
-	case BLS12381G2KeyType:
-		return bbs12381g2pub.UnmarshalPublicKey(value)
-	case x25519ECDHKW, p256ecdhkw, p384ecdhkw, p521ecdhkw:
-		pubKey := &crypto.PublicKey{}
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
 
-		err := json.Unmarshal(value, pubKey)
+	if request.DID == "" {
+		logutil.LogError(logger, CommandName, GetOrbDIDStatusCommandMethod, errInvalidDID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
+
+	status, err := c.publicationTracker.get(request.DID)
+	if errors.Is(err, errOrbDIDStatusNotFound) {
+		// DID wasn't created by this agent, resolve it to find out
+		var docResolution *did.DocResolution
+
+		docResolution, err = c.didBlocClient.Read(request.DID)
 		if err != nil {
-			return nil, fmt.Errorf("unmarshal key type: %s, value: %s failed: %w", keyType, value, err)
+			err = fmt.Errorf("failed to resolve DID %s : %w", request.DID, err)
 		}
 
-		return pubKey, nil
-	default:
-		return nil, fmt.Errorf("invalid key type: %s", keyType)
+		status = statusFromResolution(request.DID, docResolution)
 	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetOrbDIDStatusCommandMethod, err.Error())
+
+		return command.NewExecuteError(GetOrbDIDStatusErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, status, logger)
+
+	logutil.LogDebug(logger, CommandName, GetOrbDIDStatusCommandMethod, successString)
+
+	return nil
 }
 
-// CreatePeerDID creates a new peer DID.
-func (c *Command) CreatePeerDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen,gocyclo
-	var request CreatePeerDIDRequest
+// CreateOrbDID creates a new orb DID. In queued mode the long-form DID is returned right away while the create
+// operation is submitted in background until Orb accepts it.
+func (c *Command) CreateOrbDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen
+	var request CreateOrbDIDRequest
 
 	err := json.NewDecoder(req).Decode(&request)
 	if err != nil {
-		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
 
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	if request.RouterConnectionID == "" {
-		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, errInvalidRouterConnectionID)
+	if request.DryRun {
+		command.WriteNillableResponse(rw, c.validateOrbDID(&request), logger)
 
-		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidRouterConnectionID))
+		logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, successString)
+
+		return nil
 	}
 
-	config, err := c.mediatorClient.GetConfig(request.RouterConnectionID)
-	if err != nil {
-		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+	didDoc, didMethodOpt, keys, cmdErr := c.newOrbDIDDoc(&request, c.keyManager, c.kmsKeys)
+	if cmdErr != nil {
+		return cmdErr
+	}
 
-		return command.NewExecuteError(CreateDIDErrorCode, err)
+	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("about to create DID Doc, "+
+		"keyAgreements: %+v", didDoc.KeyAgreement))
+
+	var docResolution *did.DocResolution
+
+	if request.Queued {
+		docResolution, err = c.queueOrbDIDCreation(didDoc, didMethodOpt)
+	} else {
+		docResolution, err = c.didBlocClient.Create(didDoc, didMethodOpt...)
 	}
 
-	// TODO - key type should be configurable
-	keyID, keyBytes, err := c.keyManager.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
-		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+		// keys of DID which failed to get created are of no use.
+		c.kmsKeys.delete(keys.keyIDs()...)
 
 		return command.NewExecuteError(CreateDIDErrorCode, err)
 	}
 
-	docResolution, err := c.vdrRegistry.Create(
-		peer.DIDMethod,
-		&did.Doc{
-			Service: []did.Service{{
-				ServiceEndpoint: model.NewDIDCommV2Endpoint(
-					[]model.DIDCommV2Endpoint{{URI: config.Endpoint(), RoutingKeys: config.Keys()}}),
-			}},
-			VerificationMethod: []did.VerificationMethod{*did.NewVerificationMethodFromBytes(
-				"#"+keyID,
-				"Ed25519VerificationKey2018",
-				"",
-				keyBytes,
-			)},
-		},
-	)
+	didID := docResolution.DIDDocument.ID
+	if request.Queued {
+		if docResolution.DocumentMetadata == nil || len(docResolution.DocumentMetadata.EquivalentID) == 0 {
+			err = fmt.Errorf("short-form DID missing in metadata of queued DID %s", didID)
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(CreateDIDErrorCode, err)
+		}
+
+		// keys are kept under short-form DID used by later operations on the DID.
+		didID = docResolution.DocumentMetadata.EquivalentID[0]
+	}
+
+	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("ORB DID Doc crated: %+v",
+		docResolution.DIDDocument))
+
+	if keys != nil {
+		keys.DID = didID
+
+		err = c.managedKeys.put(keys)
+		if err != nil {
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(CreateDIDErrorCode, err)
+		}
+	}
+
+	// publication of queued DID is tracked once its create operation is submitted.
+	if !request.Queued && statusFromResolution(didID, docResolution).Status == orbDIDStatusPending {
+		err = c.publicationTracker.track(docResolution.DIDDocument.ID)
+		if err != nil {
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(CreateDIDErrorCode, err)
+		}
+	}
+
+	// add all keyAgreements to router connections
+	for _, val := range docResolution.DIDDocument.KeyAgreement {
+		for _, rConn := range request.RouterConnections {
+			err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, rConn, val.VerificationMethod.ID)
+			if err != nil {
+				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+				return command.NewExecuteError(CreateDIDErrorCode, fmt.Errorf(errFailedToRegisterDIDRecKey+
+					" for KeyAgreement ID %v, connection: %v", err, val.VerificationMethod.ID, rConn))
+			}
+
+			logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("added keyAgreements ID %v"+
+				" to router connection: %+v", val.VerificationMethod.ID, rConn))
+		}
+	}
+
+	bytes, err := docResolution.JSONBytes()
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(CreateDIDErrorCode, err)
+	}
+
+	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, successString)
+
+	if _, err := rw.Write(bytes); err != nil {
+		logger.Errorf(err.Error())
+	}
+
+	return nil
+}
+
+// newOrbDIDDoc builds DID document and create options of orb DID from the request, keys managed by the agent
+// are created in the given KMS and deleted through the given KMS key store if the document can't be built.
+func (c *Command) newOrbDIDDoc(request *CreateOrbDIDRequest, keyManager kms.KeyManager, // nolint: funlen
+	kmsKeys *kmsKeyStore) (*did.Doc, []vdr.DIDMethodOption, *managedKeys, command.Error) {
+	didDoc := &did.Doc{}
+
+	didcommServicetype := didCommV2ServiceType
+	if request.DIDcommServiceType != "" {
+		didcommServicetype = request.DIDcommServiceType
+	}
+
+	serviceID := "sidetree"
+	if request.ServiceID != "" {
+		serviceID = request.ServiceID
+	}
+
+	serviceEndpoint := "https://testnet.orb.local"
+	if request.ServiceEndpoint != "" {
+		serviceEndpoint = request.ServiceEndpoint
+	}
+
+	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("request.RoutersKeyAgrIDS: %+v",
+		request.RoutersKeyAgrIDS))
+
+	var routerKeys []string
+	routerKeys = append(routerKeys, request.RoutersKeyAgrIDS...)
+
+	logutil.LogDebug(logger, CommandName, CreateOrbDIDCommandMethod, fmt.Sprintf("routerKeys: %+v", routerKeys))
+
+	didDoc.Service = []did.Service{newService(serviceID, didcommServicetype, serviceEndpoint, routerKeys)}
+
+	var (
+		didMethodOpt []vdr.DIDMethodOption
+		keys         *managedKeys
+	)
+
+	if request.ManagedKeys {
+		for _, v := range request.PublicKeys {
+			if v.Recovery || v.Update {
+				logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errManagedUpdateRecoveryKeys)
+
+				return nil, nil, nil, command.NewValidationError(InvalidRequestErrorCode,
+					fmt.Errorf(errManagedUpdateRecoveryKeys))
+			}
+		}
+
+		var errKeys error
+
+		keys, didMethodOpt, errKeys = createManagedKeys(keyManager, kmsKeys, request)
+		if errKeys != nil {
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errKeys.Error())
+
+			return nil, nil, nil, command.NewExecuteError(CreateDIDErrorCode, errKeys)
+		}
+	}
+
+	for i := range request.PublicKeys {
+		v := &request.PublicKeys[i]
+
+		k, errKey := parsePublicKey(v)
+		if errKey != nil {
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errKey.Error())
+			kmsKeys.delete(keys.keyIDs()...)
+
+			return nil, nil, nil, command.NewExecuteError(CreateDIDErrorCode, errKey)
+		}
+
+		if v.Recovery {
+			didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.RecoveryPublicKeyOpt, k))
+
+			continue
+		}
+
+		if v.Update {
+			didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.UpdatePublicKeyOpt, k))
+
+			continue
+		}
+
+		errAdd := addVerificationMethod(didDoc, v, k)
+		if errAdd != nil {
+			logutil.LogError(logger, CommandName, CreateOrbDIDCommandMethod, errAdd.Error())
+			kmsKeys.delete(keys.keyIDs()...)
+
+			return nil, nil, nil, command.NewExecuteError(CreateDIDErrorCode, errAdd)
+		}
+	}
+
+	if request.AnchorOrigin != "" {
+		didMethodOpt = append(didMethodOpt, vdr.WithOption(orb.AnchorOriginOpt, request.AnchorOrigin))
+	}
+
+	if request.Domain != "" {
+		didMethodOpt = append(didMethodOpt, vdr.WithOption(orbDomainOpt, request.Domain))
+	}
+
+	return didDoc, didMethodOpt, keys, nil
+}
+
+// UpdateOrbDID updates orb DID keys and services.
+func (c *Command) UpdateOrbDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen
+	var request UpdateOrbDIDRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.DID == "" {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, errInvalidDID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
+
+	// update key ID and next update key are omitted for DIDs whose keys are managed by the agent
+	if request.UpdateKeyID == "" && request.NextUpdateKey != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, errInvalidUpdateKeyID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidUpdateKeyID))
+	}
+
+	if request.UpdateKeyID != "" && request.NextUpdateKey == nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, errInvalidNextUpdateKey)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidNextUpdateKey))
+	}
+
+	docResolution, err := c.didBlocClient.Read(request.DID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, fmt.Errorf("failed to resolve DID %s : %w", request.DID, err))
+	}
+
+	didDoc, addedKeys, err := updatedDoc(request.DID, docResolution.DIDDocument, &request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, err)
+	}
+
+	opKeys, managedOp, err := c.updateOperationKeys(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, err)
+	}
+
+	err = c.keyRetriever.add(request.DID, opKeys)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, err)
+	}
+
+	defer c.keyRetriever.remove(request.DID)
+
+	err = c.didBlocClient.Update(didDoc)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, err)
+	}
+
+	c.invalidateDIDCache(request.DID)
+
+	if managedOp != nil {
+		err = c.completeManagedOperation(managedOp)
+		if err != nil {
+			logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(UpdateDIDErrorCode, err)
+		}
+	}
+
+	// remove dropped keyAgreements from router connections
+	err = c.removeKeyAgreementsFromRouters(&did.Doc{
+		KeyAgreement: removedVerifications(docResolution.DIDDocument.KeyAgreement, didDoc.KeyAgreement),
+	}, request.RouterConnections)
+	if err != nil {
+		logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(UpdateDIDErrorCode, err)
+	}
+
+	// add new keyAgreements to router connections
+	for _, val := range didDoc.KeyAgreement {
+		if _, ok := addedKeys[val.VerificationMethod.ID]; !ok {
+			continue
+		}
+
+		for _, rConn := range request.RouterConnections {
+			err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, rConn, val.VerificationMethod.ID)
+			if err != nil {
+				logutil.LogError(logger, CommandName, UpdateOrbDIDCommandMethod, err.Error())
+
+				return command.NewExecuteError(UpdateDIDErrorCode, fmt.Errorf(errFailedToRegisterDIDRecKey+
+					" for KeyAgreement ID %v, connection: %v", err, val.VerificationMethod.ID, rConn))
+			}
+		}
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, UpdateOrbDIDCommandMethod, successString)
+
+	return nil
+}
+
+// RecoverOrbDID recovers orb DID by replacing the whole document using the recovery key.
+func (c *Command) RecoverOrbDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen,gocyclo
+	var request RecoverOrbDIDRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if errMsg := validateRecoverRequest(&request); errMsg != "" {
+		logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, errMsg)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errMsg))
+	}
+
+	didDoc := &did.Doc{ID: request.DID}
+
+	for _, svc := range request.Services {
+		didDoc.Service = append(didDoc.Service, newService(svc.ID, svc.Type, svc.ServiceEndpoint, svc.RoutingKeys))
+	}
+
+	for i := range request.PublicKeys {
+		v := &request.PublicKeys[i]
+
+		k, errKey := parsePublicKey(v)
+		if errKey != nil {
+			logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, errKey.Error())
+
+			return command.NewExecuteError(RecoverDIDErrorCode, errKey)
+		}
+
+		errAdd := addVerificationMethod(didDoc, v, k)
+		if errAdd != nil {
+			logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, errAdd.Error())
+
+			return command.NewExecuteError(RecoverDIDErrorCode, errAdd)
+		}
+	}
+
+	opKeys, managedOp, err := c.recoverOperationKeys(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(RecoverDIDErrorCode, err)
+	}
+
+	err = c.keyRetriever.add(request.DID, opKeys)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(RecoverDIDErrorCode, err)
+	}
+
+	defer c.keyRetriever.remove(request.DID)
+
+	err = c.didBlocClient.Update(didDoc, vdr.WithOption(orb.RecoverOpt, true))
+	if err != nil {
+		logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(RecoverDIDErrorCode, err)
+	}
+
+	c.invalidateDIDCache(request.DID)
+
+	if managedOp != nil {
+		err = c.completeManagedOperation(managedOp)
+		if err != nil {
+			logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(RecoverDIDErrorCode, err)
+		}
+	}
+
+	// add all keyAgreements to router connections
+	for _, val := range didDoc.KeyAgreement {
+		for _, rConn := range request.RouterConnections {
+			err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, rConn, val.VerificationMethod.ID)
+			if err != nil {
+				logutil.LogError(logger, CommandName, RecoverOrbDIDCommandMethod, err.Error())
+
+				return command.NewExecuteError(RecoverDIDErrorCode, fmt.Errorf(errFailedToRegisterDIDRecKey+
+					" for KeyAgreement ID %v, connection: %v", err, val.VerificationMethod.ID, rConn))
+			}
+		}
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, RecoverOrbDIDCommandMethod, successString)
+
+	return nil
+}
+
+func validateRecoverRequest(request *RecoverOrbDIDRequest) string {
+	switch {
+	case request.DID == "":
+		return errInvalidDID
+	case request.RecoveryKeyID == "" && request.NextUpdateKey == nil && request.NextRecoveryKey == nil:
+		// keys are managed by the agent
+		return ""
+	case request.RecoveryKeyID == "":
+		return errInvalidRecoveryKeyID
+	case request.NextUpdateKey == nil:
+		return errInvalidNextUpdateKey
+	case request.NextRecoveryKey == nil:
+		return errInvalidNextRecoveryKey
+	default:
+		return ""
+	}
+}
+
+// updateOperationKeys returns operation keys of the update request, or creates them from managed keys of the DID
+// if the request doesn't provide update key.
+func (c *Command) updateOperationKeys(request *UpdateOrbDIDRequest) (*operationKeys, *managedOperation, error) {
+	if request.UpdateKeyID == "" {
+		op, err := c.prepareManagedOperation(request.DID, true, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signer, err := newKMSSigner(c.keyManager, c.crypto, op.keys.UpdateKeyID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &operationKeys{signer: signer, nextUpdateKey: op.nextUpdateKey}, op, nil
+	}
+
+	nextUpdateKey, err := parsePublicKey(request.NextUpdateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, err := newKMSSigner(c.keyManager, c.crypto, request.UpdateKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &operationKeys{signer: signer, nextUpdateKey: nextUpdateKey}, nil, nil
+}
+
+// recoverOperationKeys returns operation keys of the recover request, or creates them from managed keys of the DID
+// if the request doesn't provide recovery key.
+func (c *Command) recoverOperationKeys(request *RecoverOrbDIDRequest) (*operationKeys, *managedOperation, error) {
+	if request.RecoveryKeyID == "" {
+		op, err := c.prepareManagedOperation(request.DID, true, true)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signer, err := newKMSSigner(c.keyManager, c.crypto, op.keys.RecoveryKeyID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &operationKeys{
+			signer:          signer,
+			nextUpdateKey:   op.nextUpdateKey,
+			nextRecoveryKey: op.nextRecoveryKey,
+		}, op, nil
+	}
+
+	nextUpdateKey, err := parsePublicKey(request.NextUpdateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextRecoveryKey, err := parsePublicKey(request.NextRecoveryKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, err := newKMSSigner(c.keyManager, c.crypto, request.RecoveryKeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &operationKeys{
+		signer:          signer,
+		nextUpdateKey:   nextUpdateKey,
+		nextRecoveryKey: nextRecoveryKey,
+	}, nil, nil
+}
+
+// DeactivateOrbDID deactivates orb DID and removes its keyAgreement keys from the router connections.
+func (c *Command) DeactivateOrbDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen
+	var request DeactivateOrbDIDRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.DID == "" {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, errInvalidDID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
+
+	docResolution, err := c.didBlocClient.Read(request.DID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(DeactivateDIDErrorCode,
+			fmt.Errorf("failed to resolve DID %s : %w", request.DID, err))
+	}
+
+	recoveryKeyID := request.RecoveryKeyID
+
+	// recovery key ID is omitted for DIDs whose keys are managed by the agent
+	var keys *managedKeys
+
+	if recoveryKeyID == "" {
+		keys, err = c.managedKeys.get(request.DID)
+		if err != nil {
+			logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(DeactivateDIDErrorCode, err)
+		}
+
+		recoveryKeyID = keys.RecoveryKeyID
+	}
+
+	signer, err := newKMSSigner(c.keyManager, c.crypto, recoveryKeyID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(DeactivateDIDErrorCode, err)
+	}
+
+	err = c.keyRetriever.add(request.DID, &operationKeys{signer: signer})
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(DeactivateDIDErrorCode, err)
+	}
+
+	defer c.keyRetriever.remove(request.DID)
+
+	err = c.didBlocClient.Deactivate(request.DID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(DeactivateDIDErrorCode, err)
+	}
+
+	c.invalidateDIDCache(request.DID)
+
+	if keys != nil {
+		err = c.managedKeys.delete(request.DID)
+		if err != nil {
+			logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+			return command.NewExecuteError(DeactivateDIDErrorCode, err)
+		}
+	}
+
+	err = c.removeKeyAgreementsFromRouters(docResolution.DIDDocument, request.RouterConnections)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeactivateOrbDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(DeactivateDIDErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, DeactivateOrbDIDCommandMethod, successString)
+
+	return nil
+}
+
+// removeKeyAgreementsFromRouters removes keyAgreement keys of the DID document from given router connections,
+// or from all registered router connections if none given.
+func (c *Command) removeKeyAgreementsFromRouters(didDoc *did.Doc, routerConnections []string) error {
+	var keys []string
+
+	for _, val := range didDoc.KeyAgreement {
+		keys = append(keys, val.VerificationMethod.ID)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if len(routerConnections) == 0 {
+		var err error
+
+		routerConnections, err = c.mediatorSvc.GetConnections()
+		if err != nil {
+			return fmt.Errorf("failed to get router connections : %w", err)
+		}
+	}
+
+	for _, rConn := range routerConnections {
+		err := routeutil.RemoveKeyFromRouter(c.routeProvider, rConn, keys...)
+		if err != nil {
+			return fmt.Errorf("failed to remove keyAgreements from router connection %s : %w", rConn, err)
+		}
+	}
+
+	return nil
+}
+
+// updatedDoc builds the desired state of the DID document by applying the changes in the request to
+// the current document. It also returns IDs of newly added verification methods.
+func updatedDoc(didID string, current *did.Doc, request *UpdateOrbDIDRequest) (*did.Doc, map[string]struct{}, error) {
+	removedKeys := make(map[string]struct{})
+	for _, id := range request.RemovePublicKeys {
+		removedKeys[fragment(id)] = struct{}{}
+	}
+
+	removedServices := make(map[string]struct{})
+	for _, id := range request.RemoveServices {
+		removedServices[fragment(id)] = struct{}{}
+	}
+
+	keep := func(ver []did.Verification) []did.Verification {
+		var result []did.Verification
+
+		for _, v := range ver {
+			if _, ok := removedKeys[fragment(v.VerificationMethod.ID)]; !ok {
+				result = append(result, v)
+			}
+		}
+
+		return result
+	}
+
+	didDoc := &did.Doc{
+		ID:                   didID,
+		Authentication:       keep(current.Authentication),
+		AssertionMethod:      keep(current.AssertionMethod),
+		CapabilityDelegation: keep(current.CapabilityDelegation),
+		CapabilityInvocation: keep(current.CapabilityInvocation),
+		KeyAgreement:         keep(current.KeyAgreement),
+	}
+
+	for _, svc := range current.Service {
+		if _, ok := removedServices[fragment(svc.ID)]; !ok {
+			didDoc.Service = append(didDoc.Service, svc)
+		}
+	}
+
+	for _, svc := range request.AddServices {
+		didDoc.Service = append(didDoc.Service, newService(svc.ID, svc.Type, svc.ServiceEndpoint, svc.RoutingKeys))
+	}
+
+	addedKeys := make(map[string]struct{})
+
+	for i := range request.AddPublicKeys {
+		v := &request.AddPublicKeys[i]
+
+		k, err := parsePublicKey(v)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = addVerificationMethod(didDoc, v, k)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		addedKeys[v.ID] = struct{}{}
+	}
+
+	return didDoc, addedKeys, nil
+}
+
+// removedVerifications returns verifications of the current document which aren't kept in the updated one.
+func removedVerifications(current, updated []did.Verification) []did.Verification {
+	kept := make(map[string]struct{})
+	for _, v := range updated {
+		kept[v.VerificationMethod.ID] = struct{}{}
+	}
+
+	var removed []did.Verification
+
+	for _, v := range current {
+		if _, ok := kept[v.VerificationMethod.ID]; !ok {
+			removed = append(removed, v)
+		}
+	}
+
+	return removed
+}
+
+// fragment returns the part of DID URL after '#', or the value itself when there is none.
+func fragment(id string) string {
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		return id[i+1:]
+	}
+
+	return id
+}
+
+func newService(id, svcType, endpoint string, routingKeys []string) did.Service {
+	if svcType == "" {
+		svcType = didCommV2ServiceType
+	}
+
+	if svcType == didCommServiceType {
+		return did.Service{
+			ID:              id,
+			Type:            svcType,
+			ServiceEndpoint: model.NewDIDCommV1Endpoint(endpoint),
+			RoutingKeys:     routingKeys,
+		}
+	}
+
+	return did.Service{
+		ID:   id,
+		Type: svcType,
+		ServiceEndpoint: model.NewDIDCommV2Endpoint(
+			[]model.DIDCommV2Endpoint{{RoutingKeys: routingKeys, URI: endpoint}}),
+	}
+}
+
+// parsePublicKey decodes public key value of given key type.
+func parsePublicKey(v *PublicKey) (interface{}, error) {
+	value, err := base64.RawURLEncoding.DecodeString(v.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return getKey(v.KeyType, value)
+}
+
+func addVerificationMethod(didDoc *did.Doc, v *PublicKey, k interface{}) error {
+	jwk, err := getJWK(v.KeyType, k)
+	if err != nil {
+		return err
+	}
+
+	vm, err := did.NewVerificationMethodFromJWK(v.ID, v.Type, "", jwk)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range v.Purposes {
+		switch p {
+		case doc.KeyPurposeAuthentication:
+			didDoc.Authentication = append(didDoc.Authentication,
+				*did.NewReferencedVerification(vm, did.Authentication))
+		case doc.KeyPurposeAssertionMethod:
+			didDoc.AssertionMethod = append(didDoc.AssertionMethod,
+				*did.NewReferencedVerification(vm, did.AssertionMethod))
+		case doc.KeyPurposeKeyAgreement:
+			didDoc.KeyAgreement = append(didDoc.KeyAgreement,
+				*did.NewReferencedVerification(vm, did.KeyAgreement))
+		case doc.KeyPurposeCapabilityDelegation:
+			didDoc.CapabilityDelegation = append(didDoc.CapabilityDelegation,
+				*did.NewReferencedVerification(vm, did.CapabilityDelegation))
+		case doc.KeyPurposeCapabilityInvocation:
+			didDoc.CapabilityInvocation = append(didDoc.CapabilityInvocation,
+				*did.NewReferencedVerification(vm, did.CapabilityInvocation))
+		default:
+			return fmt.Errorf("public key purpose %s not supported", p)
+		}
+	}
+
+	return nil
+}
+
+func getJWK(keyType string, k interface{}) (*jwk2.JWK, error) {
+	switch {
+	case strings.EqualFold(keyType, x25519ECDHKW):
+		return jwksupport.JWKFromX25519Key(k.(*crypto.PublicKey).X)
+	case strings.EqualFold(keyType, p256ecdhkw) || strings.EqualFold(keyType, p384ecdhkw) ||
+		strings.EqualFold(keyType, p521ecdhkw):
+		pubKey, ok := k.(*crypto.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key '%+v' is not NIST P ECDH KW type", k)
+		}
+
+		ecdsaKey := &ecdsa.PublicKey{
+			X:     new(big.Int).SetBytes(pubKey.X),
+			Y:     new(big.Int).SetBytes(pubKey.Y),
+			Curve: getCurve(pubKey.Curve),
+		}
+
+		jwk, err := jwksupport.JWKFromKey(ecdsaKey)
+		if err != nil {
+			return nil, fmt.Errorf("JWKFromKey() jwk: %+v, ecdsa key: %+v, error: %w", jwk, ecdsaKey, err)
+		}
+
+		return jwk, nil
+	default:
+		return jwksupport.JWKFromKey(k)
+	}
+}
+
+func getCurve(crv string) elliptic.Curve {
+	c := elliptic.P256()
+
+	switch crv {
+	case "NIST_P256", "P-256":
+	case "NIST_P384", "P-384":
+		c = elliptic.P384()
+	case "NIST_P521", "P-521":
+		c = elliptic.P521()
+	}
+
+	return c
+}
+
+func getKey(keyType string, value []byte) (interface{}, error) {
+	switch strings.ToLower(keyType) {
+	case ed25519KeyType:
+		return ed25519.PublicKey(value), nil
+	case p256KeyType:
+		x, y := elliptic.Unmarshal(elliptic.P256(), value)
+
+		return &ecdsa.PublicKey{X: x, Y: y, Curve: elliptic.P256()}, nil
+	case p384KeyType:
+		x, y := elliptic.Unmarshal(elliptic.P384(), value)
+
+		return &ecdsa.PublicKey{X: x, Y: y, Curve: elliptic.P384()}, nil
+	case BLS12381G2KeyType:
+		return bbs12381g2pub.UnmarshalPublicKey(value)
+	case x25519ECDHKW, p256ecdhkw, p384ecdhkw, p521ecdhkw:
+		pubKey := &crypto.PublicKey{}
+
+		err := json.Unmarshal(value, pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal key type: %s, value: %s failed: %w", keyType, value, err)
+		}
+
+		return pubKey, nil
+	default:
+		return nil, fmt.Errorf("invalid key type: %s", keyType)
+	}
+}
+
+// CreatePeerDID creates a new peer DID.
+func (c *Command) CreatePeerDID(rw io.Writer, req io.Reader) command.Error { // nolint: funlen,gocyclo
+	var request CreatePeerDIDRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.RouterConnectionID == "" {
+		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, errInvalidRouterConnectionID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidRouterConnectionID))
+	}
+
+	opts, err := getPeerDIDOptions(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	config, err := c.mediatorClient.GetConfig(request.RouterConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(CreateDIDErrorCode, err)
+	}
+
+	didDoc, err := newPeerDIDDoc(c.keyManager, opts, config)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
+
+		return command.NewExecuteError(CreateDIDErrorCode, err)
+	}
+
+	docResolution, err := c.vdrRegistry.Create(peer.DIDMethod, didDoc)
 	if err != nil {
 		logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
 
@@ -517,8 +1708,8 @@ func (c *Command) CreatePeerDID(rw io.Writer, req io.Reader) command.Error { //
 		}
 	}
 
-	for _, val := range didSvc.RecipientKeys {
-		err = mediatorservice.AddKeyToRouter(c.mediatorSvc, request.RouterConnectionID, val)
+	for _, val := range routeutil.DIDRouterKeys(docResolution.DIDDocument, didSvc) {
+		err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, request.RouterConnectionID, val)
 
 		if err != nil {
 			logutil.LogError(logger, CommandName, CreatePeerDIDCommandMethod, err.Error())
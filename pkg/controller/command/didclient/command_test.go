@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
 	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
 	"github.com/hyperledger/aries-framework-go/pkg/common/model"
 	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
@@ -27,13 +28,18 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockprotocol "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockservice "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/service"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
 )
 
 //nolint:lll
@@ -66,7 +72,7 @@ const sampleDoc = `{
 
 func TestNew(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 		require.NotNil(t, c.GetHandlers())
@@ -75,7 +81,7 @@ func TestNew(t *testing.T) {
 	t.Run("test no coordination service error", func(t *testing.T) {
 		c, err := New("domain", "origin", "", 0, &mockprotocol.MockProvider{
 			ServiceErr: fmt.Errorf("sample-error"),
-		})
+		}, mocks.NewMockNotifier())
 		require.Error(t, err)
 		require.Nil(t, c)
 		require.EqualError(t, err, "sample-error")
@@ -86,7 +92,7 @@ func TestNew(t *testing.T) {
 			ServiceMap: map[string]interface{}{
 				mediatorsvc.Coordination: "xyz",
 			},
-		})
+		}, mocks.NewMockNotifier())
 		require.Error(t, err)
 		require.Nil(t, c)
 		require.EqualError(t, err, "cast service to route service failed")
@@ -95,7 +101,7 @@ func TestNew(t *testing.T) {
 
 func TestCommand_ResolveOrbDID(t *testing.T) {
 	t.Run("test error from request", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -108,7 +114,7 @@ func TestCommand_ResolveOrbDID(t *testing.T) {
 	})
 
 	t.Run("test error from resolve did", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -127,7 +133,7 @@ func TestCommand_ResolveOrbDID(t *testing.T) {
 	})
 
 	t.Run("test success", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 1, getMockProvider())
+		c, err := New("domain", "origin", "", 1, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -148,11 +154,206 @@ func TestCommand_ResolveOrbDID(t *testing.T) {
 		require.NotEmpty(t, docRes)
 		require.Contains(t, "did:123", docRes.DIDDocument.ID)
 	})
+
+	t.Run("test resolve version", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 1, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		client := &mockDIDClient{resolveDIDValue: &did.DocResolution{DIDDocument: &did.Doc{
+			ID:      "did:123",
+			Context: []string{"https://www.w3.org/ns/did/v1"},
+		}}}
+		c.didBlocClient = client
+
+		for _, request := range []ResolveOrbDIDRequest{
+			{DID: "did:123", VersionID: "hl:uEiA123"},
+			{DID: "did:123", VersionTime: "2021-05-10T17:00:00Z"},
+		} {
+			req, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+			cmdErr := c.ResolveOrbDID(&b, bytes.NewBuffer(req))
+			require.NoError(t, cmdErr)
+
+			opts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+			for _, opt := range client.resolveOpts {
+				opt(opts)
+			}
+
+			if request.VersionID != "" {
+				require.Equal(t, request.VersionID, opts.Values[orb.VersionIDOpt])
+			} else {
+				require.Equal(t, request.VersionTime, opts.Values[orb.VersionTimeOpt])
+			}
+		}
+	})
+
+	t.Run("test invalid version", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 1, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		for _, request := range []ResolveOrbDIDRequest{
+			{DID: "did:123", VersionID: "hl:uEiA123", VersionTime: "2021-05-10T17:00:00Z"},
+			{DID: "did:123", VersionTime: "yesterday"},
+		} {
+			req, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+			cmdErr := c.ResolveOrbDID(&b, bytes.NewBuffer(req))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+}
+
+func TestCommand_ResolveDID(t *testing.T) {
+	didDoc, err := did.ParseDocument([]byte(sampleDoc))
+	require.NoError(t, err)
+
+	resolve := func(t *testing.T, c *Command, request *ResolveDIDRequest) *ResolveDIDResponse {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.ResolveDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		response := &ResolveDIDResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), response))
+
+		return response
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.ResolveDID(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = c.ResolveDID(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errInvalidDID)
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var resolveOpts []vdr.DIDMethodOption
+
+		c.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+				resolveOpts = opts
+
+				return &did.DocResolution{
+					Context:          []string{"https://w3id.org/did-resolution/v1"},
+					DIDDocument:      didDoc,
+					DocumentMetadata: &did.DocumentMetadata{VersionID: "v1"},
+				}, nil
+			},
+		}
+
+		response := resolve(t, c, &ResolveDIDRequest{DID: didDoc.ID})
+		require.Empty(t, response.DIDResolutionMetadata.Error)
+		require.Equal(t, didLDJSONMediaType, response.DIDResolutionMetadata.ContentType)
+		require.Equal(t, "v1", response.DIDDocumentMetadata.VersionID)
+		require.Empty(t, resolveOpts)
+
+		resolved, err := did.ParseDocument(response.DIDDocument)
+		require.NoError(t, err)
+		require.Equal(t, didDoc.ID, resolved.ID)
+
+		response = resolve(t, c, &ResolveDIDRequest{DID: "did:orb:uAAA:EiA123", VersionID: "v1"})
+		require.Empty(t, response.DIDResolutionMetadata.Error)
+
+		opts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+		for _, opt := range resolveOpts {
+			opt(opts)
+		}
+
+		require.Equal(t, "v1", opts.Values[orb.VersionIDOpt])
+		require.Nil(t, opts.Values[orb.VersionTimeOpt])
+
+		response = resolve(t, c, &ResolveDIDRequest{DID: "did:orb:uAAA:EiA123", VersionTime: "2021-05-10T17:00:00Z"})
+		require.Empty(t, response.DIDResolutionMetadata.Error)
+
+		opts = &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+		for _, opt := range resolveOpts {
+			opt(opts)
+		}
+
+		require.Equal(t, "2021-05-10T17:00:00Z", opts.Values[orb.VersionTimeOpt])
+
+		response = resolve(t, c, &ResolveDIDRequest{DID: didDoc.ID, Accept: didJSONMediaType})
+		require.Empty(t, response.DIDResolutionMetadata.Error)
+		require.Equal(t, didJSONMediaType, response.DIDResolutionMetadata.ContentType)
+		require.NotContains(t, string(response.DIDDocument), "@context")
+	})
+
+	t.Run("test resolution errors", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			request    *ResolveDIDRequest
+			resolveErr error
+			code       string
+		}{
+			{request: &ResolveDIDRequest{DID: "invalid"}, code: resolutionErrInvalidDID},
+			{
+				request: &ResolveDIDRequest{DID: "did:ex:123", Accept: "text/plain"},
+				code:    resolutionErrRepresentationUnsupported,
+			},
+			{request: &ResolveDIDRequest{DID: "did:ex:123", VersionID: "v1"}, code: resolutionErrInvalidOptions},
+			{
+				request: &ResolveDIDRequest{DID: "did:ex:123", VersionTime: "2021-05-10T17:00:00Z"},
+				code:    resolutionErrInvalidOptions,
+			},
+			{
+				request: &ResolveDIDRequest{DID: "did:orb:uAAA:EiA123", VersionID: "v1", VersionTime: "2021-05-10T17:00:00Z"},
+				code:    resolutionErrInvalidOptions,
+			},
+			{
+				request: &ResolveDIDRequest{DID: "did:orb:uAAA:EiA123", VersionTime: "yesterday"},
+				code:    resolutionErrInvalidOptions,
+			},
+			{request: &ResolveDIDRequest{DID: "did:ex:123"}, resolveErr: vdr.ErrNotFound, code: resolutionErrNotFound},
+			{
+				request:    &ResolveDIDRequest{DID: "did:ex:123"},
+				resolveErr: fmt.Errorf("did method ex not supported for vdr"),
+				code:       resolutionErrMethodNotSupported,
+			},
+			{
+				request:    &ResolveDIDRequest{DID: "did:ex:123"},
+				resolveErr: fmt.Errorf("read failed"),
+				code:       resolutionErrInternal,
+			},
+		} {
+			c.vdrRegistry = &mockvdr.MockVDRegistry{ResolveErr: tc.resolveErr}
+
+			response := resolve(t, c, tc.request)
+			require.Equal(t, tc.code, response.DIDResolutionMetadata.Error)
+			require.NotEmpty(t, response.DIDResolutionMetadata.ErrorMessage)
+			require.Empty(t, response.DIDDocument)
+		}
+	})
 }
 
 func TestCommand_CreateOrbDID(t *testing.T) {
 	t.Run("test error from request", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -167,7 +368,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 	})
 
 	t.Run("bad didDoc", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -192,7 +393,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 	})
 
 	t.Run("test error unsupported purpose", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -217,7 +418,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 	})
 
 	t.Run("test error from create did", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -249,7 +450,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 	})
 
 	t.Run("test recovery key not supported", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -277,7 +478,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 	})
 
 	t.Run("test error from did base64 decode", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -300,7 +501,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 		require.Contains(t, cmdErr.Error(), "illegal base64 data")
 	})
 
-	c, err := New("domain", "origin", "", 0, getMockProvider())
+	c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 	require.NoError(t, err)
 	require.NotNil(t, c)
 
@@ -542,7 +743,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 		badC, err := New("domain", "origin", "", 0,
 			getMockProviderWithMediator(&mockroute.MockMediatorSvc{
 				AddKeyErr: addRouterKeyErr,
-			}))
+			}), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -579,7 +780,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 		var b bytes.Buffer
 		cmdErr := badC.CreateOrbDID(&b, bytes.NewBuffer(r))
 		require.Contains(t, cmdErr.Error(), "failed to register did doc recipient key")
-		require.True(t, errors.As(cmdErr, &addRouterKeyErr))
+		require.Contains(t, cmdErr.Error(), addRouterKeyErr.Error())
 	})
 
 	t.Run("test success create did with custom properties and NISTP256ECDHKW keyAgreement", func(t *testing.T) {
@@ -818,7 +1019,7 @@ func TestCommand_CreateOrbDID(t *testing.T) {
 
 func TestCommand_CreatePeerDID(t *testing.T) {
 	t.Run("test error from request", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -836,7 +1037,7 @@ func TestCommand_CreatePeerDID(t *testing.T) {
 	})
 
 	t.Run("success (registered route)", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -878,7 +1079,7 @@ func TestCommand_CreatePeerDID(t *testing.T) {
 	})
 
 	t.Run("success (default)", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -915,7 +1116,7 @@ func TestCommand_CreatePeerDID(t *testing.T) {
 	})
 
 	t.Run("test error while creating peer DID", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
@@ -987,7 +1188,7 @@ func TestCommand_CreatePeerDID(t *testing.T) {
 	})
 
 	t.Run("test error while creating verification method", func(t *testing.T) {
-		c, err := New("domain", "origin", "", 0, getMockProvider())
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
 		require.NoError(t, err)
 
 		c.vdrRegistry = &mockvdr.MockVDRegistry{CreateValue: &did.Doc{
@@ -1020,21 +1221,712 @@ func TestCommand_CreatePeerDID(t *testing.T) {
 	})
 }
 
+func TestCommand_UpdateOrbDID(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	x25519Key, err := json.Marshal(&cryptoapi.PublicKey{
+		X:     pubKey,
+		Curve: "X25519",
+		Type:  "OKP",
+	})
+	require.NoError(t, err)
+
+	currentDoc, err := did.ParseDocument([]byte(sampleDoc))
+	require.NoError(t, err)
+
+	currentDoc.Authentication = []did.Verification{
+		*did.NewReferencedVerification(&currentDoc.VerificationMethod[0], did.Authentication),
+	}
+	currentDoc.AssertionMethod = []did.Verification{
+		*did.NewReferencedVerification(&currentDoc.VerificationMethod[1], did.AssertionMethod),
+	}
+	currentDoc.Service = []did.Service{{ID: "did:peer:21tDAKCERh95uGgKbJNHYp#svc1"}, {ID: "#svc2"}}
+
+	updateReq := UpdateOrbDIDRequest{
+		DID:              "did:orb:123",
+		RemovePublicKeys: []string{"key2"},
+		RemoveServices:   []string{"svc2"},
+		AddPublicKeys: []PublicKey{
+			{
+				ID:       "key3",
+				Type:     doc.JWSVerificationKey2020,
+				KeyType:  ed25519KeyType,
+				Value:    base64.RawURLEncoding.EncodeToString(pubKey),
+				Purposes: []string{doc.KeyPurposeAuthentication},
+			},
+			{
+				ID:       "key4",
+				Type:     doc.JWSVerificationKey2020,
+				KeyType:  x25519ECDHKW,
+				Value:    base64.RawURLEncoding.EncodeToString(x25519Key),
+				Purposes: []string{doc.KeyPurposeKeyAgreement},
+			},
+		},
+		AddServices: []Service{
+			{ID: "svc3", ServiceEndpoint: "https://example.com", RoutingKeys: []string{"key"}},
+			{ID: "svc4", Type: didCommServiceType, ServiceEndpoint: "https://example.com"},
+		},
+		UpdateKeyID: "update-key",
+		NextUpdateKey: &PublicKey{
+			KeyType: ed25519KeyType,
+			Value:   base64.RawURLEncoding.EncodeToString(pubKey),
+		},
+		RouterConnections: []string{"conn1"},
+	}
+
+	newCommand := func(t *testing.T, mediator interface{}) *Command {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProviderWithMediator(mediator), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager = &mockkms.KeyManager{
+			ExportPubKeyBytesValue: pubKey,
+			ExportPubKeyTypeValue:  kms.ED25519Type,
+		}
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: &did.DocResolution{DIDDocument: currentDoc}}
+
+		return c
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("test validation errors", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		for _, tc := range []struct {
+			req UpdateOrbDIDRequest
+			err string
+		}{
+			{req: UpdateOrbDIDRequest{}, err: errInvalidDID},
+			{
+				req: UpdateOrbDIDRequest{DID: "did:orb:123", NextUpdateKey: updateReq.NextUpdateKey},
+				err: errInvalidUpdateKeyID,
+			},
+			{req: UpdateOrbDIDRequest{DID: "did:orb:123", UpdateKeyID: "key"}, err: errInvalidNextUpdateKey},
+		} {
+			req, err := json.Marshal(tc.req)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+
+			cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test error from resolve did", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		c.didBlocClient = &mockDIDClient{resolveDIDErr: fmt.Errorf("error resolve did")}
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "error resolve did")
+	})
+
+	t.Run("test error from invalid public keys", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		invalidKey := PublicKey{KeyType: ed25519KeyType, Value: "!", Purposes: []string{doc.KeyPurposeAuthentication}}
+
+		request := updateReq
+		request.AddPublicKeys = []PublicKey{invalidKey}
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "illegal base64 data")
+
+		request = updateReq
+		request.AddPublicKeys = []PublicKey{updateReq.AddPublicKeys[0]}
+		request.AddPublicKeys[0].Purposes = []string{"invalid"}
+
+		req, err = json.Marshal(request)
+		require.NoError(t, err)
+
+		cmdErr = c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "public key purpose invalid not supported")
+
+		request.AddPublicKeys = nil
+		request.NextUpdateKey = &invalidKey
+
+		req, err = json.Marshal(request)
+		require.NoError(t, err)
+
+		cmdErr = c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "illegal base64 data")
+	})
+
+	t.Run("test error from signer", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		c.keyManager = &mockkms.KeyManager{GetKeyErr: fmt.Errorf("key not found")}
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "key not found")
+	})
+
+	t.Run("test error operation in progress", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		require.NoError(t, c.keyRetriever.add(updateReq.DID, &operationKeys{}))
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "operation already in progress")
+	})
+
+	t.Run("test error from update did", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		c.didBlocClient = &mockDIDClient{
+			resolveDIDValue: &did.DocResolution{DIDDocument: currentDoc},
+			updateDIDErr:    fmt.Errorf("error update did"),
+		}
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error update did")
+
+		_, err = c.keyRetriever.get(updateReq.DID)
+		require.Error(t, err)
+	})
+
+	t.Run("test error from router", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{AddKeyErr: fmt.Errorf("add router key failed")})
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to register did doc recipient key")
+	})
+
+	t.Run("test removed keyAgreements are removed from routers", func(t *testing.T) {
+		kaDoc, err := did.ParseDocument([]byte(sampleDoc))
+		require.NoError(t, err)
+
+		kaDoc.KeyAgreement = []did.Verification{
+			*did.NewReferencedVerification(&kaDoc.VerificationMethod[2], did.KeyAgreement),
+		}
+
+		c := newCommand(t, &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf("connections error")})
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: &did.DocResolution{DIDDocument: kaDoc}}
+
+		request := updateReq
+		request.RemovePublicKeys = []string{"keys-3"}
+		request.RouterConnections = nil
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to get router connections")
+
+		updated := c.didBlocClient.(*mockDIDClient).updatedDoc
+		require.Len(t, updated.KeyAgreement, 1)
+		require.Equal(t, "key4", updated.KeyAgreement[0].VerificationMethod.ID)
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		req, err := json.Marshal(updateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.UpdateOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		updated := c.didBlocClient.(*mockDIDClient).updatedDoc
+		require.NotNil(t, updated)
+		require.Equal(t, "did:orb:123", updated.ID)
+		require.Len(t, updated.Authentication, 2)
+		require.Empty(t, updated.AssertionMethod)
+		require.Len(t, updated.KeyAgreement, 1)
+		require.Equal(t, "key4", updated.KeyAgreement[0].VerificationMethod.ID)
+		require.Len(t, updated.Service, 3)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp#svc1", updated.Service[0].ID)
+		require.Equal(t, didCommV2ServiceType, updated.Service[1].Type)
+		require.Equal(t, didCommServiceType, updated.Service[2].Type)
+	})
+}
+
+func TestCommand_RecoverOrbDID(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key := &PublicKey{KeyType: ed25519KeyType, Value: base64.RawURLEncoding.EncodeToString(pubKey)}
+
+	recoverReq := RecoverOrbDIDRequest{
+		DID: "did:orb:123",
+		PublicKeys: []PublicKey{{
+			ID:       "key1",
+			Type:     doc.JWSVerificationKey2020,
+			KeyType:  ed25519KeyType,
+			Value:    base64.RawURLEncoding.EncodeToString(pubKey),
+			Purposes: []string{doc.KeyPurposeAuthentication, doc.KeyPurposeKeyAgreement},
+		}},
+		Services:          []Service{{ID: "svc1", ServiceEndpoint: "https://example.com"}},
+		RecoveryKeyID:     "recovery-key",
+		NextUpdateKey:     key,
+		NextRecoveryKey:   key,
+		RouterConnections: []string{"conn1"},
+	}
+
+	newCommand := func(t *testing.T, mediator interface{}) *Command {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProviderWithMediator(mediator), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager = &mockkms.KeyManager{
+			ExportPubKeyBytesValue: pubKey,
+			ExportPubKeyTypeValue:  kms.ED25519Type,
+		}
+		c.didBlocClient = &mockDIDClient{}
+
+		return c
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("test validation errors", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		for _, tc := range []struct {
+			req RecoverOrbDIDRequest
+			err string
+		}{
+			{req: RecoverOrbDIDRequest{}, err: errInvalidDID},
+			{req: RecoverOrbDIDRequest{DID: "did:orb:123", NextUpdateKey: key}, err: errInvalidRecoveryKeyID},
+			{req: RecoverOrbDIDRequest{DID: "did:orb:123", RecoveryKeyID: "key"}, err: errInvalidNextUpdateKey},
+			{
+				req: RecoverOrbDIDRequest{DID: "did:orb:123", RecoveryKeyID: "key", NextUpdateKey: key},
+				err: errInvalidNextRecoveryKey,
+			},
+		} {
+			req, err := json.Marshal(tc.req)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+
+			cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test error from invalid keys", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		invalidKey := &PublicKey{KeyType: ed25519KeyType, Value: "!"}
+
+		for _, modify := range []func(r *RecoverOrbDIDRequest){
+			func(r *RecoverOrbDIDRequest) { r.PublicKeys = []PublicKey{*invalidKey} },
+			func(r *RecoverOrbDIDRequest) {
+				r.PublicKeys = []PublicKey{{KeyType: ed25519KeyType, Value: key.Value, Purposes: []string{"invalid"}}}
+			},
+			func(r *RecoverOrbDIDRequest) { r.NextUpdateKey = invalidKey },
+			func(r *RecoverOrbDIDRequest) { r.NextRecoveryKey = invalidKey },
+		} {
+			request := recoverReq
+			modify(&request)
+
+			req, err := json.Marshal(request)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+
+			cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+			require.Error(t, cmdErr)
+			require.Equal(t, RecoverDIDErrorCode, cmdErr.Code())
+			require.Equal(t, command.ExecuteError, cmdErr.Type())
+		}
+	})
+
+	t.Run("test error from signer", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		c.keyManager = &mockkms.KeyManager{GetKeyErr: fmt.Errorf("key not found")}
+
+		req, err := json.Marshal(recoverReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, RecoverDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "key not found")
+	})
+
+	t.Run("test error operation in progress", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		require.NoError(t, c.keyRetriever.add(recoverReq.DID, &operationKeys{}))
+
+		req, err := json.Marshal(recoverReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "operation already in progress")
+	})
+
+	t.Run("test error from recover did", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+		c.didBlocClient = &mockDIDClient{updateDIDErr: fmt.Errorf("error recover did")}
+
+		req, err := json.Marshal(recoverReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, RecoverDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error recover did")
+	})
+
+	t.Run("test error from router", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{AddKeyErr: fmt.Errorf("add router key failed")})
+
+		req, err := json.Marshal(recoverReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, RecoverDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to register did doc recipient key")
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{})
+
+		req, err := json.Marshal(recoverReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RecoverOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		client := c.didBlocClient.(*mockDIDClient)
+		require.Equal(t, "did:orb:123", client.updatedDoc.ID)
+		require.Len(t, client.updatedDoc.Authentication, 1)
+		require.Len(t, client.updatedDoc.KeyAgreement, 1)
+		require.Len(t, client.updatedDoc.Service, 1)
+
+		opts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+		for _, opt := range client.updateOpts {
+			opt(opts)
+		}
+
+		require.Equal(t, true, opts.Values[orb.RecoverOpt])
+	})
+}
+
+func TestCommand_DeactivateOrbDID(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	currentDoc, err := did.ParseDocument([]byte(sampleDoc))
+	require.NoError(t, err)
+
+	currentDoc.KeyAgreement = []did.Verification{
+		*did.NewReferencedVerification(&currentDoc.VerificationMethod[2], did.KeyAgreement),
+	}
+
+	deactivateReq := DeactivateOrbDIDRequest{DID: "did:orb:123", RecoveryKeyID: "recovery-key"}
+
+	newCommand := func(t *testing.T, mediator interface{}, messenger *mockservice.MockMessenger) *Command {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProviderWithMediator(mediator), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager = &mockkms.KeyManager{
+			ExportPubKeyBytesValue: pubKey,
+			ExportPubKeyTypeValue:  kms.ED25519Type,
+		}
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: &did.DocResolution{DIDDocument: currentDoc}}
+
+		p := &mockprotocol.MockProvider{
+			StoreProvider:   mockstorage.NewMockStoreProvider(),
+			CustomMessenger: messenger,
+		}
+
+		recorder, err := connection.NewRecorder(p)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "conn1",
+			State:        connection.StateNameCompleted,
+			MyDID:        "did:example:me",
+			TheirDID:     "did:example:router",
+		}))
+
+		c.routeProvider = p
+
+		return c
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("test validation errors", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+
+		for _, tc := range []struct {
+			req DeactivateOrbDIDRequest
+			err string
+		}{
+			{req: DeactivateOrbDIDRequest{}, err: errInvalidDID},
+		} {
+			req, err := json.Marshal(tc.req)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+
+			cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test error from resolve did", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+		c.didBlocClient = &mockDIDClient{resolveDIDErr: fmt.Errorf("error resolve did")}
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error resolve did")
+	})
+
+	t.Run("test error from signer", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+		c.keyManager = &mockkms.KeyManager{GetKeyErr: fmt.Errorf("key not found")}
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "key not found")
+	})
+
+	t.Run("test error operation in progress", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+		require.NoError(t, c.keyRetriever.add(deactivateReq.DID, &operationKeys{}))
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "operation already in progress")
+	})
+
+	t.Run("test error from deactivate did", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+		c.didBlocClient = &mockDIDClient{
+			resolveDIDValue: &did.DocResolution{DIDDocument: currentDoc},
+			deactivateErr:   fmt.Errorf("error deactivate did"),
+		}
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "error deactivate did")
+	})
+
+	t.Run("test error from router connections", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf("connections error")}, nil)
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "connections error")
+	})
+
+	t.Run("test error removing keys from router", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{Connections: []string{"conn1"}},
+			&mockservice.MockMessenger{ErrSend: fmt.Errorf("send error")})
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to remove keyAgreements from router connection conn1")
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{}, nil)
+
+		request := deactivateReq
+		request.RouterConnections = []string{"conn1"}
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		_, err = c.keyRetriever.get(deactivateReq.DID)
+		require.Error(t, err)
+	})
+
+	t.Run("test success without keyAgreements", func(t *testing.T) {
+		c := newCommand(t, &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf("connections error")}, nil)
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: &did.DocResolution{DIDDocument: &did.Doc{}}}
+
+		req, err := json.Marshal(deactivateReq)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.DeactivateOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+	})
+}
+
 type mockDIDClient struct {
 	createDIDValue  *did.DocResolution
 	createDIDErr    error
+	createOpts      []vdr.DIDMethodOption
 	resolveDIDValue *did.DocResolution
 	resolveDIDErr   error
+	resolveOpts     []vdr.DIDMethodOption
+	updateDIDErr    error
+	updatedDoc      *did.Doc
+	updateOpts      []vdr.DIDMethodOption
+	deactivateErr   error
 }
 
 func (m *mockDIDClient) Create(didDoc *did.Doc, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	m.createOpts = opts
+
 	return m.createDIDValue, m.createDIDErr
 }
 
 func (m *mockDIDClient) Read(id string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	m.resolveOpts = opts
+
 	return m.resolveDIDValue, m.resolveDIDErr
 }
 
+func (m *mockDIDClient) Update(didDoc *did.Doc, opts ...vdr.DIDMethodOption) error {
+	m.updatedDoc = didDoc
+	m.updateOpts = opts
+
+	return m.updateDIDErr
+}
+
+func (m *mockDIDClient) Deactivate(didID string, opts ...vdr.DIDMethodOption) error {
+	return m.deactivateErr
+}
+
 // mockMediatorClient mock mediator client.
 type mockMediatorClient struct {
 	RegisterErr   error
@@ -0,0 +1,488 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	didAuthChallengeKeyPrefix = "didauthchallenge_"
+	didAuthChallengeTag       = "didAuthChallenge"
+	didAuthChallengeSize      = 32
+
+	// DID Auth response formats.
+	didAuthFormatJWT    = "jwt"
+	didAuthFormatJSONLD = "jsonld"
+
+	// default validity of DID Auth challenge.
+	defaultDIDAuthChallengeValidity = 5 * time.Minute
+)
+
+var (
+	errUnknownDIDAuthChallenge = errors.New("unknown or already used challenge")
+	errInvalidDIDAuthChallenge = errors.New("invalid challenge")
+)
+
+// CreateDIDAuthChallenge creates single-use DID Auth challenge for the domain of relying party, the challenge
+// is kept in the agent until it's answered or expires.
+func (c *Command) CreateDIDAuthChallenge(rw io.Writer, req io.Reader) command.Error {
+	var request CreateDIDAuthChallengeRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	expires := time.Now().Add(defaultDIDAuthChallengeValidity)
+	if request.ExpirationDate != nil {
+		expires = *request.ExpirationDate
+	}
+
+	switch {
+	case request.Domain == "":
+		err = errors.New(errMissingDIDAuthDomain)
+	case !time.Now().Before(expires):
+		err = errors.New(errExpiredDIDAuthChallenge)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	challenge, err := c.didAuthChallenges.create(request.Domain, expires.UTC())
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDAuthErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, challenge, logger)
+
+	logutil.LogDebug(logger, CommandName, CreateDIDAuthChallengeCommandMethod, successString)
+
+	return nil
+}
+
+// RespondDIDAuthChallenge answers DID Auth challenge with Verifiable Presentation of the DID holder signed by
+// an authentication key of the DID, the presentation is bound to the challenge and domain of relying party.
+func (c *Command) RespondDIDAuthChallenge(rw io.Writer, req io.Reader) command.Error {
+	var request RespondDIDAuthChallengeRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RespondDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.Format == "" {
+		request.Format = didAuthFormatJWT
+	}
+
+	err = validateDIDAuthResponseRequest(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RespondDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	presentation, err := c.respondDIDAuthChallenge(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RespondDIDAuthChallengeCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDAuthErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &RespondDIDAuthChallengeResponse{
+		Presentation: presentation,
+		Format:       request.Format,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, RespondDIDAuthChallengeCommandMethod, successString)
+
+	return nil
+}
+
+// VerifyDIDAuthResponse verifies DID Auth response to a challenge created by the agent. The presentation has
+// to be signed by an authentication key of the holder DID and bound to an unexpired challenge and its domain,
+// the challenge can't be used again once the response is verified.
+func (c *Command) VerifyDIDAuthResponse(rw io.Writer, req io.Reader) command.Error {
+	var request VerifyDIDAuthResponseRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err == nil && (len(request.Presentation) == 0 || string(request.Presentation) == "null") {
+		err = errors.New("presentation is required")
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyDIDAuthResponseCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.verifyDIDAuthResponse(request.Presentation)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyDIDAuthResponseCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDAuthErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyDIDAuthResponseCommandMethod, successString)
+
+	return nil
+}
+
+func validateDIDAuthResponseRequest(request *RespondDIDAuthChallengeRequest) error {
+	if _, err := did.Parse(request.DID); err != nil {
+		return fmt.Errorf(errInvalidDID)
+	}
+
+	switch {
+	case request.Challenge == "":
+		return errors.New(errMissingDIDAuthChallenge)
+	case request.Domain == "":
+		return errors.New(errMissingDIDAuthDomain)
+	case request.Format != didAuthFormatJWT && request.Format != didAuthFormatJSONLD:
+		return fmt.Errorf(errUnsupportedDIDAuthFormat, request.Format)
+	}
+
+	return nil
+}
+
+// didAuthClaims claims of JWT DID Auth response.
+type didAuthClaims struct {
+	*verifiable.JWTPresClaims
+
+	Nonce string `json:"nonce"`
+}
+
+func (c *Command) respondDIDAuthChallenge(request *RespondDIDAuthChallengeRequest) (json.RawMessage, error) {
+	signer, err := c.relationshipSigner(request.DID, request.KeyID, signaturePurposeAuthentication)
+	if err != nil {
+		return nil, err
+	}
+
+	vp, err := verifiable.NewPresentation()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create presentation : %w", err)
+	}
+
+	vp.Holder = request.DID
+
+	if request.Format == didAuthFormatJWT {
+		presClaims, errClaims := vp.JWTClaims([]string{request.Domain}, false)
+		if errClaims != nil {
+			return nil, fmt.Errorf("failed to create JWT claims of presentation : %w", errClaims)
+		}
+
+		token, errSign := jwt.NewSigned(&didAuthClaims{JWTPresClaims: presClaims, Nonce: request.Challenge},
+			signingHeaders(nil, signer, jwt.TypeJWT), signer)
+		if errSign != nil {
+			return nil, fmt.Errorf("failed to sign presentation : %w", errSign)
+		}
+
+		serialized, errSign := token.Serialize(false)
+		if errSign != nil {
+			return nil, fmt.Errorf("failed to serialize presentation : %w", errSign)
+		}
+
+		return json.Marshal(serialized)
+	}
+
+	if signer.vmType == jsonWebKey2020 {
+		vp.Context = append(vp.Context, jws2020Context)
+	}
+
+	ldpContext := linkedDataProofContext(signer, signaturePurposeAuthentication)
+	ldpContext.Challenge = request.Challenge
+	ldpContext.Domain = request.Domain
+
+	err = vp.AddLinkedDataProof(ldpContext, jsonld.WithDocumentLoader(c.documentLoader))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign presentation : %w", err)
+	}
+
+	return vp.MarshalJSON()
+}
+
+// verifyDIDAuthResponse checks signature of DID Auth response before using up its challenge, so that responses
+// with invalid signatures can't revoke challenges of other holders.
+func (c *Command) verifyDIDAuthResponse(presentation json.RawMessage) (*VerifyDIDAuthResponseResponse, error) {
+	var (
+		format                    = didAuthFormatJSONLD
+		holder, challenge, domain string
+		err                       error
+	)
+
+	var vpJWT string
+	if json.Unmarshal(presentation, &vpJWT) == nil {
+		format = didAuthFormatJWT
+		holder, challenge, domain, err = c.verifyDIDAuthJWT(vpJWT)
+	} else {
+		holder, challenge, domain, err = c.verifyDIDAuthLD(presentation)
+	}
+
+	notVerified := func(err error) *VerifyDIDAuthResponseResponse {
+		return &VerifyDIDAuthResponseResponse{DID: holder, Format: format, Error: err.Error()}
+	}
+
+	if err != nil {
+		return notVerified(err), nil
+	}
+
+	err = c.didAuthChallenges.use(challenge, domain)
+	if errors.Is(err, errUnknownDIDAuthChallenge) || errors.Is(err, errInvalidDIDAuthChallenge) {
+		return notVerified(err), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifyDIDAuthResponseResponse{Verified: true, DID: holder, Domain: domain, Format: format}, nil
+}
+
+// verifyDIDAuthJWT verifies JWT presentation and returns its holder, challenge and domain.
+func (c *Command) verifyDIDAuthJWT(vpJWT string) (string, string, string, error) {
+	resp, err := c.verifyJWT(&VerifyJWTRequest{JWT: vpJWT, Purpose: signaturePurposeAuthentication})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	claims := &struct {
+		jwt.Claims
+		Nonce        string          `json:"nonce"`
+		Presentation json.RawMessage `json:"vp"`
+	}{}
+
+	err = json.Unmarshal(resp.Claims, claims)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid JWT presentation claims : %w", err)
+	}
+
+	if claims.Issuer != resp.DID {
+		return "", "", "", fmt.Errorf("JWT presentation isn't issued by the signer %s", resp.DID)
+	}
+
+	if len(claims.Audience) != 1 {
+		return "", "", "", errors.New("JWT presentation must have a single audience")
+	}
+
+	_, err = verifiable.ParsePresentation(claims.Presentation, verifiable.WithPresDisabledProofCheck(),
+		verifiable.WithPresJSONLDDocumentLoader(c.documentLoader))
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid JWT presentation : %w", err)
+	}
+
+	return resp.DID, claims.Nonce, claims.Audience[0], nil
+}
+
+// verifyDIDAuthLD verifies JSON-LD presentation and returns its holder, challenge and domain.
+func (c *Command) verifyDIDAuthLD(presentation []byte) (string, string, string, error) {
+	vp, err := verifiable.ParsePresentation(presentation,
+		verifiable.WithPresPublicKeyFetcher(verifiable.NewVDRKeyResolver(c.cachingVDR()).PublicKeyFetcher()),
+		verifiable.WithPresEmbeddedSignatureSuites(
+			ed25519signature2018.New(suite.WithVerifier(ed25519signature2018.NewPublicKeyVerifier())),
+			jsonwebsignature2020.New(suite.WithVerifier(jsonwebsignature2020.NewPublicKeyVerifier())),
+		),
+		verifiable.WithPresJSONLDDocumentLoader(c.documentLoader),
+	)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid presentation : %w", err)
+	}
+
+	if len(vp.Proofs) != 1 {
+		return "", "", "", errors.New("presentation must have a single proof")
+	}
+
+	proof := vp.Proofs[0]
+
+	purpose, _ := proof["proofPurpose"].(string)    // nolint:errcheck
+	vmID, _ := proof["verificationMethod"].(string) // nolint:errcheck
+	challenge, _ := proof["challenge"].(string)     // nolint:errcheck
+	domain, _ := proof["domain"].(string)           // nolint:errcheck
+
+	if purpose != signaturePurposeAuthentication {
+		return "", "", "", fmt.Errorf("unexpected proof purpose %s", purpose)
+	}
+
+	if vp.Holder == "" || didOfURL(vmID) != vp.Holder {
+		return "", "", "", fmt.Errorf("presentation isn't signed by its holder %s", vp.Holder)
+	}
+
+	docResolution, err := c.resolve(vp.Holder)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to resolve DID %s : %w", vp.Holder, err)
+	}
+
+	_, err = relationshipVerificationMethod(docResolution.DIDDocument, signaturePurposeAuthentication, vmID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return vp.Holder, challenge, domain, nil
+}
+
+type didAuthChallenge struct {
+	Domain         string    `json:"domain"`
+	ExpirationDate time.Time `json:"expirationDate"`
+}
+
+// didAuthChallengeStore keeps DID Auth challenges until they're used or expire.
+type didAuthChallengeStore struct {
+	store storage.Store
+	mutex sync.Mutex
+}
+
+func (s *didAuthChallengeStore) create(domain string, expires time.Time) (*CreateDIDAuthChallengeResponse, error) {
+	nonce := make([]byte, didAuthChallengeSize)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create challenge : %w", err)
+	}
+
+	data, err := json.Marshal(&didAuthChallenge{Domain: domain, ExpirationDate: expires})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal challenge : %w", err)
+	}
+
+	err = s.purgeExpired()
+	if err != nil {
+		logger.Warnf("failed to purge expired DID Auth challenges : %s", err)
+	}
+
+	challenge := base64.RawURLEncoding.EncodeToString(nonce)
+
+	err = s.store.Put(didAuthChallengeKeyPrefix+challenge, data, storage.Tag{Name: didAuthChallengeTag})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save challenge : %w", err)
+	}
+
+	return &CreateDIDAuthChallengeResponse{Challenge: challenge, Domain: domain, ExpirationDate: expires}, nil
+}
+
+// use removes challenge from the store, it fails if challenge is unknown, of another domain or expired.
+func (s *didAuthChallengeStore) use(challenge, domain string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if challenge == "" {
+		return errUnknownDIDAuthChallenge
+	}
+
+	data, err := s.store.Get(didAuthChallengeKeyPrefix + challenge)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return errUnknownDIDAuthChallenge
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get challenge : %w", err)
+	}
+
+	record := &didAuthChallenge{}
+
+	err = json.Unmarshal(data, record)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal challenge : %w", err)
+	}
+
+	if record.Domain != domain {
+		return fmt.Errorf("%w : domain %s isn't %s", errInvalidDIDAuthChallenge, domain, record.Domain)
+	}
+
+	err = s.store.Delete(didAuthChallengeKeyPrefix + challenge)
+	if err != nil {
+		return fmt.Errorf("failed to delete challenge : %w", err)
+	}
+
+	if !time.Now().Before(record.ExpirationDate) {
+		return fmt.Errorf("%w : challenge has expired", errInvalidDIDAuthChallenge)
+	}
+
+	return nil
+}
+
+// purgeExpired removes expired challenges which were never answered.
+func (s *didAuthChallengeStore) purgeExpired() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	iter, err := s.store.Query(didAuthChallengeTag)
+	if err != nil {
+		return fmt.Errorf("failed to query challenges : %w", err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("failed to close iterator : %s", e)
+		}
+	}()
+
+	var expired []string
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("failed to get next challenge : %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		key, err := iter.Key()
+		if err != nil {
+			return fmt.Errorf("failed to get challenge key : %w", err)
+		}
+
+		data, err := iter.Value()
+		if err != nil {
+			return fmt.Errorf("failed to get challenge : %w", err)
+		}
+
+		record := &didAuthChallenge{}
+
+		if json.Unmarshal(data, record) != nil || !time.Now().Before(record.ExpirationDate) {
+			expired = append(expired, key)
+		}
+	}
+
+	for _, key := range expired {
+		err = s.store.Delete(key)
+		if err != nil {
+			return fmt.Errorf("failed to delete challenge : %w", err)
+		}
+	}
+
+	return nil
+}
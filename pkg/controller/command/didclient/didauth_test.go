@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+)
+
+func TestCommand_DIDAuth(t *testing.T) {
+	const domain = "https://rp.example.com"
+
+	c, didDoc := newCommandWithWebDID(t,
+		KeySpec{ID: "auth", KeyType: ed25519KeyType, Purposes: []string{doc.KeyPurposeAuthentication}},
+		KeySpec{ID: "auth2", KeyType: p256KeyType, Purposes: []string{doc.KeyPurposeAuthentication}},
+		KeySpec{ID: "assertion", KeyType: ed25519KeyType, Purposes: []string{doc.KeyPurposeAssertionMethod}},
+	)
+
+	execute := func(t *testing.T, cmd func(rw io.Writer, req io.Reader) command.Error, request,
+		response interface{}) command.Error {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd(&b, bytes.NewBuffer(req))
+		if cmdErr == nil {
+			require.NoError(t, json.Unmarshal(b.Bytes(), response))
+		}
+
+		return cmdErr
+	}
+
+	createChallenge := func(t *testing.T) string {
+		t.Helper()
+
+		resp := &CreateDIDAuthChallengeResponse{}
+		require.NoError(t, execute(t, c.CreateDIDAuthChallenge, &CreateDIDAuthChallengeRequest{Domain: domain}, resp))
+		require.Equal(t, domain, resp.Domain)
+		require.True(t, resp.ExpirationDate.After(time.Now()))
+
+		return resp.Challenge
+	}
+
+	respond := func(t *testing.T, request *RespondDIDAuthChallengeRequest) json.RawMessage {
+		t.Helper()
+
+		resp := &RespondDIDAuthChallengeResponse{}
+		require.NoError(t, execute(t, c.RespondDIDAuthChallenge, request, resp))
+
+		return resp.Presentation
+	}
+
+	verify := func(t *testing.T, presentation json.RawMessage) *VerifyDIDAuthResponseResponse {
+		t.Helper()
+
+		resp := &VerifyDIDAuthResponseResponse{}
+		require.NoError(t, execute(t, c.VerifyDIDAuthResponse, &VerifyDIDAuthResponseRequest{
+			Presentation: presentation,
+		}, resp))
+
+		return resp
+	}
+
+	for _, test := range []struct {
+		name   string
+		format string
+		keyID  string
+	}{
+		{name: "test JWT response", keyID: "#auth"},
+		{name: "test JWT response with EC key", keyID: "#auth2"},
+		{name: "test JSON-LD response", format: didAuthFormatJSONLD},
+		{name: "test JSON-LD response with EC key", format: didAuthFormatJSONLD, keyID: "#auth2"},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			challenge := createChallenge(t)
+
+			presentation := respond(t, &RespondDIDAuthChallengeRequest{
+				DID: didDoc.ID, KeyID: test.keyID, Challenge: challenge, Domain: domain, Format: test.format,
+			})
+
+			resp := verify(t, presentation)
+			require.True(t, resp.Verified, resp.Error)
+			require.Equal(t, didDoc.ID, resp.DID)
+			require.Equal(t, domain, resp.Domain)
+
+			// challenge can't be reused.
+			resp = verify(t, presentation)
+			require.False(t, resp.Verified)
+			require.Contains(t, resp.Error, errUnknownDIDAuthChallenge.Error())
+		})
+	}
+
+	t.Run("test response for another domain", func(t *testing.T) {
+		challenge := createChallenge(t)
+
+		resp := verify(t, respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: "https://other.example.com",
+		}))
+		require.False(t, resp.Verified)
+		require.Contains(t, resp.Error, "domain")
+
+		// challenge is still valid for its domain.
+		resp = verify(t, respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: domain, Format: didAuthFormatJSONLD,
+		}))
+		require.True(t, resp.Verified, resp.Error)
+	})
+
+	t.Run("test response with invalid signature", func(t *testing.T) {
+		challenge := createChallenge(t)
+
+		var vpJWT string
+		require.NoError(t, json.Unmarshal(respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: domain,
+		}), &vpJWT))
+
+		parts := strings.Split(vpJWT, ".")
+
+		var other string
+		require.NoError(t, json.Unmarshal(respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: "other", Domain: domain,
+		}), &other))
+
+		tampered, err := json.Marshal(parts[0] + "." + strings.Split(other, ".")[1] + "." + parts[2])
+		require.NoError(t, err)
+
+		resp := verify(t, tampered)
+		require.False(t, resp.Verified)
+		require.Contains(t, resp.Error, "invalid signature")
+
+		// challenge isn't used up by invalid response.
+		resp = verify(t, respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: domain,
+		}))
+		require.True(t, resp.Verified, resp.Error)
+	})
+
+	t.Run("test expired challenge", func(t *testing.T) {
+		challenge := createChallenge(t)
+
+		data, err := json.Marshal(&didAuthChallenge{Domain: domain, ExpirationDate: time.Now().Add(-time.Second)})
+		require.NoError(t, err)
+		require.NoError(t, c.didAuthChallenges.store.Put(didAuthChallengeKeyPrefix+challenge, data))
+
+		resp := verify(t, respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: domain,
+		}))
+		require.False(t, resp.Verified)
+		require.Contains(t, resp.Error, "expired")
+	})
+
+	t.Run("test expired challenges are purged", func(t *testing.T) {
+		challenge := createChallenge(t)
+
+		data, err := json.Marshal(&didAuthChallenge{Domain: domain, ExpirationDate: time.Now().Add(-time.Second)})
+		require.NoError(t, err)
+		require.NoError(t, c.didAuthChallenges.store.Put(didAuthChallengeKeyPrefix+challenge, data,
+			storage.Tag{Name: didAuthChallengeTag}))
+
+		createChallenge(t)
+
+		resp := verify(t, respond(t, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, Challenge: challenge, Domain: domain,
+		}))
+		require.False(t, resp.Verified)
+		require.Contains(t, resp.Error, errUnknownDIDAuthChallenge.Error())
+	})
+
+	t.Run("test response signed by assertion key", func(t *testing.T) {
+		cmdErr := execute(t, c.RespondDIDAuthChallenge, &RespondDIDAuthChallengeRequest{
+			DID: didDoc.ID, KeyID: "#assertion", Challenge: "challenge", Domain: domain,
+		}, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDAuthErrorCode, cmdErr.Code())
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+
+		for _, test := range []struct {
+			cmd     func(rw io.Writer, req io.Reader) command.Error
+			request interface{}
+		}{
+			{cmd: c.CreateDIDAuthChallenge, request: &CreateDIDAuthChallengeRequest{}},
+			{cmd: c.CreateDIDAuthChallenge, request: &CreateDIDAuthChallengeRequest{Domain: domain, ExpirationDate: &past}},
+			{cmd: c.RespondDIDAuthChallenge, request: &RespondDIDAuthChallengeRequest{Challenge: "c", Domain: domain}},
+			{cmd: c.RespondDIDAuthChallenge, request: &RespondDIDAuthChallengeRequest{DID: didDoc.ID, Domain: domain}},
+			{cmd: c.RespondDIDAuthChallenge, request: &RespondDIDAuthChallengeRequest{DID: didDoc.ID, Challenge: "c"}},
+			{
+				cmd:     c.RespondDIDAuthChallenge,
+				request: &RespondDIDAuthChallengeRequest{DID: didDoc.ID, Challenge: "c", Domain: domain, Format: "x"},
+			},
+			{cmd: c.VerifyDIDAuthResponse, request: &VerifyDIDAuthResponseRequest{}},
+		} {
+			cmdErr := execute(t, test.cmd, test.request, nil)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+}
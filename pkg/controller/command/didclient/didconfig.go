@@ -0,0 +1,739 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	_ "embed" //nolint:gci // required for go:embed
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ldcontext"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	didConfigurationContext     = "https://identity.foundation/.well-known/did-configuration/v1"
+	didConfigurationPath        = "/.well-known/did-configuration.json"
+	domainLinkageCredentialType = "DomainLinkageCredential"
+	linkedDomainsServiceType    = "LinkedDomains"
+	credentialsContext          = "https://www.w3.org/2018/credentials/v1"
+	jws2020Context              = "https://w3id.org/security/suites/jws-2020/v1"
+
+	// DID configuration credential formats.
+	didConfigurationFormatJWT    = "jwt"
+	didConfigurationFormatJSONLD = "jsonld"
+
+	// default validity of domain linkage credential.
+	defaultDomainLinkageValidity = 365 * 24 * time.Hour
+)
+
+// nolint:gochecknoglobals // embedded DID configuration context
+var (
+	//go:embed contexts/did-configuration-v1.jsonld
+	didConfigurationContextDocument []byte
+)
+
+// didConfiguration well-known DID configuration resource.
+type didConfiguration struct {
+	Context    string            `json:"@context"`
+	LinkedDIDs []json.RawMessage `json:"linked_dids"`
+}
+
+// CreateDIDConfiguration creates Domain Linkage Credential binding DID to the origin, signed by a key of the DID
+// kept in the agent KMS, and returns it along with the DID configuration resource to be published at
+// '/.well-known/did-configuration.json' of the origin.
+func (c *Command) CreateDIDConfiguration(rw io.Writer, req io.Reader) command.Error {
+	var request CreateDIDConfigurationRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateDIDConfigurationRequest(request.DID, request.Origin)
+	if err == nil && request.Format != "" && request.Format != didConfigurationFormatJWT &&
+		request.Format != didConfigurationFormatJSONLD {
+		err = fmt.Errorf(errUnsupportedDIDConfigFormat, request.Format)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.createDIDConfiguration(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDConfigurationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, CreateDIDConfigurationCommandMethod, successString)
+
+	return nil
+}
+
+// VerifyDIDConfiguration verifies that the origin is linked to the DID. The DID must have a LinkedDomains
+// service for the origin and the DID configuration resource, either given or fetched from the origin, must
+// contain a valid Domain Linkage Credential of the DID for the origin.
+func (c *Command) VerifyDIDConfiguration(rw io.Writer, req io.Reader) command.Error {
+	var request VerifyDIDConfigurationRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateDIDConfigurationRequest(request.DID, request.Origin)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.verifyDIDConfiguration(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyDIDConfigurationCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDConfigurationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyDIDConfigurationCommandMethod, successString)
+
+	return nil
+}
+
+func validateDIDConfigurationRequest(didID, origin string) error {
+	if _, err := did.Parse(didID); err != nil {
+		return fmt.Errorf(errInvalidDID)
+	}
+
+	if _, err := parseOrigin(origin); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseOrigin returns web origin in its serialized form, the origin can't have path, query or fragment.
+func parseOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") ||
+		strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf(errInvalidOrigin, origin)
+	}
+
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func (c *Command) createDIDConfiguration(request *CreateDIDConfigurationRequest) (*CreateDIDConfigurationResponse,
+	error) {
+	origin, err := parseOrigin(request.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	docResolution, err := c.resolve(request.DID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s : %w", request.DID, err)
+	}
+
+	vm, err := signingVerificationMethod(docResolution.DIDDocument, request.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := c.newVerificationMethodSigner(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(signer.vmID, "#") {
+		signer.vmID = request.DID + signer.vmID
+	}
+
+	issued := time.Now().UTC().Truncate(time.Second)
+
+	expires := issued.Add(defaultDomainLinkageValidity)
+	if request.ExpirationDate != nil {
+		expires = request.ExpirationDate.UTC()
+	}
+
+	vc := &verifiable.Credential{
+		Context: []string{credentialsContext, didConfigurationContext},
+		Types:   []string{verifiable.VCType, domainLinkageCredentialType},
+		Issuer:  verifiable.Issuer{ID: request.DID},
+		Issued:  util.NewTime(issued),
+		Expired: util.NewTime(expires),
+		Subject: verifiable.Subject{
+			ID:           request.DID,
+			CustomFields: verifiable.CustomFields{"origin": origin},
+		},
+	}
+
+	var credential json.RawMessage
+
+	if request.Format == didConfigurationFormatJSONLD {
+		credential, err = c.signDomainLinkageLD(vc, signer)
+	} else {
+		credential, err = signDomainLinkageJWT(vc, signer)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	configuration, err := json.Marshal(&didConfiguration{
+		Context:    didConfigurationContext,
+		LinkedDIDs: []json.RawMessage{credential},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID configuration : %w", err)
+	}
+
+	return &CreateDIDConfigurationResponse{
+		DIDConfiguration:        configuration,
+		DomainLinkageCredential: credential,
+	}, nil
+}
+
+func signDomainLinkageJWT(vc *verifiable.Credential, signer *verificationMethodSigner) (json.RawMessage, error) {
+	claims, err := vc.JWTClaims(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWT claims of domain linkage credential : %w", err)
+	}
+
+	token, err := jwt.NewSigned(claims, jose.Headers{
+		jose.HeaderType:  jwt.TypeJWT,
+		jose.HeaderKeyID: signer.vmID,
+	}, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign domain linkage credential : %w", err)
+	}
+
+	serialized, err := token.Serialize(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize domain linkage credential : %w", err)
+	}
+
+	return json.Marshal(serialized)
+}
+
+func (c *Command) signDomainLinkageLD(vc *verifiable.Credential,
+	signer *verificationMethodSigner) (json.RawMessage, error) {
+	ldpContext := linkedDataProofContext(signer, signaturePurposeAssertionMethod)
+
+	if signer.vmType == jsonWebKey2020 {
+		vc.Context = append(vc.Context, jws2020Context)
+	}
+
+	err := vc.AddLinkedDataProof(ldpContext, jsonld.WithDocumentLoader(c.documentLoader))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign domain linkage credential : %w", err)
+	}
+
+	return vc.MarshalJSON()
+}
+
+// linkedDataProofContext returns context of linked data proof signed by the signer for the purpose, documents
+// signed by JsonWebKey2020 keys need the JWS 2020 context.
+func linkedDataProofContext(signer *verificationMethodSigner, purpose string) *verifiable.LinkedDataProofContext {
+	ldpContext := &verifiable.LinkedDataProofContext{
+		SignatureType:           "Ed25519Signature2018",
+		Suite:                   ed25519signature2018.New(suite.WithSigner(signer)),
+		SignatureRepresentation: verifiable.SignatureJWS,
+		VerificationMethod:      signer.vmID,
+		Purpose:                 purpose,
+	}
+
+	if signer.vmType == jsonWebKey2020 {
+		ldpContext.SignatureType = "JsonWebSignature2020"
+		ldpContext.Suite = jsonwebsignature2020.New(suite.WithSigner(signer))
+	}
+
+	return ldpContext
+}
+
+func (c *Command) verifyDIDConfiguration(request *VerifyDIDConfigurationRequest) (*VerifyDIDConfigurationResponse,
+	error) {
+	origin, err := parseOrigin(request.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := request.DIDConfiguration
+	if len(configuration) == 0 {
+		configuration, err = c.fetchDIDConfiguration(origin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := &didConfiguration{}
+
+	err = json.Unmarshal(configuration, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DID configuration : %w", err)
+	}
+
+	docResolution, err := c.resolve(request.DID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s : %w", request.DID, err)
+	}
+
+	notVerified := func(err error) *VerifyDIDConfigurationResponse {
+		return &VerifyDIDConfigurationResponse{Error: err.Error()}
+	}
+
+	if config.Context != didConfigurationContext {
+		return notVerified(fmt.Errorf("unexpected DID configuration context %s", config.Context)), nil
+	}
+
+	if !hasLinkedDomain(docResolution.DIDDocument, origin) {
+		return notVerified(fmt.Errorf("DID %s has no %s service for origin %s",
+			request.DID, linkedDomainsServiceType, origin)), nil
+	}
+
+	verifyErr := fmt.Errorf("no domain linkage credential of DID %s for origin %s", request.DID, origin)
+
+	for _, linkedDID := range config.LinkedDIDs {
+		format, vcData := didConfigurationFormatJSONLD, []byte(linkedDID)
+
+		var jwtVC string
+		if json.Unmarshal(linkedDID, &jwtVC) == nil {
+			format, vcData = didConfigurationFormatJWT, []byte(jwtVC)
+		}
+
+		// skip credentials of other DIDs and origins before resolving keys to check the proof.
+		vc, err := verifiable.ParseCredential(vcData, verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(c.documentLoader))
+		if err != nil || checkDomainLinkage(vc, request.DID, origin) != nil {
+			continue
+		}
+
+		err = c.verifyDomainLinkageProof(vcData, format, request.DID, docResolution.DIDDocument)
+		if err != nil {
+			verifyErr = err
+
+			continue
+		}
+
+		return &VerifyDIDConfigurationResponse{Verified: true, Format: format}, nil
+	}
+
+	return notVerified(verifyErr), nil
+}
+
+func (c *Command) fetchDIDConfiguration(origin string) ([]byte, error) {
+	resp, err := c.httpClient.Get(origin + didConfigurationPath) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DID configuration from %s : %w", origin, err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("failed to close response body : %s", e)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DID configuration from %s : %w", origin, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch DID configuration from %s : status %d", origin, resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// verifyDomainLinkageProof verifies proof of domain linkage credential, the credential must be signed by an
+// assertion method of its issuer which is the linked DID.
+func (c *Command) verifyDomainLinkageProof(vcData []byte, format, didID string, didDoc *did.Doc) error {
+	if format == didConfigurationFormatJWT {
+		return c.verifyDomainLinkageJWT(string(vcData), didID, didDoc)
+	}
+
+	vc, err := verifiable.ParseCredential(vcData,
+		verifiable.WithPublicKeyFetcher(verifiable.NewVDRKeyResolver(c.cachingVDR()).PublicKeyFetcher()),
+		verifiable.WithEmbeddedSignatureSuites(
+			ed25519signature2018.New(suite.WithVerifier(ed25519signature2018.NewPublicKeyVerifier())),
+			jsonwebsignature2020.New(suite.WithVerifier(jsonwebsignature2020.NewPublicKeyVerifier())),
+		),
+		verifiable.WithJSONLDDocumentLoader(c.documentLoader),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid domain linkage credential : %w", err)
+	}
+
+	if len(vc.Proofs) == 0 {
+		return errors.New("invalid domain linkage credential : proof is missing")
+	}
+
+	for _, proof := range vc.Proofs {
+		vmID, _ := proof["verificationMethod"].(string) // nolint:errcheck
+
+		err = checkDomainLinkageSigner(vc.Issuer.ID, vmID, didID, didDoc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyDomainLinkageJWT verifies signature of JWT domain linkage credential, the JWT verifier of credential
+// parser supports EdDSA only while domain linkage credentials are commonly signed by EC keys as well.
+func (c *Command) verifyDomainLinkageJWT(vcJWT, didID string, didDoc *did.Doc) error {
+	fetcher := verifiable.NewVDRKeyResolver(c.cachingVDR()).PublicKeyFetcher()
+
+	jwtVerifier := jose.SignatureVerifierFunc(func(headers jose.Headers, payload, signingInput,
+		signature []byte) error {
+		claims := &jwt.Claims{}
+
+		err := json.Unmarshal(payload, claims)
+		if err != nil {
+			return fmt.Errorf("read JWT claims : %w", err)
+		}
+
+		alg, _ := headers.Algorithm() // nolint:errcheck
+		kid, _ := headers.KeyID()     // nolint:errcheck
+
+		if strings.HasPrefix(kid, "#") {
+			kid = claims.Issuer + kid
+		}
+
+		err = checkDomainLinkageSigner(claims.Issuer, kid, didID, didDoc)
+		if err != nil {
+			return err
+		}
+
+		sigVerifier, err := algSignatureVerifier(alg)
+		if err != nil {
+			return err
+		}
+
+		pubKey, err := fetcher(claims.Issuer, kid)
+		if err != nil {
+			return err
+		}
+
+		return sigVerifier.Verify(pubKey, signingInput, signature)
+	})
+
+	_, err := jwt.Parse(vcJWT, jwt.WithSignatureVerifier(jwtVerifier))
+	if err != nil {
+		return fmt.Errorf("invalid domain linkage credential : %w", err)
+	}
+
+	return nil
+}
+
+// checkDomainLinkageSigner checks that the key which signed domain linkage credential is an assertion method of
+// the linked DID and the DID is the issuer of the credential.
+func checkDomainLinkageSigner(issuer, vmID, didID string, didDoc *did.Doc) error {
+	if issuer != didID || didOfURL(vmID) != issuer {
+		return fmt.Errorf("invalid domain linkage credential : credential isn't signed by its issuer %s", issuer)
+	}
+
+	_, err := relationshipVerificationMethod(didDoc, signaturePurposeAssertionMethod, vmID)
+	if err != nil {
+		return fmt.Errorf("invalid domain linkage credential : %w", err)
+	}
+
+	return nil
+}
+
+type signatureVerifier interface {
+	Verify(pubKey *verifier.PublicKey, msg, signature []byte) error
+}
+
+func algSignatureVerifier(alg string) (signatureVerifier, error) {
+	switch alg {
+	case "EdDSA":
+		return verifier.NewEd25519SignatureVerifier(), nil
+	case "ES256":
+		return verifier.NewECDSAES256SignatureVerifier(), nil
+	case "ES384":
+		return verifier.NewECDSAES384SignatureVerifier(), nil
+	case "ES512":
+		return verifier.NewECDSAES521SignatureVerifier(), nil
+	default:
+		return nil, fmt.Errorf("unsupported JWS algorithm %s", alg)
+	}
+}
+
+// checkDomainLinkage checks that credential is domain linkage credential of DID for the origin.
+func checkDomainLinkage(vc *verifiable.Credential, didID, origin string) error {
+	if !containsString(vc.Types, domainLinkageCredentialType) {
+		return fmt.Errorf("credential isn't %s", domainLinkageCredentialType)
+	}
+
+	if vc.Issuer.ID != didID {
+		return fmt.Errorf("credential issuer %s isn't DID %s", vc.Issuer.ID, didID)
+	}
+
+	subjects, ok := vc.Subject.([]verifiable.Subject)
+	if !ok || len(subjects) != 1 || subjects[0].ID != didID {
+		return fmt.Errorf("credential subject isn't DID %s", didID)
+	}
+
+	subjectOrigin, _ := subjects[0].CustomFields["origin"].(string) // nolint:errcheck
+	if normalized, err := parseOrigin(subjectOrigin); err != nil || normalized != origin {
+		return fmt.Errorf("credential origin %s isn't %s", subjectOrigin, origin)
+	}
+
+	if vc.Expired == nil || !time.Now().Before(vc.Expired.Time) {
+		return errors.New("credential has expired")
+	}
+
+	return nil
+}
+
+// hasLinkedDomain checks whether DID document has LinkedDomains service for the origin, the service endpoint
+// is either an origin, a list of origins or an object listing origins.
+func hasLinkedDomain(didDoc *did.Doc, origin string) bool {
+	for i := range didDoc.Service {
+		if didDoc.Service[i].Type != linkedDomainsServiceType {
+			continue
+		}
+
+		endpoint, err := didDoc.Service[i].ServiceEndpoint.MarshalJSON()
+		if err != nil {
+			continue
+		}
+
+		var origins []string
+
+		var value interface{}
+
+		if json.Unmarshal(endpoint, &value) != nil {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			origins = append(origins, v)
+		case []interface{}:
+			origins = appendStrings(origins, v)
+		case map[string]interface{}:
+			list, _ := v["origins"].([]interface{}) // nolint:errcheck
+			origins = appendStrings(origins, list)
+		}
+
+		for _, o := range origins {
+			if normalized, err := parseOrigin(o); err == nil && normalized == origin {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func appendStrings(values []string, list []interface{}) []string {
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	return values
+}
+
+// signingVerificationMethod returns verification method with given ID or the first assertion method,
+// authentication or verification method of DID document.
+func signingVerificationMethod(didDoc *did.Doc, keyID string) (*did.VerificationMethod, error) {
+	var vm *did.VerificationMethod
+
+	if keyID != "" {
+		for i := range didDoc.VerificationMethod {
+			if fragment(didDoc.VerificationMethod[i].ID) == fragment(keyID) {
+				vm = &didDoc.VerificationMethod[i]
+			}
+		}
+
+		if vm == nil {
+			return nil, fmt.Errorf("key %s not found in DID %s", keyID, didDoc.ID)
+		}
+	}
+
+	for _, verifications := range [][]did.Verification{didDoc.AssertionMethod, didDoc.Authentication} {
+		if vm == nil && len(verifications) > 0 {
+			vm = &verifications[0].VerificationMethod
+		}
+	}
+
+	if vm == nil && len(didDoc.VerificationMethod) > 0 {
+		vm = &didDoc.VerificationMethod[0]
+	}
+
+	if vm == nil {
+		return nil, fmt.Errorf("DID %s has no signing key", didDoc.ID)
+	}
+
+	return vm, nil
+}
+
+// verificationMethodSigner signs with the KMS key of verification method.
+type verificationMethodSigner struct {
+	keyHandle interface{}
+	crypto    crypto.Crypto
+	alg       string
+	vmID      string
+	vmType    string
+}
+
+func (c *Command) newVerificationMethodSigner(vm *did.VerificationMethod) (*verificationMethodSigner, error) {
+	pubKey, keyType, alg, err := verificationMethodKey(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := localkms.CreateKID(pubKey, keyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KMS key ID of %s : %w", vm.ID, err)
+	}
+
+	keyHandle, err := c.keyManager.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key handle of %s : %w", vm.ID, err)
+	}
+
+	return &verificationMethodSigner{keyHandle: keyHandle, crypto: c.crypto, alg: alg, vmID: vm.ID, vmType: vm.Type},
+		nil
+}
+
+// verificationMethodKey returns public key of verification method along with its KMS key type and JWS algorithm.
+func verificationMethodKey(vm *did.VerificationMethod) ([]byte, kms.KeyType, string, error) {
+	switch vm.Type {
+	case ed25519VerificationKey2018:
+		return vm.Value, kms.ED25519Type, "EdDSA", nil
+	case jsonWebKey2020:
+		j := vm.JSONWebKey()
+		if j == nil {
+			return nil, "", "", fmt.Errorf("verification method %s has no JWK", vm.ID)
+		}
+
+		pubKey, err := j.PublicKeyBytes()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to get public key of %s : %w", vm.ID, err)
+		}
+
+		keyType, err := j.KeyType()
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to get key type of %s : %w", vm.ID, err)
+		}
+
+		alg, err := jwsAlgorithm(j.Crv)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		return pubKey, keyType, alg, nil
+	default:
+		return nil, "", "", fmt.Errorf("verification method type %s not supported for signing", vm.Type)
+	}
+}
+
+// Sign signs data with the key of verification method.
+func (s *verificationMethodSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.keyHandle)
+}
+
+// Headers provides JWS headers.
+func (s *verificationMethodSigner) Headers() jose.Headers {
+	return jose.Headers{jose.HeaderAlgorithm: s.alg}
+}
+
+// Alg returns signature algorithm.
+func (s *verificationMethodSigner) Alg() string {
+	return s.alg
+}
+
+func jwsAlgorithm(crv string) (string, error) {
+	switch crv {
+	case "Ed25519":
+		return "EdDSA", nil
+	case "P-256":
+		return "ES256", nil
+	case "P-384":
+		return "ES384", nil
+	case "P-521":
+		return "ES512", nil
+	default:
+		return "", fmt.Errorf("curve %s not supported for signing", crv)
+	}
+}
+
+// newDocumentLoader returns JSON-LD document loader with embedded contexts along with the DID configuration
+// context, contexts are kept in memory and aren't fetched from remote.
+func newDocumentLoader() (*ld.DocumentLoader, error) {
+	contextStore, err := ldstore.NewContextStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON-LD context store : %w", err)
+	}
+
+	remoteProviderStore, err := ldstore.NewRemoteProviderStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON-LD remote provider store : %w", err)
+	}
+
+	return ld.NewDocumentLoader(&ldStoreProvider{contextStore: contextStore, remoteProviderStore: remoteProviderStore},
+		ld.WithExtraContexts(ldcontext.Document{
+			URL:     didConfigurationContext,
+			Content: didConfigurationContextDocument,
+		}),
+	)
+}
+
+type ldStoreProvider struct {
+	contextStore        ldstore.ContextStore
+	remoteProviderStore ldstore.RemoteProviderStore
+}
+
+func (p *ldStoreProvider) JSONLDContextStore() ldstore.ContextStore {
+	return p.contextStore
+}
+
+func (p *ldStoreProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
+	return p.remoteProviderStore
+}
@@ -0,0 +1,472 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_DIDConfiguration(t *testing.T) {
+	const origin = "https://example.com"
+
+	newCommand := func(t *testing.T, keyType string, endpoint model.Endpoint) (*Command, *did.Doc) {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager, err = localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		c.crypto, err = tinkcrypto.New()
+		require.NoError(t, err)
+
+		request := &CreateDIDRequest{Method: didMethodWeb, Domain: "example.com", Keys: []KeySpec{{KeyType: keyType}}}
+		require.NoError(t, validateCreateDIDRequest(request))
+
+		didDoc, _, _, err := c.createWebDID(request)
+		require.NoError(t, err)
+
+		didDoc.Service = []did.Service{{ID: didDoc.ID + "#linked", Type: linkedDomainsServiceType,
+			ServiceEndpoint: endpoint}}
+
+		c.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				if didID != didDoc.ID {
+					return nil, vdrapi.ErrNotFound
+				}
+
+				return &did.DocResolution{DIDDocument: didDoc}, nil
+			},
+		}
+
+		return c, didDoc
+	}
+
+	createConfig := func(t *testing.T, c *Command, request *CreateDIDConfigurationRequest) (
+		*CreateDIDConfigurationResponse, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDIDConfiguration(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp := &CreateDIDConfigurationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp, nil
+	}
+
+	verifyConfig := func(t *testing.T, c *Command, request *VerifyDIDConfigurationRequest) (
+		*VerifyDIDConfigurationResponse, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.VerifyDIDConfiguration(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp := &VerifyDIDConfigurationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp, nil
+	}
+
+	serve := func(t *testing.T, c *Command, configuration *[]byte) string {
+		t.Helper()
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != didConfigurationPath {
+				rw.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			_, err := rw.Write(*configuration)
+			require.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		c.httpClient = server.Client()
+
+		return server.URL
+	}
+
+	t.Run("test JWT domain linkage hosted by origin", func(t *testing.T) {
+		c, didDoc := newCommand(t, ed25519KeyType, model.NewDIDCommV1Endpoint(origin))
+
+		resp, cmdErr := createConfig(t, c, &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: origin})
+		require.NoError(t, cmdErr)
+
+		var jwtVC string
+		require.NoError(t, json.Unmarshal(resp.DomainLinkageCredential, &jwtVC))
+		require.Len(t, strings.Split(jwtVC, "."), 3)
+
+		config := &didConfiguration{}
+		require.NoError(t, json.Unmarshal(resp.DIDConfiguration, config))
+		require.Equal(t, didConfigurationContext, config.Context)
+		require.Len(t, config.LinkedDIDs, 1)
+
+		hosted := []byte(resp.DIDConfiguration)
+		serverURL := serve(t, c, &hosted)
+
+		// the DID is linked to origin of the credential only.
+		didDoc.Service[0].ServiceEndpoint = model.NewDIDCommV1Endpoint(serverURL)
+		c.invalidateDIDCache(didDoc.ID)
+
+		result, cmdErr := verifyConfig(t, c, &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: serverURL})
+		require.NoError(t, cmdErr)
+		require.False(t, result.Verified)
+		require.Contains(t, result.Error, "no domain linkage credential")
+
+		resp, cmdErr = createConfig(t, c, &CreateDIDConfigurationRequest{
+			DID:    didDoc.ID,
+			Origin: serverURL,
+			KeyID:  didDoc.VerificationMethod[0].ID,
+		})
+		require.NoError(t, cmdErr)
+
+		hosted = resp.DIDConfiguration
+
+		result, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: serverURL})
+		require.NoError(t, cmdErr)
+		require.True(t, result.Verified, result.Error)
+		require.Equal(t, didConfigurationFormatJWT, result.Format)
+	})
+
+	t.Run("test JSON-LD domain linkage", func(t *testing.T) {
+		for _, keyType := range []string{ed25519KeyType, p256KeyType} {
+			c, didDoc := newCommand(t, keyType,
+				model.NewDIDCoreEndpoint(map[string]interface{}{"origins": []interface{}{origin + "/"}}))
+
+			resp, cmdErr := createConfig(t, c, &CreateDIDConfigurationRequest{
+				DID:    didDoc.ID,
+				Origin: origin,
+				Format: didConfigurationFormatJSONLD,
+			})
+			require.NoError(t, cmdErr)
+			require.Contains(t, string(resp.DomainLinkageCredential), `"proof"`)
+
+			result, cmdErr := verifyConfig(t, c, &VerifyDIDConfigurationRequest{
+				DID:              didDoc.ID,
+				Origin:           origin,
+				DIDConfiguration: resp.DIDConfiguration,
+			})
+			require.NoError(t, cmdErr)
+			require.True(t, result.Verified, result.Error)
+			require.Equal(t, didConfigurationFormatJSONLD, result.Format)
+
+			// tampered credential.
+			tampered := bytes.Replace(resp.DIDConfiguration,
+				[]byte(`"expirationDate":"2`), []byte(`"expirationDate":"3`), 1)
+
+			result, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{
+				DID:              didDoc.ID,
+				Origin:           origin,
+				DIDConfiguration: tampered,
+			})
+			require.NoError(t, cmdErr)
+			require.False(t, result.Verified)
+			require.Contains(t, result.Error, "invalid domain linkage credential")
+		}
+	})
+
+	t.Run("test JWT domain linkage signed by P-256 key", func(t *testing.T) {
+		c, didDoc := newCommand(t, p256KeyType, model.NewDIDCommV1Endpoint(origin))
+
+		resp, cmdErr := createConfig(t, c, &CreateDIDConfigurationRequest{
+			DID:    didDoc.ID,
+			Origin: origin,
+			Format: didConfigurationFormatJWT,
+		})
+		require.NoError(t, cmdErr)
+
+		result, cmdErr := verifyConfig(t, c, &VerifyDIDConfigurationRequest{
+			DID:              didDoc.ID,
+			Origin:           origin,
+			DIDConfiguration: resp.DIDConfiguration,
+		})
+		require.NoError(t, cmdErr)
+		require.True(t, result.Verified, result.Error)
+		require.Equal(t, didConfigurationFormatJWT, result.Format)
+
+		var jwtVC string
+		require.NoError(t, json.Unmarshal(resp.DomainLinkageCredential, &jwtVC))
+
+		parts := strings.Split(jwtVC, ".")
+		parts[2] = strings.Repeat("A", len(parts[2]))
+
+		tampered, err := json.Marshal(map[string]interface{}{
+			"@context":    didConfigurationContext,
+			"linked_dids": []string{strings.Join(parts, ".")},
+		})
+		require.NoError(t, err)
+
+		result, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{
+			DID:              didDoc.ID,
+			Origin:           origin,
+			DIDConfiguration: tampered,
+		})
+		require.NoError(t, cmdErr)
+		require.False(t, result.Verified)
+		require.Contains(t, result.Error, "invalid domain linkage credential")
+	})
+
+	t.Run("test domain linkage signed by key of other DID", func(t *testing.T) {
+		c, didDoc := newCommand(t, ed25519KeyType,
+			model.NewDIDCoreEndpoint(map[string]interface{}{"origins": []interface{}{origin}}))
+
+		request := &CreateDIDRequest{Method: didMethodWeb, Domain: "other.com", Keys: []KeySpec{{KeyType: ed25519KeyType}}}
+		require.NoError(t, validateCreateDIDRequest(request))
+
+		otherDoc, _, _, err := c.createWebDID(request)
+		require.NoError(t, err)
+
+		c.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+				for _, doc := range []*did.Doc{didDoc, otherDoc} {
+					if didID == doc.ID {
+						return &did.DocResolution{DIDDocument: doc}, nil
+					}
+				}
+
+				return nil, vdrapi.ErrNotFound
+			},
+		}
+
+		vm, err := signingVerificationMethod(otherDoc, "")
+		require.NoError(t, err)
+
+		signer, err := c.newVerificationMethodSigner(vm)
+		require.NoError(t, err)
+
+		signer.vmID = otherDoc.ID + "#" + fragment(signer.vmID)
+
+		newCredential := func() *verifiable.Credential {
+			return &verifiable.Credential{
+				Context: []string{credentialsContext, didConfigurationContext},
+				Types:   []string{verifiable.VCType, domainLinkageCredentialType},
+				Issuer:  verifiable.Issuer{ID: didDoc.ID},
+				Issued:  util.NewTime(time.Now().UTC().Truncate(time.Second)),
+				Expired: util.NewTime(time.Now().UTC().Add(time.Hour).Truncate(time.Second)),
+				Subject: verifiable.Subject{
+					ID:           didDoc.ID,
+					CustomFields: verifiable.CustomFields{"origin": origin},
+				},
+			}
+		}
+
+		ldCredential, err := c.signDomainLinkageLD(newCredential(), signer)
+		require.NoError(t, err)
+
+		jwtCredential, err := signDomainLinkageJWT(newCredential(), signer)
+		require.NoError(t, err)
+
+		for _, credential := range []json.RawMessage{ldCredential, jwtCredential} {
+			configuration, err := json.Marshal(&didConfiguration{
+				Context:    didConfigurationContext,
+				LinkedDIDs: []json.RawMessage{credential},
+			})
+			require.NoError(t, err)
+
+			result, cmdErr := verifyConfig(t, c, &VerifyDIDConfigurationRequest{
+				DID:              didDoc.ID,
+				Origin:           origin,
+				DIDConfiguration: configuration,
+			})
+			require.NoError(t, cmdErr)
+			require.False(t, result.Verified)
+			require.Contains(t, result.Error, "credential isn't signed by its issuer")
+		}
+	})
+
+	t.Run("test not verified", func(t *testing.T) {
+		c, didDoc := newCommand(t, ed25519KeyType, model.NewDIDCommV1Endpoint("https://other.com"))
+
+		expired := time.Now().Add(-time.Hour)
+
+		resp, cmdErr := createConfig(t, c, &CreateDIDConfigurationRequest{
+			DID:            didDoc.ID,
+			Origin:         origin,
+			ExpirationDate: &expired,
+		})
+		require.NoError(t, cmdErr)
+
+		request := &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: origin, DIDConfiguration: resp.DIDConfiguration}
+
+		result, cmdErr := verifyConfig(t, c, request)
+		require.NoError(t, cmdErr)
+		require.False(t, result.Verified)
+		require.Contains(t, result.Error, "has no LinkedDomains service for origin")
+
+		didDoc.Service[0].ServiceEndpoint = model.NewDIDCoreEndpoint([]interface{}{origin})
+		c.invalidateDIDCache(didDoc.ID)
+
+		result, cmdErr = verifyConfig(t, c, request)
+		require.NoError(t, cmdErr)
+		require.False(t, result.Verified)
+		require.Contains(t, result.Error, "no domain linkage credential")
+
+		request.DIDConfiguration = json.RawMessage(`{"@context":"https://example.com/context","linked_dids":[]}`)
+
+		result, cmdErr = verifyConfig(t, c, request)
+		require.NoError(t, cmdErr)
+		require.False(t, result.Verified)
+		require.Contains(t, result.Error, "unexpected DID configuration context")
+	})
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, didDoc := newCommand(t, ed25519KeyType, model.NewDIDCommV1Endpoint(origin))
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDIDConfiguration(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = c.VerifyDIDConfiguration(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		for _, tc := range []struct {
+			request *CreateDIDConfigurationRequest
+			err     string
+		}{
+			{
+				request: &CreateDIDConfigurationRequest{DID: "invalid", Origin: origin},
+				err:     errInvalidDID,
+			},
+			{
+				request: &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: "https://example.com/path"},
+				err:     fmt.Sprintf(errInvalidOrigin, "https://example.com/path"),
+			},
+			{
+				request: &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: "example.com"},
+				err:     fmt.Sprintf(errInvalidOrigin, "example.com"),
+			},
+			{
+				request: &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: origin, Format: "cbor"},
+				err:     fmt.Sprintf(errUnsupportedDIDConfigFormat, "cbor"),
+			},
+		} {
+			_, cmdErr = createConfig(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+
+		_, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: "ftp://example.com"})
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), fmt.Sprintf(errInvalidOrigin, "ftp://example.com"))
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		c, didDoc := newCommand(t, ed25519KeyType, model.NewDIDCommV1Endpoint(origin))
+
+		for _, tc := range []struct {
+			request *CreateDIDConfigurationRequest
+			err     string
+		}{
+			{
+				request: &CreateDIDConfigurationRequest{DID: "did:web:other.com", Origin: origin},
+				err:     "failed to resolve DID",
+			},
+			{
+				request: &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: origin, KeyID: "#unknown"},
+				err:     "key #unknown not found",
+			},
+		} {
+			_, cmdErr := createConfig(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, DIDConfigurationErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+
+		c.keyManager = &mockkms.KeyManager{GetKeyErr: fmt.Errorf("get key error")}
+
+		_, cmdErr := createConfig(t, c, &CreateDIDConfigurationRequest{DID: didDoc.ID, Origin: origin})
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDConfigurationErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "get key error")
+
+		hosted := []byte("--")
+		serverURL := serve(t, c, &hosted)
+
+		for _, tc := range []struct {
+			request *VerifyDIDConfigurationRequest
+			err     string
+		}{
+			{
+				request: &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: serverURL},
+				err:     "failed to parse DID configuration",
+			},
+			{
+				request: &VerifyDIDConfigurationRequest{
+					DID:              "did:web:other.com",
+					Origin:           serverURL,
+					DIDConfiguration: json.RawMessage(`{}`),
+				},
+				err: "failed to resolve DID",
+			},
+		} {
+			_, cmdErr = verifyConfig(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, DIDConfigurationErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+
+		c.httpClient = &http.Client{Transport: &http.Transport{}}
+
+		_, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: serverURL})
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDConfigurationErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to fetch DID configuration")
+
+		_, cmdErr = verifyConfig(t, c, &VerifyDIDConfigurationRequest{DID: didDoc.ID, Origin: "https://localhost:1"})
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDConfigurationErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to fetch DID configuration")
+	})
+}
@@ -0,0 +1,358 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk/jwksupport"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	vdrkey "github.com/hyperledger/aries-framework-go/pkg/vdr/key"
+)
+
+const (
+	// DID methods supported by CreateDID.
+	didMethodKey = "key"
+	didMethodJWK = "jwk"
+	didMethodWeb = "web"
+
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+	bls12381G2Key2020          = "Bls12381G2Key2020"
+	jsonWebKey2020             = "JsonWebKey2020"
+	x25519KeyAgreementKey2019  = "X25519KeyAgreementKey2019"
+
+	// JWK public key use values.
+	jwkUseSignature  = "sig"
+	jwkUseEncryption = "enc"
+
+	didWebWellKnownPath = "/.well-known"
+	didWebDocumentName  = "did.json"
+)
+
+// verificationMethodTypes verification method types of supported key types.
+// nolint:gochecknoglobals
+var verificationMethodTypes = map[kms.KeyType]string{
+	kms.ED25519Type:            ed25519VerificationKey2018,
+	kms.BLS12381G2Type:         bls12381G2Key2020,
+	kms.ECDSAP256TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP384TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP521TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP256TypeDER:       jsonWebKey2020,
+	kms.ECDSAP384TypeDER:       jsonWebKey2020,
+	kms.ECDSAP521TypeDER:       jsonWebKey2020,
+	kms.X25519ECDHKWType:       x25519KeyAgreementKey2019,
+	kms.NISTP256ECDHKWType:     jsonWebKey2020,
+	kms.NISTP384ECDHKWType:     jsonWebKey2020,
+	kms.NISTP521ECDHKWType:     jsonWebKey2020,
+}
+
+// verificationMethodContexts JSON-LD contexts of verification method types.
+// nolint:gochecknoglobals
+var verificationMethodContexts = map[string]string{
+	ed25519VerificationKey2018: "https://w3id.org/security/suites/ed25519-2018/v1",
+	bls12381G2Key2020:          "https://w3id.org/security/suites/bls12381-2020/v1",
+	jsonWebKey2020:             "https://w3id.org/security/suites/jws-2020/v1",
+	x25519KeyAgreementKey2019:  "https://w3id.org/security/suites/x25519-2019/v1",
+}
+
+// verificationRelationships verification relationships of key purposes.
+// nolint:gochecknoglobals
+var verificationRelationships = map[string]did.VerificationRelationship{
+	doc.KeyPurposeAuthentication:       did.Authentication,
+	doc.KeyPurposeAssertionMethod:      did.AssertionMethod,
+	doc.KeyPurposeKeyAgreement:         did.KeyAgreement,
+	doc.KeyPurposeCapabilityDelegation: did.CapabilityDelegation,
+	doc.KeyPurposeCapabilityInvocation: did.CapabilityInvocation,
+}
+
+// validateCreateDIDRequest validates the request and applies default key and purposes.
+func validateCreateDIDRequest(request *CreateDIDRequest) error {
+	request.Method = strings.TrimPrefix(request.Method, "did:")
+
+	switch request.Method {
+	case didMethodKey, didMethodJWK:
+		if len(request.Keys) > 1 {
+			return fmt.Errorf(errSingleKeyMethod, request.Method)
+		}
+	case didMethodWeb:
+		if request.Domain == "" || strings.Contains(request.Domain, "/") {
+			return fmt.Errorf(errInvalidDomain)
+		}
+	default:
+		return fmt.Errorf(errUnsupportedDIDMethod, request.Method)
+	}
+
+	if len(request.Keys) == 0 {
+		request.Keys = []KeySpec{{KeyType: ed25519KeyType}}
+	}
+
+	for i := range request.Keys {
+		key := &request.Keys[i]
+
+		if key.KeyType == "" {
+			key.KeyType = ed25519KeyType
+		}
+
+		vmType, ok := verificationMethodTypes[kms.KeyType(strings.ToUpper(key.KeyType))]
+		if !ok || (request.Method == didMethodKey && vmType == x25519KeyAgreementKey2019) {
+			return fmt.Errorf(errUnsupportedKeyType, key.KeyType)
+		}
+
+		if len(key.Purposes) == 0 {
+			key.Purposes = defaultKeyPurposes(key.KeyType)
+		}
+
+		for _, purpose := range key.Purposes {
+			if _, ok := verificationRelationships[purpose]; !ok {
+				return fmt.Errorf(errUnsupportedKeyPurpose, purpose)
+			}
+		}
+	}
+
+	return nil
+}
+
+func defaultKeyPurposes(keyType string) []string {
+	if _, ok := keyAgreementTypes[kms.KeyType(strings.ToUpper(keyType))]; ok {
+		return []string{doc.KeyPurposeKeyAgreement}
+	}
+
+	return []string{doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod}
+}
+
+// createDID creates keys in the KMS and returns the DID document of the requested method.
+func (c *Command) createDID(request *CreateDIDRequest) (*CreateDIDResponse, error) {
+	var (
+		didDoc *did.Doc
+		keyIDs map[string]string
+		docURL string
+		err    error
+	)
+
+	switch request.Method {
+	case didMethodKey:
+		didDoc, keyIDs, err = c.createKeyDID(&request.Keys[0])
+	case didMethodJWK:
+		didDoc, keyIDs, err = c.createJWKDID(&request.Keys[0])
+	default:
+		didDoc, keyIDs, docURL, err = c.createWebDID(request)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	docBytes, err := didDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID document : %w", err)
+	}
+
+	return &CreateDIDResponse{
+		DID:            didDoc.ID,
+		DIDDocument:    docBytes,
+		KeyIDs:         keyIDs,
+		DIDDocumentURL: docURL,
+	}, nil
+}
+
+func (c *Command) createKeyDID(key *KeySpec) (*did.Doc, map[string]string, error) {
+	keyID, vm, err := newVerificationMethod(c.keyManager, kms.KeyType(strings.ToUpper(key.KeyType)), "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docResolution, err := c.vdrRegistry.Create(vdrkey.DIDMethod, &did.Doc{VerificationMethod: []did.VerificationMethod{*vm}})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create did:key : %w", err)
+	}
+
+	didDoc := docResolution.DIDDocument
+
+	return didDoc, map[string]string{didDoc.VerificationMethod[0].ID: keyID}, nil
+}
+
+func (c *Command) createJWKDID(key *KeySpec) (*did.Doc, map[string]string, error) {
+	keyType := kms.KeyType(strings.ToUpper(key.KeyType))
+
+	keyID, pubKeyBytes, err := c.keyManager.CreateAndExportPubKeyBytes(keyType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s key : %w", key.KeyType, err)
+	}
+
+	j, err := jwksupport.PubKeyBytesToJWK(pubKeyBytes, keyType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert public key to JWK : %w", err)
+	}
+
+	j.Use = jwkUseSignature
+	if _, ok := keyAgreementTypes[keyType]; ok {
+		j.Use = jwkUseEncryption
+	}
+
+	jwkBytes, err := j.MarshalJSON()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal JWK : %w", err)
+	}
+
+	didID := "did:jwk:" + base64.RawURLEncoding.EncodeToString(jwkBytes)
+
+	vm, err := did.NewVerificationMethodFromJWK(didID+"#0", jsonWebKey2020, didID, j)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create verification method : %w", err)
+	}
+
+	didDoc := &did.Doc{
+		Context:            []string{did.ContextV1, verificationMethodContexts[jsonWebKey2020]},
+		ID:                 didID,
+		VerificationMethod: []did.VerificationMethod{*vm},
+	}
+
+	// did:jwk relationships are determined by the use of the key
+	purposes := []string{
+		doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod,
+		doc.KeyPurposeCapabilityInvocation, doc.KeyPurposeCapabilityDelegation,
+	}
+
+	if j.Use == jwkUseEncryption {
+		purposes = []string{doc.KeyPurposeKeyAgreement}
+	}
+
+	addVerifications(didDoc, &didDoc.VerificationMethod[0], purposes)
+
+	return didDoc, map[string]string{vm.ID: keyID}, nil
+}
+
+func (c *Command) createWebDID(request *CreateDIDRequest) (*did.Doc, map[string]string, string, error) {
+	didID, docURL := webDIDLocation(request.Domain, request.Path)
+
+	didDoc := &did.Doc{Context: []string{did.ContextV1}, ID: didID}
+	keyIDs := make(map[string]string)
+
+	for _, key := range request.Keys {
+		keyID, vm, err := newVerificationMethod(c.keyManager, kms.KeyType(strings.ToUpper(key.KeyType)), didID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		if key.ID != "" {
+			vm.ID = didID + "#" + key.ID
+		}
+
+		if _, ok := keyIDs[vm.ID]; ok {
+			return nil, nil, "", fmt.Errorf("duplicate key ID : %s", vm.ID)
+		}
+
+		keyIDs[vm.ID] = keyID
+
+		didDoc.VerificationMethod = append(didDoc.VerificationMethod, *vm)
+	}
+
+	for i := range didDoc.VerificationMethod {
+		vm := &didDoc.VerificationMethod[i]
+
+		if ctx := verificationMethodContexts[vm.Type]; !containsString(didDoc.Context, ctx) {
+			didDoc.Context = append(didDoc.Context, ctx)
+		}
+
+		addVerifications(didDoc, vm, request.Keys[i].Purposes)
+	}
+
+	return didDoc, keyIDs, docURL, nil
+}
+
+// webDIDLocation returns did:web DID for the domain and path along with URL at which its document
+// is to be published.
+func webDIDLocation(domain, path string) (string, string) {
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+
+	didID := "did:web:" + strings.ReplaceAll(domain, ":", "%3A")
+	docURL := "https://" + domain + didWebWellKnownPath
+
+	if len(segments) > 0 {
+		didID += ":" + strings.Join(segments, ":")
+		docURL = "https://" + domain + "/" + strings.Join(segments, "/")
+	}
+
+	return didID, docURL + "/" + didWebDocumentName
+}
+
+// addVerifications references the verification method from relationships of given purposes.
+func addVerifications(didDoc *did.Doc, vm *did.VerificationMethod, purposes []string) {
+	for _, purpose := range purposes {
+		relationship := verificationRelationships[purpose]
+		verification := *did.NewReferencedVerification(vm, relationship)
+
+		switch relationship { // nolint:exhaustive // only relationships of supported purposes
+		case did.Authentication:
+			didDoc.Authentication = append(didDoc.Authentication, verification)
+		case did.AssertionMethod:
+			didDoc.AssertionMethod = append(didDoc.AssertionMethod, verification)
+		case did.KeyAgreement:
+			didDoc.KeyAgreement = append(didDoc.KeyAgreement, verification)
+		case did.CapabilityDelegation:
+			didDoc.CapabilityDelegation = append(didDoc.CapabilityDelegation, verification)
+		case did.CapabilityInvocation:
+			didDoc.CapabilityInvocation = append(didDoc.CapabilityInvocation, verification)
+		}
+	}
+}
+
+// newVerificationMethod creates a key of given type in the KMS and returns its KMS key ID along with
+// the verification method of the key controlled by the DID.
+func newVerificationMethod(km kms.KeyManager, keyType kms.KeyType,
+	didID string) (string, *did.VerificationMethod, error) {
+	keyID, pubKeyBytes, err := km.CreateAndExportPubKeyBytes(keyType)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create %s key : %w", keyType, err)
+	}
+
+	vmID := didID + "#" + keyID
+
+	switch vmType := verificationMethodTypes[keyType]; vmType {
+	case ed25519VerificationKey2018, bls12381G2Key2020:
+		return keyID, did.NewVerificationMethodFromBytes(vmID, vmType, didID, pubKeyBytes), nil
+	case x25519KeyAgreementKey2019:
+		key := &crypto.PublicKey{}
+
+		err = json.Unmarshal(pubKeyBytes, key)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to unmarshal X25519 key : %w", err)
+		}
+
+		return keyID, did.NewVerificationMethodFromBytes(vmID, vmType, didID, key.X), nil
+	default:
+		var j *jwk.JWK
+
+		j, err = jwksupport.PubKeyBytesToJWK(pubKeyBytes, keyType)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to convert public key to JWK : %w", err)
+		}
+
+		vm, err := did.NewVerificationMethodFromJWK(vmID, jsonWebKey2020, didID, j)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create verification method : %w", err)
+		}
+
+		return keyID, vm, nil
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose/jwk"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	vdrpkg "github.com/hyperledger/aries-framework-go/pkg/vdr"
+	vdrkey "github.com/hyperledger/aries-framework-go/pkg/vdr/key"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_CreateDID(t *testing.T) {
+	newCommand := func(t *testing.T) *Command {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager, err = localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		c.vdrRegistry = vdrpkg.New(vdrpkg.WithVDR(vdrkey.New()))
+
+		return c
+	}
+
+	createDID := func(t *testing.T, c *Command, request interface{}) (*CreateDIDResponse, *did.Doc, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDID(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, nil, cmdErr
+		}
+
+		resp := &CreateDIDResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		didDoc, err := did.ParseDocument(resp.DIDDocument)
+		require.NoError(t, err)
+
+		return resp, didDoc, nil
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c := newCommand(t)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateDID(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		for _, tc := range []struct {
+			request *CreateDIDRequest
+			err     string
+		}{
+			{
+				request: &CreateDIDRequest{Method: "orb"},
+				err:     fmt.Sprintf(errUnsupportedDIDMethod, "orb"),
+			},
+			{
+				request: &CreateDIDRequest{Method: didMethodKey, Keys: []KeySpec{{}, {}}},
+				err:     fmt.Sprintf(errSingleKeyMethod, didMethodKey),
+			},
+			{
+				request: &CreateDIDRequest{Method: didMethodWeb},
+				err:     errInvalidDomain,
+			},
+			{
+				request: &CreateDIDRequest{Method: didMethodWeb, Domain: "https://example.com"},
+				err:     errInvalidDomain,
+			},
+			{
+				request: &CreateDIDRequest{Method: didMethodKey, Keys: []KeySpec{{KeyType: x25519ECDHKW}}},
+				err:     fmt.Sprintf(errUnsupportedKeyType, x25519ECDHKW),
+			},
+			{
+				request: &CreateDIDRequest{Method: didMethodJWK, Keys: []KeySpec{{KeyType: "rsa"}}},
+				err:     fmt.Sprintf(errUnsupportedKeyType, "rsa"),
+			},
+			{
+				request: &CreateDIDRequest{
+					Method: didMethodWeb,
+					Domain: "example.com",
+					Keys:   []KeySpec{{Purposes: []string{"signing"}}},
+				},
+				err: fmt.Sprintf(errUnsupportedKeyPurpose, "signing"),
+			},
+		} {
+			_, _, cmdErr = createDID(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test did:key", func(t *testing.T) {
+		c := newCommand(t)
+
+		for _, keyType := range []string{ed25519KeyType, BLS12381G2KeyType, p256KeyType} {
+			resp, didDoc, cmdErr := createDID(t, c, &CreateDIDRequest{
+				Method: "did:key",
+				Keys:   []KeySpec{{KeyType: keyType}},
+			})
+			require.NoError(t, cmdErr)
+			require.True(t, strings.HasPrefix(resp.DID, "did:key:z"))
+			require.Equal(t, resp.DID, didDoc.ID)
+			require.Empty(t, resp.DIDDocumentURL)
+			require.Len(t, resp.KeyIDs, 1)
+			require.NotEmpty(t, resp.KeyIDs[didDoc.VerificationMethod[0].ID])
+		}
+	})
+
+	t.Run("test did:jwk", func(t *testing.T) {
+		c := newCommand(t)
+
+		resp, didDoc, cmdErr := createDID(t, c, &CreateDIDRequest{Method: didMethodJWK})
+		require.NoError(t, cmdErr)
+		require.True(t, strings.HasPrefix(resp.DID, "did:jwk:"))
+		require.Equal(t, resp.DID+"#0", didDoc.VerificationMethod[0].ID)
+		require.Len(t, didDoc.Authentication, 1)
+		require.Len(t, didDoc.AssertionMethod, 1)
+		require.Len(t, didDoc.CapabilityInvocation, 1)
+		require.Len(t, didDoc.CapabilityDelegation, 1)
+		require.Empty(t, didDoc.KeyAgreement)
+		require.NotEmpty(t, resp.KeyIDs[resp.DID+"#0"])
+
+		jwkBytes, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(resp.DID, "did:jwk:"))
+		require.NoError(t, err)
+
+		j := &jwk.JWK{}
+		require.NoError(t, j.UnmarshalJSON(jwkBytes))
+		require.Equal(t, "Ed25519", j.Crv)
+		require.Equal(t, jwkUseSignature, j.Use)
+
+		resp, didDoc, cmdErr = createDID(t, c, &CreateDIDRequest{
+			Method: didMethodJWK,
+			Keys:   []KeySpec{{KeyType: p256ecdhkw}},
+		})
+		require.NoError(t, cmdErr)
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Empty(t, didDoc.Authentication)
+		require.NotEmpty(t, resp.KeyIDs[resp.DID+"#0"])
+	})
+
+	t.Run("test did:web", func(t *testing.T) {
+		c := newCommand(t)
+
+		resp, didDoc, cmdErr := createDID(t, c, &CreateDIDRequest{
+			Method: didMethodWeb,
+			Domain: "example.com:8443",
+			Path:   "/users/alice/",
+			Keys: []KeySpec{
+				{ID: "key1"},
+				{KeyType: x25519ECDHKW},
+				{KeyType: p384KeyType, Purposes: []string{doc.KeyPurposeCapabilityInvocation}},
+			},
+		})
+		require.NoError(t, cmdErr)
+		require.Equal(t, "did:web:example.com%3A8443:users:alice", resp.DID)
+		require.Equal(t, "https://example.com:8443/users/alice/did.json", resp.DIDDocumentURL)
+		require.Len(t, resp.KeyIDs, 3)
+
+		require.Len(t, didDoc.VerificationMethod, 3)
+		require.Equal(t, resp.DID+"#key1", didDoc.VerificationMethod[0].ID)
+		require.Equal(t, resp.DID, didDoc.VerificationMethod[0].Controller)
+		require.Equal(t, ed25519VerificationKey2018, didDoc.VerificationMethod[0].Type)
+		require.Equal(t, x25519KeyAgreementKey2019, didDoc.VerificationMethod[1].Type)
+		require.Equal(t, jsonWebKey2020, didDoc.VerificationMethod[2].Type)
+
+		require.Len(t, didDoc.Authentication, 1)
+		require.Len(t, didDoc.AssertionMethod, 1)
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Len(t, didDoc.CapabilityInvocation, 1)
+		require.Contains(t, didDoc.Context, verificationMethodContexts[jsonWebKey2020])
+
+		resp, _, cmdErr = createDID(t, c, &CreateDIDRequest{Method: didMethodWeb, Domain: "example.com"})
+		require.NoError(t, cmdErr)
+		require.Equal(t, "did:web:example.com", resp.DID)
+		require.Equal(t, "https://example.com/.well-known/did.json", resp.DIDDocumentURL)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		c := newCommand(t)
+		c.keyManager = &mockkms.KeyManager{CrAndExportPubKeyErr: fmt.Errorf("create key error")}
+
+		for _, method := range []string{didMethodKey, didMethodJWK} {
+			_, _, cmdErr := createDID(t, c, &CreateDIDRequest{Method: method})
+			require.Error(t, cmdErr)
+			require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+			require.Contains(t, cmdErr.Error(), "create key error")
+		}
+
+		_, _, cmdErr := createDID(t, c, &CreateDIDRequest{Method: didMethodWeb, Domain: "example.com"})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "create key error")
+
+		c = newCommand(t)
+		c.vdrRegistry = &mockvdr.MockVDRegistry{CreateErr: fmt.Errorf("create error")}
+
+		_, _, cmdErr = createDID(t, c, &CreateDIDRequest{Method: didMethodKey})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "create error")
+
+		c = newCommand(t)
+
+		_, _, cmdErr = createDID(t, c, &CreateDIDRequest{
+			Method: didMethodWeb,
+			Domain: "example.com",
+			Keys:   []KeySpec{{ID: "key1"}, {ID: "key1"}},
+		})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "duplicate key ID")
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+// sidetreeOperationDeactivate type of Sidetree operation deactivating DID, there is no document state after it.
+const sidetreeOperationDeactivate = "deactivate"
+
+// GetOrbDIDHistory returns Sidetree operations of orb DID in the order they were applied, along with their
+// anchor times and the document state after each of them.
+func (c *Command) GetOrbDIDHistory(rw io.Writer, req io.Reader) command.Error {
+	var request GetOrbDIDHistoryRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetOrbDIDHistoryCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.DID == "" {
+		logutil.LogError(logger, CommandName, GetOrbDIDHistoryCommandMethod, errInvalidDID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
+
+	resp, err := c.orbDIDHistory(request.DID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetOrbDIDHistoryCommandMethod, err.Error())
+
+		return command.NewExecuteError(GetOrbDIDHistoryErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, GetOrbDIDHistoryCommandMethod, successString)
+
+	return nil
+}
+
+func (c *Command) orbDIDHistory(didID string) (*GetOrbDIDHistoryResponse, error) {
+	docResolution, err := c.didBlocClient.Read(didID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s : %w", didID, err)
+	}
+
+	resp := &GetOrbDIDHistoryResponse{DID: didID, Operations: []OrbDIDOperation{}}
+
+	if docResolution.DocumentMetadata == nil || docResolution.DocumentMetadata.Method == nil {
+		return resp, nil
+	}
+
+	published := append([]*did.ProtocolOperation(nil), docResolution.DocumentMetadata.Method.PublishedOperations...)
+
+	sort.SliceStable(published, func(i, j int) bool {
+		if published[i].TransactionTime != published[j].TransactionTime {
+			return published[i].TransactionTime < published[j].TransactionTime
+		}
+
+		return published[i].TransactionNumber < published[j].TransactionNumber
+	})
+
+	for _, op := range published {
+		operation := newOrbDIDOperation(op, true)
+
+		if op.Type != sidetreeOperationDeactivate && op.CanonicalReference != "" {
+			operation.DIDDocument, err = c.orbDIDVersion(didID, op.CanonicalReference)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp.Operations = append(resp.Operations, *operation)
+	}
+
+	for _, op := range docResolution.DocumentMetadata.Method.UnpublishedOperations {
+		resp.Operations = append(resp.Operations, *newOrbDIDOperation(op, false))
+	}
+
+	return resp, nil
+}
+
+// orbDIDVersion resolves document of orb DID at the given version, versions are immutable so they are cached.
+func (c *Command) orbDIDVersion(didID, versionID string) (json.RawMessage, error) {
+	docResolution, err := c.cachedResolve(didID, versionID, "", false, func() (*did.DocResolution, error) {
+		return c.didBlocClient.Read(didID, vdr.WithOption(orb.VersionIDOpt, versionID))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s at version %s : %w", didID, versionID, err)
+	}
+
+	docBytes, err := docResolution.DIDDocument.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID document : %w", err)
+	}
+
+	return docBytes, nil
+}
+
+func newOrbDIDOperation(op *did.ProtocolOperation, published bool) *OrbDIDOperation {
+	operation := &OrbDIDOperation{
+		Type:                 op.Type,
+		Published:            published,
+		AnchorOrigin:         op.AnchorOrigin,
+		TransactionNumber:    op.TransactionNumber,
+		ProtocolVersion:      op.ProtocolVersion,
+		EquivalentReferences: op.EquivalentReferences,
+	}
+
+	if !published {
+		return operation
+	}
+
+	operation.VersionID = op.CanonicalReference
+
+	if op.TransactionTime > 0 {
+		anchorTime := time.Unix(op.TransactionTime, 0).UTC()
+		operation.AnchorTime = &anchorTime
+	}
+
+	return operation
+}
+
+// versionOptions returns resolution options for resolving DID document at a version or a point in time.
+func versionOptions(versionID, versionTime string) ([]vdr.DIDMethodOption, error) {
+	if versionID != "" && versionTime != "" {
+		return nil, errors.New(errVersionIDAndTime)
+	}
+
+	var opts []vdr.DIDMethodOption
+
+	if versionID != "" {
+		opts = append(opts, vdr.WithOption(orb.VersionIDOpt, versionID))
+	}
+
+	if versionTime != "" {
+		if _, err := time.Parse(time.RFC3339, versionTime); err != nil {
+			return nil, fmt.Errorf(errInvalidVersionTime, err)
+		}
+
+		opts = append(opts, vdr.WithOption(orb.VersionTimeOpt, versionTime))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_GetOrbDIDHistory(t *testing.T) {
+	const didID = "did:orb:uAAA:EiA123"
+
+	versionDoc := func(versionID string) *did.Doc {
+		return &did.Doc{
+			ID:      didID,
+			Context: []string{"https://www.w3.org/ns/did/v1"},
+			VerificationMethod: []did.VerificationMethod{{
+				ID:         "#" + versionID,
+				Controller: didID,
+				Type:       ed25519VerificationKey2018,
+				Value:      []byte(versionID),
+			}},
+		}
+	}
+
+	getHistory := func(t *testing.T, c *Command, didID string) (*GetOrbDIDHistoryResponse, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(GetOrbDIDHistoryRequest{DID: didID})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.GetOrbDIDHistory(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp := &GetOrbDIDHistoryResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp, nil
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		client := &versionedDIDClient{
+			current: &did.DocResolution{
+				DIDDocument: versionDoc("v3"),
+				DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{
+					PublishedOperations: []*did.ProtocolOperation{
+						{Type: "update", CanonicalReference: "v2", TransactionTime: 1620666000, TransactionNumber: 2},
+						{Type: "create", CanonicalReference: "v1", TransactionTime: 1620666000, TransactionNumber: 1,
+							AnchorOrigin: "https://orb.domain1.com", EquivalentReferences: []string{"hl:v1"}},
+						{Type: "recover", CanonicalReference: "v3", TransactionTime: 1620752400, TransactionNumber: 3},
+					},
+					UnpublishedOperations: []*did.ProtocolOperation{{Type: "deactivate"}},
+				}},
+			},
+			versions: map[string]*did.Doc{"v1": versionDoc("v1"), "v2": versionDoc("v2"), "v3": versionDoc("v3")},
+		}
+		c.didBlocClient = client
+
+		resp, cmdErr := getHistory(t, c, didID)
+		require.NoError(t, cmdErr)
+		require.Equal(t, didID, resp.DID)
+		require.Len(t, resp.Operations, 4)
+
+		for i, expected := range []struct{ opType, versionID string }{
+			{"create", "v1"}, {"update", "v2"}, {"recover", "v3"},
+		} {
+			op := resp.Operations[i]
+			require.Equal(t, expected.opType, op.Type)
+			require.Equal(t, expected.versionID, op.VersionID)
+			require.True(t, op.Published)
+			require.NotNil(t, op.AnchorTime)
+
+			didDoc, err := did.ParseDocument(op.DIDDocument)
+			require.NoError(t, err)
+			require.Equal(t, didID+"#"+expected.versionID, didDoc.VerificationMethod[0].ID)
+		}
+
+		require.Equal(t, "2021-05-10T17:00:00Z", resp.Operations[0].AnchorTime.Format("2006-01-02T15:04:05Z07:00"))
+		require.Equal(t, "https://orb.domain1.com", resp.Operations[0].AnchorOrigin)
+		require.Equal(t, []string{"hl:v1"}, resp.Operations[0].EquivalentReferences)
+
+		require.Equal(t, "deactivate", resp.Operations[3].Type)
+		require.False(t, resp.Operations[3].Published)
+		require.Nil(t, resp.Operations[3].AnchorTime)
+		require.Empty(t, resp.Operations[3].DIDDocument)
+
+		// versions are resolved once.
+		reads := client.reads
+
+		_, cmdErr = getHistory(t, c, didID)
+		require.NoError(t, cmdErr)
+		require.Equal(t, reads+1, client.reads)
+	})
+
+	t.Run("test DID without operations metadata", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.didBlocClient = &versionedDIDClient{current: &did.DocResolution{DIDDocument: versionDoc("v1")}}
+
+		resp, cmdErr := getHistory(t, c, didID)
+		require.NoError(t, cmdErr)
+		require.Empty(t, resp.Operations)
+	})
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.GetOrbDIDHistory(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		_, cmdErr = getHistory(t, c, "")
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errInvalidDID)
+	})
+
+	t.Run("test error from resolve", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.didBlocClient = &mockDIDClient{resolveDIDErr: fmt.Errorf("error resolve did")}
+
+		_, cmdErr := getHistory(t, c, didID)
+		require.Error(t, cmdErr)
+		require.Equal(t, GetOrbDIDHistoryErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), "error resolve did")
+
+		c.didBlocClient = &versionedDIDClient{
+			current: &did.DocResolution{
+				DIDDocument: versionDoc("v1"),
+				DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{
+					PublishedOperations: []*did.ProtocolOperation{{Type: "create", CanonicalReference: "v1"}},
+				}},
+			},
+		}
+
+		_, cmdErr = getHistory(t, c, didID)
+		require.Error(t, cmdErr)
+		require.Equal(t, GetOrbDIDHistoryErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to resolve DID did:orb:uAAA:EiA123 at version v1")
+	})
+}
+
+// versionedDIDClient resolves orb DID documents by version ID.
+type versionedDIDClient struct {
+	mockDIDClient
+	current  *did.DocResolution
+	versions map[string]*did.Doc
+	reads    int
+}
+
+func (m *versionedDIDClient) Read(id string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	m.reads++
+
+	didMethodOpts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+	for _, opt := range opts {
+		opt(didMethodOpts)
+	}
+
+	versionID, ok := didMethodOpts.Values[orb.VersionIDOpt].(string)
+	if !ok {
+		return m.current, nil
+	}
+
+	didDoc, ok := m.versions[versionID]
+	if !ok {
+		return nil, vdr.ErrNotFound
+	}
+
+	return &did.DocResolution{DIDDocument: didDoc}, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	gocrypto "crypto"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/api"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+)
+
+// operationKeys holds the keys orb VDR needs to submit a single sidetree operation for a DID.
+type operationKeys struct {
+	signer          api.Signer
+	nextUpdateKey   gocrypto.PublicKey
+	nextRecoveryKey gocrypto.PublicKey
+}
+
+// keyRetriever implements orb.KeyRetriever, serving keys of operations in progress.
+type keyRetriever struct {
+	mutex      sync.Mutex
+	operations map[string]*operationKeys
+}
+
+func newKeyRetriever() *keyRetriever {
+	return &keyRetriever{operations: make(map[string]*operationKeys)}
+}
+
+// add registers the keys for the next operation on given DID.
+func (k *keyRetriever) add(didID string, keys *operationKeys) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if _, ok := k.operations[didID]; ok {
+		return fmt.Errorf("operation already in progress for DID %s", didID)
+	}
+
+	k.operations[didID] = keys
+
+	return nil
+}
+
+// remove releases the keys registered for given DID.
+func (k *keyRetriever) remove(didID string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	delete(k.operations, didID)
+}
+
+func (k *keyRetriever) get(didID string) (*operationKeys, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	keys, ok := k.operations[didID]
+	if !ok {
+		return nil, fmt.Errorf("no operation keys found for DID %s", didID)
+	}
+
+	return keys, nil
+}
+
+// GetNextRecoveryPublicKey returns next recovery public key.
+func (k *keyRetriever) GetNextRecoveryPublicKey(didID, _ string) (gocrypto.PublicKey, error) {
+	keys, err := k.get(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.nextRecoveryKey == nil {
+		return nil, fmt.Errorf("next recovery key not provided for DID %s", didID)
+	}
+
+	return keys.nextRecoveryKey, nil
+}
+
+// GetNextUpdatePublicKey returns next update public key.
+func (k *keyRetriever) GetNextUpdatePublicKey(didID, _ string) (gocrypto.PublicKey, error) {
+	keys, err := k.get(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.nextUpdateKey == nil {
+		return nil, fmt.Errorf("next update key not provided for DID %s", didID)
+	}
+
+	return keys.nextUpdateKey, nil
+}
+
+// GetSigner returns signer for the operation.
+func (k *keyRetriever) GetSigner(didID string, _ orb.OperationType, _ string) (api.Signer, error) {
+	keys, err := k.get(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.signer == nil {
+		return nil, fmt.Errorf("signer not provided for DID %s", didID)
+	}
+
+	return keys.signer, nil
+}
+
+// kmsSigner signs sidetree requests with a key stored in the agent KMS.
+type kmsSigner struct {
+	keyHandle interface{}
+	crypto    crypto.Crypto
+	alg       string
+	jwk       *jws.JWK
+}
+
+func newKMSSigner(keyManager kms.KeyManager, c crypto.Crypto, keyID string) (*kmsSigner, error) {
+	keyHandle, err := keyManager.Get(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key handle %s : %w", keyID, err)
+	}
+
+	pubKeyBytes, keyType, err := keyManager.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export public key %s : %w", keyID, err)
+	}
+
+	alg, err := getSignatureAlgorithm(keyType)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := getKey(string(keyType), pubKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk, err := pubkey.GetPublicKeyJWK(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key JWK : %w", err)
+	}
+
+	return &kmsSigner{keyHandle: keyHandle, crypto: c, alg: alg, jwk: jwk}, nil
+}
+
+// Sign signs data and returns signature value.
+func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.keyHandle)
+}
+
+// Headers provides required JWS protected headers.
+func (s *kmsSigner) Headers() jws.Headers {
+	return jws.Headers{jws.HeaderAlgorithm: s.alg}
+}
+
+// PublicKeyJWK returns public key in JWK format.
+func (s *kmsSigner) PublicKeyJWK() *jws.JWK {
+	return s.jwk
+}
+
+func getSignatureAlgorithm(keyType kms.KeyType) (string, error) {
+	switch strings.ToLower(string(keyType)) {
+	case ed25519KeyType:
+		return "EdDSA", nil
+	case p256KeyType:
+		return "ES256", nil
+	case p384KeyType:
+		return "ES384", nil
+	default:
+		return "", fmt.Errorf("key type %s not supported for signing sidetree requests", keyType)
+	}
+}
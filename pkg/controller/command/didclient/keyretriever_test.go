@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+)
+
+func TestKeyRetriever(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		r := newKeyRetriever()
+
+		signer := &kmsSigner{alg: "EdDSA"}
+		updateKey := ed25519.PublicKey("update")
+		recoveryKey := ed25519.PublicKey("recovery")
+
+		require.NoError(t, r.add("did:123", &operationKeys{
+			signer:          signer,
+			nextUpdateKey:   updateKey,
+			nextRecoveryKey: recoveryKey,
+		}))
+
+		k, err := r.GetNextUpdatePublicKey("did:123", "")
+		require.NoError(t, err)
+		require.Equal(t, updateKey, k)
+
+		k, err = r.GetNextRecoveryPublicKey("did:123", "")
+		require.NoError(t, err)
+		require.Equal(t, recoveryKey, k)
+
+		s, err := r.GetSigner("did:123", orb.Update, "")
+		require.NoError(t, err)
+		require.Equal(t, signer, s)
+
+		r.remove("did:123")
+
+		_, err = r.GetSigner("did:123", orb.Update, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no operation keys found for DID did:123")
+	})
+
+	t.Run("test operation already in progress", func(t *testing.T) {
+		r := newKeyRetriever()
+
+		require.NoError(t, r.add("did:123", &operationKeys{}))
+
+		err := r.add("did:123", &operationKeys{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operation already in progress for DID did:123")
+	})
+
+	t.Run("test missing keys", func(t *testing.T) {
+		r := newKeyRetriever()
+
+		require.NoError(t, r.add("did:123", &operationKeys{}))
+
+		_, err := r.GetNextUpdatePublicKey("did:123", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next update key not provided")
+
+		_, err = r.GetNextRecoveryPublicKey("did:123", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "next recovery key not provided")
+
+		_, err = r.GetSigner("did:123", orb.Recover, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "signer not provided")
+
+		_, err = r.GetNextUpdatePublicKey("did:456", "")
+		require.Error(t, err)
+
+		_, err = r.GetNextRecoveryPublicKey("did:456", "")
+		require.Error(t, err)
+	})
+}
+
+func TestKMSSigner(t *testing.T) {
+	k, err := localkms.New(
+		"local-lock://custom/master/key/",
+		mockkms.NewProviderForKMS(storage.NewMockStoreProvider(), &noop.NoLock{}),
+	)
+	require.NoError(t, err)
+
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	t.Run("test success", func(t *testing.T) {
+		for _, kt := range []kms.KeyType{kms.ED25519Type, kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363} {
+			keyID, pubKeyBytes, err := k.CreateAndExportPubKeyBytes(kt)
+			require.NoError(t, err)
+
+			s, err := newKMSSigner(k, tc, keyID)
+			require.NoError(t, err)
+			require.NotEmpty(t, s.Headers()[jws.HeaderAlgorithm])
+			require.NotNil(t, s.PublicKeyJWK())
+
+			sig, err := s.Sign([]byte("data"))
+			require.NoError(t, err)
+
+			kh, err := k.PubKeyBytesToHandle(pubKeyBytes, kt)
+			require.NoError(t, err)
+
+			require.NoError(t, tc.Verify(sig, []byte("data"), kh))
+		}
+	})
+
+	t.Run("test key not found", func(t *testing.T) {
+		_, err := newKMSSigner(k, tc, "invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get key handle")
+	})
+
+	t.Run("test export public key error", func(t *testing.T) {
+		_, err := newKMSSigner(&mockkms.KeyManager{ExportPubKeyBytesErr: fmt.Errorf("export error")}, tc, "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export error")
+	})
+
+	t.Run("test unsupported key type", func(t *testing.T) {
+		keyID, _, err := k.CreateAndExportPubKeyBytes(kms.ECDSAP256TypeDER)
+		require.NoError(t, err)
+
+		_, err = newKMSSigner(k, tc, keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported for signing sidetree requests")
+	})
+}
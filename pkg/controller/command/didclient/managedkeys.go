@@ -0,0 +1,445 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
+)
+
+const (
+	// sha2_256 multihash code used by orb for commitments.
+	sha2_256 = 18
+
+	managedKeysKeyPrefix = "managedkeys_"
+
+	// IDs of KMS keys which are of no use are recorded under the prefix and tagged.
+	orphanedKMSKeyPrefix = "orphanedkmskey_"
+	orphanedKMSKeyTag    = "orphanedKMSKey"
+)
+
+var errManagedKeysNotFound = errors.New("managed keys not found")
+
+// managedKeys contains KMS key IDs and commitments of the update and recovery keys of an orb DID
+// whose keys are kept by the agent.
+type managedKeys struct {
+	DID                string `json:"did"`
+	UpdateKeyID        string `json:"updateKeyID"`
+	UpdateCommitment   string `json:"updateCommitment"`
+	RecoveryKeyID      string `json:"recoveryKeyID"`
+	RecoveryCommitment string `json:"recoveryCommitment"`
+
+	// next keys of an operation which was submitted, but isn't known to be accepted yet.
+	NextUpdateKeyID   string `json:"nextUpdateKeyID,omitempty"`
+	NextRecoveryKeyID string `json:"nextRecoveryKeyID,omitempty"`
+
+	// KMS key IDs of verification keys created for the DID, they aren't kept once the DID is created.
+	verificationKeyIDs []string
+}
+
+// keyIDs returns KMS key IDs of all keys created for the DID, or nil for DID whose keys aren't managed.
+func (k *managedKeys) keyIDs() []string {
+	if k == nil {
+		return nil
+	}
+
+	var keyIDs []string
+
+	for _, keyID := range append([]string{k.UpdateKeyID, k.RecoveryKeyID}, k.verificationKeyIDs...) {
+		if keyID != "" {
+			keyIDs = append(keyIDs, keyID)
+		}
+	}
+
+	return keyIDs
+}
+
+// managedKeyStore persists managed keys per DID.
+type managedKeyStore struct {
+	store storage.Store
+}
+
+func (s *managedKeyStore) get(didID string) (*managedKeys, error) {
+	data, err := s.store.Get(managedKeysKeyPrefix + didSuffix(didID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w for DID %s", errManagedKeysNotFound, didID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed keys : %w", err)
+	}
+
+	keys := &managedKeys{}
+
+	err = json.Unmarshal(data, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal managed keys : %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *managedKeyStore) put(keys *managedKeys) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal managed keys : %w", err)
+	}
+
+	err = s.store.Put(managedKeysKeyPrefix+didSuffix(keys.DID), data)
+	if err != nil {
+		return fmt.Errorf("failed to save managed keys : %w", err)
+	}
+
+	return nil
+}
+
+func (s *managedKeyStore) delete(didID string) error {
+	err := s.store.Delete(managedKeysKeyPrefix + didSuffix(didID))
+	if err != nil {
+		return fmt.Errorf("failed to delete managed keys : %w", err)
+	}
+
+	return nil
+}
+
+// didSuffix returns the unique suffix of orb DID, which stays the same once the DID gets anchored.
+// Long-form DID `<short-form DID>:<initial state>` has the suffix of its short-form DID.
+func didSuffix(didID string) string {
+	parts := strings.Split(didID, ":")
+
+	if len(parts) > 1 && isInitialState(parts[len(parts)-1]) {
+		return parts[len(parts)-2]
+	}
+
+	return parts[len(parts)-1]
+}
+
+// isInitialState tells if the DID segment is encoded create request ending long-form DID.
+func isInitialState(segment string) bool {
+	data, err := encoder.DecodeString(segment)
+	if err != nil {
+		return false
+	}
+
+	createRequest := &model.CreateRequest{}
+
+	return json.Unmarshal(data, createRequest) == nil && createRequest.SuffixData != nil
+}
+
+// keyDeleter is implemented by KMSs which can delete keys, KMS API doesn't delete keys.
+type keyDeleter interface {
+	Delete(keyID string) error
+}
+
+// kmsKeyStore gets rid of keys created in the KMS which turned out to be of no use, keys are deleted if the KMS
+// can delete them, otherwise IDs of the orphaned keys are recorded so that they can be cleaned up.
+type kmsKeyStore struct {
+	keyManager kms.KeyManager
+	store      storage.Store
+}
+
+func newKMSKeyStore(keyManager kms.KeyManager, store storage.Store) *kmsKeyStore {
+	return &kmsKeyStore{keyManager: keyManager, store: store}
+}
+
+func (s *kmsKeyStore) delete(keyIDs ...string) {
+	deleter, canDelete := s.keyManager.(keyDeleter)
+
+	for _, keyID := range keyIDs {
+		if canDelete {
+			err := deleter.Delete(keyID)
+			if err == nil {
+				continue
+			}
+
+			logger.Warnf("failed to delete KMS key %s : %s", keyID, err)
+		}
+
+		err := s.store.Put(orphanedKMSKeyPrefix+keyID, []byte(keyID), storage.Tag{Name: orphanedKMSKeyTag})
+		if err != nil {
+			logger.Warnf("failed to record orphaned KMS key %s : %s", keyID, err)
+		}
+	}
+}
+
+// createManagedKey creates a key of given type in the KMS and returns its ID and public key.
+func createManagedKey(keyManager kms.KeyManager, keyType kms.KeyType) (string, interface{}, error) {
+	keyID, pubKeyBytes, err := keyManager.CreateAndExportPubKeyBytes(keyType)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create %s key : %w", keyType, err)
+	}
+
+	pubKey, err := getKey(string(keyType), pubKeyBytes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return keyID, pubKey, nil
+}
+
+// getCommitment returns sidetree commitment of the public key.
+func getCommitment(pubKey interface{}) (string, error) {
+	jwk, err := pubkey.GetPublicKeyJWK(pubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to get public key JWK : %w", err)
+	}
+
+	return commitment.GetCommitment(jwk, sha2_256)
+}
+
+// createManagedKeys creates update and recovery keys in the KMS along with the verification keys
+// which don't have a value in the request. Created keys are deleted if any of the keys can't be created.
+func createManagedKeys(keyManager kms.KeyManager, kmsKeys *kmsKeyStore, // nolint: funlen
+	request *CreateOrbDIDRequest) (_ *managedKeys, _ []vdr.DIDMethodOption, err error) {
+	keys := &managedKeys{}
+
+	defer func() {
+		if err != nil {
+			kmsKeys.delete(keys.keyIDs()...)
+		}
+	}()
+
+	if len(request.PublicKeys) == 0 {
+		request.PublicKeys = []PublicKey{{
+			Type:     doc.JWSVerificationKey2020,
+			KeyType:  ed25519KeyType,
+			Purposes: []string{doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod},
+		}}
+	}
+
+	for i := range request.PublicKeys {
+		v := &request.PublicKeys[i]
+
+		if v.Value != "" {
+			continue
+		}
+
+		keyID, pubKeyBytes, errCreate := keyManager.CreateAndExportPubKeyBytes(kms.KeyType(strings.ToUpper(v.KeyType)))
+		if errCreate != nil {
+			return nil, nil, fmt.Errorf("failed to create %s key : %w", v.KeyType, errCreate)
+		}
+
+		keys.verificationKeyIDs = append(keys.verificationKeyIDs, keyID)
+
+		if v.ID == "" {
+			v.ID = keyID
+		}
+
+		v.Value = base64.RawURLEncoding.EncodeToString(pubKeyBytes)
+	}
+
+	var updateKey, recoveryKey interface{}
+
+	keys.UpdateKeyID, updateKey, err = createManagedKey(keyManager, kms.ED25519Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys.RecoveryKeyID, recoveryKey, err = createManagedKey(keyManager, kms.ED25519Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys.UpdateCommitment, err = getCommitment(updateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys.RecoveryCommitment, err = getCommitment(recoveryKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keys, []vdr.DIDMethodOption{
+		vdr.WithOption(orb.UpdatePublicKeyOpt, updateKey),
+		vdr.WithOption(orb.RecoveryPublicKeyOpt, recoveryKey),
+	}, nil
+}
+
+// managedOperation holds managed keys of a DID along with the next keys of an operation.
+type managedOperation struct {
+	keys            *managedKeys
+	nextUpdateKey   interface{}
+	nextRecoveryKey interface{}
+}
+
+// prepareManagedOperation loads managed keys of the DID and gets the next update and/or recovery keys. Next keys are
+// saved as pending before the operation is submitted, so that they aren't lost if outcome of the operation doesn't
+// get recorded.
+func (c *Command) prepareManagedOperation(didID string, nextUpdate, // nolint: gocyclo
+	nextRecovery bool) (_ *managedOperation, err error) {
+	keys, err := c.managedKeys.get(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.NextUpdateKeyID != "" || keys.NextRecoveryKeyID != "" {
+		err = c.promoteCommittedKeys(didID, keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	op := &managedOperation{keys: keys}
+
+	var created []string
+
+	defer func() {
+		if err != nil {
+			c.kmsKeys.delete(created...)
+		}
+	}()
+
+	if nextUpdate {
+		var isNew bool
+
+		keys.NextUpdateKeyID, op.nextUpdateKey, isNew, err = c.nextManagedKey(keys.NextUpdateKeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNew {
+			created = append(created, keys.NextUpdateKeyID)
+		}
+	}
+
+	if nextRecovery {
+		var isNew bool
+
+		keys.NextRecoveryKeyID, op.nextRecoveryKey, isNew, err = c.nextManagedKey(keys.NextRecoveryKeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNew {
+			created = append(created, keys.NextRecoveryKeyID)
+		}
+	}
+
+	err = c.managedKeys.put(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// nextManagedKey returns pending next key of an earlier operation which didn't get accepted, or creates a new key.
+// It also tells whether the key was created.
+func (c *Command) nextManagedKey(pendingKeyID string) (string, interface{}, bool, error) {
+	if pendingKeyID != "" {
+		key, err := managedPublicKey(c.keyManager, pendingKeyID)
+
+		return pendingKeyID, key, false, err
+	}
+
+	keyID, key, err := createManagedKey(c.keyManager, kms.ED25519Type)
+
+	return keyID, key, true, err
+}
+
+// promoteCommittedKeys makes pending next keys current ones if the DID already commits to them, which happens when
+// an earlier operation got accepted but its outcome didn't get recorded.
+func (c *Command) promoteCommittedKeys(didID string, keys *managedKeys) error {
+	docResolution, err := c.didBlocClient.Read(didID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve DID %s : %w", didID, err)
+	}
+
+	if docResolution.DocumentMetadata == nil || docResolution.DocumentMetadata.Method == nil {
+		return nil
+	}
+
+	method := docResolution.DocumentMetadata.Method
+
+	committed, err := c.isCommitted(keys.NextUpdateKeyID, method.UpdateCommitment)
+	if err != nil {
+		return err
+	}
+
+	if committed {
+		keys.UpdateKeyID, keys.UpdateCommitment, keys.NextUpdateKeyID = keys.NextUpdateKeyID, method.UpdateCommitment, ""
+	}
+
+	committed, err = c.isCommitted(keys.NextRecoveryKeyID, method.RecoveryCommitment)
+	if err != nil {
+		return err
+	}
+
+	if committed {
+		keys.RecoveryKeyID, keys.RecoveryCommitment, keys.NextRecoveryKeyID =
+			keys.NextRecoveryKeyID, method.RecoveryCommitment, ""
+	}
+
+	return nil
+}
+
+// isCommitted tells whether the commitment is made to the managed key.
+func (c *Command) isCommitted(keyID, commitment string) (bool, error) {
+	if keyID == "" || commitment == "" {
+		return false, nil
+	}
+
+	key, err := managedPublicKey(c.keyManager, keyID)
+	if err != nil {
+		return false, err
+	}
+
+	keyCommitment, err := getCommitment(key)
+	if err != nil {
+		return false, err
+	}
+
+	return keyCommitment == commitment, nil
+}
+
+// managedPublicKey returns public key of the key kept in the KMS.
+func managedPublicKey(keyManager kms.KeyManager, keyID string) (interface{}, error) {
+	pubKeyBytes, keyType, err := keyManager.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s key : %w", keyID, err)
+	}
+
+	return getKey(string(keyType), pubKeyBytes)
+}
+
+// completeManagedOperation makes the pending next keys of the accepted operation current ones.
+func (c *Command) completeManagedOperation(op *managedOperation) error {
+	if op.nextUpdateKey != nil {
+		updateCommitment, err := getCommitment(op.nextUpdateKey)
+		if err != nil {
+			return err
+		}
+
+		op.keys.UpdateKeyID, op.keys.UpdateCommitment, op.keys.NextUpdateKeyID =
+			op.keys.NextUpdateKeyID, updateCommitment, ""
+	}
+
+	if op.nextRecoveryKey != nil {
+		recoveryCommitment, err := getCommitment(op.nextRecoveryKey)
+		if err != nil {
+			return err
+		}
+
+		op.keys.RecoveryKeyID, op.keys.RecoveryCommitment, op.keys.NextRecoveryKeyID =
+			op.keys.NextRecoveryKeyID, recoveryCommitment, ""
+	}
+
+	return c.managedKeys.put(op.keys)
+}
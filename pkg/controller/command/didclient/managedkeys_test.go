@@ -0,0 +1,429 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestManagedKeyStore(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		s := &managedKeyStore{store: mockstorage.NewMockStoreProvider().Store}
+
+		keys := &managedKeys{DID: "did:orb:uAAA:EiA123", UpdateKeyID: "update", RecoveryKeyID: "recovery"}
+
+		require.NoError(t, s.put(keys))
+
+		// anchored DID shares the suffix of unpublished one
+		result, err := s.get("did:orb:https:example.com:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, keys, result)
+
+		// so does long-form DID
+		initialState := encoder.EncodeToString([]byte(`{"suffixData":{"deltaHash":"EiB","recoveryCommitment":"EiC"}}`))
+
+		result, err = s.get("did:orb:uAAA:EiA123:" + initialState)
+		require.NoError(t, err)
+		require.Equal(t, keys, result)
+
+		require.NoError(t, s.delete("did:orb:uAAA:EiA123"))
+
+		_, err = s.get("did:orb:uAAA:EiA123")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errManagedKeysNotFound))
+	})
+
+	t.Run("test store errors", func(t *testing.T) {
+		store := &mockstorage.MockStore{
+			Store:     make(map[string]mockstorage.DBEntry),
+			ErrGet:    fmt.Errorf("get error"),
+			ErrPut:    fmt.Errorf("put error"),
+			ErrDelete: fmt.Errorf("delete error"),
+		}
+		s := &managedKeyStore{store: store}
+
+		_, err := s.get("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		err = s.put(&managedKeys{DID: "did:orb:123"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+
+		err = s.delete("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete error")
+	})
+
+	t.Run("test invalid data", func(t *testing.T) {
+		s := &managedKeyStore{store: mockstorage.NewMockStoreProvider().Store}
+
+		require.NoError(t, s.store.Put(managedKeysKeyPrefix+"123", []byte("{")))
+
+		_, err := s.get("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal managed keys")
+	})
+}
+
+func TestCommand_ManagedKeys(t *testing.T) {
+	newCommand := func(t *testing.T) *Command {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		keyManager, err := localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		c.keyManager = &deletingKeyManager{KeyManager: keyManager}
+		c.kmsKeys = newKMSKeyStore(c.keyManager, mockstorage.NewMockStoreProvider().Store)
+
+		c.crypto, err = tinkcrypto.New()
+		require.NoError(t, err)
+
+		c.didBlocClient = &mockDIDClient{
+			createDIDValue:  &did.DocResolution{DIDDocument: &did.Doc{ID: "did:orb:uAAA:EiA123"}},
+			resolveDIDValue: &did.DocResolution{DIDDocument: &did.Doc{ID: "did:orb:uAAA:EiA123"}},
+		}
+
+		return c
+	}
+
+	execute := func(t *testing.T, fn command.Exec, request interface{}) command.Error {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		return fn(&b, bytes.NewBuffer(req))
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{ManagedKeys: true})
+		require.NoError(t, cmdErr)
+
+		client := c.didBlocClient.(*mockDIDClient)
+
+		opts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+		for _, opt := range client.createOpts {
+			opt(opts)
+		}
+
+		require.NotNil(t, opts.Values[orb.UpdatePublicKeyOpt])
+		require.NotNil(t, opts.Values[orb.RecoveryPublicKeyOpt])
+
+		created, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UpdateKeyID)
+		require.NotEmpty(t, created.RecoveryKeyID)
+
+		commitment, err := getCommitment(opts.Values[orb.UpdatePublicKeyOpt])
+		require.NoError(t, err)
+		require.Equal(t, commitment, created.UpdateCommitment)
+
+		cmdErr = execute(t, c.UpdateOrbDID, UpdateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.NoError(t, cmdErr)
+
+		updated, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.NotEqual(t, created.UpdateKeyID, updated.UpdateKeyID)
+		require.NotEqual(t, created.UpdateCommitment, updated.UpdateCommitment)
+		require.Equal(t, created.RecoveryKeyID, updated.RecoveryKeyID)
+
+		cmdErr = execute(t, c.RecoverOrbDID, RecoverOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.NoError(t, cmdErr)
+
+		recovered, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.NotEqual(t, updated.UpdateKeyID, recovered.UpdateKeyID)
+		require.NotEqual(t, updated.RecoveryKeyID, recovered.RecoveryKeyID)
+		require.NotEqual(t, updated.RecoveryCommitment, recovered.RecoveryCommitment)
+
+		cmdErr = execute(t, c.DeactivateOrbDID, DeactivateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.NoError(t, cmdErr)
+
+		_, err = c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.True(t, errors.Is(err, errManagedKeysNotFound))
+	})
+
+	t.Run("test update and recovery keys provided", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{
+			ManagedKeys: true,
+			PublicKeys:  []PublicKey{{KeyType: ed25519KeyType, Recovery: true}},
+		})
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errManagedUpdateRecoveryKeys)
+	})
+
+	t.Run("test error from creating keys", func(t *testing.T) {
+		c := newCommand(t)
+		c.keyManager = &mockkms.KeyManager{CrAndExportPubKeyErr: fmt.Errorf("create key error")}
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{ManagedKeys: true})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "create key error")
+	})
+
+	t.Run("test error from saving keys", func(t *testing.T) {
+		c := newCommand(t)
+		c.managedKeys.store = &mockstorage.MockStore{
+			Store:  make(map[string]mockstorage.DBEntry),
+			ErrPut: fmt.Errorf("put error"),
+		}
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{ManagedKeys: true})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "put error")
+	})
+
+	t.Run("test keys not managed", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.UpdateOrbDID, UpdateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.Error(t, cmdErr)
+		require.Equal(t, UpdateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errManagedKeysNotFound.Error())
+
+		cmdErr = execute(t, c.RecoverOrbDID, RecoverOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.Error(t, cmdErr)
+		require.Equal(t, RecoverDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errManagedKeysNotFound.Error())
+
+		cmdErr = execute(t, c.DeactivateOrbDID, DeactivateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.Error(t, cmdErr)
+		require.Equal(t, DeactivateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errManagedKeysNotFound.Error())
+	})
+
+	t.Run("test keys kept on failed update", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{ManagedKeys: true})
+		require.NoError(t, cmdErr)
+
+		created, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+
+		c.didBlocClient.(*mockDIDClient).updateDIDErr = fmt.Errorf("update error")
+
+		cmdErr = execute(t, c.UpdateOrbDID, UpdateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "update error")
+
+		current, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, created.UpdateKeyID, current.UpdateKeyID)
+		require.Equal(t, created.UpdateCommitment, current.UpdateCommitment)
+		require.NotEmpty(t, current.NextUpdateKeyID)
+
+		// retried update uses the pending next key.
+		c.didBlocClient.(*mockDIDClient).updateDIDErr = nil
+
+		cmdErr = execute(t, c.UpdateOrbDID, UpdateOrbDIDRequest{DID: "did:orb:uAAA:EiA123"})
+		require.NoError(t, cmdErr)
+
+		updated, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, current.NextUpdateKeyID, updated.UpdateKeyID)
+		require.Empty(t, updated.NextUpdateKeyID)
+	})
+
+	t.Run("test pending keys promoted once DID commits to them", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{ManagedKeys: true})
+		require.NoError(t, cmdErr)
+
+		// outcome of recover operation doesn't get recorded.
+		op, err := c.prepareManagedOperation("did:orb:uAAA:EiA123", true, true)
+		require.NoError(t, err)
+
+		pending, err := c.managedKeys.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.NotEmpty(t, pending.NextUpdateKeyID)
+		require.NotEmpty(t, pending.NextRecoveryKeyID)
+
+		updateCommitment, err := getCommitment(op.nextUpdateKey)
+		require.NoError(t, err)
+
+		recoveryCommitment, err := getCommitment(op.nextRecoveryKey)
+		require.NoError(t, err)
+
+		c.didBlocClient.(*mockDIDClient).resolveDIDValue = &did.DocResolution{
+			DIDDocument: &did.Doc{ID: "did:orb:uAAA:EiA123"},
+			DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{
+				UpdateCommitment:   updateCommitment,
+				RecoveryCommitment: recoveryCommitment,
+			}},
+		}
+
+		op, err = c.prepareManagedOperation("did:orb:uAAA:EiA123", true, false)
+		require.NoError(t, err)
+		require.Equal(t, pending.NextUpdateKeyID, op.keys.UpdateKeyID)
+		require.Equal(t, updateCommitment, op.keys.UpdateCommitment)
+		require.Equal(t, pending.NextRecoveryKeyID, op.keys.RecoveryKeyID)
+		require.Equal(t, recoveryCommitment, op.keys.RecoveryCommitment)
+		require.Empty(t, op.keys.NextRecoveryKeyID)
+		require.NotEmpty(t, op.keys.NextUpdateKeyID)
+		require.NotEqual(t, pending.NextUpdateKeyID, op.keys.NextUpdateKeyID)
+
+		c.didBlocClient.(*mockDIDClient).resolveDIDErr = fmt.Errorf("resolve error")
+
+		_, err = c.prepareManagedOperation("did:orb:uAAA:EiA123", true, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve error")
+	})
+
+	t.Run("test unsupported verification key type", func(t *testing.T) {
+		c := newCommand(t)
+
+		cmdErr := execute(t, c.CreateOrbDID, CreateOrbDIDRequest{
+			ManagedKeys: true,
+			PublicKeys:  []PublicKey{{KeyType: "invalid"}},
+		})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to create invalid key")
+	})
+
+	t.Run("test verification key created in KMS", func(t *testing.T) {
+		c := newCommand(t)
+
+		request := CreateOrbDIDRequest{PublicKeys: []PublicKey{{ID: "key1", KeyType: ed25519KeyType}}}
+
+		_, _, err := createManagedKeys(c.keyManager, c.kmsKeys, &request)
+		require.NoError(t, err)
+		require.Equal(t, "key1", request.PublicKeys[0].ID)
+		require.NotEmpty(t, request.PublicKeys[0].Value)
+
+		request = CreateOrbDIDRequest{}
+
+		_, _, err = createManagedKeys(c.keyManager, c.kmsKeys, &request)
+		require.NoError(t, err)
+		require.Len(t, request.PublicKeys, 1)
+		require.NotEmpty(t, request.PublicKeys[0].ID)
+		require.Equal(t, ed25519KeyType, request.PublicKeys[0].KeyType)
+	})
+
+	t.Run("test created keys deleted when a key can't be created", func(t *testing.T) {
+		c := newCommand(t)
+
+		request := CreateOrbDIDRequest{PublicKeys: []PublicKey{{KeyType: ed25519KeyType}, {KeyType: "invalid"}}}
+
+		_, _, err := createManagedKeys(c.keyManager, c.kmsKeys, &request)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create invalid key")
+		require.NotEmpty(t, request.PublicKeys[0].ID)
+
+		require.Equal(t, []string{request.PublicKeys[0].ID}, c.keyManager.(*deletingKeyManager).deleted)
+	})
+
+	t.Run("test created keys deleted on failed create", func(t *testing.T) {
+		c := newCommand(t)
+		c.didBlocClient.(*mockDIDClient).createDIDErr = fmt.Errorf("create error")
+
+		request := CreateOrbDIDRequest{ManagedKeys: true}
+
+		cmdErr := execute(t, c.CreateOrbDID, request)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "create error")
+
+		opts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+		for _, opt := range c.didBlocClient.(*mockDIDClient).createOpts {
+			opt(opts)
+		}
+
+		for _, key := range []interface{}{opts.Values[orb.UpdatePublicKeyOpt], opts.Values[orb.RecoveryPublicKeyOpt]} {
+			require.NotNil(t, key)
+
+			keyID, err := localkms.CreateKID(key.(ed25519.PublicKey), kms.ED25519Type)
+			require.NoError(t, err)
+
+			require.Contains(t, c.keyManager.(*deletingKeyManager).deleted, keyID)
+		}
+	})
+
+	t.Run("test orphaned keys recorded when KMS can't delete keys", func(t *testing.T) {
+		c := newCommand(t)
+
+		store := mockstorage.NewMockStoreProvider().Store
+		c.kmsKeys = newKMSKeyStore(c.keyManager.(*deletingKeyManager).KeyManager, store)
+
+		request := CreateOrbDIDRequest{PublicKeys: []PublicKey{{KeyType: ed25519KeyType}, {KeyType: "invalid"}}}
+
+		_, _, err := createManagedKeys(c.keyManager, c.kmsKeys, &request)
+		require.Error(t, err)
+		require.NotEmpty(t, request.PublicKeys[0].ID)
+
+		keyID, err := store.Get(orphanedKMSKeyPrefix + request.PublicKeys[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, request.PublicKeys[0].ID, string(keyID))
+	})
+
+	t.Run("test orphaned keys recorded when KMS fails to delete keys", func(t *testing.T) {
+		c := newCommand(t)
+		c.keyManager.(*deletingKeyManager).deleteErr = fmt.Errorf("delete error")
+
+		store := mockstorage.NewMockStoreProvider().Store
+		c.kmsKeys = newKMSKeyStore(c.keyManager, store)
+
+		c.kmsKeys.delete("key1")
+
+		keyID, err := store.Get(orphanedKMSKeyPrefix + "key1")
+		require.NoError(t, err)
+		require.Equal(t, "key1", string(keyID))
+	})
+}
+
+// deletingKeyManager is a KMS which can delete keys.
+type deletingKeyManager struct {
+	kms.KeyManager
+	deleted   []string
+	deleteErr error
+}
+
+func (m *deletingKeyManager) Delete(keyID string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+
+	m.deleted = append(m.deleted, keyID)
+
+	return nil
+}
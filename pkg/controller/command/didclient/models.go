@@ -5,9 +5,23 @@ SPDX-License-Identifier: Apache-2.0
 
 package didclient
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+)
+
 // CreateOrbDIDRequest model
 //
-// This is used for creating orb DID.
+// This is used for creating orb DID. If ManagedKeys is set, update and recovery keys, along with public keys
+// given without a value, are created in the agent KMS and kept for later operations on the DID. If Queued is set,
+// the long-form DID is returned right away and the create operation is submitted in background until Orb
+// accepts it, see ListPendingDIDOperations. If DryRun is set, nothing is created and ValidateOrbDIDResponse
+// is returned instead. Domain pins the request to one of the configured Orb domains, otherwise it fails over
+// among them, and AnchorOrigin overrides anchor origin of the domain.
 //
 type CreateOrbDIDRequest struct {
 	ServiceID          string      `json:"serviceID,omitempty"`
@@ -16,22 +30,304 @@ type CreateOrbDIDRequest struct {
 	PublicKeys         []PublicKey `json:"publicKeys,omitempty"`
 	RoutersKeyAgrIDS   []string    `json:"routerKAIDS,omitempty"`
 	RouterConnections  []string    `json:"routerConnections,omitempty"`
+	ManagedKeys        bool        `json:"managedKeys,omitempty"`
+	Queued             bool        `json:"queued,omitempty"`
+	DryRun             bool        `json:"dryRun,omitempty"`
+	Domain             string      `json:"domain,omitempty"`
+	AnchorOrigin       string      `json:"anchorOrigin,omitempty"`
+}
+
+// ValidateOrbDIDResponse model
+//
+// This is used for returning the document which would be created from CreateOrbDIDRequest, as it resolves
+// from its long-form DID, along with validation findings. Keys managed by the agent are replaced with
+// throwaway keys. Valid is false if any of the findings is an error, the document is then omitted.
+//
+type ValidateOrbDIDResponse struct {
+	Valid       bool                `json:"valid"`
+	DIDDocument json.RawMessage     `json:"didDocument,omitempty"`
+	Findings    []ValidationFinding `json:"findings"`
+}
+
+// ValidationFinding issue found in orb DID create request. Code identifies the kind of issue, Severity is either
+// 'error', which makes Orb reject the request, or 'warning'. Path points at the offending request field.
+type ValidationFinding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
 }
 
 // ResolveOrbDIDRequest model
 //
-// This is used for resolving orb DID.
+// This is used for resolving orb DID. Either VersionID or VersionTime (RFC 3339) can be given to resolve
+// the document as it was at the given version or time.
 //
 type ResolveOrbDIDRequest struct {
+	DID         string `json:"did,omitempty"`
+	VersionID   string `json:"versionId,omitempty"`
+	VersionTime string `json:"versionTime,omitempty"`
+}
+
+// ResolveDIDRequest model
+//
+// This is used for resolving DID of any supported method. Resolutions are cached, NoCache skips the cached one.
+//
+type ResolveDIDRequest struct {
+	DID         string `json:"did,omitempty"`
+	VersionID   string `json:"versionId,omitempty"`
+	VersionTime string `json:"versionTime,omitempty"`
+	NoCache     bool   `json:"noCache,omitempty"`
+	Accept      string `json:"accept,omitempty"`
+}
+
+// ResolveDIDResponse model
+//
+// This is used for returning DID resolution result.
+//
+type ResolveDIDResponse struct {
+	Context               []string               `json:"@context,omitempty"`
+	DIDDocument           json.RawMessage        `json:"didDocument,omitempty"`
+	DIDDocumentMetadata   *did.DocumentMetadata  `json:"didDocumentMetadata,omitempty"`
+	DIDResolutionMetadata *DIDResolutionMetadata `json:"didResolutionMetadata,omitempty"`
+}
+
+// DIDResolutionMetadata DID resolution metadata.
+type DIDResolutionMetadata struct {
+	ContentType  string `json:"contentType,omitempty"`
+	Error        string `json:"error,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// CreateDIDRequest model
+//
+// This is used for creating DID of 'key', 'jwk' or 'web' method with keys created in the agent KMS.
+// Methods 'key' and 'jwk' support a single key. Domain, optionally with a port, and path are used by
+// 'web' method only. An ed25519 key is created when no keys are given.
+//
+type CreateDIDRequest struct {
+	Method string    `json:"method,omitempty"`
+	Keys   []KeySpec `json:"keys,omitempty"`
+	Domain string    `json:"domain,omitempty"`
+	Path   string    `json:"path,omitempty"`
+}
+
+// KeySpec specification of key to be created in the agent KMS. Key ID and purposes are used by did:web only,
+// purposes default to authentication and assertionMethod or keyAgreement for key agreement types.
+type KeySpec struct {
+	ID       string   `json:"id,omitempty"`
+	KeyType  string   `json:"keyType,omitempty"`
+	Purposes []string `json:"purposes,omitempty"`
+}
+
+// CreateDIDResponse model
+//
+// This is used for returning created DID along with KMS key IDs of its verification methods. For did:web
+// the DID document is the did.json to be published at the DID document URL.
+//
+type CreateDIDResponse struct {
+	DID            string            `json:"did,omitempty"`
+	DIDDocument    json.RawMessage   `json:"didDocument,omitempty"`
+	KeyIDs         map[string]string `json:"keyIDs,omitempty"`
+	DIDDocumentURL string            `json:"didDocumentURL,omitempty"`
+}
+
+// InvalidateDIDCacheRequest model
+//
+// This is used for removing cached resolutions of DID, the whole cache is cleared if DID is empty.
+//
+type InvalidateDIDCacheRequest struct {
+	DID string `json:"did,omitempty"`
+}
+
+// ListCachedDIDsResponse model
+//
+// This is used for returning DIDs whose resolutions are cached.
+//
+type ListCachedDIDsResponse struct {
+	DIDs []CachedDID `json:"dids"`
+}
+
+// GetOrbDIDStatusRequest model
+//
+// This is used for getting publication status of orb DID.
+//
+type GetOrbDIDStatusRequest struct {
+	DID string `json:"did,omitempty"`
+}
+
+// ListPendingDIDOperationsResponse model
+//
+// This is used for returning orb DID operations waiting to be submitted.
+//
+type ListPendingDIDOperationsResponse struct {
+	Operations []PendingDIDOperation `json:"operations"`
+}
+
+// CancelPendingDIDOperationRequest model
+//
+// This is used for removing orb DID operation from the queue.
+//
+type CancelPendingDIDOperationRequest struct {
+	ID string `json:"id,omitempty"`
+}
+
+// GetOrbDIDHistoryRequest model
+//
+// This is used for getting operation history of orb DID.
+//
+type GetOrbDIDHistoryRequest struct {
 	DID string `json:"did,omitempty"`
 }
 
+// GetOrbDIDHistoryResponse model
+//
+// This is used for returning Sidetree operations of orb DID in the order they were applied.
+//
+type GetOrbDIDHistoryResponse struct {
+	DID        string           `json:"did"`
+	Operations []OrbDIDOperation `json:"operations"`
+}
+
+// OrbDIDOperation model
+//
+// This is used for describing a Sidetree operation of orb DID. VersionID can be used for resolving DIDDocument,
+// the document state after the operation, which is missing for deactivate and not yet anchored operations.
+//
+type OrbDIDOperation struct {
+	Type                 string          `json:"type"`
+	Published            bool            `json:"published"`
+	VersionID            string          `json:"versionId,omitempty"`
+	AnchorTime           *time.Time      `json:"anchorTime,omitempty"`
+	AnchorOrigin         string          `json:"anchorOrigin,omitempty"`
+	TransactionNumber    int             `json:"transactionNumber,omitempty"`
+	ProtocolVersion      int             `json:"protocolVersion,omitempty"`
+	EquivalentReferences []string        `json:"equivalentReferences,omitempty"`
+	DIDDocument          json.RawMessage `json:"didDocument,omitempty"`
+}
+
+// CreateDIDBatchRequest model
+//
+// This is used for creating orb and peer DIDs in batch. At most Concurrency items, 10 by default, are created
+// at a time. If Atomic is set, no more items are started once an item fails and the DIDs already created are
+// deactivated, or unregistered from the router for peer DIDs. Atomic batch requires orb DIDs with managed keys.
+//
+type CreateDIDBatchRequest struct {
+	Items       []CreateDIDBatchItem `json:"items,omitempty"`
+	Concurrency int                  `json:"concurrency,omitempty"`
+	Atomic      bool                 `json:"atomic,omitempty"`
+}
+
+// CreateDIDBatchItem create request of a single DID in batch. Method is either 'orb' or 'peer' and Request
+// is CreateOrbDIDRequest or CreatePeerDIDRequest respectively.
+type CreateDIDBatchItem struct {
+	Method  string          `json:"method,omitempty"`
+	Request json.RawMessage `json:"request,omitempty"`
+}
+
+// CreateDIDBatchResponse model
+//
+// This is used for returning results of batch DID creation in the order of request items. RolledBack is set
+// when atomic batch failed and the created DIDs were rolled back.
+//
+type CreateDIDBatchResponse struct {
+	Results    []CreateDIDBatchResult `json:"results"`
+	RolledBack bool                   `json:"rolledBack,omitempty"`
+}
+
+// CreateDIDBatchResult result of a single batch item, either DID resolution of the created DID or the command
+// error code and message.
+type CreateDIDBatchResult struct {
+	DID           string          `json:"did,omitempty"`
+	DocResolution json.RawMessage `json:"docResolution,omitempty"`
+	ErrorCode     command.Code    `json:"errorCode,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	RolledBack    bool            `json:"rolledBack,omitempty"`
+	RollbackError string          `json:"rollbackError,omitempty"`
+}
+
 // CreatePeerDIDRequest model
 //
-// This is used for creating peer DID.
+// This is used for creating peer DID. Key type of the verification method defaults to ed25519. Service type
+// can be either 'did-communication' or 'DIDCommMessaging', a DIDCommMessaging service gets a key agreement key of
+// x25519ecdhkw type unless another key agreement type is given.
 //
 type CreatePeerDIDRequest struct {
 	RouterConnectionID string `json:"routerConnectionID,omitempty"`
+	KeyType            string `json:"keyType,omitempty"`
+	KeyAgreementType   string `json:"keyAgreementType,omitempty"`
+	ServiceType        string `json:"serviceType,omitempty"`
+}
+
+// RotatePeerDIDKeysRequest model
+//
+// This is used for rotating keys of peer DID. A new peer DID with new keys is created on the router of
+// RouterConnectionID, key and service types are the same as for CreatePeerDIDRequest. Recipient keys of the
+// old DID are removed from RouterConnections or from all router connections if none are given.
+//
+type RotatePeerDIDKeysRequest struct {
+	DID                string   `json:"did,omitempty"`
+	RouterConnectionID string   `json:"routerConnectionID,omitempty"`
+	KeyType            string   `json:"keyType,omitempty"`
+	KeyAgreementType   string   `json:"keyAgreementType,omitempty"`
+	ServiceType        string   `json:"serviceType,omitempty"`
+	RouterConnections  []string `json:"routerConnections,omitempty"`
+}
+
+// RotatePeerDIDKeysResponse model
+//
+// This is used for returning the new peer DID along with IDs of the DIDComm V2 connections rotated to it.
+//
+type RotatePeerDIDKeysResponse struct {
+	DID                string          `json:"did"`
+	DIDDocument        json.RawMessage `json:"didDocument"`
+	RotatedConnections []string        `json:"rotatedConnections,omitempty"`
+}
+
+// CreateDIDConfigurationRequest model
+//
+// This is used for creating Domain Linkage Credential and DID configuration resource of DID for the origin.
+// The credential is signed by KeyID, or by the first assertion method of DID if not given, whose key must be
+// kept in the agent KMS. Format is either 'jwt' (default) or 'jsonld'. ExpirationDate defaults to a year.
+//
+type CreateDIDConfigurationRequest struct {
+	DID            string     `json:"did,omitempty"`
+	Origin         string     `json:"origin,omitempty"`
+	KeyID          string     `json:"keyID,omitempty"`
+	Format         string     `json:"format,omitempty"`
+	ExpirationDate *time.Time `json:"expirationDate,omitempty"`
+}
+
+// CreateDIDConfigurationResponse model
+//
+// This is used for returning DID configuration resource to be published at
+// '/.well-known/did-configuration.json' of the origin along with the Domain Linkage Credential in it.
+//
+type CreateDIDConfigurationResponse struct {
+	DIDConfiguration        json.RawMessage `json:"didConfiguration"`
+	DomainLinkageCredential json.RawMessage `json:"domainLinkageCredential"`
+}
+
+// VerifyDIDConfigurationRequest model
+//
+// This is used for verifying that the origin is linked to DID. DID configuration resource is fetched from
+// '/.well-known/did-configuration.json' of the origin unless DIDConfiguration is given.
+//
+type VerifyDIDConfigurationRequest struct {
+	DID              string          `json:"did,omitempty"`
+	Origin           string          `json:"origin,omitempty"`
+	DIDConfiguration json.RawMessage `json:"didConfiguration,omitempty"`
+}
+
+// VerifyDIDConfigurationResponse model
+//
+// This is used for returning result of DID configuration verification. Format is the format of verified
+// Domain Linkage Credential and Error explains why verification failed.
+//
+type VerifyDIDConfigurationResponse struct {
+	Verified bool   `json:"verified"`
+	Format   string `json:"format,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 // PublicKey public key.
@@ -45,3 +341,207 @@ type PublicKey struct {
 	Update   bool     `json:"update,omitempty"`
 	Value    string   `json:"value,omitempty"`
 }
+
+// UpdateOrbDIDRequest model
+//
+// This is used for updating orb DID. UpdateKeyID and NextUpdateKey can be omitted if the DID keys
+// are managed by the agent.
+//
+type UpdateOrbDIDRequest struct {
+	DID               string      `json:"did,omitempty"`
+	AddPublicKeys     []PublicKey `json:"addPublicKeys,omitempty"`
+	RemovePublicKeys  []string    `json:"removePublicKeys,omitempty"`
+	AddServices       []Service   `json:"addServices,omitempty"`
+	RemoveServices    []string    `json:"removeServices,omitempty"`
+	UpdateKeyID       string      `json:"updateKeyID,omitempty"`
+	NextUpdateKey     *PublicKey  `json:"nextUpdateKey,omitempty"`
+	RouterConnections []string    `json:"routerConnections,omitempty"`
+}
+
+// Service DID document service.
+type Service struct {
+	ID              string   `json:"id,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint,omitempty"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+}
+
+// RecoverOrbDIDRequest model
+//
+// This is used for recovering orb DID. RecoveryKeyID, NextUpdateKey and NextRecoveryKey can be omitted
+// if the DID keys are managed by the agent.
+//
+type RecoverOrbDIDRequest struct {
+	DID               string      `json:"did,omitempty"`
+	PublicKeys        []PublicKey `json:"publicKeys,omitempty"`
+	Services          []Service   `json:"services,omitempty"`
+	RecoveryKeyID     string      `json:"recoveryKeyID,omitempty"`
+	NextUpdateKey     *PublicKey  `json:"nextUpdateKey,omitempty"`
+	NextRecoveryKey   *PublicKey  `json:"nextRecoveryKey,omitempty"`
+	RouterConnections []string    `json:"routerConnections,omitempty"`
+}
+
+// DeactivateOrbDIDRequest model
+//
+// This is used for deactivating orb DID. RecoveryKeyID can be omitted if the DID keys are managed
+// by the agent.
+//
+type DeactivateOrbDIDRequest struct {
+	DID               string   `json:"did,omitempty"`
+	RecoveryKeyID     string   `json:"recoveryKeyID,omitempty"`
+	RouterConnections []string `json:"routerConnections,omitempty"`
+}
+
+// SignJWTRequest model
+//
+// This is used for signing JWT claims with a key of DID. Purpose is the verification relationship the key is
+// chosen from, either 'authentication' or 'assertionMethod' which is the default, and KeyID picks one of
+// its keys, otherwise the first one is used. Headers are added to the protected JWT headers.
+//
+type SignJWTRequest struct {
+	DID     string                 `json:"did"`
+	KeyID   string                 `json:"keyID,omitempty"`
+	Purpose string                 `json:"purpose,omitempty"`
+	Claims  json.RawMessage        `json:"claims"`
+	Headers map[string]interface{} `json:"headers,omitempty"`
+}
+
+// SignJWTResponse model
+//
+// This is used for returning signed JWT along with the DID URL of the signing key, which is its 'kid' header.
+//
+type SignJWTResponse struct {
+	JWT   string `json:"jwt"`
+	KeyID string `json:"keyID"`
+}
+
+// VerifyJWTRequest model
+//
+// This is used for verifying JWT signed by a key of DID, which is resolved from its 'kid' header. The key must
+// be in the Purpose verification relationship, 'assertionMethod' by default.
+//
+type VerifyJWTRequest struct {
+	JWT     string `json:"jwt"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// VerifyJWTResponse model
+//
+// This is used for returning claims and headers of verified JWT along with the signer DID and key.
+//
+type VerifyJWTResponse struct {
+	Claims  json.RawMessage        `json:"claims"`
+	Headers map[string]interface{} `json:"headers"`
+	DID     string                 `json:"did"`
+	KeyID   string                 `json:"keyID"`
+}
+
+// SignJWSRequest model
+//
+// This is used for signing payload, base64 encoded in JSON, with a key of DID chosen the same way as for
+// SignJWTRequest. If Detached is set, the payload is left out of the compact JWS.
+//
+type SignJWSRequest struct {
+	DID      string                 `json:"did"`
+	KeyID    string                 `json:"keyID,omitempty"`
+	Purpose  string                 `json:"purpose,omitempty"`
+	Payload  []byte                 `json:"payload"`
+	Detached bool                   `json:"detached,omitempty"`
+	Headers  map[string]interface{} `json:"headers,omitempty"`
+}
+
+// SignJWSResponse model
+//
+// This is used for returning compact JWS along with the DID URL of the signing key.
+//
+type SignJWSResponse struct {
+	JWS   string `json:"jws"`
+	KeyID string `json:"keyID"`
+}
+
+// VerifyJWSRequest model
+//
+// This is used for verifying compact JWS signed by a key of DID the same way as VerifyJWTRequest. Payload
+// of detached JWS has to be given.
+//
+type VerifyJWSRequest struct {
+	JWS     string `json:"jws"`
+	Payload []byte `json:"payload,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// VerifyJWSResponse model
+//
+// This is used for returning payload and headers of verified JWS along with the signer DID and key.
+//
+type VerifyJWSResponse struct {
+	Payload []byte                 `json:"payload"`
+	Headers map[string]interface{} `json:"headers"`
+	DID     string                 `json:"did"`
+	KeyID   string                 `json:"keyID"`
+}
+
+// CreateDIDAuthChallengeRequest model
+//
+// This is used for creating DID Auth challenge by relying party of the Domain, the challenge can be answered
+// until ExpirationDate, five minutes from now by default.
+//
+type CreateDIDAuthChallengeRequest struct {
+	Domain         string     `json:"domain"`
+	ExpirationDate *time.Time `json:"expirationDate,omitempty"`
+}
+
+// CreateDIDAuthChallengeResponse model
+//
+// This is used for returning DID Auth challenge to be sent to the holder along with the domain.
+//
+type CreateDIDAuthChallengeResponse struct {
+	Challenge      string    `json:"challenge"`
+	Domain         string    `json:"domain"`
+	ExpirationDate time.Time `json:"expirationDate"`
+}
+
+// RespondDIDAuthChallengeRequest model
+//
+// This is used for answering DID Auth challenge of the domain with Verifiable Presentation signed by an
+// authentication key of DID, KeyID picks one of the authentication keys. Format is either 'jwt', which is the
+// default, or 'jsonld'.
+//
+type RespondDIDAuthChallengeRequest struct {
+	DID       string `json:"did"`
+	KeyID     string `json:"keyID,omitempty"`
+	Challenge string `json:"challenge"`
+	Domain    string `json:"domain"`
+	Format    string `json:"format,omitempty"`
+}
+
+// RespondDIDAuthChallengeResponse model
+//
+// This is used for returning DID Auth response to be sent to the relying party, Presentation is JWT string
+// or JSON-LD Verifiable Presentation depending on the format.
+//
+type RespondDIDAuthChallengeResponse struct {
+	Presentation json.RawMessage `json:"presentation"`
+	Format       string          `json:"format"`
+}
+
+// VerifyDIDAuthResponseRequest model
+//
+// This is used for verifying DID Auth response to a challenge created by the agent.
+//
+type VerifyDIDAuthResponseRequest struct {
+	Presentation json.RawMessage `json:"presentation"`
+}
+
+// VerifyDIDAuthResponseResponse model
+//
+// This is used for returning result of DID Auth response verification. DID is the authenticated DID and Error
+// explains why verification failed.
+//
+type VerifyDIDAuthResponseResponse struct {
+	Verified bool   `json:"verified"`
+	DID      string `json:"did,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Format   string `json:"format,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
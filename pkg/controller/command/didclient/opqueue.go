@@ -0,0 +1,724 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	endpointclient "github.com/trustbloc/orb/pkg/discovery/endpoint/client"
+	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// DIDOperationTopic is the notifier topic on which results of queued orb DID operations are published.
+	DIDOperationTopic = "didclient-did-operation"
+
+	// queued DID operation statuses.
+	didOperationStatusPending   = "pending"
+	didOperationStatusSubmitted = "submitted"
+	didOperationStatusFailed    = "failed"
+
+	didOperationTypeCreate = "create"
+
+	didOperationKeyPrefix  = "didoperation_"
+	didOperationPendingTag = "didOperationPending"
+
+	defaultOperationInitialBackoff = 5 * time.Second
+	defaultOperationMaxBackoff     = 5 * time.Minute
+	defaultOperationMaxAttempts    = 20
+
+	// defaultOrbUnpublishedDIDLabel is the label Orb gives to unpublished DIDs unless the domain is configured
+	// with another one, Orb doesn't publish the label through endpoint discovery.
+	defaultOrbUnpublishedDIDLabel = "uAAA"
+
+	// capturedOperationEndpoint is operation endpoint given to orb client which builds create requests, the
+	// request is captured instead of being sent.
+	capturedOperationEndpoint = "https://localhost/sidetree/v1/operations"
+)
+
+var (
+	errDIDOperationNotFound = errors.New("pending DID operation not found")
+	// errDIDOperationRejected is returned when Orb rejects the operation, it won't be accepted by a retry.
+	errDIDOperationRejected = errors.New("DID operation rejected")
+	// errSidetreeRequestCaptured stops orb client from sending Sidetree request which gets queued instead.
+	errSidetreeRequestCaptured = errors.New("sidetree request captured")
+)
+
+// PendingDIDOperation orb DID operation queued for submission.
+type PendingDIDOperation struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	DID         string    `json:"did"`
+	LongFormDID string    `json:"longFormDID,omitempty"`
+	Domain      string    `json:"domain,omitempty"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"lastError,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	NextAttempt time.Time `json:"nextAttempt,omitempty"`
+}
+
+// DIDOperationEvent is published on DIDOperationTopic once queued operation gets submitted or fails permanently.
+type DIDOperationEvent struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	DID    string `json:"did"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type queuedDIDOperation struct {
+	PendingDIDOperation
+	Request json.RawMessage `json:"request"`
+}
+
+// didOperationQueue keeps orb DID operations in the store and resubmits them with exponential backoff until
+// they are accepted, rejected or run out of attempts.
+type didOperationQueue struct {
+	submit         func(ctx context.Context, request []byte, domain string) error
+	onSubmitted    func(op *PendingDIDOperation)
+	store          storage.Store
+	notifier       ariescmd.Notifier
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxAttempts    int
+	mutex          sync.Mutex
+	workers        map[string]chan struct{}
+	ctx            context.Context
+	cancelCtx      context.CancelFunc
+	running        sync.WaitGroup
+	closed         bool
+}
+
+func newDIDOperationQueue(submit func(context.Context, []byte, string) error,
+	onSubmitted func(*PendingDIDOperation), store storage.Store, notifier ariescmd.Notifier) *didOperationQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &didOperationQueue{
+		submit:         submit,
+		onSubmitted:    onSubmitted,
+		store:          store,
+		notifier:       notifier,
+		initialBackoff: defaultOperationInitialBackoff,
+		maxBackoff:     defaultOperationMaxBackoff,
+		maxAttempts:    defaultOperationMaxAttempts,
+		workers:        make(map[string]chan struct{}),
+		ctx:            ctx,
+		cancelCtx:      cancel,
+	}
+}
+
+// resume restarts submission of operations queued before the agent was stopped.
+func (q *didOperationQueue) resume() error {
+	ops, err := q.list()
+	if err != nil {
+		return err
+	}
+
+	for i := range ops {
+		q.start(ops[i].ID)
+	}
+
+	return nil
+}
+
+// enqueue saves operation and starts submitting it, operation is submitted to the given Orb domain if it is set.
+func (q *didOperationQueue) enqueue(opType, didID, longFormDID, domain string,
+	request []byte) (*PendingDIDOperation, error) {
+	op := &queuedDIDOperation{
+		PendingDIDOperation: PendingDIDOperation{
+			ID:          uuid.New().String(),
+			Type:        opType,
+			DID:         didID,
+			LongFormDID: longFormDID,
+			Domain:      domain,
+			Status:      didOperationStatusPending,
+			CreatedAt:   time.Now().UTC(),
+		},
+		Request: request,
+	}
+
+	err := q.put(op)
+	if err != nil {
+		return nil, err
+	}
+
+	q.start(op.ID)
+
+	return &op.PendingDIDOperation, nil
+}
+
+// cancel removes operation from the queue, an operation which was already accepted by Orb can't be recalled.
+func (q *didOperationQueue) cancel(id string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	_, err := q.get(id)
+	if err != nil {
+		return err
+	}
+
+	err = q.store.Delete(didOperationKeyPrefix + id)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending DID operation : %w", err)
+	}
+
+	if stop, ok := q.workers[id]; ok {
+		close(stop)
+		delete(q.workers, id)
+	}
+
+	return nil
+}
+
+// close stops submitting operations and waits for submissions in progress to stop, operations stay queued and
+// are resumed by the next queue created on the same store.
+func (q *didOperationQueue) close() {
+	q.mutex.Lock()
+	q.closed = true
+	q.mutex.Unlock()
+
+	q.cancelCtx()
+	q.running.Wait()
+}
+
+func (q *didOperationQueue) start(id string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, ok := q.workers[id]; ok || q.closed {
+		return
+	}
+
+	stop := make(chan struct{})
+	q.workers[id] = stop
+
+	q.running.Add(1)
+
+	go func() {
+		defer q.running.Done()
+
+		q.run(id, stop)
+	}()
+}
+
+func (q *didOperationQueue) run(id string, stop chan struct{}) {
+	backoff := q.initialBackoff
+
+	for {
+		op, err := q.get(id)
+		if err != nil {
+			logger.Warnf("stopped submitting DID operation %s : %s", id, err)
+
+			return
+		}
+
+		err = q.submit(q.ctx, op.Request, op.Domain)
+		if err != nil && q.ctx.Err() != nil {
+			// the attempt interrupted by closing the queue is made again once operation is resumed.
+			return
+		}
+
+		if err == nil || errors.Is(err, errDIDOperationRejected) || op.Attempts+1 >= q.maxAttempts {
+			q.complete(op, err, stop)
+
+			return
+		}
+
+		logger.Warnf("failed to submit DID operation %s, retrying in %s : %s", id, backoff, err)
+
+		op.Attempts++
+		op.LastError = err.Error()
+		op.NextAttempt = time.Now().Add(backoff).UTC()
+
+		if !q.update(op, stop) {
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-q.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > q.maxBackoff {
+			backoff = q.maxBackoff
+		}
+	}
+}
+
+// update saves progress of operation unless it was cancelled meanwhile.
+func (q *didOperationQueue) update(op *queuedDIDOperation, stop chan struct{}) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if cancelled(stop) {
+		return false
+	}
+
+	err := q.put(op)
+	if err != nil {
+		logger.Warnf("failed to save DID operation %s : %s", op.ID, err)
+	}
+
+	return true
+}
+
+// complete removes submitted or permanently failed operation and notifies subscribers.
+func (q *didOperationQueue) complete(op *queuedDIDOperation, submitErr error, stop chan struct{}) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if cancelled(stop) {
+		return
+	}
+
+	delete(q.workers, op.ID)
+
+	err := q.store.Delete(didOperationKeyPrefix + op.ID)
+	if err != nil {
+		logger.Warnf("failed to delete DID operation %s : %s", op.ID, err)
+	}
+
+	event := &DIDOperationEvent{ID: op.ID, Type: op.Type, DID: op.DID, Status: didOperationStatusSubmitted}
+
+	if submitErr != nil {
+		event.Status = didOperationStatusFailed
+		event.Error = submitErr.Error()
+
+		logger.Errorf("DID operation %s failed : %s", op.ID, submitErr)
+	} else if q.onSubmitted != nil {
+		q.onSubmitted(&op.PendingDIDOperation)
+	}
+
+	msg, err := json.Marshal(event)
+	if err != nil {
+		logger.Warnf("failed to marshal DID operation event : %s", err)
+
+		return
+	}
+
+	err = q.notifier.Notify(DIDOperationTopic, msg)
+	if err != nil {
+		logger.Warnf("failed to notify DID operation %s : %s", op.ID, err)
+	}
+}
+
+func (q *didOperationQueue) list() ([]*queuedDIDOperation, error) {
+	iter, err := q.store.Query(didOperationPendingTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending DID operations : %w", err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("failed to close iterator : %s", e)
+		}
+	}()
+
+	var ops []*queuedDIDOperation
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next pending DID operation : %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		data, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending DID operation : %w", err)
+		}
+
+		op := &queuedDIDOperation{}
+
+		err = json.Unmarshal(data, op)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pending DID operation : %w", err)
+		}
+
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.Before(ops[j].CreatedAt)
+	})
+
+	return ops, nil
+}
+
+func (q *didOperationQueue) get(id string) (*queuedDIDOperation, error) {
+	data, err := q.store.Get(didOperationKeyPrefix + id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w : %s", errDIDOperationNotFound, id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending DID operation : %w", err)
+	}
+
+	op := &queuedDIDOperation{}
+
+	err = json.Unmarshal(data, op)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending DID operation : %w", err)
+	}
+
+	return op, nil
+}
+
+func (q *didOperationQueue) put(op *queuedDIDOperation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending DID operation : %w", err)
+	}
+
+	err = q.store.Put(didOperationKeyPrefix+op.ID, data, storage.Tag{Name: didOperationPendingTag})
+	if err != nil {
+		return fmt.Errorf("failed to save pending DID operation : %w", err)
+	}
+
+	return nil
+}
+
+func cancelled(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListPendingDIDOperations lists orb DID operations waiting to be submitted.
+func (c *Command) ListPendingDIDOperations(rw io.Writer, _ io.Reader) command.Error {
+	ops, err := c.operationQueue.list()
+	if err != nil {
+		logutil.LogError(logger, CommandName, ListPendingDIDOperationsCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDOperationQueueErrorCode, err)
+	}
+
+	resp := &ListPendingDIDOperationsResponse{Operations: []PendingDIDOperation{}}
+
+	for _, op := range ops {
+		resp.Operations = append(resp.Operations, op.PendingDIDOperation)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, ListPendingDIDOperationsCommandMethod, successString)
+
+	return nil
+}
+
+// CancelPendingDIDOperation removes orb DID operation from the queue.
+func (c *Command) CancelPendingDIDOperation(rw io.Writer, req io.Reader) command.Error {
+	var request CancelPendingDIDOperationRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CancelPendingDIDOperationCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ID == "" {
+		logutil.LogError(logger, CommandName, CancelPendingDIDOperationCommandMethod, errInvalidOperationID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidOperationID))
+	}
+
+	err = c.operationQueue.cancel(request.ID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CancelPendingDIDOperationCommandMethod, err.Error())
+
+		return command.NewExecuteError(DIDOperationQueueErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, CancelPendingDIDOperationCommandMethod, successString)
+
+	return nil
+}
+
+// queueOrbDIDCreation builds Sidetree create request of orb DID and queues it for submission. The returned
+// resolution is the one of the long-form DID, short-form DID is its equivalent ID.
+func (c *Command) queueOrbDIDCreation(didDoc *did.Doc, opts []vdr.DIDMethodOption) (*did.DocResolution, error) {
+	request, anchorOrigin, label, err := c.orbCreateRequest(didDoc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	shortFormDID, longFormDID, err := orbDIDFromCreateRequest(request, label)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.operationQueue.enqueue(didOperationTypeCreate, shortFormDID, longFormDID, pinnedOrbDomain(opts),
+		request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &did.DocResolution{
+		DIDDocument: longFormDIDDoc(didDoc, longFormDID),
+		DocumentMetadata: &did.DocumentMetadata{
+			EquivalentID: []string{shortFormDID},
+			Method:       &did.MethodMetadata{AnchorOrigin: anchorOrigin},
+		},
+	}, nil
+}
+
+// orbCreateRequest builds Sidetree create request of orb DID with the given create options through orb client,
+// it also returns anchor origin of the DID and the label of unpublished DIDs of the domain the DID is created through.
+func (c *Command) orbCreateRequest(didDoc *did.Doc, opts []vdr.DIDMethodOption) ([]byte, string, string, error) {
+	domain := pinnedOrbDomain(opts)
+
+	label, err := c.orbDomains.unpublishedDIDLabel(domain)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	anchorOrigin, _ := orbOption(opts, orb.AnchorOriginOpt).(string) // nolint:errcheck
+	if anchorOrigin == "" {
+		anchorOrigin, err = c.orbDomains.anchorOrigin(domain)
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	capture := &sidetreeRequestCapture{}
+
+	orbClient, err := orb.New(nil, orb.WithDomain(anchorOrigin), orb.WithDocumentLoader(c.documentLoader),
+		orb.WithHTTPClient(&http.Client{Transport: capture}))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create orb client : %w", err)
+	}
+
+	createOpts := append([]vdr.DIDMethodOption{}, opts...)
+	createOpts = append(createOpts, vdr.WithOption(orb.AnchorOriginOpt, anchorOrigin),
+		vdr.WithOption(orb.OperationEndpointsOpt, []string{capturedOperationEndpoint}))
+
+	_, err = orbClient.Create(didDoc, createOpts...)
+	if !errors.Is(err, errSidetreeRequestCaptured) {
+		return nil, "", "", fmt.Errorf("failed to build create request : %w", err)
+	}
+
+	return capture.request, anchorOrigin, label, nil
+}
+
+// sidetreeRequestCapture is HTTP transport of orb client, it captures Sidetree request instead of sending it.
+type sidetreeRequestCapture struct {
+	request []byte
+}
+
+func (t *sidetreeRequestCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	t.request = request
+
+	return nil, errSidetreeRequestCaptured
+}
+
+// longFormDIDDoc returns document the way it is resolved from long-form DID before the DID gets published.
+func longFormDIDDoc(didDoc *did.Doc, longFormDID string) *did.Doc {
+	relative := func(vm did.VerificationMethod) did.VerificationMethod {
+		vm.ID = "#" + vm.ID[strings.LastIndex(vm.ID, "#")+1:]
+		vm.Controller = longFormDID
+
+		return vm
+	}
+
+	longFormDoc := &did.Doc{Context: []string{did.ContextV1}, ID: longFormDID, Service: didDoc.Service}
+	methods := make(map[string]bool)
+
+	// referenced verification methods are listed in the document.
+	relativeVerifications := func(verifications []did.Verification) []did.Verification {
+		var result []did.Verification
+
+		for _, v := range verifications {
+			v.VerificationMethod = relative(v.VerificationMethod)
+			result = append(result, v)
+
+			if !methods[v.VerificationMethod.ID] {
+				methods[v.VerificationMethod.ID] = true
+				longFormDoc.VerificationMethod = append(longFormDoc.VerificationMethod, v.VerificationMethod)
+			}
+		}
+
+		return result
+	}
+
+	longFormDoc.Authentication = relativeVerifications(didDoc.Authentication)
+	longFormDoc.AssertionMethod = relativeVerifications(didDoc.AssertionMethod)
+	longFormDoc.CapabilityDelegation = relativeVerifications(didDoc.CapabilityDelegation)
+	longFormDoc.CapabilityInvocation = relativeVerifications(didDoc.CapabilityInvocation)
+	longFormDoc.KeyAgreement = relativeVerifications(didDoc.KeyAgreement)
+
+	return longFormDoc
+}
+
+// orbDIDFromCreateRequest returns unpublished short-form orb DID with the given label and long-form DID of Sidetree
+// create request, DID suffix is hashed with the multihash algorithm of the request.
+func orbDIDFromCreateRequest(request []byte, label string) (string, string, error) {
+	createRequest := &model.CreateRequest{}
+
+	err := json.Unmarshal(request, createRequest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse create request : %w", err)
+	}
+
+	if createRequest.SuffixData == nil {
+		return "", "", errors.New("failed to parse create request : suffix data is missing")
+	}
+
+	multihashCode, err := hashing.GetMultihashCode(createRequest.SuffixData.DeltaHash)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get multihash algorithm of create request : %w", err)
+	}
+
+	suffix, err := hashing.CalculateModelMultihash(createRequest.SuffixData, uint(multihashCode))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to calculate DID suffix : %w", err)
+	}
+
+	// long-form: '<namespace>:<unique-portion>:Base64url(JCS({suffixData, delta}))'
+	initialState, err := canonicalizer.MarshalCanonical(&model.CreateRequest{
+		SuffixData: createRequest.SuffixData,
+		Delta:      createRequest.Delta,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to canonicalize create request : %w", err)
+	}
+
+	shortFormDID := fmt.Sprintf("did:%s:%s:%s", orb.DIDMethod, label, suffix)
+
+	return shortFormDID, shortFormDID + ":" + encoder.EncodeToString(initialState), nil
+}
+
+// submitOrbOperation posts Sidetree operation to the operation endpoint of the given Orb domain, or of the first
+// reachable domain if none is given.
+func (c *Command) submitOrbOperation(ctx context.Context, request []byte, domain string) error {
+	return c.orbDomains.do([]vdr.DIDMethodOption{vdr.WithOption(orbDomainOpt, domain)}, func(d *orbDomainClient) error {
+		return c.submitOrbOperationToDomain(ctx, request, &d.OrbDomain)
+	})
+}
+
+func (c *Command) submitOrbOperationToDomain(ctx context.Context, request []byte, d *OrbDomain) error {
+	domain := orbDomainURL(d.URL)
+
+	// operation endpoints are discovered by the endpoint discovery of orb VDR.
+	discovery, err := endpointclient.New(c.documentLoader, &unsupportedCASReader{},
+		endpointclient.WithHTTPClient(c.httpClient), endpointclient.WithAuthToken(d.Token))
+	if err != nil {
+		return fmt.Errorf("failed to create endpoint discovery : %w", err)
+	}
+
+	endpoint, err := discovery.GetEndpoint(domain)
+	if err != nil {
+		return fmt.Errorf("failed to discover operation endpoint of %s : %w", domain, err)
+	}
+
+	if len(endpoint.OperationEndpoints) == 0 {
+		return fmt.Errorf("failed to discover operation endpoint of %s : no operation endpoints", domain)
+	}
+
+	_, err = c.sendOrbRequest(ctx, http.MethodPost, endpoint.OperationEndpoints[0], d.Token, request)
+	if err != nil {
+		return fmt.Errorf("failed to submit operation to %s : %w", endpoint.OperationEndpoints[0], err)
+	}
+
+	return nil
+}
+
+// unsupportedCASReader is CAS reader of endpoint discovery, CAS isn't needed to discover endpoints of a domain.
+type unsupportedCASReader struct{}
+
+func (r *unsupportedCASReader) Read(string) ([]byte, error) {
+	return nil, errors.New("reading from CAS is not supported")
+}
+
+func (c *Command) sendOrbRequest(ctx context.Context, method, url, token string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("failed to close response body : %s", e)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return respBody, nil
+	}
+
+	err = fmt.Errorf("status %d : %s", resp.StatusCode, respBody)
+
+	// Orb won't accept the operation unless the request changes.
+	if method == http.MethodPost && resp.StatusCode >= http.StatusBadRequest &&
+		resp.StatusCode < http.StatusInternalServerError &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		err = fmt.Errorf("%w : %s", errDIDOperationRejected, err.Error())
+	}
+
+	return nil, err
+}
@@ -0,0 +1,434 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+// mockOrbServer serves Orb well-known document and operation endpoint, responding to posted operations with
+// the given statuses in turn.
+type mockOrbServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	statuses []int
+	requests [][]byte
+}
+
+func newMockOrbServer(statuses ...int) *mockOrbServer {
+	s := &mockOrbServer{statuses: statuses}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/.well-known/did-orb":
+			fmt.Fprintf(w, `{"resolutionEndpoint":"%s/sidetree/v1/identifiers",`+
+				`"operationEndpoint":"%s/sidetree/v1/operations"}`, s.URL, s.URL)
+
+			return
+		case r.Method == http.MethodGet && r.URL.Path == "/.well-known/webfinger":
+			fmt.Fprintf(w, `{"properties":{"https://trustbloc.dev/ns/min-resolvers":1},`+
+				`"links":[{"rel":"self","href":"%s"}]}`, r.URL.Query().Get("resource"))
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requests = append(s.requests, body)
+
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+
+		w.WriteHeader(status)
+	}))
+
+	return s
+}
+
+func (s *mockOrbServer) submitted() [][]byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([][]byte(nil), s.requests...)
+}
+
+func TestCommand_CreateOrbDIDQueued(t *testing.T) {
+	newCommand := func(t *testing.T, server *mockOrbServer, events chan *DIDOperationEvent) *Command {
+		t.Helper()
+
+		c, err := New(server.URL, "origin", "", 0, getMockProvider(), &mocks.Notifier{
+			NotifyFunc: func(topic string, message []byte) error {
+				if topic != DIDOperationTopic {
+					return nil
+				}
+
+				event := &DIDOperationEvent{}
+				require.NoError(t, json.Unmarshal(message, event))
+
+				events <- event
+
+				return nil
+			},
+		}, WithDIDOperationRetry(time.Millisecond, 5*time.Millisecond, 3))
+		require.NoError(t, err)
+
+		c.keyManager, err = localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		c.crypto, err = tinkcrypto.New()
+		require.NoError(t, err)
+
+		c.httpClient = server.Client()
+		c.didBlocClient = &mockDIDClient{createDIDErr: errors.New("create must not be called")}
+		c.publicationTracker.initialBackoff = time.Hour
+
+		return c
+	}
+
+	createQueued := func(t *testing.T, c *Command) (*did.DocResolution, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(CreateOrbDIDRequest{ManagedKeys: true, Queued: true, PublicKeys: []PublicKey{
+			{ID: "key1", Type: ed25519VerificationKey2018, KeyType: ed25519KeyType, Purposes: []string{"authentication"}},
+		}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateOrbDID(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		docResolution, err := did.ParseDocumentResolution(b.Bytes())
+		require.NoError(t, err)
+
+		return docResolution, nil
+	}
+
+	listPending := func(t *testing.T, c *Command) []PendingDIDOperation {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.ListPendingDIDOperations(&b, nil))
+
+		resp := &ListPendingDIDOperationsResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp.Operations
+	}
+
+	t.Run("test success after retry", func(t *testing.T) {
+		server := newMockOrbServer(http.StatusServiceUnavailable)
+		defer server.Close()
+
+		events := make(chan *DIDOperationEvent, 1)
+		c := newCommand(t, server, events)
+
+		docResolution, cmdErr := createQueued(t, c)
+		require.NoError(t, cmdErr)
+		require.Len(t, docResolution.DocumentMetadata.EquivalentID, 1)
+
+		shortFormDID := docResolution.DocumentMetadata.EquivalentID[0]
+		require.True(t, strings.HasPrefix(shortFormDID, "did:orb:"+defaultOrbUnpublishedDIDLabel+":"))
+		require.True(t, strings.HasPrefix(docResolution.DIDDocument.ID, shortFormDID+":"))
+
+		select {
+		case event := <-events:
+			require.Equal(t, didOperationStatusSubmitted, event.Status)
+			require.Equal(t, didOperationTypeCreate, event.Type)
+			require.Equal(t, shortFormDID, event.DID)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for DID operation event")
+		}
+
+		requests := server.submitted()
+		require.Len(t, requests, 2)
+		require.Equal(t, requests[0], requests[1])
+
+		submittedShortForm, submittedLongForm, err := orbDIDFromCreateRequest(requests[1], defaultOrbUnpublishedDIDLabel)
+		require.NoError(t, err)
+		require.Equal(t, shortFormDID, submittedShortForm)
+		require.Equal(t, docResolution.DIDDocument.ID, submittedLongForm)
+
+		require.Empty(t, listPending(t, c))
+
+		keys, err := c.managedKeys.get(shortFormDID)
+		require.NoError(t, err)
+		require.Equal(t, shortFormDID, keys.DID)
+	})
+
+	t.Run("test operation rejected", func(t *testing.T) {
+		server := newMockOrbServer(http.StatusBadRequest)
+		defer server.Close()
+
+		events := make(chan *DIDOperationEvent, 1)
+		c := newCommand(t, server, events)
+
+		_, cmdErr := createQueued(t, c)
+		require.NoError(t, cmdErr)
+
+		select {
+		case event := <-events:
+			require.Equal(t, didOperationStatusFailed, event.Status)
+			require.Contains(t, event.Error, errDIDOperationRejected.Error())
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for DID operation event")
+		}
+
+		require.Len(t, server.submitted(), 1)
+		require.Empty(t, listPending(t, c))
+	})
+
+	t.Run("test attempts exhausted", func(t *testing.T) {
+		server := newMockOrbServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+			http.StatusServiceUnavailable)
+		defer server.Close()
+
+		events := make(chan *DIDOperationEvent, 1)
+		c := newCommand(t, server, events)
+
+		_, cmdErr := createQueued(t, c)
+		require.NoError(t, cmdErr)
+
+		select {
+		case event := <-events:
+			require.Equal(t, didOperationStatusFailed, event.Status)
+			require.Contains(t, event.Error, "status 503")
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for DID operation event")
+		}
+
+		require.Len(t, server.submitted(), 3)
+	})
+
+	t.Run("test list and cancel pending operation", func(t *testing.T) {
+		server := newMockOrbServer(http.StatusServiceUnavailable)
+		defer server.Close()
+
+		events := make(chan *DIDOperationEvent, 1)
+		c := newCommand(t, server, events)
+		c.operationQueue.initialBackoff = time.Hour
+
+		docResolution, cmdErr := createQueued(t, c)
+		require.NoError(t, cmdErr)
+
+		require.Eventually(t, func() bool {
+			ops := listPending(t, c)
+
+			return len(ops) == 1 && ops[0].Attempts == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		ops := listPending(t, c)
+		require.Equal(t, docResolution.DocumentMetadata.EquivalentID[0], ops[0].DID)
+		require.Equal(t, docResolution.DIDDocument.ID, ops[0].LongFormDID)
+		require.Equal(t, didOperationStatusPending, ops[0].Status)
+		require.Contains(t, ops[0].LastError, "status 503")
+
+		req, err := json.Marshal(CancelPendingDIDOperationRequest{ID: ops[0].ID})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.CancelPendingDIDOperation(&b, bytes.NewBuffer(req)))
+		require.Empty(t, listPending(t, c))
+
+		cmdErr = c.CancelPendingDIDOperation(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, DIDOperationQueueErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Contains(t, cmdErr.Error(), errDIDOperationNotFound.Error())
+
+		select {
+		case <-events:
+			require.Fail(t, "cancelled operation must not be completed")
+		default:
+		}
+	})
+
+	t.Run("test resume after restart", func(t *testing.T) {
+		store := mockstorage.NewMockStoreProvider().Store
+		request := []byte(`{"type":"create"}`)
+
+		stopped := newDIDOperationQueue(func(context.Context, []byte, string) error {
+			return errors.New("orb is not reachable")
+		}, nil, store, mocks.NewMockNotifier())
+		stopped.initialBackoff = time.Hour
+
+		op, err := stopped.enqueue(didOperationTypeCreate, "did:orb:uAAA:EiA123", "", "", request)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			pending, e := stopped.get(op.ID)
+
+			return e == nil && pending.Attempts == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		events := make(chan *DIDOperationEvent, 1)
+
+		q := newDIDOperationQueue(func(_ context.Context, req []byte, _ string) error {
+			require.Equal(t, request, req)
+
+			return nil
+		}, nil, store, &mocks.Notifier{NotifyFunc: func(_ string, message []byte) error {
+			event := &DIDOperationEvent{}
+			require.NoError(t, json.Unmarshal(message, event))
+
+			events <- event
+
+			return nil
+		}})
+
+		require.NoError(t, q.resume())
+
+		select {
+		case event := <-events:
+			require.Equal(t, op.ID, event.ID)
+			require.Equal(t, didOperationStatusSubmitted, event.Status)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for DID operation event")
+		}
+
+		ops, err := q.list()
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+
+	t.Run("test close", func(t *testing.T) {
+		submitting := make(chan struct{})
+
+		q := newDIDOperationQueue(func(ctx context.Context, _ []byte, _ string) error {
+			close(submitting)
+			<-ctx.Done()
+
+			return ctx.Err()
+		}, nil, mockstorage.NewMockStoreProvider().Store, mocks.NewMockNotifier())
+
+		op, err := q.enqueue(didOperationTypeCreate, "did:orb:uAAA:EiA123", "", "", []byte(`{}`))
+		require.NoError(t, err)
+
+		select {
+		case <-submitting:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for DID operation submission")
+		}
+
+		// submission in progress is interrupted and operation stays queued.
+		q.close()
+
+		pending, err := q.get(op.ID)
+		require.NoError(t, err)
+		require.Zero(t, pending.Attempts)
+
+		// operations queued after close are kept for the next queue.
+		op, err = q.enqueue(didOperationTypeCreate, "did:orb:uAAA:EiA456", "", "", []byte(`{}`))
+		require.NoError(t, err)
+
+		ops, err := q.list()
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.NotContains(t, q.workers, op.ID)
+	})
+
+	t.Run("test missing update and recovery keys", func(t *testing.T) {
+		server := newMockOrbServer()
+		defer server.Close()
+
+		c := newCommand(t, server, make(chan *DIDOperationEvent, 1))
+
+		req, err := json.Marshal(CreateOrbDIDRequest{Queued: true})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to build create request : updatePublicKey opt is empty")
+	})
+}
+
+func TestCommand_CancelPendingDIDOperation(t *testing.T) {
+	c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+
+	cmdErr := c.CancelPendingDIDOperation(&b, bytes.NewBufferString("--"))
+	require.Error(t, cmdErr)
+	require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	require.Equal(t, command.ValidationError, cmdErr.Type())
+
+	cmdErr = c.CancelPendingDIDOperation(&b, bytes.NewBufferString("{}"))
+	require.Error(t, cmdErr)
+	require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	require.Contains(t, cmdErr.Error(), errInvalidOperationID)
+}
+
+func TestDIDOperationQueue_StoreErrors(t *testing.T) {
+	q := newDIDOperationQueue(nil, nil, &mockstorage.MockStore{
+		Store:     map[string]mockstorage.DBEntry{},
+		ErrPut:    fmt.Errorf("put error"),
+		ErrQuery:  fmt.Errorf("query error"),
+		ErrGet:    fmt.Errorf("get error"),
+		ErrDelete: fmt.Errorf("delete error"),
+	}, mocks.NewMockNotifier())
+
+	_, err := q.enqueue(didOperationTypeCreate, "did:orb:uAAA:EiA123", "", "", []byte("{}"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "put error")
+
+	require.Error(t, q.resume())
+
+	err = q.cancel("123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "get error")
+
+	c := &Command{operationQueue: q}
+
+	var b bytes.Buffer
+
+	cmdErr := c.ListPendingDIDOperations(&b, nil)
+	require.Error(t, cmdErr)
+	require.Equal(t, DIDOperationQueueErrorCode, cmdErr.Code())
+	require.Contains(t, cmdErr.Error(), "query error")
+}
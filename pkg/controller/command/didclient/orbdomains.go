@@ -0,0 +1,282 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+)
+
+const (
+	// orbDomainOpt pins orb DID operation to the given Orb domain.
+	orbDomainOpt = "orbDomain"
+
+	defaultOrbDomainCooldown = 30 * time.Second
+)
+
+var errUnknownOrbDomain = errors.New("unknown orb domain")
+
+// OrbDomain Orb domain DID operations are sent to. Token authorizes Sidetree requests to the domain and
+// AnchorOrigin is used for DIDs created through it, unless the create request sets its own. UnpublishedDIDLabel
+// is the label the domain gives to DIDs which aren't published yet, if it isn't the default one of Orb. Domains
+// are tried in ascending order of Priority, round-robin among domains of the same priority.
+type OrbDomain struct {
+	URL                 string `json:"url"`
+	Token               string `json:"token,omitempty"`
+	AnchorOrigin        string `json:"anchorOrigin,omitempty"`
+	UnpublishedDIDLabel string `json:"unpublishedDIDLabel,omitempty"`
+	Priority            int    `json:"priority,omitempty"`
+}
+
+// WithOrbDomains adds Orb domains which DID operations fail over to when the domain given to New is not
+// reachable.
+func WithOrbDomains(domains ...OrbDomain) Option {
+	return func(opts *options) {
+		opts.orbDomains = append(opts.orbDomains, domains...)
+	}
+}
+
+type orbDomainClient struct {
+	OrbDomain
+	client         didBlocClient
+	unhealthyUntil time.Time
+}
+
+// orbDomainPool sends orb DID operations to Orb domains, it skips domains which couldn't be reached
+// until their cooldown is over and tries them only when healthy domains fail too.
+type orbDomainPool struct {
+	domains  []*orbDomainClient
+	cooldown time.Duration
+	mutex    sync.Mutex
+	next     map[int]int
+}
+
+func newOrbDomainPool(domains []OrbDomain, newClient func(domain *OrbDomain) (didBlocClient, error)) (
+	*orbDomainPool, error) {
+	p := &orbDomainPool{cooldown: defaultOrbDomainCooldown, next: make(map[int]int)}
+
+	for i := range domains {
+		client, err := newClient(&domains[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client of orb domain %s : %w", domains[i].URL, err)
+		}
+
+		p.domains = append(p.domains, &orbDomainClient{OrbDomain: domains[i], client: client})
+	}
+
+	// stable sort keeps configuration order within the same priority.
+	sort.SliceStable(p.domains, func(i, j int) bool {
+		return p.domains[i].Priority < p.domains[j].Priority
+	})
+
+	return p, nil
+}
+
+// candidates returns domains in the order they should be tried, pinned domain is the only candidate.
+func (p *orbDomainPool) candidates(pinned string) ([]*orbDomainClient, error) {
+	if pinned != "" {
+		for _, d := range p.domains {
+			if orbDomainURL(d.URL) == orbDomainURL(pinned) {
+				return []*orbDomainClient{d}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%w : %s", errUnknownOrbDomain, pinned)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+
+	var healthy, unhealthy []*orbDomainClient
+
+	for i := 0; i < len(p.domains); {
+		// domains of the same priority
+		j := i
+		for j < len(p.domains) && p.domains[j].Priority == p.domains[i].Priority {
+			j++
+		}
+
+		group := p.domains[i:j]
+		start := p.next[group[0].Priority] % len(group)
+		p.next[group[0].Priority] = start + 1
+
+		for k := range group {
+			d := group[(start+k)%len(group)]
+
+			if now.Before(d.unhealthyUntil) {
+				unhealthy = append(unhealthy, d)
+
+				continue
+			}
+
+			healthy = append(healthy, d)
+		}
+
+		i = j
+	}
+
+	return append(healthy, unhealthy...), nil
+}
+
+func (p *orbDomainPool) setHealthy(d *orbDomainClient, healthy bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if healthy {
+		d.unhealthyUntil = time.Time{}
+
+		return
+	}
+
+	d.unhealthyUntil = time.Now().Add(p.cooldown)
+}
+
+// do runs operation against candidate domains until one of them is reachable.
+func (p *orbDomainPool) do(opts []vdr.DIDMethodOption, op func(d *orbDomainClient) error) error {
+	domains, err := p.candidates(pinnedOrbDomain(opts))
+	if err != nil {
+		return err
+	}
+
+	for _, d := range domains {
+		err = op(d)
+		if err == nil || !isUnreachableError(err) {
+			p.setHealthy(d, err == nil)
+
+			return err
+		}
+
+		logger.Warnf("orb domain %s is not reachable : %s", d.URL, err)
+
+		p.setHealthy(d, false)
+	}
+
+	return err
+}
+
+// anchorOrigin returns anchor origin of DIDs created through the domain which would be tried first.
+func (p *orbDomainPool) anchorOrigin(pinned string) (string, error) {
+	domains, err := p.candidates(pinned)
+	if err != nil {
+		return "", err
+	}
+
+	if domains[0].AnchorOrigin != "" {
+		return domains[0].AnchorOrigin, nil
+	}
+
+	return domains[0].URL, nil
+}
+
+// unpublishedDIDLabel returns label of unpublished DIDs created through the pinned domain, or through the first
+// domain if none is pinned.
+func (p *orbDomainPool) unpublishedDIDLabel(pinned string) (string, error) {
+	domains, err := p.candidates(pinned)
+	if err != nil {
+		return "", err
+	}
+
+	if domains[0].UnpublishedDIDLabel != "" {
+		return domains[0].UnpublishedDIDLabel, nil
+	}
+
+	return defaultOrbUnpublishedDIDLabel, nil
+}
+
+// Create creates orb DID through the first reachable domain, anchor origin of the domain is used unless
+// the options set one.
+func (p *orbDomainPool) Create(didDoc *did.Doc, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	var docResolution *did.DocResolution
+
+	err := p.do(opts, func(d *orbDomainClient) error {
+		createOpts := opts
+
+		if orbOption(opts, orb.AnchorOriginOpt) == nil {
+			createOpts = append([]vdr.DIDMethodOption{vdr.WithOption(orb.AnchorOriginOpt, d.AnchorOrigin)}, opts...)
+		}
+
+		var err error
+
+		docResolution, err = d.client.Create(didDoc, createOpts...)
+
+		return err
+	})
+
+	return docResolution, err
+}
+
+// Read resolves orb DID through the first reachable domain.
+func (p *orbDomainPool) Read(didID string, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	var docResolution *did.DocResolution
+
+	err := p.do(opts, func(d *orbDomainClient) error {
+		var err error
+
+		docResolution, err = d.client.Read(didID, opts...)
+
+		return err
+	})
+
+	return docResolution, err
+}
+
+// Update updates orb DID through the first reachable domain.
+func (p *orbDomainPool) Update(didDoc *did.Doc, opts ...vdr.DIDMethodOption) error {
+	return p.do(opts, func(d *orbDomainClient) error {
+		return d.client.Update(didDoc, opts...)
+	})
+}
+
+// Deactivate deactivates orb DID through the first reachable domain.
+func (p *orbDomainPool) Deactivate(didID string, opts ...vdr.DIDMethodOption) error {
+	return p.do(opts, func(d *orbDomainClient) error {
+		return d.client.Deactivate(didID, opts...)
+	})
+}
+
+func pinnedOrbDomain(opts []vdr.DIDMethodOption) string {
+	domain, _ := orbOption(opts, orbDomainOpt).(string) // nolint:errcheck
+
+	return domain
+}
+
+func orbOption(opts []vdr.DIDMethodOption, key string) interface{} {
+	didMethodOpts := &vdr.DIDMethodOpts{Values: make(map[string]interface{})}
+
+	for _, opt := range opts {
+		opt(didMethodOpts)
+	}
+
+	return didMethodOpts.Values[key]
+}
+
+// isUnreachableError tells whether operation failed because Orb domain couldn't be dialed, either for discovery
+// of its endpoints or for the operation itself. Such an operation never reached the domain, so it's safe to send it
+// to another domain, whereas operation which failed after it was sent might have been accepted.
+func isUnreachableError(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func orbDomainURL(domain string) string {
+	if !strings.HasPrefix(domain, "http://") && !strings.HasPrefix(domain, "https://") {
+		return "https://" + domain
+	}
+
+	return domain
+}
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/orb"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestOrbDomainPool(t *testing.T) {
+	unreachable := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	newPool := func(t *testing.T, calls *domainCalls, domains ...OrbDomain) *orbDomainPool {
+		t.Helper()
+
+		p, err := newOrbDomainPool(domains, func(d *OrbDomain) (didBlocClient, error) {
+			return &domainDIDClient{domain: d.URL, calls: calls}, nil
+		})
+		require.NoError(t, err)
+
+		return p
+	}
+
+	t.Run("test priority and round-robin", func(t *testing.T) {
+		calls := &domainCalls{}
+		p := newPool(t, calls,
+			OrbDomain{URL: "orb3.domain.com", Priority: 1},
+			OrbDomain{URL: "orb1.domain.com"},
+			OrbDomain{URL: "orb2.domain.com"},
+		)
+
+		for i := 0; i < 4; i++ {
+			_, err := p.Read("did:orb:EiA123")
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, []string{"orb1.domain.com", "orb2.domain.com", "orb1.domain.com", "orb2.domain.com"},
+			calls.get())
+	})
+
+	t.Run("test fail over on unreachable domain", func(t *testing.T) {
+		calls := &domainCalls{errs: map[string]error{"orb1.domain.com": unreachable}}
+		p := newPool(t, calls,
+			OrbDomain{URL: "orb1.domain.com"},
+			OrbDomain{URL: "orb2.domain.com", Priority: 1},
+		)
+
+		require.NoError(t, p.Update(&did.Doc{ID: "did:orb:EiA123"}))
+		require.Equal(t, []string{"orb1.domain.com", "orb2.domain.com"}, calls.get())
+
+		// unhealthy domain is skipped until its cooldown is over.
+		require.NoError(t, p.Deactivate("did:orb:EiA123"))
+		require.Equal(t, []string{"orb1.domain.com", "orb2.domain.com", "orb2.domain.com"}, calls.get())
+
+		p.domains[0].unhealthyUntil = time.Now().Add(-time.Second)
+		calls.errs = nil
+
+		require.NoError(t, p.Deactivate("did:orb:EiA123"))
+		require.Equal(t, "orb1.domain.com", calls.get()[3])
+	})
+
+	t.Run("test all domains unreachable", func(t *testing.T) {
+		calls := &domainCalls{errs: map[string]error{
+			"orb1.domain.com": unreachable,
+			"orb2.domain.com": fmt.Errorf("failed to get endpoints: %w", unreachable),
+		}}
+		p := newPool(t, calls, OrbDomain{URL: "orb1.domain.com"}, OrbDomain{URL: "orb2.domain.com"})
+
+		err := p.Deactivate("did:orb:EiA123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get endpoints")
+		require.Len(t, calls.get(), 2)
+
+		// unhealthy domains are still tried as the last resort.
+		calls.errs = nil
+
+		require.NoError(t, p.Deactivate("did:orb:EiA123"))
+	})
+
+	t.Run("test no fail over once operation is sent", func(t *testing.T) {
+		sent := &url.Error{Op: "Post", URL: "https://orb1.domain.com/sidetree/v1/operations", Err: &net.OpError{
+			Op: "read", Net: "tcp", Err: errors.New("i/o timeout"),
+		}}
+
+		calls := &domainCalls{errs: map[string]error{
+			"orb1.domain.com": fmt.Errorf("failed to send create sidetree request: %w", sent),
+		}}
+		p := newPool(t, calls, OrbDomain{URL: "orb1.domain.com"}, OrbDomain{URL: "orb2.domain.com", Priority: 1})
+
+		_, err := p.Create(&did.Doc{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "i/o timeout")
+		require.Equal(t, []string{"orb1.domain.com"}, calls.get())
+	})
+
+	t.Run("test no fail over on operation error", func(t *testing.T) {
+		calls := &domainCalls{errs: map[string]error{"orb1.domain.com": errors.New("invalid update key")}}
+		p := newPool(t, calls, OrbDomain{URL: "orb1.domain.com"}, OrbDomain{URL: "orb2.domain.com", Priority: 1})
+
+		err := p.Update(&did.Doc{ID: "did:orb:EiA123"})
+		require.EqualError(t, err, "invalid update key")
+		require.Equal(t, []string{"orb1.domain.com"}, calls.get())
+	})
+
+	t.Run("test pinned domain", func(t *testing.T) {
+		calls := &domainCalls{errs: map[string]error{"https://orb2.domain.com": unreachable}}
+		p := newPool(t, calls, OrbDomain{URL: "orb1.domain.com"}, OrbDomain{URL: "https://orb2.domain.com"})
+
+		_, err := p.Read("did:orb:EiA123", vdr.WithOption(orbDomainOpt, "orb2.domain.com"))
+		require.Error(t, err)
+		require.Equal(t, []string{"https://orb2.domain.com"}, calls.get())
+
+		_, err = p.Read("did:orb:EiA123", vdr.WithOption(orbDomainOpt, "orb3.domain.com"))
+		require.True(t, errors.Is(err, errUnknownOrbDomain))
+	})
+
+	t.Run("test anchor origin of domain", func(t *testing.T) {
+		calls := &domainCalls{errs: map[string]error{"orb1.domain.com": unreachable}}
+		p := newPool(t, calls,
+			OrbDomain{URL: "orb1.domain.com", AnchorOrigin: "https://anchor1.domain.com"},
+			OrbDomain{URL: "orb2.domain.com", AnchorOrigin: "https://anchor2.domain.com"},
+		)
+
+		docResolution, err := p.Create(&did.Doc{})
+		require.NoError(t, err)
+		require.Equal(t, "https://anchor2.domain.com", docResolution.DocumentMetadata.Method.AnchorOrigin)
+
+		docResolution, err = p.Create(&did.Doc{}, vdr.WithOption(orb.AnchorOriginOpt, "https://anchor.domain.com"))
+		require.NoError(t, err)
+		require.Equal(t, "https://anchor.domain.com", docResolution.DocumentMetadata.Method.AnchorOrigin)
+
+		anchorOrigin, err := p.anchorOrigin("orb1.domain.com")
+		require.NoError(t, err)
+		require.Equal(t, "https://anchor1.domain.com", anchorOrigin)
+
+		_, err = p.anchorOrigin("orb3.domain.com")
+		require.Error(t, err)
+	})
+
+	t.Run("test unpublished DID label of domain", func(t *testing.T) {
+		p := newPool(t, &domainCalls{},
+			OrbDomain{URL: "orb1.domain.com"},
+			OrbDomain{URL: "orb2.domain.com", UnpublishedDIDLabel: "uBBB"},
+		)
+
+		label, err := p.unpublishedDIDLabel("")
+		require.NoError(t, err)
+		require.Equal(t, defaultOrbUnpublishedDIDLabel, label)
+
+		label, err = p.unpublishedDIDLabel("orb2.domain.com")
+		require.NoError(t, err)
+		require.Equal(t, "uBBB", label)
+
+		_, err = p.unpublishedDIDLabel("orb3.domain.com")
+		require.Error(t, err)
+	})
+
+	t.Run("test error from client", func(t *testing.T) {
+		_, err := newOrbDomainPool([]OrbDomain{{URL: "orb1.domain.com"}}, func(*OrbDomain) (didBlocClient, error) {
+			return nil, errors.New("client error")
+		})
+		require.EqualError(t, err, "failed to create client of orb domain orb1.domain.com : client error")
+	})
+}
+
+func TestCommand_OrbDomains(t *testing.T) {
+	t.Run("test domains from options", func(t *testing.T) {
+		c, err := New("orb1.domain.com", "https://anchor1.domain.com", "token1", 0, getMockProvider(),
+			mocks.NewMockNotifier(), WithOrbDomains(
+				OrbDomain{URL: "orb2.domain.com", Token: "token2"},
+				OrbDomain{URL: "orb3.domain.com", AnchorOrigin: "https://anchor3.domain.com", Priority: -1},
+			))
+		require.NoError(t, err)
+
+		require.Len(t, c.orbDomains.domains, 3)
+		require.Equal(t, OrbDomain{URL: "orb3.domain.com", AnchorOrigin: "https://anchor3.domain.com", Priority: -1},
+			c.orbDomains.domains[0].OrbDomain)
+		require.Equal(t, OrbDomain{URL: "orb1.domain.com", Token: "token1", AnchorOrigin: "https://anchor1.domain.com"},
+			c.orbDomains.domains[1].OrbDomain)
+		require.Equal(t, OrbDomain{URL: "orb2.domain.com", Token: "token2", AnchorOrigin: "https://anchor1.domain.com"},
+			c.orbDomains.domains[2].OrbDomain)
+	})
+
+	t.Run("test create request pins domain and anchor origin", func(t *testing.T) {
+		c, err := New("orb1.domain.com", "", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		client := &mockDIDClient{createDIDErr: errors.New("create error")}
+		c.didBlocClient = client
+
+		req, err := json.Marshal(&CreateOrbDIDRequest{
+			Domain:       "orb1.domain.com",
+			AnchorOrigin: "https://anchor.domain.com",
+		})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.Error(t, c.CreateOrbDID(&b, bytes.NewBuffer(req)))
+		require.Equal(t, "orb1.domain.com", pinnedOrbDomain(client.createOpts))
+		require.Equal(t, "https://anchor.domain.com", orbOption(client.createOpts, orb.AnchorOriginOpt))
+	})
+
+	t.Run("test queued operation fails over to reachable domain", func(t *testing.T) {
+		down := newMockOrbServer()
+		down.Close()
+
+		up := newMockOrbServer()
+		defer up.Close()
+
+		c, err := New(down.URL, "origin", "", 0, getMockProvider(), mocks.NewMockNotifier(),
+			WithOrbDomains(OrbDomain{URL: up.URL}))
+		require.NoError(t, err)
+
+		require.NoError(t, c.submitOrbOperation(context.Background(), []byte(`{}`), ""))
+		require.Len(t, up.submitted(), 1)
+
+		err = c.submitOrbOperation(context.Background(), []byte(`{}`), down.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to discover operation endpoint")
+		require.Len(t, up.submitted(), 1)
+	})
+}
+
+// domainCalls records domains operations were sent to and fails operations of the given domains.
+type domainCalls struct {
+	mutex   sync.Mutex
+	errs    map[string]error
+	domains []string
+}
+
+func (c *domainCalls) call(domain string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.domains = append(c.domains, domain)
+
+	return c.errs[domain]
+}
+
+func (c *domainCalls) get() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.domains...)
+}
+
+type domainDIDClient struct {
+	domain string
+	calls  *domainCalls
+}
+
+func (m *domainDIDClient) Create(_ *did.Doc, opts ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	if err := m.calls.call(m.domain); err != nil {
+		return nil, err
+	}
+
+	anchorOrigin, _ := orbOption(opts, orb.AnchorOriginOpt).(string) // nolint:errcheck
+
+	return &did.DocResolution{
+		DocumentMetadata: &did.DocumentMetadata{Method: &did.MethodMetadata{AnchorOrigin: anchorOrigin}},
+	}, nil
+}
+
+func (m *domainDIDClient) Read(didID string, _ ...vdr.DIDMethodOption) (*did.DocResolution, error) {
+	if err := m.calls.call(m.domain); err != nil {
+		return nil, err
+	}
+
+	return &did.DocResolution{DIDDocument: &did.Doc{ID: didID}}, nil
+}
+
+func (m *domainDIDClient) Update(_ *did.Doc, _ ...vdr.DIDMethodOption) error {
+	return m.calls.call(m.domain)
+}
+
+func (m *domainDIDClient) Deactivate(_ string, _ ...vdr.DIDMethodOption) error {
+	return m.calls.call(m.domain)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+)
+
+// peerVerificationMethodTypes verification method types of key types supported for peer DID signing keys.
+// nolint:gochecknoglobals
+var peerVerificationMethodTypes = map[kms.KeyType]string{
+	kms.ED25519Type:            ed25519VerificationKey2018,
+	kms.ECDSAP256TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP384TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP521TypeIEEEP1363: jsonWebKey2020,
+	kms.ECDSAP256TypeDER:       jsonWebKey2020,
+	kms.ECDSAP384TypeDER:       jsonWebKey2020,
+	kms.ECDSAP521TypeDER:       jsonWebKey2020,
+}
+
+// keyAgreementTypes verification method types of key types supported for key agreement keys.
+// nolint:gochecknoglobals
+var keyAgreementTypes = map[kms.KeyType]string{
+	kms.X25519ECDHKWType:   x25519KeyAgreementKey2019,
+	kms.NISTP256ECDHKWType: jsonWebKey2020,
+	kms.NISTP384ECDHKWType: jsonWebKey2020,
+	kms.NISTP521ECDHKWType: jsonWebKey2020,
+}
+
+// peerDIDOptions key and service types of peer DID to be created.
+type peerDIDOptions struct {
+	keyType          kms.KeyType
+	keyAgreementType kms.KeyType
+	serviceType      string
+}
+
+// getPeerDIDOptions validates the request and returns peer DID options with defaults applied.
+func getPeerDIDOptions(request *CreatePeerDIDRequest) (*peerDIDOptions, error) {
+	opts := &peerDIDOptions{keyType: kms.ED25519Type, serviceType: request.ServiceType}
+
+	if request.KeyType != "" {
+		opts.keyType = kms.KeyType(strings.ToUpper(request.KeyType))
+	}
+
+	if _, ok := peerVerificationMethodTypes[opts.keyType]; !ok {
+		return nil, fmt.Errorf(errUnsupportedKeyType, request.KeyType)
+	}
+
+	switch request.ServiceType {
+	case "", didCommServiceType:
+	case didCommV2ServiceType:
+		// DIDComm V2 requires key agreement key
+		opts.keyAgreementType = kms.X25519ECDHKWType
+	default:
+		return nil, fmt.Errorf(errUnsupportedServiceType, request.ServiceType)
+	}
+
+	if request.KeyAgreementType != "" {
+		opts.keyAgreementType = kms.KeyType(strings.ToUpper(request.KeyAgreementType))
+	}
+
+	if _, ok := keyAgreementTypes[opts.keyAgreementType]; opts.keyAgreementType != "" && !ok {
+		return nil, fmt.Errorf(errUnsupportedKeyAgreementType, request.KeyAgreementType)
+	}
+
+	return opts, nil
+}
+
+// newPeerDIDDoc creates keys in the KMS and returns peer DID document to be created.
+func newPeerDIDDoc(km kms.KeyManager, opts *peerDIDOptions, config *mediatorservice.Config) (*did.Doc, error) {
+	_, vm, err := newVerificationMethod(km, opts.keyType, "")
+	if err != nil {
+		return nil, err
+	}
+
+	didDoc := &did.Doc{
+		Service:            []did.Service{newPeerService(opts.serviceType, config)},
+		VerificationMethod: []did.VerificationMethod{*vm},
+	}
+
+	if opts.keyAgreementType != "" {
+		_, kaVM, err := newVerificationMethod(km, opts.keyAgreementType, "")
+		if err != nil {
+			return nil, err
+		}
+
+		didDoc.KeyAgreement = []did.Verification{*did.NewReferencedVerification(kaVM, did.KeyAgreement)}
+	}
+
+	return didDoc, nil
+}
+
+func newPeerService(serviceType string, config *mediatorservice.Config) did.Service {
+	switch serviceType {
+	case didCommServiceType:
+		return did.Service{
+			Type:            serviceType,
+			ServiceEndpoint: model.NewDIDCommV1Endpoint(config.Endpoint()),
+			RoutingKeys:     config.Keys(),
+		}
+	case didCommV2ServiceType:
+		return did.Service{
+			Type: serviceType,
+			ServiceEndpoint: model.NewDIDCommV2Endpoint([]model.DIDCommV2Endpoint{{
+				URI:         config.Endpoint(),
+				Accept:      []string{transport.MediaTypeDIDCommV2Profile},
+				RoutingKeys: config.Keys(),
+			}}),
+		}
+	default:
+		return did.Service{
+			ServiceEndpoint: model.NewDIDCommV2Endpoint(
+				[]model.DIDCommV2Endpoint{{URI: config.Endpoint(), RoutingKeys: config.Keys()}}),
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	vdrpkg "github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_CreatePeerDIDKeyTypes(t *testing.T) {
+	const routerEndpoint = "http://router.com"
+
+	routingKeys := []string{"did:key:z6MkRouter1", "did:key:z6MkRouter2"}
+
+	newCommand := func(t *testing.T) (*Command, *[]string) {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.keyManager, err = localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		peerVDR, err := peer.New(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		c.vdrRegistry = vdrpkg.New(vdrpkg.WithVDR(peerVDR))
+
+		c.mediatorClient = &mockMediatorClient{
+			GetConfigFunc: func(connID string) (*mediatorsvc.Config, error) {
+				return mediatorsvc.NewConfig(routerEndpoint, routingKeys), nil
+			},
+		}
+
+		var registered []string
+
+		c.mediatorSvc = &mockroute.MockMediatorSvc{AddKeyFunc: func(key string) error {
+			registered = append(registered, key)
+
+			return nil
+		}}
+
+		return c, &registered
+	}
+
+	createPeerDID := func(t *testing.T, c *Command, request *CreatePeerDIDRequest) (*did.Doc, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreatePeerDID(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp, err := did.ParseDocumentResolution(b.Bytes())
+		require.NoError(t, err)
+
+		return resp.DIDDocument, nil
+	}
+
+	t.Run("test DIDComm V2 service", func(t *testing.T) {
+		c, registered := newCommand(t)
+
+		didDoc, cmdErr := createPeerDID(t, c, &CreatePeerDIDRequest{
+			RouterConnectionID: "conn1",
+			ServiceType:        didCommV2ServiceType,
+		})
+		require.NoError(t, cmdErr)
+
+		require.Len(t, didDoc.VerificationMethod, 2)
+		require.Equal(t, ed25519VerificationKey2018, didDoc.VerificationMethod[0].Type)
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Equal(t, x25519KeyAgreementKey2019, didDoc.KeyAgreement[0].VerificationMethod.Type)
+
+		svc, ok := did.LookupService(didDoc, didCommV2ServiceType)
+		require.True(t, ok)
+
+		uri, err := svc.ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, routerEndpoint, uri)
+
+		svcRoutingKeys, err := svc.ServiceEndpoint.RoutingKeys()
+		require.NoError(t, err)
+		require.Equal(t, routingKeys, svcRoutingKeys)
+
+		require.Len(t, *registered, 1)
+		require.True(t, strings.HasPrefix((*registered)[0], didDoc.ID+"#"))
+		require.True(t, strings.HasSuffix((*registered)[0], didDoc.KeyAgreement[0].VerificationMethod.ID))
+	})
+
+	t.Run("test configured key types with DIDComm V1 service", func(t *testing.T) {
+		c, registered := newCommand(t)
+
+		didDoc, cmdErr := createPeerDID(t, c, &CreatePeerDIDRequest{
+			RouterConnectionID: "conn1",
+			KeyType:            p256KeyType,
+			KeyAgreementType:   p256ecdhkw,
+			ServiceType:        didCommServiceType,
+		})
+		require.NoError(t, cmdErr)
+
+		require.Equal(t, jsonWebKey2020, didDoc.VerificationMethod[0].Type)
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Equal(t, jsonWebKey2020, didDoc.KeyAgreement[0].VerificationMethod.Type)
+
+		svc, ok := did.LookupService(didDoc, didCommServiceType)
+		require.True(t, ok)
+		require.Equal(t, routingKeys, svc.RoutingKeys)
+		require.NotEmpty(t, svc.RecipientKeys)
+
+		require.Len(t, *registered, len(svc.RecipientKeys)+1)
+		require.Equal(t, svc.RecipientKeys, (*registered)[:len(svc.RecipientKeys)])
+	})
+
+	t.Run("test unsupported types", func(t *testing.T) {
+		c, _ := newCommand(t)
+
+		for _, tc := range []struct {
+			request *CreatePeerDIDRequest
+			err     string
+		}{
+			{
+				request: &CreatePeerDIDRequest{RouterConnectionID: "conn1", KeyType: x25519ECDHKW},
+				err:     fmt.Sprintf(errUnsupportedKeyType, x25519ECDHKW),
+			},
+			{
+				request: &CreatePeerDIDRequest{RouterConnectionID: "conn1", KeyAgreementType: ed25519KeyType},
+				err:     fmt.Sprintf(errUnsupportedKeyAgreementType, ed25519KeyType),
+			},
+			{
+				request: &CreatePeerDIDRequest{RouterConnectionID: "conn1", ServiceType: "LinkedDomains"},
+				err:     fmt.Sprintf(errUnsupportedServiceType, "LinkedDomains"),
+			},
+		} {
+			_, cmdErr := createPeerDID(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test error from creating key agreement key", func(t *testing.T) {
+		c, _ := newCommand(t)
+
+		c.keyManager = &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("invalid")}
+
+		_, cmdErr := createPeerDID(t, c, &CreatePeerDIDRequest{
+			RouterConnectionID: "conn1",
+			ServiceType:        didCommV2ServiceType,
+		})
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to unmarshal X25519 key")
+	})
+}
@@ -0,0 +1,340 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// PublishedTopic is the notifier topic on which anchored orb DIDs are published.
+	PublishedTopic = "didclient-published"
+	// PublicationFailedTopic is the notifier topic on which orb DIDs not anchored within their max lifetime
+	// are published.
+	PublicationFailedTopic = "didclient-publication-failed"
+
+	// orb DID publication statuses.
+	orbDIDStatusPending   = "pending"
+	orbDIDStatusPublished = "published"
+	orbDIDStatusFailed    = "failed"
+
+	orbDIDStatusKeyPrefix = "orbdidstatus_"
+	orbDIDPendingTag      = "orbDIDPending"
+
+	defaultPublicationInitialBackoff = time.Second
+	defaultPublicationMaxBackoff     = time.Minute
+	defaultPublicationMaxLifetime    = 24 * time.Hour
+)
+
+var errOrbDIDStatusNotFound = errors.New("orb DID status not found")
+
+// OrbDIDStatus publication status of orb DID.
+type OrbDIDStatus struct {
+	DID          string   `json:"did"`
+	Status       string   `json:"status"`
+	CanonicalID  string   `json:"canonicalId,omitempty"`
+	EquivalentID []string `json:"equivalentId,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// orbDIDStatusRecord is orb DID status kept in the store along with the time its publication started to be
+// tracked, so that max lifetime of the DID spans agent restarts.
+type orbDIDStatusRecord struct {
+	*OrbDIDStatus
+	TrackedSince time.Time `json:"trackedSince"`
+}
+
+// publicationTracker re-resolves unpublished orb DIDs until they get anchored and notifies about
+// published ones. DIDs not anchored within max lifetime are given up on.
+type publicationTracker struct {
+	resolve        func(didID string) (*did.DocResolution, error)
+	store          storage.Store
+	notifier       ariescmd.Notifier
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxLifetime    time.Duration
+	mutex          sync.Mutex
+	tracked        map[string]struct{}
+	stop           chan struct{}
+	running        sync.WaitGroup
+	closed         bool
+}
+
+func newPublicationTracker(resolve func(string) (*did.DocResolution, error), store storage.Store,
+	notifier ariescmd.Notifier) *publicationTracker {
+	return &publicationTracker{
+		resolve:        resolve,
+		store:          store,
+		notifier:       notifier,
+		initialBackoff: defaultPublicationInitialBackoff,
+		maxBackoff:     defaultPublicationMaxBackoff,
+		maxLifetime:    defaultPublicationMaxLifetime,
+		tracked:        make(map[string]struct{}),
+		stop:           make(chan struct{}),
+	}
+}
+
+// resume starts tracking of DIDs which weren't published before the agent was stopped.
+func (t *publicationTracker) resume() error {
+	iter, err := t.store.Query(orbDIDPendingTag)
+	if err != nil {
+		return fmt.Errorf("failed to query pending orb DIDs : %w", err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("failed to close iterator : %s", e)
+		}
+	}()
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("failed to get next pending orb DID : %w", err)
+		}
+
+		if !ok {
+			return nil
+		}
+
+		data, err := iter.Value()
+		if err != nil {
+			return fmt.Errorf("failed to get pending orb DID : %w", err)
+		}
+
+		record := &orbDIDStatusRecord{OrbDIDStatus: &OrbDIDStatus{}}
+
+		err = json.Unmarshal(data, record)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal orb DID status : %w", err)
+		}
+
+		// statuses saved before tracking start was recorded are tracked from now on.
+		if record.TrackedSince.IsZero() {
+			record.TrackedSince = time.Now().UTC()
+		}
+
+		t.start(record.DID, record.TrackedSince)
+	}
+}
+
+// track records DID as pending and starts re-resolving it until it gets published.
+func (t *publicationTracker) track(didID string) error {
+	trackedSince := time.Now().UTC()
+
+	err := t.put(&OrbDIDStatus{DID: didID, Status: orbDIDStatusPending}, trackedSince)
+	if err != nil {
+		return err
+	}
+
+	t.start(didID, trackedSince)
+
+	return nil
+}
+
+// close stops tracking of the DIDs and waits for the tracking to stop. Pending DIDs are tracked again on resume.
+func (t *publicationTracker) close() {
+	t.mutex.Lock()
+
+	if !t.closed {
+		t.closed = true
+		close(t.stop)
+	}
+
+	t.mutex.Unlock()
+
+	t.running.Wait()
+}
+
+func (t *publicationTracker) start(didID string, trackedSince time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.tracked[didID]; ok || t.closed {
+		return
+	}
+
+	t.tracked[didID] = struct{}{}
+
+	t.running.Add(1)
+
+	go func() {
+		defer t.running.Done()
+
+		t.poll(didID, trackedSince)
+	}()
+}
+
+func (t *publicationTracker) poll(didID string, trackedSince time.Time) {
+	defer func() {
+		t.mutex.Lock()
+		delete(t.tracked, didID)
+		t.mutex.Unlock()
+	}()
+
+	deadline := trackedSince.Add(t.maxLifetime)
+	backoff := t.initialBackoff
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		published, err := t.check(didID, trackedSince)
+		if err != nil {
+			logger.Warnf("failed to check publication of orb DID %s : %s", didID, err)
+		}
+
+		if published {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			err = t.fail(didID, trackedSince)
+			if err != nil {
+				logger.Warnf("failed to record publication failure of orb DID %s : %s", didID, err)
+			}
+
+			return
+		}
+
+		backoff *= 2
+		if backoff > t.maxBackoff {
+			backoff = t.maxBackoff
+		}
+	}
+}
+
+// check resolves DID and, once it is published, saves its status and notifies subscribers.
+func (t *publicationTracker) check(didID string, trackedSince time.Time) (bool, error) {
+	docResolution, err := t.resolve(didID)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve DID : %w", err)
+	}
+
+	status := statusFromResolution(didID, docResolution)
+	if status.Status != orbDIDStatusPublished {
+		return false, nil
+	}
+
+	err = t.finish(PublishedTopic, status, trackedSince)
+	if err != nil {
+		return true, err
+	}
+
+	logger.Debugf("orb DID %s published with canonical ID %s", didID, status.CanonicalID)
+
+	return true, nil
+}
+
+// fail records DID which wasn't published within max lifetime as failed and notifies subscribers.
+func (t *publicationTracker) fail(didID string, trackedSince time.Time) error {
+	status := &OrbDIDStatus{
+		DID:    didID,
+		Status: orbDIDStatusFailed,
+		Error:  fmt.Sprintf("DID wasn't published within %s", t.maxLifetime),
+	}
+
+	err := t.finish(PublicationFailedTopic, status, trackedSince)
+	if err != nil {
+		return err
+	}
+
+	logger.Warnf("orb DID %s wasn't published within %s, stopped tracking it", didID, t.maxLifetime)
+
+	return nil
+}
+
+// finish saves final status of the DID and publishes it on the topic.
+func (t *publicationTracker) finish(topic string, status *OrbDIDStatus, trackedSince time.Time) error {
+	err := t.put(status, trackedSince)
+	if err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal orb DID status : %w", err)
+	}
+
+	err = t.notifier.Notify(topic, msg)
+	if err != nil {
+		return fmt.Errorf("failed to notify orb DID publication status : %w", err)
+	}
+
+	return nil
+}
+
+func (t *publicationTracker) get(didID string) (*OrbDIDStatus, error) {
+	data, err := t.store.Get(orbDIDStatusKeyPrefix + didSuffix(didID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w for DID %s", errOrbDIDStatusNotFound, didID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orb DID status : %w", err)
+	}
+
+	record := &orbDIDStatusRecord{OrbDIDStatus: &OrbDIDStatus{}}
+
+	err = json.Unmarshal(data, record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal orb DID status : %w", err)
+	}
+
+	return record.OrbDIDStatus, nil
+}
+
+func (t *publicationTracker) put(status *OrbDIDStatus, trackedSince time.Time) error {
+	data, err := json.Marshal(&orbDIDStatusRecord{OrbDIDStatus: status, TrackedSince: trackedSince})
+	if err != nil {
+		return fmt.Errorf("failed to marshal orb DID status : %w", err)
+	}
+
+	var tags []storage.Tag
+
+	if status.Status == orbDIDStatusPending {
+		tags = append(tags, storage.Tag{Name: orbDIDPendingTag})
+	}
+
+	err = t.store.Put(orbDIDStatusKeyPrefix+didSuffix(status.DID), data, tags...)
+	if err != nil {
+		return fmt.Errorf("failed to save orb DID status : %w", err)
+	}
+
+	return nil
+}
+
+// statusFromResolution returns publication status of the resolved orb DID.
+func statusFromResolution(didID string, docResolution *did.DocResolution) *OrbDIDStatus {
+	status := &OrbDIDStatus{DID: didID, Status: orbDIDStatusPending}
+
+	if docResolution == nil || docResolution.DocumentMetadata == nil {
+		return status
+	}
+
+	metadata := docResolution.DocumentMetadata
+
+	if metadata.Method == nil || !metadata.Method.Published {
+		return status
+	}
+
+	status.Status = orbDIDStatusPublished
+	status.CanonicalID = metadata.CanonicalID
+	status.EquivalentID = metadata.EquivalentID
+
+	return status
+}
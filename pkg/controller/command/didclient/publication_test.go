@@ -0,0 +1,397 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func publishedResolution(canonicalID string) *did.DocResolution {
+	return &did.DocResolution{
+		DIDDocument: &did.Doc{Context: []string{did.ContextV1}, ID: canonicalID},
+		DocumentMetadata: &did.DocumentMetadata{
+			CanonicalID:  canonicalID,
+			EquivalentID: []string{canonicalID, "did:orb:https:example.com:EiA123"},
+			Method:       &did.MethodMetadata{Published: true},
+		},
+	}
+}
+
+func TestPublicationTracker(t *testing.T) {
+	t.Run("test DID published", func(t *testing.T) {
+		notifications := make(chan []byte, 1)
+
+		notifier := &mocks.Notifier{NotifyFunc: func(topic string, message []byte) error {
+			require.Equal(t, PublishedTopic, topic)
+
+			notifications <- message
+
+			return nil
+		}}
+
+		attempts := 0
+
+		tracker := newPublicationTracker(func(string) (*did.DocResolution, error) {
+			attempts++
+
+			if attempts < 3 {
+				return &did.DocResolution{DocumentMetadata: &did.DocumentMetadata{
+					Method: &did.MethodMetadata{Published: false},
+				}}, nil
+			}
+
+			return publishedResolution("did:orb:bafy:EiA123"), nil
+		}, mockstorage.NewMockStoreProvider().Store, notifier)
+		tracker.initialBackoff = time.Millisecond
+
+		require.NoError(t, tracker.track("did:orb:uAAA:EiA123"))
+
+		status, err := tracker.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, orbDIDStatusPending, status.Status)
+
+		select {
+		case msg := <-notifications:
+			published := &OrbDIDStatus{}
+			require.NoError(t, json.Unmarshal(msg, published))
+			require.Equal(t, "did:orb:uAAA:EiA123", published.DID)
+			require.Equal(t, orbDIDStatusPublished, published.Status)
+			require.Equal(t, "did:orb:bafy:EiA123", published.CanonicalID)
+			require.Len(t, published.EquivalentID, 2)
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for published notification")
+		}
+
+		status, err = tracker.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, orbDIDStatusPublished, status.Status)
+		require.Equal(t, "did:orb:bafy:EiA123", status.CanonicalID)
+	})
+
+	t.Run("test resume pending DIDs", func(t *testing.T) {
+		store := mockstorage.NewMockStoreProvider().Store
+
+		resolved := make(chan string, 1)
+
+		tracker := newPublicationTracker(func(didID string) (*did.DocResolution, error) {
+			resolved <- didID
+
+			return publishedResolution("did:orb:bafy:EiA123"), nil
+		}, store, mocks.NewMockNotifier())
+		tracker.initialBackoff = time.Millisecond
+
+		require.NoError(t, tracker.put(&OrbDIDStatus{DID: "did:orb:uAAA:EiA123", Status: orbDIDStatusPending},
+			time.Now()))
+		require.NoError(t, tracker.put(&OrbDIDStatus{DID: "did:orb:bafy:EiA456", Status: orbDIDStatusPublished},
+			time.Now()))
+
+		require.NoError(t, tracker.resume())
+
+		select {
+		case didID := <-resolved:
+			require.Equal(t, "did:orb:uAAA:EiA123", didID)
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for pending DID resolution")
+		}
+	})
+
+	t.Run("test DID not published within max lifetime", func(t *testing.T) {
+		notifications := make(chan []byte, 1)
+
+		notifier := &mocks.Notifier{NotifyFunc: func(topic string, message []byte) error {
+			require.Equal(t, PublicationFailedTopic, topic)
+
+			notifications <- message
+
+			return nil
+		}}
+
+		store := mockstorage.NewMockStoreProvider().Store
+
+		tracker := newPublicationTracker(func(string) (*did.DocResolution, error) {
+			return &did.DocResolution{DocumentMetadata: &did.DocumentMetadata{
+				Method: &did.MethodMetadata{Published: false},
+			}}, nil
+		}, store, notifier)
+		tracker.initialBackoff = time.Millisecond
+		tracker.maxLifetime = time.Hour
+
+		defer tracker.close()
+
+		// DID has been tracked for longer than its max lifetime before the agent was restarted.
+		require.NoError(t, tracker.put(&OrbDIDStatus{DID: "did:orb:uAAA:EiA123", Status: orbDIDStatusPending},
+			time.Now().Add(-2*time.Hour)))
+		require.NoError(t, tracker.resume())
+
+		select {
+		case msg := <-notifications:
+			failed := &OrbDIDStatus{}
+			require.NoError(t, json.Unmarshal(msg, failed))
+			require.Equal(t, "did:orb:uAAA:EiA123", failed.DID)
+			require.Equal(t, orbDIDStatusFailed, failed.Status)
+			require.Contains(t, failed.Error, "DID wasn't published within 1h0m0s")
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for publication failed notification")
+		}
+
+		status, err := tracker.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, orbDIDStatusFailed, status.Status)
+
+		// failed DID isn't tracked anymore.
+		iter, err := store.Query(orbDIDPendingTag)
+		require.NoError(t, err)
+
+		ok, err := iter.Next()
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("test closed tracker stops tracking", func(t *testing.T) {
+		resolved := make(chan string, 10)
+
+		tracker := newPublicationTracker(func(didID string) (*did.DocResolution, error) {
+			resolved <- didID
+
+			return &did.DocResolution{}, nil
+		}, mockstorage.NewMockStoreProvider().Store, mocks.NewMockNotifier())
+		tracker.initialBackoff = time.Millisecond
+		tracker.maxBackoff = time.Millisecond
+
+		require.NoError(t, tracker.track("did:orb:uAAA:EiA123"))
+
+		select {
+		case <-resolved:
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for DID resolution")
+		}
+
+		tracker.close()
+
+		count := len(resolved)
+
+		time.Sleep(20 * time.Millisecond)
+		require.Equal(t, count, len(resolved))
+
+		// DID stays pending, so that it is tracked again on resume.
+		status, err := tracker.get("did:orb:uAAA:EiA123")
+		require.NoError(t, err)
+		require.Equal(t, orbDIDStatusPending, status.Status)
+
+		require.NoError(t, tracker.track("did:orb:uAAA:EiA456"))
+		time.Sleep(20 * time.Millisecond)
+		require.Equal(t, count, len(resolved))
+	})
+
+	t.Run("test resume errors", func(t *testing.T) {
+		tracker := newPublicationTracker(nil, &mockstorage.MockStore{ErrQuery: fmt.Errorf("query error")},
+			mocks.NewMockNotifier())
+
+		err := tracker.resume()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+
+		store := mockstorage.NewMockStoreProvider().Store
+		tracker = newPublicationTracker(nil, store, mocks.NewMockNotifier())
+
+		require.NoError(t, store.Put(orbDIDStatusKeyPrefix+"123", []byte("{"), storage.Tag{Name: orbDIDPendingTag}))
+
+		err = tracker.resume()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal orb DID status")
+	})
+
+	t.Run("test check errors", func(t *testing.T) {
+		tracker := newPublicationTracker(func(string) (*did.DocResolution, error) {
+			return nil, fmt.Errorf("resolve error")
+		}, mockstorage.NewMockStoreProvider().Store, mocks.NewMockNotifier())
+
+		published, err := tracker.check("did:orb:uAAA:EiA123", time.Now())
+		require.Error(t, err)
+		require.False(t, published)
+		require.Contains(t, err.Error(), "resolve error")
+
+		tracker = newPublicationTracker(func(string) (*did.DocResolution, error) {
+			return publishedResolution("did:orb:bafy:EiA123"), nil
+		}, mockstorage.NewMockStoreProvider().Store, &mocks.Notifier{
+			NotifyFunc: func(string, []byte) error {
+				return fmt.Errorf("notify error")
+			},
+		})
+
+		published, err = tracker.check("did:orb:uAAA:EiA123", time.Now())
+		require.Error(t, err)
+		require.True(t, published)
+		require.Contains(t, err.Error(), "notify error")
+
+		tracker = newPublicationTracker(func(string) (*did.DocResolution, error) {
+			return publishedResolution("did:orb:bafy:EiA123"), nil
+		}, &mockstorage.MockStore{ErrPut: fmt.Errorf("put error")}, mocks.NewMockNotifier())
+
+		published, err = tracker.check("did:orb:uAAA:EiA123", time.Now())
+		require.Error(t, err)
+		require.True(t, published)
+		require.Contains(t, err.Error(), "put error")
+	})
+
+	t.Run("test store errors", func(t *testing.T) {
+		tracker := newPublicationTracker(nil, &mockstorage.MockStore{
+			Store:  make(map[string]mockstorage.DBEntry),
+			ErrGet: fmt.Errorf("get error"),
+			ErrPut: fmt.Errorf("put error"),
+		}, mocks.NewMockNotifier())
+
+		_, err := tracker.get("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+
+		err = tracker.track("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "put error")
+
+		tracker = newPublicationTracker(nil, mockstorage.NewMockStoreProvider().Store, mocks.NewMockNotifier())
+		require.NoError(t, tracker.store.Put(orbDIDStatusKeyPrefix+"123", []byte("{")))
+
+		_, err = tracker.get("did:orb:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal orb DID status")
+	})
+}
+
+func TestCommand_GetOrbDIDStatus(t *testing.T) {
+	getStatus := func(t *testing.T, c *Command, didID string) (*OrbDIDStatus, command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(GetOrbDIDStatusRequest{DID: didID})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.GetOrbDIDStatus(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		status := &OrbDIDStatus{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), status))
+
+		return status, nil
+	}
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.GetOrbDIDStatus(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		_, cmdErr = getStatus(t, c, "")
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errInvalidDID)
+	})
+
+	t.Run("test DIDs tracked for max lifetime of unanchored DIDs", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 60, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		defer c.Close()
+
+		require.Equal(t, time.Minute, c.publicationTracker.maxLifetime)
+	})
+
+	t.Run("test created DID tracked", func(t *testing.T) {
+		notifications := make(chan []byte, 1)
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), &mocks.Notifier{
+			NotifyFunc: func(topic string, message []byte) error {
+				notifications <- message
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		c.publicationTracker.initialBackoff = time.Millisecond
+
+		c.didBlocClient = &mockDIDClient{
+			createDIDValue:  &did.DocResolution{DIDDocument: &did.Doc{ID: "did:orb:uAAA:EiA123"}},
+			resolveDIDValue: publishedResolution("did:orb:bafy:EiA123"),
+		}
+
+		req, err := json.Marshal(CreateOrbDIDRequest{})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		select {
+		case <-notifications:
+		case <-time.After(time.Second):
+			require.Fail(t, "timeout waiting for published notification")
+		}
+
+		status, cmdErr := getStatus(t, c, "did:orb:uAAA:EiA123")
+		require.NoError(t, cmdErr)
+		require.Equal(t, orbDIDStatusPublished, status.Status)
+		require.Equal(t, "did:orb:bafy:EiA123", status.CanonicalID)
+	})
+
+	t.Run("test DID not created by agent", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.didBlocClient = &mockDIDClient{resolveDIDValue: publishedResolution("did:orb:bafy:EiA123")}
+
+		status, cmdErr := getStatus(t, c, "did:orb:https:example.com:EiA123")
+		require.NoError(t, cmdErr)
+		require.Equal(t, orbDIDStatusPublished, status.Status)
+		require.Equal(t, "did:orb:bafy:EiA123", status.CanonicalID)
+
+		c.didBlocClient = &mockDIDClient{resolveDIDErr: fmt.Errorf("resolve error")}
+
+		_, cmdErr = getStatus(t, c, "did:orb:https:example.com:EiA123")
+		require.Error(t, cmdErr)
+		require.Equal(t, GetOrbDIDStatusErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "resolve error")
+	})
+
+	t.Run("test error from tracking created DID", func(t *testing.T) {
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		c.publicationTracker.store = &mockstorage.MockStore{ErrPut: fmt.Errorf("put error")}
+		c.didBlocClient = &mockDIDClient{createDIDValue: &did.DocResolution{DIDDocument: &did.Doc{ID: "did:orb:123"}}}
+
+		req, err := json.Marshal(CreateOrbDIDRequest{})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateOrbDID(&b, bytes.NewBuffer(req))
+		require.Error(t, cmdErr)
+		require.Equal(t, CreateDIDErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "put error")
+	})
+}
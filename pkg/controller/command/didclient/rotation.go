@@ -0,0 +1,274 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	peerDIDPrefix = "did:peer:"
+
+	// trustPingMsgType DIDComm V2 trust ping used for announcing rotated DID to the other party.
+	trustPingMsgType = "https://didcomm.org/trust-ping/2.0/ping"
+)
+
+// didRotator rotates DID of DIDComm V2 connection, the other party is informed of the new DID by the
+// 'from_prior' header of messages sent over the connection.
+type didRotator interface {
+	RotateConnectionDID(connectionID, signingKID, newDID string) error
+}
+
+// RotatePeerDIDKeys creates new peer DID with new keys to replace the given peer DID, registers its keys with
+// the router and removes the old ones. DIDComm V2 connections of the old DID are rotated to the new DID and
+// the other parties are notified by a trust ping carrying 'from_prior'.
+func (c *Command) RotatePeerDIDKeys(rw io.Writer, req io.Reader) command.Error {
+	var request RotatePeerDIDKeysRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RotatePeerDIDKeysCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if !strings.HasPrefix(request.DID, peerDIDPrefix) {
+		logutil.LogError(logger, CommandName, RotatePeerDIDKeysCommandMethod, errInvalidDID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidDID))
+	}
+
+	if request.RouterConnectionID == "" {
+		logutil.LogError(logger, CommandName, RotatePeerDIDKeysCommandMethod, errInvalidRouterConnectionID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidRouterConnectionID))
+	}
+
+	opts, err := getPeerDIDOptions(&CreatePeerDIDRequest{
+		KeyType:          request.KeyType,
+		KeyAgreementType: request.KeyAgreementType,
+		ServiceType:      request.ServiceType,
+	})
+	if err != nil {
+		logutil.LogError(logger, CommandName, RotatePeerDIDKeysCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.rotatePeerDID(&request, opts)
+	if err != nil {
+		logutil.LogError(logger, CommandName, RotatePeerDIDKeysCommandMethod, err.Error())
+
+		return command.NewExecuteError(RotatePeerDIDKeysErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, RotatePeerDIDKeysCommandMethod, successString)
+
+	return nil
+}
+
+func (c *Command) rotatePeerDID(request *RotatePeerDIDKeysRequest,
+	opts *peerDIDOptions) (*RotatePeerDIDKeysResponse, error) {
+	oldDocResolution, err := c.vdrRegistry.Resolve(request.DID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s : %w", request.DID, err)
+	}
+
+	oldDoc := oldDocResolution.DIDDocument
+
+	signingKID, err := peerSigningKeyID(oldDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc, err := c.createRoutedPeerDID(request.RouterConnectionID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := c.rotateConnections(oldDoc.ID, newDoc.ID, signingKID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.removePeerKeysFromRouters(oldDoc, request.RouterConnections)
+	if err != nil {
+		return nil, err
+	}
+
+	docBytes, err := newDoc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal DID document : %w", err)
+	}
+
+	return &RotatePeerDIDKeysResponse{
+		DID:                newDoc.ID,
+		DIDDocument:        docBytes,
+		RotatedConnections: rotated,
+	}, nil
+}
+
+// createRoutedPeerDID creates peer DID served by the router of given connection and registers its keys with
+// the router.
+func (c *Command) createRoutedPeerDID(routerConnectionID string, opts *peerDIDOptions) (*did.Doc, error) {
+	config, err := c.mediatorClient.GetConfig(routerConnectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	didDoc, err := newPeerDIDDoc(c.keyManager, opts, config)
+	if err != nil {
+		return nil, err
+	}
+
+	docResolution, err := c.vdrRegistry.Create(peer.DIDMethod, didDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create peer DID : %w", err)
+	}
+
+	didSvc, ok := lookupDIDCommService(docResolution.DIDDocument)
+	if !ok {
+		return nil, fmt.Errorf(errMissingDIDCommServiceType, didCommServiceType)
+	}
+
+	for _, key := range routeutil.DIDRouterKeys(docResolution.DIDDocument, didSvc) {
+		err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, routerConnectionID, key)
+		if err != nil {
+			return nil, fmt.Errorf(errFailedToRegisterDIDRecKey, err)
+		}
+	}
+
+	return docResolution.DIDDocument, nil
+}
+
+// rotateConnections rotates DIDComm V2 connections of the old DID to the new DID and sends a trust ping over
+// each of them so that the other party learns about the rotation. Failing to send the ping isn't fatal as
+// 'from_prior' is attached to every message sent over the connection until the other party uses the new DID.
+func (c *Command) rotateConnections(oldDID, newDID, signingKID string) ([]string, error) {
+	lookup, err := connection.NewLookup(c.routeProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection lookup : %w", err)
+	}
+
+	records, err := lookup.QueryConnectionRecords()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connections : %w", err)
+	}
+
+	var rotated []string
+
+	for _, rec := range records {
+		// records saved under the old DID by a previous rotation are kept for inbound messages only.
+		if rec.MyDID != oldDID || rec.DIDCommVersion != service.V2 ||
+			(rec.MyDIDRotation != nil && rec.MyDIDRotation.OldDID == oldDID) {
+			continue
+		}
+
+		err = c.didRotator.RotateConnectionDID(rec.ConnectionID, signingKID, newDID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rotate DID of connection %s : %w", rec.ConnectionID, err)
+		}
+
+		rotated = append(rotated, rec.ConnectionID)
+
+		err = c.routeProvider.Messenger().Send(service.NewDIDCommMsgMap(&trustPing{
+			ID:   uuid.New().String(),
+			Type: trustPingMsgType,
+			Body: trustPingBody{ResponseRequested: false},
+		}), newDID, rec.TheirDID)
+		if err != nil {
+			logger.Warnf("failed to notify connection %s of DID rotation : %s", rec.ConnectionID, err)
+		}
+	}
+
+	return rotated, nil
+}
+
+// removePeerKeysFromRouters removes recipient keys of peer DID from the given routers or from all router
+// connections if none are given.
+func (c *Command) removePeerKeysFromRouters(didDoc *did.Doc, routerConnections []string) error {
+	var keys []string
+
+	if didSvc, ok := lookupDIDCommService(didDoc); ok {
+		keys = routeutil.DIDRouterKeys(didDoc, didSvc)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if len(routerConnections) == 0 {
+		var err error
+
+		routerConnections, err = c.mediatorSvc.GetConnections()
+		if err != nil {
+			return fmt.Errorf("failed to get router connections : %w", err)
+		}
+	}
+
+	for _, rConn := range routerConnections {
+		err := routeutil.RemoveKeyFromRouter(c.routeProvider, rConn, keys...)
+		if err != nil {
+			return fmt.Errorf("failed to remove keys from router connection %s : %w", rConn, err)
+		}
+	}
+
+	return nil
+}
+
+// peerSigningKeyID returns absolute ID of the key signing 'from_prior' of DID rotation.
+func peerSigningKeyID(didDoc *did.Doc) (string, error) {
+	var kid string
+
+	switch {
+	case len(didDoc.Authentication) > 0:
+		kid = didDoc.Authentication[0].VerificationMethod.ID
+	case len(didDoc.VerificationMethod) > 0:
+		kid = didDoc.VerificationMethod[0].ID
+	default:
+		return "", fmt.Errorf("DID %s has no signing key", didDoc.ID)
+	}
+
+	if strings.HasPrefix(kid, "#") {
+		kid = didDoc.ID + kid
+	}
+
+	return kid, nil
+}
+
+func lookupDIDCommService(didDoc *did.Doc) (*did.Service, bool) {
+	didSvc, ok := did.LookupService(didDoc, didCommServiceType)
+	if !ok {
+		didSvc, ok = did.LookupService(didDoc, didCommV2ServiceType)
+	}
+
+	return didSvc, ok
+}
+
+type trustPing struct {
+	ID   string        `json:"id"`
+	Type string        `json:"type"`
+	Body trustPingBody `json:"body"`
+}
+
+type trustPingBody struct {
+	ResponseRequested bool `json:"response_requested"`
+}
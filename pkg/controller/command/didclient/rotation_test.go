@@ -0,0 +1,390 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/middleware"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockprotocol "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockservice "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/service"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	vdrpkg "github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+func TestCommand_RotatePeerDIDKeys(t *testing.T) {
+	const (
+		routerConnID = "router-conn"
+		routerDID    = "did:example:router"
+		theirDID     = "did:example:bob"
+	)
+
+	newCommand := func(t *testing.T) (*Command, *rotationProvider) {
+		t.Helper()
+
+		c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		km, err := localkms.New(
+			"local-lock://custom/master/key/",
+			mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+		)
+		require.NoError(t, err)
+
+		cr, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		peerVDR, err := peer.New(mockstorage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		p := &rotationProvider{
+			MockProvider: mockprotocol.MockProvider{
+				StoreProvider:              mockstorage.NewMockStoreProvider(),
+				ProtocolStateStoreProvider: mockstorage.NewMockStoreProvider(),
+			},
+			km:        km,
+			cr:        cr,
+			registry:  vdrpkg.New(vdrpkg.WithVDR(peerVDR)),
+			messenger: &sentMessages{},
+		}
+
+		c.keyManager = km
+		c.vdrRegistry = p.registry
+		c.routeProvider = p
+		c.mediatorClient = &mockMediatorClient{
+			GetConfigFunc: func(connID string) (*mediatorsvc.Config, error) {
+				return mediatorsvc.NewConfig("http://router.com", []string{"did:key:z6MkRouter"}), nil
+			},
+		}
+		c.mediatorSvc = &mockroute.MockMediatorSvc{
+			AddKeyFunc: func(key string) error {
+				p.registered = append(p.registered, key)
+
+				return nil
+			},
+			Connections: []string{routerConnID},
+		}
+
+		c.didRotator, err = middleware.New(p)
+		require.NoError(t, err)
+
+		recorder, err := connection.NewRecorder(p)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID: routerConnID,
+			State:        connection.StateNameCompleted,
+			MyDID:        "did:example:me",
+			TheirDID:     routerDID,
+		}))
+
+		return c, p
+	}
+
+	createPeerDID := func(t *testing.T, c *Command) *did.Doc {
+		t.Helper()
+
+		req, err := json.Marshal(&CreatePeerDIDRequest{
+			RouterConnectionID: routerConnID,
+			ServiceType:        didCommV2ServiceType,
+		})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, c.CreatePeerDID(&b, bytes.NewBuffer(req)))
+
+		resp, err := did.ParseDocumentResolution(b.Bytes())
+		require.NoError(t, err)
+
+		return resp.DIDDocument
+	}
+
+	rotate := func(t *testing.T, c *Command, request *RotatePeerDIDKeysRequest) (*RotatePeerDIDKeysResponse,
+		command.Error) {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RotatePeerDIDKeys(&b, bytes.NewBuffer(req))
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		resp := &RotatePeerDIDKeysResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp, nil
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		c, p := newCommand(t)
+
+		oldDoc := createPeerDID(t, c)
+		oldKeys := p.registered
+		p.registered = nil
+
+		recorder, err := connection.NewRecorder(p)
+		require.NoError(t, err)
+
+		for _, rec := range []*connection.Record{
+			{ConnectionID: "conn-v2", MyDID: oldDoc.ID, TheirDID: theirDID, DIDCommVersion: service.V2},
+			{ConnectionID: "conn-v1", MyDID: oldDoc.ID, TheirDID: theirDID, DIDCommVersion: service.V1},
+			{ConnectionID: "conn-other", MyDID: "did:peer:other", TheirDID: theirDID, DIDCommVersion: service.V2},
+		} {
+			rec.State = connection.StateNameCompleted
+			require.NoError(t, recorder.SaveConnectionRecord(rec))
+		}
+
+		resp, cmdErr := rotate(t, c, &RotatePeerDIDKeysRequest{
+			DID:                oldDoc.ID,
+			RouterConnectionID: routerConnID,
+			ServiceType:        didCommV2ServiceType,
+		})
+		require.NoError(t, cmdErr)
+		require.NotEqual(t, oldDoc.ID, resp.DID)
+		require.Equal(t, []string{"conn-v2"}, resp.RotatedConnections)
+
+		newDoc, err := did.ParseDocument(resp.DIDDocument)
+		require.NoError(t, err)
+		require.Equal(t, resp.DID, newDoc.ID)
+
+		require.Len(t, p.registered, 1)
+		require.Equal(t, routeutil.DIDRouterKeys(newDoc, &newDoc.Service[0]), p.registered)
+
+		rec, err := recorder.GetConnectionRecord("conn-v2")
+		require.NoError(t, err)
+		require.Equal(t, resp.DID, rec.MyDID)
+		require.NotNil(t, rec.MyDIDRotation)
+		require.Equal(t, oldDoc.ID, rec.MyDIDRotation.OldDID)
+		require.NotEmpty(t, rec.MyDIDRotation.FromPrior)
+
+		rec, err = recorder.GetConnectionRecord("conn-v1")
+		require.NoError(t, err)
+		require.Equal(t, oldDoc.ID, rec.MyDID)
+
+		require.Len(t, p.messenger.sent, 2)
+		require.Equal(t, trustPingMsgType, p.messenger.sent[0].msg.Type())
+		require.Equal(t, resp.DID, p.messenger.sent[0].myDID)
+		require.Equal(t, theirDID, p.messenger.sent[0].theirDID)
+
+		require.Equal(t, mediatorsvc.KeylistUpdateMsgType, p.messenger.sent[1].msg.Type())
+		require.Equal(t, routerDID, p.messenger.sent[1].theirDID)
+
+		update := &mediatorsvc.KeylistUpdate{}
+		require.NoError(t, p.messenger.sent[1].msg.Decode(update))
+		require.Len(t, update.Updates, len(oldKeys))
+		require.Equal(t, oldKeys[0], update.Updates[0].RecipientKey)
+		require.Equal(t, "remove", update.Updates[0].Action)
+	})
+
+	t.Run("test error from request", func(t *testing.T) {
+		c, _ := newCommand(t)
+
+		var b bytes.Buffer
+
+		cmdErr := c.RotatePeerDIDKeys(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		for _, tc := range []struct {
+			request *RotatePeerDIDKeysRequest
+			err     string
+		}{
+			{
+				request: &RotatePeerDIDKeysRequest{DID: "did:orb:123", RouterConnectionID: routerConnID},
+				err:     errInvalidDID,
+			},
+			{
+				request: &RotatePeerDIDKeysRequest{DID: "did:peer:123"},
+				err:     errInvalidRouterConnectionID,
+			},
+			{
+				request: &RotatePeerDIDKeysRequest{
+					DID:                "did:peer:123",
+					RouterConnectionID: routerConnID,
+					ServiceType:        "LinkedDomains",
+				},
+				err: fmt.Sprintf(errUnsupportedServiceType, "LinkedDomains"),
+			},
+		} {
+			_, cmdErr = rotate(t, c, tc.request)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+			require.Contains(t, cmdErr.Error(), tc.err)
+		}
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		c, _ := newCommand(t)
+		oldDoc := createPeerDID(t, c)
+		request := &RotatePeerDIDKeysRequest{
+			DID:                oldDoc.ID,
+			RouterConnectionID: routerConnID,
+			ServiceType:        didCommV2ServiceType,
+		}
+
+		_, cmdErr := rotate(t, c, &RotatePeerDIDKeysRequest{DID: "did:peer:123", RouterConnectionID: routerConnID})
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to resolve DID")
+
+		c.mediatorClient = &mockMediatorClient{
+			GetConfigFunc: func(connID string) (*mediatorsvc.Config, error) {
+				return nil, fmt.Errorf("get config error")
+			},
+		}
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "get config error")
+
+		c, _ = newCommand(t)
+		oldDoc = createPeerDID(t, c)
+		request.DID = oldDoc.ID
+		c.mediatorSvc = &mockroute.MockMediatorSvc{AddKeyErr: fmt.Errorf("add key error")}
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "add key error")
+
+		c.mediatorSvc = &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf("get connections error")}
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "get connections error")
+
+		c.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveValue: oldDoc,
+			CreateErr:    fmt.Errorf("create error"),
+		}
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "create error")
+
+		c, p := newCommand(t)
+		oldDoc = createPeerDID(t, c)
+		request.DID = oldDoc.ID
+
+		recorder, err := connection.NewRecorder(p)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID:   "conn-v2",
+			State:          connection.StateNameCompleted,
+			MyDID:          oldDoc.ID,
+			TheirDID:       theirDID,
+			DIDCommVersion: service.V2,
+		}))
+
+		c.didRotator = &mockDIDRotator{err: fmt.Errorf("rotate error")}
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "rotate error")
+
+		c.didRotator = &mockDIDRotator{}
+		p.messenger.ErrSend = fmt.Errorf("send error")
+
+		_, cmdErr = rotate(t, c, request)
+		require.Error(t, cmdErr)
+		require.Equal(t, RotatePeerDIDKeysErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to remove keys from router connection")
+	})
+
+	t.Run("test DID without keys", func(t *testing.T) {
+		_, err := peerSigningKeyID(&did.Doc{ID: "did:peer:123"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has no signing key")
+	})
+}
+
+type mockDIDRotator struct {
+	err error
+}
+
+func (m *mockDIDRotator) RotateConnectionDID(_, _, _ string) error {
+	return m.err
+}
+
+type rotationProvider struct {
+	mockprotocol.MockProvider
+	km         kms.KeyManager
+	cr         crypto.Crypto
+	registry   vdrapi.Registry
+	messenger  *sentMessages
+	registered []string
+}
+
+func (p *rotationProvider) KMS() kms.KeyManager {
+	return p.km
+}
+
+func (p *rotationProvider) Crypto() crypto.Crypto {
+	return p.cr
+}
+
+func (p *rotationProvider) VDRegistry() vdrapi.Registry {
+	return p.registry
+}
+
+func (p *rotationProvider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+type sentMessage struct {
+	msg      service.DIDCommMsgMap
+	myDID    string
+	theirDID string
+}
+
+type sentMessages struct {
+	mockservice.MockMessenger
+	sent []sentMessage
+}
+
+func (m *sentMessages) Send(msg service.DIDCommMsgMap, myDID, theirDID string, _ ...service.Opt) error {
+	if m.ErrSend != nil {
+		return m.ErrSend
+	}
+
+	m.sent = append(m.sent, sentMessage{msg: msg, myDID: myDID, theirDID: theirDID})
+
+	return nil
+}
@@ -0,0 +1,391 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// signature purposes, verification relationships signing keys are chosen from.
+	signaturePurposeAuthentication  = "authentication"
+	signaturePurposeAssertionMethod = "assertionMethod"
+)
+
+// SignJWT signs JWT claims with a key of DID from the requested verification relationship, the key is kept
+// in the agent KMS and its DID URL is set as 'kid' header of the JWT.
+func (c *Command) SignJWT(rw io.Writer, req io.Reader) command.Error {
+	var request SignJWTRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWTCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	var claims map[string]interface{}
+
+	err = validateSignRequest(request.DID, request.Purpose)
+	if err == nil && (json.Unmarshal(request.Claims, &claims) != nil || claims == nil) {
+		err = errors.New(errMissingClaims)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWTCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.signJWT(&request, claims)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWTCommandMethod, err.Error())
+
+		return command.NewExecuteError(SignErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, SignJWTCommandMethod, successString)
+
+	return nil
+}
+
+// VerifyJWT verifies JWT signed by a key of DID from the requested verification relationship, the key is
+// resolved from 'kid' header of the JWT. Expiry and not before claims are checked and issuer, if set, has to
+// be the signer DID.
+func (c *Command) VerifyJWT(rw io.Writer, req io.Reader) command.Error {
+	var request VerifyJWTRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWTCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateSignaturePurpose(request.Purpose)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWTCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.verifyJWT(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWTCommandMethod, err.Error())
+
+		return command.NewExecuteError(VerifySignatureErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyJWTCommandMethod, successString)
+
+	return nil
+}
+
+// SignJWS signs payload with a key of DID from the requested verification relationship and returns compact
+// JWS, with the payload left out if detached.
+func (c *Command) SignJWS(rw io.Writer, req io.Reader) command.Error {
+	var request SignJWSRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWSCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateSignRequest(request.DID, request.Purpose)
+	if err == nil && len(request.Payload) == 0 {
+		err = errors.New(errMissingPayload)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWSCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.signJWS(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SignJWSCommandMethod, err.Error())
+
+		return command.NewExecuteError(SignErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, SignJWSCommandMethod, successString)
+
+	return nil
+}
+
+// VerifyJWS verifies compact JWS signed by a key of DID from the requested verification relationship, the key
+// is resolved from 'kid' header of the JWS.
+func (c *Command) VerifyJWS(rw io.Writer, req io.Reader) command.Error {
+	var request VerifyJWSRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWSCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = validateSignaturePurpose(request.Purpose)
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWSCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	var keyID string
+
+	jws, err := jose.ParseJWS(request.JWS, c.didSignatureVerifier(request.Purpose, &keyID),
+		jose.WithJWSDetachedPayload(request.Payload))
+	if err != nil {
+		logutil.LogError(logger, CommandName, VerifyJWSCommandMethod, err.Error())
+
+		return command.NewExecuteError(VerifySignatureErrorCode, fmt.Errorf("invalid JWS : %w", err))
+	}
+
+	command.WriteNillableResponse(rw, &VerifyJWSResponse{
+		Payload: jws.Payload,
+		Headers: jws.ProtectedHeaders,
+		DID:     didOfURL(keyID),
+		KeyID:   keyID,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, VerifyJWSCommandMethod, successString)
+
+	return nil
+}
+
+func (c *Command) signJWT(request *SignJWTRequest, claims map[string]interface{}) (*SignJWTResponse, error) {
+	signer, err := c.relationshipSigner(request.DID, request.KeyID, request.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.NewSigned(claims, signingHeaders(request.Headers, signer, jwt.TypeJWT), signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign JWT : %w", err)
+	}
+
+	serialized, err := token.Serialize(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize JWT : %w", err)
+	}
+
+	return &SignJWTResponse{JWT: serialized, KeyID: signer.vmID}, nil
+}
+
+func (c *Command) signJWS(request *SignJWSRequest) (*SignJWSResponse, error) {
+	signer, err := c.relationshipSigner(request.DID, request.KeyID, request.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	jws, err := jose.NewJWS(signingHeaders(request.Headers, signer, ""), nil, request.Payload, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign JWS : %w", err)
+	}
+
+	serialized, err := jws.SerializeCompact(request.Detached)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize JWS : %w", err)
+	}
+
+	return &SignJWSResponse{JWS: serialized, KeyID: signer.vmID}, nil
+}
+
+func (c *Command) verifyJWT(request *VerifyJWTRequest) (*VerifyJWTResponse, error) {
+	var keyID string
+
+	token, err := jwt.Parse(request.JWT, jwt.WithSignatureVerifier(c.didSignatureVerifier(request.Purpose, &keyID)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT : %w", err)
+	}
+
+	claims := &jwt.Claims{}
+
+	err = token.DecodeClaims(claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT claims : %w", err)
+	}
+
+	now := time.Now()
+
+	if claims.Expiry != nil && now.After(claims.Expiry.Time()) {
+		return nil, errors.New("JWT is expired")
+	}
+
+	if claims.NotBefore != nil && now.Before(claims.NotBefore.Time()) {
+		return nil, errors.New("JWT is not valid yet")
+	}
+
+	if claims.Issuer != "" && claims.Issuer != didOfURL(keyID) {
+		return nil, fmt.Errorf("JWT issuer %s is not the signer %s", claims.Issuer, didOfURL(keyID))
+	}
+
+	payload, err := json.Marshal(token.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JWT claims : %w", err)
+	}
+
+	return &VerifyJWTResponse{Claims: payload, Headers: token.Headers, DID: didOfURL(keyID), KeyID: keyID}, nil
+}
+
+// relationshipSigner returns signer with the KMS key of DID verification method from the purpose verification
+// relationship, signer key ID is DID URL of the verification method.
+func (c *Command) relationshipSigner(didID, keyID, purpose string) (*verificationMethodSigner, error) {
+	docResolution, err := c.resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID %s : %w", didID, err)
+	}
+
+	vm, err := relationshipVerificationMethod(docResolution.DIDDocument, purpose, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := c.newVerificationMethodSigner(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(signer.vmID, didID) {
+		signer.vmID = didID + "#" + fragment(signer.vmID)
+	}
+
+	return signer, nil
+}
+
+// didSignatureVerifier returns JWS verifier of signatures by keys of DIDs from the purpose verification
+// relationship, key is resolved from 'kid' header which is stored to keyID once signature is verified.
+func (c *Command) didSignatureVerifier(purpose string, keyID *string) jose.SignatureVerifier {
+	return jose.SignatureVerifierFunc(func(headers jose.Headers, _, signingInput, signature []byte) error {
+		kid, _ := headers.KeyID()     // nolint:errcheck
+		alg, _ := headers.Algorithm() // nolint:errcheck
+
+		if !strings.Contains(kid, "#") {
+			return fmt.Errorf("kid header '%s' is not a DID URL", kid)
+		}
+
+		docResolution, err := c.resolve(didOfURL(kid))
+		if err != nil {
+			return fmt.Errorf("failed to resolve DID of %s : %w", kid, err)
+		}
+
+		vm, err := relationshipVerificationMethod(docResolution.DIDDocument, purpose, kid)
+		if err != nil {
+			return err
+		}
+
+		pubKey, _, vmAlg, err := verificationMethodKey(vm)
+		if err != nil {
+			return err
+		}
+
+		if alg != vmAlg {
+			return fmt.Errorf("alg header %s doesn't match key %s", alg, kid)
+		}
+
+		sigVerifier, err := algSignatureVerifier(alg)
+		if err != nil {
+			return err
+		}
+
+		err = sigVerifier.Verify(&verifier.PublicKey{Type: vm.Type, Value: pubKey, JWK: vm.JSONWebKey()},
+			signingInput, signature)
+		if err != nil {
+			return fmt.Errorf("invalid signature : %w", err)
+		}
+
+		*keyID = kid
+
+		return nil
+	})
+}
+
+// relationshipVerificationMethod returns verification method with given ID or the first verification method
+// of the purpose verification relationship.
+func relationshipVerificationMethod(didDoc *did.Doc, purpose, keyID string) (*did.VerificationMethod, error) {
+	verifications := didDoc.AssertionMethod
+	if purpose == signaturePurposeAuthentication {
+		verifications = didDoc.Authentication
+	}
+
+	if purpose == "" {
+		purpose = signaturePurposeAssertionMethod
+	}
+
+	for i := range verifications {
+		if keyID == "" || fragment(verifications[i].VerificationMethod.ID) == fragment(keyID) {
+			return &verifications[i].VerificationMethod, nil
+		}
+	}
+
+	if keyID != "" {
+		return nil, fmt.Errorf("key %s not found in %s of DID %s", keyID, purpose, didDoc.ID)
+	}
+
+	return nil, fmt.Errorf("DID %s has no %s key", didDoc.ID, purpose)
+}
+
+// signingHeaders returns protected headers of JWS, algorithm and key ID of the signer can't be overridden.
+func signingHeaders(headers map[string]interface{}, signer *verificationMethodSigner, typ string) jose.Headers {
+	h := jose.Headers{}
+
+	if typ != "" {
+		h[jose.HeaderType] = typ
+	}
+
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	h[jose.HeaderAlgorithm] = signer.alg
+	h[jose.HeaderKeyID] = signer.vmID
+
+	return h
+}
+
+func validateSignRequest(didID, purpose string) error {
+	if _, err := did.Parse(didID); err != nil {
+		return fmt.Errorf(errInvalidDID)
+	}
+
+	return validateSignaturePurpose(purpose)
+}
+
+func validateSignaturePurpose(purpose string) error {
+	switch purpose {
+	case "", signaturePurposeAuthentication, signaturePurposeAssertionMethod:
+		return nil
+	default:
+		return fmt.Errorf(errUnsupportedSignaturePurpose, purpose)
+	}
+}
+
+func didOfURL(didURL string) string {
+	return strings.SplitN(didURL, "#", 2)[0] // nolint:gomnd
+}
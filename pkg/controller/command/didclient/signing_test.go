@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_SignAndVerify(t *testing.T) {
+	c, didDoc := newCommandWithWebDID(t,
+		KeySpec{ID: "auth", KeyType: ed25519KeyType, Purposes: []string{doc.KeyPurposeAuthentication}},
+		KeySpec{ID: "assertion", KeyType: p256KeyType, Purposes: []string{doc.KeyPurposeAssertionMethod}},
+	)
+
+	execute := func(t *testing.T, cmd func(rw io.Writer, req io.Reader) command.Error, request,
+		response interface{}) command.Error {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd(&b, bytes.NewBuffer(req))
+		if cmdErr == nil {
+			require.NoError(t, json.Unmarshal(b.Bytes(), response))
+		}
+
+		return cmdErr
+	}
+
+	signJWT := func(t *testing.T, request *SignJWTRequest) string {
+		t.Helper()
+
+		resp := &SignJWTResponse{}
+		require.NoError(t, execute(t, c.SignJWT, request, resp))
+
+		return resp.JWT
+	}
+
+	verifyJWT := func(t *testing.T, request *VerifyJWTRequest) (*VerifyJWTResponse, command.Error) {
+		t.Helper()
+
+		resp := &VerifyJWTResponse{}
+
+		return resp, execute(t, c.VerifyJWT, request, resp)
+	}
+
+	signJWS := func(t *testing.T, request *SignJWSRequest) string {
+		t.Helper()
+
+		resp := &SignJWSResponse{}
+		require.NoError(t, execute(t, c.SignJWS, request, resp))
+
+		return resp.JWS
+	}
+
+	verifyJWS := func(t *testing.T, request *VerifyJWSRequest) (*VerifyJWSResponse, command.Error) {
+		t.Helper()
+
+		resp := &VerifyJWSResponse{}
+
+		return resp, execute(t, c.VerifyJWS, request, resp)
+	}
+
+	t.Run("test JWT with assertion method key", func(t *testing.T) {
+		jwt := signJWT(t, &SignJWTRequest{
+			DID:     didDoc.ID,
+			Claims:  json.RawMessage(`{"iss":"` + didDoc.ID + `","sub":"alice"}`),
+			Headers: map[string]interface{}{"alg": "none", "custom": "value"},
+		})
+
+		resp, cmdErr := verifyJWT(t, &VerifyJWTRequest{JWT: jwt})
+		require.NoError(t, cmdErr)
+		require.Equal(t, didDoc.ID, resp.DID)
+		require.Equal(t, didDoc.ID+"#assertion", resp.KeyID)
+		require.Equal(t, "ES256", resp.Headers["alg"])
+		require.Equal(t, "value", resp.Headers["custom"])
+		require.JSONEq(t, `{"iss":"`+didDoc.ID+`","sub":"alice"}`, string(resp.Claims))
+
+		// the key is not an authentication key.
+		_, cmdErr = verifyJWT(t, &VerifyJWTRequest{JWT: jwt, Purpose: signaturePurposeAuthentication})
+		require.Error(t, cmdErr)
+		require.Equal(t, VerifySignatureErrorCode, cmdErr.Code())
+	})
+
+	t.Run("test JWT with authentication key", func(t *testing.T) {
+		jwt := signJWT(t, &SignJWTRequest{
+			DID:     didDoc.ID,
+			Purpose: signaturePurposeAuthentication,
+			Claims:  json.RawMessage(`{"nonce":"123"}`),
+		})
+
+		resp, cmdErr := verifyJWT(t, &VerifyJWTRequest{JWT: jwt, Purpose: signaturePurposeAuthentication})
+		require.NoError(t, cmdErr)
+		require.Equal(t, didDoc.ID+"#auth", resp.KeyID)
+		require.Equal(t, "EdDSA", resp.Headers["alg"])
+	})
+
+	t.Run("test invalid JWT claims", func(t *testing.T) {
+		for _, claims := range []string{
+			fmt.Sprintf(`{"exp":%d}`, time.Now().Add(-time.Hour).Unix()),
+			fmt.Sprintf(`{"nbf":%d}`, time.Now().Add(time.Hour).Unix()),
+			`{"iss":"did:example:other"}`,
+		} {
+			jwt := signJWT(t, &SignJWTRequest{DID: didDoc.ID, Claims: json.RawMessage(claims)})
+
+			_, cmdErr := verifyJWT(t, &VerifyJWTRequest{JWT: jwt})
+			require.Error(t, cmdErr, claims)
+		}
+	})
+
+	t.Run("test tampered JWT", func(t *testing.T) {
+		jwt := signJWT(t, &SignJWTRequest{DID: didDoc.ID, Claims: json.RawMessage(`{"sub":"alice"}`)})
+		parts := strings.Split(jwt, ".")
+
+		other := signJWT(t, &SignJWTRequest{DID: didDoc.ID, Claims: json.RawMessage(`{"sub":"bob"}`)})
+
+		_, cmdErr := verifyJWT(t, &VerifyJWTRequest{JWT: parts[0] + "." + strings.Split(other, ".")[1] + "." +
+			parts[2]})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "invalid signature")
+	})
+
+	t.Run("test JWS", func(t *testing.T) {
+		jws := signJWS(t, &SignJWSRequest{DID: didDoc.ID, KeyID: "#assertion", Payload: []byte("payload")})
+
+		resp, cmdErr := verifyJWS(t, &VerifyJWSRequest{JWS: jws})
+		require.NoError(t, cmdErr)
+		require.Equal(t, []byte("payload"), resp.Payload)
+		require.Equal(t, didDoc.ID, resp.DID)
+	})
+
+	t.Run("test detached JWS", func(t *testing.T) {
+		jws := signJWS(t, &SignJWSRequest{
+			DID: didDoc.ID, Purpose: signaturePurposeAuthentication, Payload: []byte("payload"), Detached: true,
+		})
+		require.Empty(t, strings.Split(jws, ".")[1])
+
+		resp, cmdErr := verifyJWS(t, &VerifyJWSRequest{
+			JWS: jws, Payload: []byte("payload"), Purpose: signaturePurposeAuthentication,
+		})
+		require.NoError(t, cmdErr)
+		require.Equal(t, didDoc.ID+"#auth", resp.KeyID)
+
+		_, cmdErr = verifyJWS(t, &VerifyJWSRequest{
+			JWS: jws, Payload: []byte("other"), Purpose: signaturePurposeAuthentication,
+		})
+		require.Error(t, cmdErr)
+	})
+
+	t.Run("test algorithm of key", func(t *testing.T) {
+		jws := signJWS(t, &SignJWSRequest{DID: didDoc.ID, Payload: []byte("payload")})
+		parts := strings.Split(jws, ".")
+
+		header, err := json.Marshal(map[string]string{"alg": "EdDSA", "kid": didDoc.ID + "#assertion"})
+		require.NoError(t, err)
+
+		_, cmdErr := verifyJWS(t, &VerifyJWSRequest{
+			JWS: base64.RawURLEncoding.EncodeToString(header) + "." + parts[1] + "." + parts[2],
+		})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "doesn't match key")
+	})
+
+	t.Run("test sign errors", func(t *testing.T) {
+		// the key is not an assertion method key.
+		cmdErr := execute(t, c.SignJWT, &SignJWTRequest{DID: didDoc.ID, Claims: json.RawMessage(`{}`), KeyID: "#auth"},
+			nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, SignErrorCode, cmdErr.Code())
+
+		cmdErr = execute(t, c.SignJWS, &SignJWSRequest{DID: "did:web:other.com", Payload: []byte("payload")}, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, SignErrorCode, cmdErr.Code())
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		for _, test := range []struct {
+			cmd     func(rw io.Writer, req io.Reader) command.Error
+			request interface{}
+		}{
+			{
+				cmd:     c.SignJWT,
+				request: &SignJWTRequest{DID: didDoc.ID, Claims: json.RawMessage(`[]`)},
+			},
+			{
+				cmd:     c.SignJWT,
+				request: &SignJWTRequest{DID: "invalid", Claims: json.RawMessage(`{}`)},
+			},
+			{
+				cmd:     c.SignJWS,
+				request: &SignJWSRequest{DID: didDoc.ID},
+			},
+			{
+				cmd:     c.SignJWS,
+				request: &SignJWSRequest{DID: didDoc.ID, Payload: []byte("payload"), Purpose: "keyAgreement"},
+			},
+			{
+				cmd:     c.VerifyJWT,
+				request: &VerifyJWTRequest{Purpose: "keyAgreement"},
+			},
+			{
+				cmd:     c.VerifyJWS,
+				request: &VerifyJWSRequest{Purpose: "keyAgreement"},
+			},
+		} {
+			cmdErr := execute(t, test.cmd, test.request, nil)
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+
+		for _, cmd := range []func(rw io.Writer, req io.Reader) command.Error{
+			c.SignJWT, c.VerifyJWT, c.SignJWS, c.VerifyJWS,
+		} {
+			var b bytes.Buffer
+
+			cmdErr := cmd(&b, bytes.NewBufferString("--"))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+// newCommandWithWebDID returns command with KMS holding keys of did:web DID which is resolved by the VDR registry.
+func newCommandWithWebDID(t *testing.T, keys ...KeySpec) (*Command, *did.Doc) {
+	t.Helper()
+
+	c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	c.keyManager, err = localkms.New(
+		"local-lock://custom/master/key/",
+		mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{}),
+	)
+	require.NoError(t, err)
+
+	c.crypto, err = tinkcrypto.New()
+	require.NoError(t, err)
+
+	request := &CreateDIDRequest{Method: didMethodWeb, Domain: "example.com", Keys: keys}
+	require.NoError(t, validateCreateDIDRequest(request))
+
+	didDoc, _, _, err := c.createWebDID(request)
+	require.NoError(t, err)
+
+	c.vdrRegistry = &mockvdr.MockVDRegistry{
+		ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			if didID != didDoc.ID {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &did.DocResolution{DIDDocument: didDoc}, nil
+		},
+	}
+
+	return c, didDoc
+}
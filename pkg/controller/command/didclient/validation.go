@@ -0,0 +1,449 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/model"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser/patchvalidator"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// validation finding severities.
+	findingError   = "error"
+	findingWarning = "warning"
+
+	// validation finding codes.
+	findingInvalidKeyID                = "invalidKeyID"
+	findingDuplicateKeyID              = "duplicateKeyID"
+	findingUnsupportedKeyType          = "unsupportedKeyType"
+	findingUnsupportedVerificationType = "unsupportedVerificationMethodType"
+	findingKeyTypeMismatch             = "keyTypeMismatch"
+	findingInvalidKeyValue             = "invalidKeyValue"
+	findingInvalidKeySize              = "invalidKeySize"
+	findingUnsupportedPurpose          = "unsupportedPurpose"
+	findingPurposeKeyTypeMismatch      = "purposeKeyTypeMismatch"
+	findingDuplicatePurpose            = "duplicatePurpose"
+	findingKeyWithoutPurpose           = "keyWithoutPurpose"
+	findingMissingOperationKey         = "missingOperationKey"
+	findingManagedOperationKey         = "managedOperationKey"
+	findingInvalidServiceID            = "invalidServiceID"
+	findingInvalidServiceType          = "invalidServiceType"
+	findingInvalidServiceEndpoint      = "invalidServiceEndpoint"
+	findingInvalidRoutingKey           = "invalidRoutingKey"
+	findingRejectedBySidetree          = "rejectedBySidetree"
+	findingDocumentBuildFailed         = "documentBuildFailed"
+
+	// limits of Sidetree public key and service properties.
+	maxSidetreeIDLength          = 50
+	maxSidetreeServiceTypeLength = 30
+
+	// public key sizes.
+	ed25519PublicKeySize          = 32
+	p256UncompressedPublicKeySize = 65
+	p384UncompressedPublicKeySize = 97
+	bls12381G2PublicKeySize       = 96
+	x25519PublicKeySize           = 32
+	p256CoordinateSize            = 32
+	p384CoordinateSize            = 48
+	p521CoordinateSize            = 66
+
+	ed25519VerificationKey2020 = "Ed25519VerificationKey2020"
+	ephemeralKMSPrimaryKeyURI  = "local-lock://ephemeral/primary/key/"
+	errPurposeKeyTypeMismatch  = "%s key can't be used for %s"
+)
+
+// sidetreeIDRegex characters allowed in IDs of Sidetree public keys and services.
+// nolint:gochecknoglobals
+var sidetreeIDRegex = regexp.MustCompile("^[A-Za-z0-9_-]+$")
+
+// verificationMethodKeyTypes key types supported by verification method types allowed by Sidetree.
+// nolint:gochecknoglobals
+var verificationMethodKeyTypes = map[string][]string{
+	ed25519VerificationKey2018: {ed25519KeyType},
+	ed25519VerificationKey2020: {ed25519KeyType},
+	bls12381G2Key2020:          {BLS12381G2KeyType},
+	x25519KeyAgreementKey2019:  {x25519ECDHKW},
+	jsonWebKey2020: {
+		ed25519KeyType, p256KeyType, p384KeyType, x25519ECDHKW, p256ecdhkw, p384ecdhkw, p521ecdhkw,
+	},
+}
+
+// publicKeySizes sizes of raw public key values, ECDH key values are JSON so their coordinates are checked instead.
+// nolint:gochecknoglobals
+var publicKeySizes = map[string]int{
+	ed25519KeyType:    ed25519PublicKeySize,
+	p256KeyType:       p256UncompressedPublicKeySize,
+	p384KeyType:       p384UncompressedPublicKeySize,
+	BLS12381G2KeyType: bls12381G2PublicKeySize,
+}
+
+// ecdhCoordinateSizes sizes of coordinates of key agreement keys.
+// nolint:gochecknoglobals
+var ecdhCoordinateSizes = map[string]int{
+	x25519ECDHKW: x25519PublicKeySize,
+	p256ecdhkw:   p256CoordinateSize,
+	p384ecdhkw:   p384CoordinateSize,
+	p521ecdhkw:   p521CoordinateSize,
+}
+
+// ValidateOrbDIDRequest builds DID document from orb DID create request the same way CreateOrbDID does, without
+// creating anything, and checks both against the rules Orb applies to create operations.
+func (c *Command) ValidateOrbDIDRequest(rw io.Writer, req io.Reader) command.Error {
+	var request CreateOrbDIDRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ValidateOrbDIDRequestCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, c.validateOrbDID(&request), logger)
+
+	logutil.LogDebug(logger, CommandName, ValidateOrbDIDRequestCommandMethod, successString)
+
+	return nil
+}
+
+// orbDIDValidation collects findings of orb DID create request validation.
+type orbDIDValidation struct {
+	findings []ValidationFinding
+}
+
+func (v *orbDIDValidation) add(severity, code, path, format string, args ...interface{}) {
+	v.findings = append(v.findings, ValidationFinding{
+		Code:     code,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *orbDIDValidation) valid() bool {
+	for _, finding := range v.findings {
+		if finding.Severity == findingError {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Command) validateOrbDID(request *CreateOrbDIDRequest) *ValidateOrbDIDResponse {
+	v := &orbDIDValidation{findings: []ValidationFinding{}}
+
+	validatePublicKeys(v, request)
+	validateOrbService(v, request)
+
+	if v.valid() {
+		docBytes, err := c.dryRunOrbDIDDoc(v, request)
+		if err != nil {
+			v.add(findingError, findingDocumentBuildFailed, "", "failed to build DID document: %s", err)
+		}
+
+		if v.valid() {
+			return &ValidateOrbDIDResponse{Valid: true, DIDDocument: docBytes, Findings: v.findings}
+		}
+	}
+
+	return &ValidateOrbDIDResponse{Findings: v.findings}
+}
+
+// dryRunOrbDIDDoc builds document of orb DID with throwaway managed keys and validates its create operation
+// with Sidetree rules.
+func (c *Command) dryRunOrbDIDDoc(v *orbDIDValidation, request *CreateOrbDIDRequest) (json.RawMessage, error) {
+	dryRunRequest := *request
+	dryRunRequest.PublicKeys = append([]PublicKey(nil), request.PublicKeys...)
+
+	kmsProvider := &ephemeralKMSProvider{
+		storageProvider: mem.NewProvider(),
+		secretLock:      &noop.NoLock{},
+	}
+
+	keyManager, err := localkms.New(ephemeralKMSPrimaryKeyURI, kmsProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS : %w", err)
+	}
+
+	// keys of the dry run are thrown away with the ephemeral KMS, so orphaned keys are recorded in its storage.
+	kmsStore, err := kmsProvider.storageProvider.OpenStore(CommandName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open KMS store : %w", err)
+	}
+
+	kmsKeys := newKMSKeyStore(keyManager, kmsStore)
+
+	didDoc, opts, _, cmdErr := c.newOrbDIDDoc(&dryRunRequest, keyManager, kmsKeys)
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	createRequest, _, label, err := c.orbCreateRequest(didDoc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &model.CreateRequest{}
+
+	err = json.Unmarshal(createRequest, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse create request : %w", err)
+	}
+
+	for _, p := range operation.Delta.Patches {
+		if errPatch := patchvalidator.Validate(p); errPatch != nil {
+			v.add(findingError, findingRejectedBySidetree, "", "%s", errPatch)
+		}
+	}
+
+	_, longFormDID, err := orbDIDFromCreateRequest(createRequest, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return longFormDIDDoc(didDoc, longFormDID).JSONBytes()
+}
+
+func validatePublicKeys(v *orbDIDValidation, request *CreateOrbDIDRequest) { // nolint:gocyclo
+	ids := make(map[string]bool)
+
+	var hasUpdateKey, hasRecoveryKey bool
+
+	for i := range request.PublicKeys {
+		key := &request.PublicKeys[i]
+		path := fmt.Sprintf("publicKeys[%d]", i)
+
+		if key.Update || key.Recovery {
+			hasUpdateKey = hasUpdateKey || key.Update
+			hasRecoveryKey = hasRecoveryKey || key.Recovery
+
+			if request.ManagedKeys {
+				v.add(findingError, findingManagedOperationKey, path, errManagedUpdateRecoveryKeys)
+
+				continue
+			}
+
+			validatePublicKeyValue(v, key, path, false)
+
+			continue
+		}
+
+		// ID of managed key without value is the ID of the key created in the KMS.
+		switch {
+		case key.ID == "" && (!request.ManagedKeys || key.Value != ""):
+			v.add(findingError, findingInvalidKeyID, path+".id", "key ID is missing")
+		case key.ID != "":
+			validateSidetreeID(v, findingInvalidKeyID, path+".id", key.ID)
+
+			if ids[key.ID] {
+				v.add(findingError, findingDuplicateKeyID, path+".id", "duplicate key ID: %s", key.ID)
+			}
+
+			ids[key.ID] = true
+		}
+
+		if !validatePublicKeyValue(v, key, path, request.ManagedKeys) {
+			continue
+		}
+
+		keyType := strings.ToLower(key.KeyType)
+
+		keyTypes, ok := verificationMethodKeyTypes[key.Type]
+		if !ok {
+			v.add(findingError, findingUnsupportedVerificationType, path+".type",
+				"unsupported verification method type: %s", key.Type)
+		} else if !containsString(keyTypes, keyType) {
+			v.add(findingError, findingKeyTypeMismatch, path+".type",
+				"verification method type %s doesn't support %s key", key.Type, key.KeyType)
+		}
+
+		validatePurposes(v, key, path)
+	}
+
+	if request.ManagedKeys {
+		return
+	}
+
+	if !hasUpdateKey {
+		v.add(findingError, findingMissingOperationKey, "publicKeys", "update key is missing")
+	}
+
+	if !hasRecoveryKey {
+		v.add(findingError, findingMissingOperationKey, "publicKeys", "recovery key is missing")
+	}
+}
+
+// validatePublicKeyValue checks key type and size of key value, it returns false if the key type is not supported.
+func validatePublicKeyValue(v *orbDIDValidation, key *PublicKey, path string, managed bool) bool {
+	keyType := strings.ToLower(key.KeyType)
+
+	_, rawKey := publicKeySizes[keyType]
+	_, ecdhKey := ecdhCoordinateSizes[keyType]
+
+	if !rawKey && !ecdhKey {
+		v.add(findingError, findingUnsupportedKeyType, path+".keyType", errUnsupportedKeyType, key.KeyType)
+
+		return false
+	}
+
+	if key.Value == "" {
+		if !managed {
+			v.add(findingError, findingInvalidKeyValue, path+".value", "key value is missing")
+		}
+
+		return true
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(key.Value)
+	if err != nil {
+		v.add(findingError, findingInvalidKeyValue, path+".value", "key value is not base64url encoded: %s", err)
+
+		return true
+	}
+
+	if rawKey && len(value) != publicKeySizes[keyType] {
+		v.add(findingError, findingInvalidKeySize, path+".value", "%s key must be %d bytes long, got %d",
+			key.KeyType, publicKeySizes[keyType], len(value))
+
+		return true
+	}
+
+	if ecdhKey {
+		pubKey := &crypto.PublicKey{}
+
+		if err = json.Unmarshal(value, pubKey); err == nil && (len(pubKey.X) != ecdhCoordinateSizes[keyType] ||
+			(keyType != x25519ECDHKW && len(pubKey.Y) != ecdhCoordinateSizes[keyType])) {
+			v.add(findingError, findingInvalidKeySize, path+".value", "%s key coordinates must be %d bytes long",
+				key.KeyType, ecdhCoordinateSizes[keyType])
+
+			return true
+		}
+	}
+
+	k, err := getKey(keyType, value)
+	if err == nil {
+		_, err = getJWK(keyType, k)
+	}
+
+	if err != nil {
+		v.add(findingError, findingInvalidKeyValue, path+".value", "invalid %s key: %s", key.KeyType, err)
+	}
+
+	return true
+}
+
+func validatePurposes(v *orbDIDValidation, key *PublicKey, path string) {
+	if len(key.Purposes) == 0 {
+		v.add(findingWarning, findingKeyWithoutPurpose, path+".purposes",
+			"key %s has no purposes, it is left out of the document", key.ID)
+
+		return
+	}
+
+	_, agreementKey := ecdhCoordinateSizes[strings.ToLower(key.KeyType)]
+	purposes := make(map[string]bool)
+
+	for j, purpose := range key.Purposes {
+		purposePath := fmt.Sprintf("%s.purposes[%d]", path, j)
+
+		switch purpose {
+		case doc.KeyPurposeKeyAgreement:
+			if !agreementKey {
+				v.add(findingError, findingPurposeKeyTypeMismatch, purposePath,
+					errPurposeKeyTypeMismatch, key.KeyType, purpose)
+			}
+		case doc.KeyPurposeAuthentication, doc.KeyPurposeAssertionMethod, doc.KeyPurposeCapabilityDelegation,
+			doc.KeyPurposeCapabilityInvocation:
+			if agreementKey {
+				v.add(findingError, findingPurposeKeyTypeMismatch, purposePath,
+					errPurposeKeyTypeMismatch, key.KeyType, purpose)
+			}
+		default:
+			v.add(findingError, findingUnsupportedPurpose, purposePath, "unsupported key purpose: %s", purpose)
+
+			continue
+		}
+
+		if purposes[purpose] {
+			v.add(findingWarning, findingDuplicatePurpose, purposePath, "duplicate key purpose: %s", purpose)
+		}
+
+		purposes[purpose] = true
+	}
+}
+
+func validateOrbService(v *orbDIDValidation, request *CreateOrbDIDRequest) {
+	if request.ServiceID != "" {
+		validateSidetreeID(v, findingInvalidServiceID, "serviceID", request.ServiceID)
+	}
+
+	switch serviceType := request.DIDcommServiceType; {
+	case len(serviceType) > maxSidetreeServiceTypeLength:
+		v.add(findingError, findingInvalidServiceType, "didcommServiceType",
+			"service type exceeds maximum length: %d", maxSidetreeServiceTypeLength)
+	case serviceType != "" && serviceType != didCommServiceType && serviceType != didCommV2ServiceType:
+		v.add(findingWarning, findingInvalidServiceType, "didcommServiceType",
+			"service type %s is neither %s nor %s", serviceType, didCommServiceType, didCommV2ServiceType)
+	}
+
+	if request.ServiceEndpoint != "" {
+		endpoint, err := url.Parse(request.ServiceEndpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			v.add(findingError, findingInvalidServiceEndpoint, "serviceEndpoint",
+				"service endpoint must be an absolute URL: %s", request.ServiceEndpoint)
+		}
+	}
+
+	for i, routingKey := range request.RoutersKeyAgrIDS {
+		if _, err := did.ParseDIDURL(routingKey); err != nil {
+			v.add(findingError, findingInvalidRoutingKey, fmt.Sprintf("routerKAIDS[%d]", i),
+				"routing key must be a DID URL: %s", routingKey)
+		}
+	}
+}
+
+func validateSidetreeID(v *orbDIDValidation, code, path, id string) {
+	if len(id) > maxSidetreeIDLength {
+		v.add(findingError, code, path, "ID exceeds maximum length: %d", maxSidetreeIDLength)
+	}
+
+	if !sidetreeIDRegex.MatchString(id) {
+		v.add(findingError, code, path, "ID contains characters other than letters, digits, '-' and '_': %s", id)
+	}
+}
+
+// ephemeralKMSProvider provides in-memory storage to KMS whose keys are thrown away.
+type ephemeralKMSProvider struct {
+	storageProvider storage.Provider
+	secretLock      secretlock.Service
+}
+
+func (p *ephemeralKMSProvider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *ephemeralKMSProvider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/vdr/sidetree/doc"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+)
+
+func TestCommand_ValidateOrbDIDRequest(t *testing.T) {
+	newKey := func(t *testing.T) string {
+		t.Helper()
+
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		return base64.RawURLEncoding.EncodeToString(pubKey)
+	}
+
+	validate := func(t *testing.T, c *Command, request *CreateOrbDIDRequest) *ValidateOrbDIDResponse {
+		t.Helper()
+
+		req, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.ValidateOrbDIDRequest(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		resp := &ValidateOrbDIDResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+
+		return resp
+	}
+
+	findingCodes := func(resp *ValidateOrbDIDResponse) map[string]string {
+		codes := make(map[string]string)
+
+		for _, finding := range resp.Findings {
+			codes[finding.Path] = finding.Code
+		}
+
+		return codes
+	}
+
+	c, err := New("domain", "origin", "", 0, getMockProvider(), mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	t.Run("test valid request with managed keys", func(t *testing.T) {
+		resp := validate(t, c, &CreateOrbDIDRequest{
+			ManagedKeys: true,
+			PublicKeys: []PublicKey{
+				{KeyType: ed25519KeyType, Type: jsonWebKey2020, Purposes: []string{doc.KeyPurposeAuthentication}},
+			},
+			ServiceEndpoint: "https://agent.example.com",
+		})
+		require.True(t, resp.Valid)
+		require.Empty(t, resp.Findings)
+
+		didDoc, err := did.ParseDocument(resp.DIDDocument)
+		require.NoError(t, err)
+		require.Contains(t, didDoc.ID, "did:orb:")
+		require.Len(t, didDoc.Authentication, 1)
+
+		uri, err := didDoc.Service[0].ServiceEndpoint.URI()
+		require.NoError(t, err)
+		require.Equal(t, "https://agent.example.com", uri)
+	})
+
+	t.Run("test valid request with own keys", func(t *testing.T) {
+		resp := validate(t, c, &CreateOrbDIDRequest{
+			PublicKeys: []PublicKey{
+				{KeyType: ed25519KeyType, Value: newKey(t), Update: true},
+				{KeyType: ed25519KeyType, Value: newKey(t), Recovery: true},
+				{
+					ID: "key1", KeyType: ed25519KeyType, Type: ed25519VerificationKey2018, Value: newKey(t),
+					Purposes: []string{doc.KeyPurposeAssertionMethod, doc.KeyPurposeAssertionMethod},
+				},
+			},
+		})
+		require.True(t, resp.Valid)
+		require.NotEmpty(t, resp.DIDDocument)
+		require.Equal(t, map[string]string{"publicKeys[2].purposes[1]": findingDuplicatePurpose}, findingCodes(resp))
+	})
+
+	t.Run("test invalid request", func(t *testing.T) {
+		resp := validate(t, c, &CreateOrbDIDRequest{
+			PublicKeys: []PublicKey{
+				{
+					ID: "key1", KeyType: ed25519KeyType, Type: ed25519VerificationKey2018, Value: newKey(t),
+					Purposes: []string{"wrong"},
+				},
+				{
+					ID: "key1", KeyType: p256KeyType, Type: ed25519VerificationKey2018, Value: newKey(t),
+					Purposes: []string{doc.KeyPurposeAuthentication},
+				},
+				{
+					ID: "key 3", KeyType: ed25519KeyType, Type: jsonWebKey2020, Value: newKey(t),
+					Purposes: []string{doc.KeyPurposeKeyAgreement},
+				},
+				{ID: "key4", KeyType: "wrong", Type: jsonWebKey2020, Value: newKey(t)},
+				{ID: "key5", KeyType: ed25519KeyType, Type: "wrong", Value: "%%"},
+			},
+			ServiceEndpoint:  "agent.example.com",
+			RoutersKeyAgrIDS: []string{"key"},
+		})
+		require.False(t, resp.Valid)
+		require.Empty(t, resp.DIDDocument)
+
+		codes := findingCodes(resp)
+		require.Equal(t, findingUnsupportedPurpose, codes["publicKeys[0].purposes[0]"])
+		require.Equal(t, findingDuplicateKeyID, codes["publicKeys[1].id"])
+		require.Equal(t, findingKeyTypeMismatch, codes["publicKeys[1].type"])
+		require.Equal(t, findingInvalidKeySize, codes["publicKeys[1].value"])
+		require.Equal(t, findingInvalidKeyID, codes["publicKeys[2].id"])
+		require.Equal(t, findingPurposeKeyTypeMismatch, codes["publicKeys[2].purposes[0]"])
+		require.Equal(t, findingUnsupportedKeyType, codes["publicKeys[3].keyType"])
+		require.Equal(t, findingInvalidKeyValue, codes["publicKeys[4].value"])
+		require.Equal(t, findingInvalidServiceEndpoint, codes["serviceEndpoint"])
+		require.Equal(t, findingInvalidRoutingKey, codes["routerKAIDS[0]"])
+		require.Equal(t, findingMissingOperationKey, codes["publicKeys"])
+	})
+
+	t.Run("test managed update key", func(t *testing.T) {
+		resp := validate(t, c, &CreateOrbDIDRequest{
+			ManagedKeys: true,
+			PublicKeys:  []PublicKey{{KeyType: ed25519KeyType, Value: newKey(t), Update: true}},
+		})
+		require.False(t, resp.Valid)
+		require.Equal(t, map[string]string{"publicKeys[0]": findingManagedOperationKey}, findingCodes(resp))
+	})
+
+	t.Run("test dry run of create orb DID", func(t *testing.T) {
+		c.didBlocClient = &mockDIDClient{createDIDErr: errors.New("create is not expected")}
+
+		req, err := json.Marshal(&CreateOrbDIDRequest{
+			ManagedKeys: true,
+			DryRun:      true,
+			PublicKeys: []PublicKey{
+				{KeyType: ed25519KeyType, Type: jsonWebKey2020, Purposes: []string{doc.KeyPurposeAuthentication}},
+			},
+		})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateOrbDID(&b, bytes.NewBuffer(req))
+		require.NoError(t, cmdErr)
+
+		resp := &ValidateOrbDIDResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+		require.True(t, resp.Valid)
+		require.NotEmpty(t, resp.DIDDocument)
+	})
+
+	t.Run("test error from request", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := c.ValidateOrbDIDRequest(&b, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+}
@@ -11,22 +11,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/client/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/client/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/client/messaging"
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofbandv2"
 	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	didexchangeSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	oobv2 "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/trustbloc/edge-core/pkg/log"
 
@@ -34,6 +37,7 @@ import (
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/cmdutil"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/msghandler"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
 )
 
 var logger = log.New("agent-sdk-mediatorclient")
@@ -47,6 +51,30 @@ const (
 	CreateInvitation = "CreateInvitation"
 	// SendCreateConnectionRequest command name.
 	SendCreateConnectionRequest = "SendCreateConnectionRequest"
+	// GetConnections command name.
+	GetConnections = "GetConnections"
+	// Disconnect command name.
+	Disconnect = "Disconnect"
+	// CheckHealth command name.
+	CheckHealth = "CheckHealth"
+	// StatusRequest command name.
+	StatusRequest = "StatusRequest"
+	// BatchPickup command name.
+	BatchPickup = "BatchPickup"
+	// DeliveryRequest command name.
+	DeliveryRequest = "DeliveryRequest"
+	// MessagesReceived command name.
+	MessagesReceived = "MessagesReceived"
+	// LiveMode command name.
+	LiveMode = "LiveMode"
+	// GetKeylist command name.
+	GetKeylist = "GetKeylist"
+	// ReconcileKeylist command name.
+	ReconcileKeylist = "ReconcileKeylist"
+	// ParseInvitationURL command name.
+	ParseInvitationURL = "ParseInvitationURL"
+	// CancelPending command name.
+	CancelPending = "CancelPending"
 )
 
 const (
@@ -59,6 +87,26 @@ const (
 	CreateInvitationError
 	// SendCreateConnectionRequestError is typically a code for mediator send create connection request command errors.
 	SendCreateConnectionRequestError
+	// GetConnectionsError is typically a code for mediator get connections command errors.
+	GetConnectionsError
+	// DisconnectMediatorError is typically a code for mediator disconnect command errors.
+	DisconnectMediatorError
+	// CheckHealthError is typically a code for mediator health check command errors.
+	CheckHealthError
+	// StatusRequestError is typically a code for message pickup status request command errors.
+	StatusRequestError
+	// BatchPickupError is typically a code for batch pickup command errors.
+	BatchPickupError
+	// DeliveryRequestError is typically a code for message pickup delivery request command errors.
+	DeliveryRequestError
+	// MessagesReceivedError is typically a code for message pickup messages received command errors.
+	MessagesReceivedError
+	// LiveModeError is typically a code for message pickup live mode command errors.
+	LiveModeError
+	// GetKeylistError is typically a code for router keylist query command errors.
+	GetKeylistError
+	// ReconcileKeylistError is typically a code for router keylist reconciliation command errors.
+	ReconcileKeylistError
 
 	// errors.
 	errInvalidConnectionRequest = "invitation missing in connection request"
@@ -69,10 +117,14 @@ const (
 
 	// messaging & notifications.
 	stateCompleteTopic = "state-complete-topic"
+	replyTopic         = "reply-topic"
 
 	// timeout constants.
 	didExchangeTimeOut = 120 * time.Second
 	sendMsgTimeOut     = 120 * time.Second
+	trustPingTimeOut   = 10 * time.Second
+	pickupTimeOut      = 30 * time.Second
+	keylistTimeOut     = 30 * time.Second
 
 	// mediator connector queue buffer.
 	msgEventBufferSize = 10
@@ -94,6 +146,8 @@ type Provider interface {
 	KeyType() kms.KeyType
 	KeyAgreementType() kms.KeyType
 	MediaTypeProfiles() []string
+	Packager() transport.Packager
+	InboundMessageHandler() transport.InboundMessageHandler
 }
 
 // Command is controller command for mediator client.
@@ -102,13 +156,63 @@ type Command struct {
 	outOfBand      *outofband.Client
 	outOfBandV2    *outofbandv2.Client
 	mediator       *mediator.Client
+	mediatorSvc    mediatorservice.ProtocolService
 	messenger      *messaging.Client
 	didExchTimeout time.Duration
+	pickupTimeout  time.Duration
 	msgHandler     ariescmd.MessageHandler
+	connLookup     *connection.Lookup
+	vdRegistry     vdr.Registry
+	routeProvider  routeutil.Provider
+	routerSelector RouterSelector
+	routerHealth   *routerHealth
+	stateComplete  *stateCompleteNotifier
+	replies        *replyNotifier
+	messagePickup  *messagepickup.Client
+	packager       transport.Packager
+	inboundHandler transport.InboundMessageHandler
+	supervisor     *routerSupervisor
+	pending        *pendingRequests
+}
+
+// options contains optional configuration of mediator client command.
+type options struct {
+	routerSelector      RouterSelector
+	supervisionInterval time.Duration
+	initialBackoff      time.Duration
+	maxBackoff          time.Duration
+}
+
+// Option configures mediator client command.
+type Option func(opts *options)
+
+// WithRouterSelector sets strategy of selecting router connection for invitations and connection requests.
+// Random router is selected by default.
+func WithRouterSelector(selector RouterSelector) Option {
+	return func(opts *options) {
+		opts.routerSelector = selector
+	}
+}
+
+// WithRouterSupervision sets how often connected routers are checked and backoff of reconnecting unreachable
+// routers. Zero values keep the defaults and negative check interval disables supervision.
+func WithRouterSupervision(checkInterval, initialBackoff, maxBackoff time.Duration) Option {
+	return func(opts *options) {
+		opts.supervisionInterval = checkInterval
+		opts.initialBackoff = initialBackoff
+		opts.maxBackoff = maxBackoff
+	}
 }
 
 // New returns new mediator client controller command instance.
-func New(p Provider, msgHandler ariescmd.MessageHandler, notifier ariescmd.Notifier) (*Command, error) {
+func New(p Provider, msgHandler ariescmd.MessageHandler, notifier ariescmd.Notifier,
+	opts ...Option) (*Command, error) {
+	cmdOpts := &options{routerSelector: NewRandomRouterSelector()}
+
+	for _, opt := range opts {
+		opt(cmdOpts)
+	}
+
 	mediatorClient, err := mediator.New(p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create mediator client : %w", err)
@@ -134,15 +238,81 @@ func New(p Provider, msgHandler ariescmd.MessageHandler, notifier ariescmd.Notif
 		return nil, fmt.Errorf("failed to create out-of-band v2 client : %w", err)
 	}
 
-	return &Command{
+	connLookup, err := connection.NewLookup(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection lookup : %w", err)
+	}
+
+	s, err := p.Service(mediatorservice.Coordination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mediator service : %w", err)
+	}
+
+	mediatorSvc, ok := s.(mediatorservice.ProtocolService)
+	if !ok {
+		return nil, fmt.Errorf("cast service to mediator service failed")
+	}
+
+	store, err := p.StorageProvider().OpenStore(CommandName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mediator client store : %w", err)
+	}
+
+	// message pickup 1.0 is needed only for picking up messages from DIDComm V1 routers.
+	messagePickupClient, err := messagepickup.New(p)
+	if err != nil {
+		logger.Warnf("batch pickup from DIDComm V1 routers is not available : %s", err)
+	}
+
+	cmd := &Command{
 		didExchange:    didExchangeClient,
 		outOfBand:      outOfBandClient,
 		outOfBandV2:    outOfBandClientV2,
 		mediator:       mediatorClient,
+		mediatorSvc:    mediatorSvc,
 		messenger:      messengerClient,
 		didExchTimeout: didExchangeTimeOut,
+		pickupTimeout:  pickupTimeOut,
 		msgHandler:     msgHandler,
-	}, nil
+		connLookup:     connLookup,
+		vdRegistry:     p.VDRegistry(),
+		routeProvider:  p,
+		routerSelector: cmdOpts.routerSelector,
+		routerHealth:   &routerHealth{},
+		stateComplete:  newStateCompleteNotifier(connLookup, didExchangeTimeOut),
+		replies:        newReplyNotifier(),
+		messagePickup:  messagePickupClient,
+		packager:       p.Packager(),
+		inboundHandler: p.InboundMessageHandler(),
+		pending:        newPendingRequests(),
+	}
+
+	cmd.supervisor = newRouterSupervisor(cmd.checkRouterHealth, cmd.reconnectRouter, cmd.leaveRouter, store, notifier)
+
+	if cmdOpts.supervisionInterval != 0 {
+		cmd.supervisor.checkInterval = cmdOpts.supervisionInterval
+	}
+
+	if cmdOpts.initialBackoff != 0 {
+		cmd.supervisor.initialBackoff = cmdOpts.initialBackoff
+	}
+
+	if cmdOpts.maxBackoff != 0 {
+		cmd.supervisor.maxBackoff = cmdOpts.maxBackoff
+	}
+
+	err = cmd.supervisor.resume()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume router supervision : %w", err)
+	}
+
+	return cmd, nil
+}
+
+// Close stops supervision of the routers connected through connect command. Router registrations are kept, so
+// supervision is resumed by the next command created on the same store.
+func (c *Command) Close() {
+	c.supervisor.close()
 }
 
 // GetHandlers returns list of all commands supported by this controller command.
@@ -151,16 +321,25 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, Connect, c.Connect),
 		cmdutil.NewCommandHandler(CommandName, CreateInvitation, c.CreateInvitation),
 		cmdutil.NewCommandHandler(CommandName, SendCreateConnectionRequest, c.SendCreateConnectionRequest),
+		cmdutil.NewCommandHandler(CommandName, GetConnections, c.GetConnections),
+		cmdutil.NewCommandHandler(CommandName, Disconnect, c.Disconnect),
+		cmdutil.NewCommandHandler(CommandName, CheckHealth, c.CheckHealth),
+		cmdutil.NewCommandHandler(CommandName, StatusRequest, c.StatusRequest),
+		cmdutil.NewCommandHandler(CommandName, BatchPickup, c.BatchPickup),
+		cmdutil.NewCommandHandler(CommandName, DeliveryRequest, c.DeliveryRequest),
+		cmdutil.NewCommandHandler(CommandName, MessagesReceived, c.MessagesReceived),
+		cmdutil.NewCommandHandler(CommandName, LiveMode, c.LiveMode),
+		cmdutil.NewCommandHandler(CommandName, GetKeylist, c.GetKeylist),
+		cmdutil.NewCommandHandler(CommandName, ReconcileKeylist, c.ReconcileKeylist),
+		cmdutil.NewCommandHandler(CommandName, ParseInvitationURL, c.ParseInvitationURL),
+		cmdutil.NewCommandHandler(CommandName, CancelPending, c.CancelPending),
 	}
 }
 
-// Connect connects agent to given router endpoint.
+// Connect connects agent to given router endpoint. The router registration is kept and supervised, the agent
+// reconnects to the router whenever it becomes unreachable. Connect with request ID can be cancelled while pending.
 func (c *Command) Connect(rw io.Writer, req io.Reader) command.Error {
-	var (
-		request ConnectionRequest
-		connID  string
-		isV2    bool
-	)
+	var request ConnectionRequest
 
 	err := json.NewDecoder(req).Decode(&request)
 	if err != nil {
@@ -175,59 +354,102 @@ func (c *Command) Connect(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errInvalidConnectionRequest))
 	}
 
+	timeout, err := requestTimeout(request.Timeout, c.didExchTimeout)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Connect, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	ctx, cancel, err := c.pending.start(request.RequestID, timeout)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Connect, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	defer cancel()
+
+	connID, err := c.connect(ctx, &request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Connect, err.Error())
+
+		return command.NewExecuteError(ConnectMediatorError, err)
+	}
+
+	err = c.supervisor.add(connID, &request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Connect, err.Error())
+
+		return command.NewExecuteError(ConnectMediatorError, err)
+	}
+
+	command.WriteNillableResponse(rw, &ConnectionResponse{ConnectionID: connID}, logger)
+
+	logutil.LogDebug(logger, CommandName, Connect, successString)
+
+	return nil
+}
+
+// connect accepts invitation of the router and registers agent with the router, it returns ID of the router
+// connection. Agent isn't registered with the router once the context is done.
+func (c *Command) connect(ctx context.Context, request *ConnectionRequest) (string, error) {
+	var connID string
+
 	//nolint:nestif
-	if isV2, err = service.IsDIDCommV2(request.Invitation); isV2 && err == nil {
+	if isV2, err := service.IsDIDCommV2(request.Invitation); isV2 && err == nil {
 		inv := &oobv2.Invitation{}
 
 		err = request.Invitation.Decode(inv)
 		if err != nil {
-			return command.NewExecuteError(ConnectMediatorError, err)
+			return "", err
 		}
 
 		connID, err = c.outOfBandV2.AcceptInvitation(inv)
 		if err != nil {
-			logutil.LogError(logger, CommandName, Connect, err.Error())
-
-			return command.NewExecuteError(ConnectMediatorError, err)
+			return "", err
 		}
 	} else {
 		inv := &outofband.Invitation{}
+
 		err = request.Invitation.Decode(inv)
 		if err != nil {
-			return command.NewExecuteError(ConnectMediatorError, err)
+			return "", err
 		}
 
-		connID, err = c.createOOBInvitation(inv, request.MyLabel, request.StateCompleteMessageType)
+		connID, err = c.createOOBInvitation(ctx, inv, request.MyLabel, request.StateCompleteMessageType)
 		if err != nil {
-			return command.NewExecuteError(ConnectMediatorError, err)
+			return "", err
 		}
 	}
 
-	err = c.mediator.Register(connID)
-	if err != nil {
-		logutil.LogError(logger, CommandName, Connect, err.Error())
-
-		return command.NewExecuteError(ConnectMediatorError, err)
+	if ctx.Err() != nil {
+		return "", waitError(ctx, "timeout connecting to router")
 	}
 
-	command.WriteNillableResponse(rw, &ConnectionResponse{ConnectionID: connID}, logger)
-
-	logutil.LogDebug(logger, CommandName, Connect, successString)
+	err := c.mediator.Register(connID)
+	if err != nil {
+		return "", err
+	}
 
-	return nil
+	return connID, nil
 }
 
-func (c *Command) createOOBInvitation(inv *outofband.Invitation,
+func (c *Command) createOOBInvitation(ctx context.Context, inv *outofband.Invitation,
 	myLabel, stateCompleteMessageType string) (string, error) {
-	var notificationCh chan messaging.NotificationPayload
+	var stateComplete *stateCompleteWaiter
 
 	var statusCh chan service.StateMsg
 
 	if stateCompleteMessageType != "" { //nolint:nestif
-		notificationCh = make(chan messaging.NotificationPayload)
+		stateComplete = c.stateComplete.add(inv.ID)
+		defer c.stateComplete.remove(stateComplete)
+
+		// every connect has its own message service, so that concurrent connects don't collide.
+		topic := fmt.Sprintf("%s-%s", stateCompleteTopic, uuid.New().String())
 
-		err := c.msgHandler.Register(msghandler.NewMessageService(stateCompleteTopic, stateCompleteMessageType,
-			nil, messaging.NewNotifier(notificationCh, nil)))
+		err := c.msgHandler.Register(msghandler.NewMessageService(topic, stateCompleteMessageType,
+			nil, c.stateComplete))
 		if err != nil {
 			logutil.LogError(logger, CommandName, Connect, err.Error())
 
@@ -235,7 +457,7 @@ func (c *Command) createOOBInvitation(inv *outofband.Invitation,
 		}
 
 		defer func() {
-			e := c.msgHandler.Unregister(stateCompleteTopic)
+			e := c.msgHandler.Unregister(topic)
 			if e != nil {
 				logger.Warnf("Failed to unregister state completion notifier: %w", e)
 			}
@@ -265,7 +487,7 @@ func (c *Command) createOOBInvitation(inv *outofband.Invitation,
 		return "", err
 	}
 
-	err = c.waitForConnect(statusCh, notificationCh, connID)
+	err = c.waitForConnect(ctx, statusCh, stateComplete, connID)
 	if err != nil {
 		logutil.LogError(logger, CommandName, Connect, err.Error())
 
@@ -275,7 +497,9 @@ func (c *Command) createOOBInvitation(inv *outofband.Invitation,
 	return connID, nil
 }
 
-// CreateInvitation creates out-of-band invitation from one of the mediator connections.
+// CreateInvitation creates out-of-band invitation from one of the mediator connections, optionally with
+// attachments and encoded as invitation URL.
+//
 //nolint:funlen
 func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 	connections, err := c.mediator.GetConnections()
@@ -300,49 +524,94 @@ func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
+	err = validateInvitationRequest(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CreateInvitation, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
 	var (
-		invitation   *outofband.Invitation
-		invitationV2 *oobv2.Invitation
+		response   *CreateInvitationResponse
+		invitation interface{}
+		queryParam string
 	)
 
 	if request.From != "" {
-		invitationV2, err = c.outOfBandV2.CreateInvitation(
+		invitationV2, e := c.outOfBandV2.CreateInvitation(
 			outofbandv2.WithAccept("didcomm/aip2;env=rfc587", "didcomm/v2"),
 			outofbandv2.WithGoal(request.Goal, request.GoalCode),
 			outofbandv2.WithLabel(request.Label),
 			outofbandv2.WithFrom(request.From),
+			outofbandv2.WithAttachments(request.AttachmentsV2...),
 		)
+		if e != nil {
+			logutil.LogError(logger, CommandName, CreateInvitation, fmt.Sprintf("oob v2 error: %s", e.Error()))
 
-		if err != nil {
-			logutil.LogError(logger, CommandName, CreateInvitation, fmt.Sprintf("oob v2 error: %s", err.Error()))
-
-			return command.NewValidationError(InvalidRequestErrorCode, err)
+			return command.NewValidationError(InvalidRequestErrorCode, e)
 		}
 
-		command.WriteNillableResponse(rw, &CreateInvitationResponse{InvitationV2: invitationV2}, logger)
+		response = &CreateInvitationResponse{InvitationV2: invitationV2}
+		invitation, queryParam = invitationV2, oobV2URLParam
 	} else {
-		invitation, err = c.outOfBand.CreateInvitation(
+		routerConnID, e := c.selectRouter(connections, request.RouterConnectionID, request.Label)
+		if e != nil {
+			logutil.LogError(logger, CommandName, CreateInvitation, e.Error())
+
+			return command.NewValidationError(InvalidRequestErrorCode, e)
+		}
+
+		invitationV1, e := c.outOfBand.CreateInvitation(
 			request.Service,
 			outofband.WithHandshakeProtocols(request.Protocols...),
 			outofband.WithGoal(request.Goal, request.GoalCode),
 			outofband.WithLabel(request.Label),
 			outofband.WithAccept("didcomm/aip2;env=rfc19", "didcomm/aip1"),
-			outofband.WithRouterConnections(connections[rand.Intn(len(connections))])) //nolint: gosec
-		if err != nil {
-			logutil.LogError(logger, CommandName, CreateInvitation, fmt.Sprintf("oob v1 error: %s", err.Error()))
+			outofband.WithRouterConnections(routerConnID),
+			outofband.WithAttachments(request.Attachments...))
+		if e != nil {
+			logutil.LogError(logger, CommandName, CreateInvitation, fmt.Sprintf("oob v1 error: %s", e.Error()))
 
-			return command.NewValidationError(InvalidRequestErrorCode, err)
+			return command.NewValidationError(InvalidRequestErrorCode, e)
 		}
 
-		command.WriteNillableResponse(rw, &CreateInvitationResponse{Invitation: invitation}, logger)
+		// keys of the default service of invitation are added to the router by out-of-band client.
+		if len(request.Service) == 0 {
+			e = recordInvitationKeys(c.routeProvider, routerConnID, invitationV1)
+			if e != nil {
+				logutil.LogError(logger, CommandName, CreateInvitation, e.Error())
+
+				return command.NewExecuteError(CreateInvitationError, e)
+			}
+		}
+
+		response = &CreateInvitationResponse{
+			Invitation:         invitationV1,
+			RouterConnectionID: routerConnID,
+		}
+		invitation, queryParam = invitationV1, oobURLParam
 	}
 
+	if request.BaseURL != "" {
+		response.InvitationURL, err = encodeInvitationURL(request.BaseURL, queryParam, invitation)
+		if err != nil {
+			logutil.LogError(logger, CommandName, CreateInvitation, err.Error())
+
+			return command.NewExecuteError(CreateInvitationError, err)
+		}
+	}
+
+	command.WriteNillableResponse(rw, response, logger)
+
 	logutil.LogDebug(logger, CommandName, CreateInvitation, fmt.Sprintf("%s for %s", successString, request.Label))
 
 	return nil
 }
 
-// SendCreateConnectionRequest sends create connection request to mediator.
+// SendCreateConnectionRequest sends create connection request to mediator, request with request ID can be
+// cancelled while waiting for the response.
+//
+//nolint:funlen
 func (c *Command) SendCreateConnectionRequest(rw io.Writer, req io.Reader) command.Error {
 	connections, err := c.mediator.GetConnections()
 	if err != nil {
@@ -366,6 +635,13 @@ func (c *Command) SendCreateConnectionRequest(rw io.Writer, req io.Reader) comma
 		return command.NewValidationError(SendCreateConnectionRequestError, err)
 	}
 
+	routerConnID, err := c.selectRouter(connections, request.RouterConnectionID, "")
+	if err != nil {
+		logutil.LogError(logger, CommandName, SendCreateConnectionRequest, err.Error())
+
+		return command.NewValidationError(SendCreateConnectionRequestError, err)
+	}
+
 	msgBytes, err := json.Marshal(map[string]interface{}{
 		"@id":   uuid.New().String(),
 		"@type": createConnRequestMsgType,
@@ -379,73 +655,88 @@ func (c *Command) SendCreateConnectionRequest(rw io.Writer, req io.Reader) comma
 		return command.NewValidationError(SendCreateConnectionRequestError, err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), sendMsgTimeOut)
+	timeout, err := requestTimeout(request.Timeout, sendMsgTimeOut)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SendCreateConnectionRequest, err.Error())
+
+		return command.NewValidationError(SendCreateConnectionRequestError, err)
+	}
+
+	ctx, cancel, err := c.pending.start(request.RequestID, timeout)
+	if err != nil {
+		logutil.LogError(logger, CommandName, SendCreateConnectionRequest, err.Error())
+
+		return command.NewValidationError(SendCreateConnectionRequestError, err)
+	}
+
 	defer cancel()
 
 	res, err := c.messenger.Send(json.RawMessage(msgBytes),
-		messaging.SendByConnectionID(connections[rand.Intn(len(connections))]), //nolint: gosec
+		messaging.SendByConnectionID(routerConnID),
 		messaging.WaitForResponse(ctx, createConnResponseMsgType))
 	if err != nil {
+		if ctx.Err() != nil {
+			err = waitError(ctx, err.Error())
+		}
+
 		logutil.LogError(logger, CommandName, SendCreateConnectionRequest, err.Error())
 
 		return command.NewExecuteError(SendCreateConnectionRequestError, err)
 	}
 
-	command.WriteNillableResponse(rw, &CreateConnectionResponse{res}, logger)
+	command.WriteNillableResponse(rw, &CreateConnectionResponse{Payload: res, RouterConnectionID: routerConnID}, logger)
 
 	logutil.LogDebug(logger, CommandName, SendCreateConnectionRequest, successString)
 
 	return nil
 }
 
-//nolint: gocyclo
-func (c *Command) waitForConnect(didStateMsgs chan service.StateMsg,
-	notificationCh chan messaging.NotificationPayload, connID string) error {
-	if notificationCh != nil {
+// waitForConnect waits for the connection to be completed, message services and event registrations of the
+// connect are released as soon as the context is done.
+func (c *Command) waitForConnect(ctx context.Context, didStateMsgs chan service.StateMsg,
+	stateComplete *stateCompleteWaiter, connID string) error {
+	if stateComplete != nil {
+		c.stateComplete.setConnectionID(stateComplete, connID)
+
 		select {
-		case <-notificationCh:
-			// TODO correlate connection ID
+		case <-stateComplete.done:
 			return nil
-		case <-time.After(c.didExchTimeout):
-			return fmt.Errorf("timeout waiting for state completed message from mediator")
+		case <-ctx.Done():
+			return waitError(ctx, "timeout waiting for state completed message from mediator")
 		}
 	}
 
-	done := make(chan struct{})
-
-	go func() {
-		for msg := range didStateMsgs {
-			if msg.Type != service.PostState || msg.StateID != didexchangeSvc.StateIDCompleted {
-				continue
+	for {
+		select {
+		case msg := <-didStateMsgs:
+			if connectionCompleted(msg, connID) {
+				return nil
 			}
+		case <-ctx.Done():
+			return waitError(ctx, "time out waiting for did exchange state 'completed'")
+		}
+	}
+}
 
-			var event didexchange.Event
-
-			switch p := msg.Properties.(type) {
-			case didexchange.Event:
-				event = p
-			default:
-				logger.Warnf("failed to cast didexchange event properties")
+// connectionCompleted returns true if did exchange state message tells that given connection is completed.
+func connectionCompleted(msg service.StateMsg, connID string) bool {
+	if msg.Type != service.PostState || msg.StateID != didexchangeSvc.StateIDCompleted {
+		return false
+	}
 
-				continue
-			}
+	event, ok := msg.Properties.(didexchange.Event)
+	if !ok {
+		logger.Warnf("failed to cast didexchange event properties")
 
-			if event.ConnectionID() == connID {
-				logger.Debugf(
-					"Received connection complete event for invitationID=%s connectionID=%s",
-					event.InvitationID(), event.ConnectionID())
+		return false
+	}
 
-				close(done)
+	if event.ConnectionID() != connID {
+		return false
+	}
 
-				break
-			}
-		}
-	}()
+	logger.Debugf("Received connection complete event for invitationID=%s connectionID=%s",
+		event.InvitationID(), event.ConnectionID())
 
-	select {
-	case <-done:
-		return nil
-	case <-time.After(c.didExchTimeout):
-		return fmt.Errorf("time out waiting for did exchange state 'completed'")
-	}
+	return true
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/trustbloc/agent-sdk/pkg/controller/command"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
 	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
 )
 
 const sampleErr = "sample-error"
@@ -88,7 +89,7 @@ func TestNew(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, c)
 		require.NotEmpty(t, c.GetHandlers())
-		require.Len(t, c.GetHandlers(), 3)
+		require.Len(t, c.GetHandlers(), 15)
 	})
 
 	t.Run("test failure while creating mediator client", func(t *testing.T) {
@@ -226,6 +227,8 @@ func TestCommand_Connect(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
+		defer c.Close()
+
 		var b bytes.Buffer
 		cmdErr := c.Connect(&b, bytes.NewBufferString(sampleInvitation))
 		require.NoError(t, cmdErr)
@@ -274,7 +277,10 @@ func TestCommand_Connect(t *testing.T) {
 			for {
 				if len(mockMsgRegistrar.Services()) > 0 {
 					_, e := mockMsgRegistrar.Services()[0].HandleInbound(
-						&service.DIDCommMsgMap{},
+						service.DIDCommMsgMap{
+							"@type":   "https://trustbloc.dev/didexchange/1.0/state-complete",
+							"~thread": map[string]interface{}{"pthid": "3ae3d2cb-83bf-429f-93ea-0802f92ecf42"},
+						},
 						&sdkmockprotocol.MockDIDCommContext{},
 					)
 					require.NoError(t, e)
@@ -288,6 +294,8 @@ func TestCommand_Connect(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, c)
 
+		defer c.Close()
+
 		// reduce timeout
 		c.didExchTimeout = 10 * time.Millisecond
 
@@ -584,6 +592,18 @@ func TestCommand_CreateInvitation(t *testing.T) {
 		var b bytes.Buffer
 		cmdErr := c.CreateInvitation(&b, bytes.NewBufferString("{}"))
 		require.NoError(t, cmdErr)
+
+		var resp CreateInvitationResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Len(t, resp.Invitation.Services, 1)
+
+		// keys of invitation added to the router are recorded for keylist reconciliation.
+		svc, ok := resp.Invitation.Services[0].(map[string]interface{})
+		require.True(t, ok)
+
+		keys, err := routeutil.RegisteredKeys(c.routeProvider, "sample-connection")
+		require.NoError(t, err)
+		require.Equal(t, []string{svc["recipientKeys"].([]interface{})[0].(string)}, keys)
 	})
 
 	t.Run("test failure while saving invitation", func(t *testing.T) {
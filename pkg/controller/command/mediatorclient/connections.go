@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	// errors.
+	errMissingConnectionID = "connection ID missing in request"
+
+	// trust ping message types.
+	trustPingMsgType           = "https://didcomm.org/trust_ping/1.0/ping"
+	trustPingResponseMsgType   = "https://didcomm.org/trust_ping/1.0/ping_response"
+	trustPingV2MsgType         = "https://didcomm.org/trust-ping/2.0/ping"
+	trustPingV2ResponseMsgType = "https://didcomm.org/trust-ping/2.0/ping-response"
+)
+
+// GetConnections returns router connections of the agent along with their endpoints and routing keys.
+func (c *Command) GetConnections(rw io.Writer, _ io.Reader) command.Error {
+	connections, err := c.mediator.GetConnections()
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetConnections, err.Error())
+
+		return command.NewExecuteError(GetConnectionsError, err)
+	}
+
+	routers := make([]*RouterConnection, len(connections))
+
+	for i, connID := range connections {
+		routers[i], err = c.routerConnection(connID)
+		if err != nil {
+			logutil.LogError(logger, CommandName, GetConnections, err.Error())
+
+			return command.NewExecuteError(GetConnectionsError, err)
+		}
+	}
+
+	command.WriteNillableResponse(rw, &GetConnectionsResponse{Connections: routers}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetConnections, successString)
+
+	return nil
+}
+
+// Disconnect removes given recipient keys, or all the keys registered by the agent if none are given, from the router,
+// unregisters the agent from the router and stops supervising the router.
+func (c *Command) Disconnect(rw io.Writer, req io.Reader) command.Error {
+	var request DisconnectRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.ConnectionID == "" {
+		logutil.LogError(logger, CommandName, Disconnect, errMissingConnectionID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errMissingConnectionID))
+	}
+
+	recKeys := request.RecipientKeys
+	if len(recKeys) == 0 {
+		recKeys, err = routeutil.RegisteredKeys(c.routeProvider, request.ConnectionID)
+		if err != nil {
+			logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+			return command.NewExecuteError(DisconnectMediatorError, err)
+		}
+	}
+
+	err = routeutil.RemoveKeyFromRouter(c.routeProvider, request.ConnectionID, recKeys...)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+		return command.NewExecuteError(DisconnectMediatorError, err)
+	}
+
+	err = c.mediator.Unregister(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+		return command.NewExecuteError(DisconnectMediatorError, err)
+	}
+
+	err = routeutil.ForgetRouter(c.routeProvider, request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+		return command.NewExecuteError(DisconnectMediatorError, err)
+	}
+
+	err = c.supervisor.remove(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, Disconnect, err.Error())
+
+		return command.NewExecuteError(DisconnectMediatorError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, Disconnect, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// CheckHealth sends trust ping to the given router, or to all routers if connection ID is not provided,
+// and reports whether the router responded along with round-trip latency.
+func (c *Command) CheckHealth(rw io.Writer, req io.Reader) command.Error {
+	var request CheckHealthRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CheckHealth, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	connections := []string{request.ConnectionID}
+
+	if request.ConnectionID == "" {
+		connections, err = c.mediator.GetConnections()
+		if err != nil {
+			logutil.LogError(logger, CommandName, CheckHealth, err.Error())
+
+			return command.NewExecuteError(CheckHealthError, err)
+		}
+	}
+
+	results := make([]*HealthCheckResult, len(connections))
+
+	var wg sync.WaitGroup
+
+	for i, connID := range connections {
+		wg.Add(1)
+
+		go func(i int, connID string) {
+			defer wg.Done()
+
+			results[i] = c.checkRouterHealth(connID)
+		}(i, connID)
+	}
+
+	wg.Wait()
+
+	command.WriteNillableResponse(rw, &CheckHealthResponse{Results: results}, logger)
+
+	logutil.LogDebug(logger, CommandName, CheckHealth, successString)
+
+	return nil
+}
+
+func (c *Command) routerConnection(connID string) (*RouterConnection, error) {
+	config, err := c.mediator.GetConfig(connID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router config for connection %s : %w", connID, err)
+	}
+
+	conn, err := c.connLookup.GetConnectionRecord(connID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router connection %s : %w", connID, err)
+	}
+
+	return &RouterConnection{
+		ConnectionID:   connID,
+		Label:          conn.TheirLabel,
+		Endpoint:       config.Endpoint(),
+		RoutingKeys:    config.Keys(),
+		DIDCommVersion: string(conn.DIDCommVersion),
+	}, nil
+}
+
+// checkRouterHealth checks health of the router and records the result for router selection.
+func (c *Command) checkRouterHealth(connID string) *HealthCheckResult {
+	result := c.checkHealth(connID)
+	c.routerHealth.set(result)
+
+	return result
+}
+
+func (c *Command) checkHealth(connID string) *HealthCheckResult {
+	result := &HealthCheckResult{ConnectionID: connID}
+
+	conn, err := c.connLookup.GetConnectionRecord(connID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to get router connection : %s", err)
+
+		return result
+	}
+
+	var (
+		ping         service.DIDCommMsgMap
+		responseType string
+	)
+
+	if conn.DIDCommVersion == service.V2 {
+		ping = service.DIDCommMsgMap{
+			"id":   uuid.New().String(),
+			"type": trustPingV2MsgType,
+			"body": map[string]interface{}{"response_requested": true},
+		}
+		responseType = trustPingV2ResponseMsgType
+	} else {
+		ping = service.DIDCommMsgMap{
+			"@id":                uuid.New().String(),
+			"@type":              trustPingMsgType,
+			"response_requested": true,
+		}
+		responseType = trustPingResponseMsgType
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), trustPingTimeOut)
+	defer cancel()
+
+	start := time.Now()
+
+	// routers are checked concurrently, so replies are correlated with pings by thread.
+	_, err = c.sendAndWaitForReply(ctx, connID, ping, responseType)
+	if err != nil {
+		logger.Warnf("router %s failed health check : %s", connID, err)
+
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Healthy = true
+	result.LatencyMS = time.Since(start).Milliseconds()
+
+	return result
+}
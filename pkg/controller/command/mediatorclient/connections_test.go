@@ -0,0 +1,387 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+func TestCommand_GetConnections(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		prov := newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+				Connections:    []string{"sample-connection"},
+				RouterEndpoint: "http://router.example.com",
+				RoutingKeys:    []string{"key-1"},
+			},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		})
+		prov.StoreProvider = newRouterConnectionStore(t, &connection.Record{
+			ConnectionID: "sample-connection", TheirLabel: "router", DIDCommVersion: service.V1,
+		})
+
+		c, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.GetConnections(&b, nil))
+
+		var response GetConnectionsResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Len(t, response.Connections, 1)
+		require.Equal(t, &RouterConnection{
+			ConnectionID:   "sample-connection",
+			Label:          "router",
+			Endpoint:       "http://router.example.com",
+			RoutingKeys:    []string{"key-1"},
+			DIDCommVersion: string(service.V1),
+		}, response.Connections[0])
+	})
+
+	t.Run("test failure while getting connections", func(t *testing.T) {
+		c, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf(sampleErr)},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.GetConnections(&b, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, GetConnectionsError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+
+	t.Run("test failure while getting router config", func(t *testing.T) {
+		c, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+				Connections: []string{"sample-connection"},
+				ConfigErr:   fmt.Errorf(sampleErr),
+			},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.GetConnections(&b, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, GetConnectionsError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+
+	t.Run("test failure while getting connection record", func(t *testing.T) {
+		c, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+				Connections:    []string{"sample-connection"},
+				RouterEndpoint: "http://router.example.com",
+				RoutingKeys:    []string{"key-1"},
+			},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.GetConnections(&b, nil)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "failed to get router connection")
+	})
+}
+
+func TestCommand_Disconnect(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		prov := newMockProvider(nil)
+		prov.StoreProvider = newRouterConnectionStore(t, &connection.Record{
+			ConnectionID: "sample-connection", MyDID: "myDID", TheirDID: "theirDID",
+		})
+
+		messenger := sdkmockprotocol.NewMockMessenger()
+		prov.CustomMessenger = messenger
+
+		c, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		request, err := json.Marshal(&DisconnectRequest{ConnectionID: "sample-connection", RecipientKeys: []string{"key"}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.Disconnect(&b, bytes.NewBuffer(request)))
+		require.NotEmpty(t, messenger.GetLastID())
+	})
+
+	t.Run("test success without recipient keys", func(t *testing.T) {
+		c, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.Disconnect(&b, bytes.NewBufferString(`{"connectionID":"sample-connection"}`)))
+	})
+
+	t.Run("test success removing registered keys", func(t *testing.T) {
+		prov := newMockProvider(nil)
+		prov.StoreProvider = newRouterConnectionStore(t, &connection.Record{
+			ConnectionID: "sample-connection", MyDID: "myDID", TheirDID: "theirDID",
+		})
+
+		messenger := sdkmockprotocol.NewMockMessenger()
+		prov.CustomMessenger = messenger
+
+		c, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		require.NoError(t, routeutil.AddKeyToRouter(c.routeProvider, &mockroute.MockMediatorSvc{}, "sample-connection",
+			"key1", "key2"))
+
+		var b bytes.Buffer
+		require.NoError(t, c.Disconnect(&b, bytes.NewBufferString(`{"connectionID":"sample-connection"}`)))
+		require.NotEmpty(t, messenger.GetLastID())
+
+		keys, err := routeutil.RegisteredKeys(c.routeProvider, "sample-connection")
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		for _, request := range []string{"---", "{}"} {
+			var b bytes.Buffer
+			cmdErr := c.Disconnect(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+			require.Equal(t, command.ValidationError, cmdErr.Type())
+		}
+	})
+
+	t.Run("test failure while removing keys", func(t *testing.T) {
+		c, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.Disconnect(&b, bytes.NewBufferString(`{"connectionID":"sample-connection","recipientKeys":["key"]}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DisconnectMediatorError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to get router connection")
+	})
+
+	t.Run("test failure while unregistering", func(t *testing.T) {
+		c, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{UnregisterErr: fmt.Errorf(sampleErr)},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.Disconnect(&b, bytes.NewBufferString(`{"connectionID":"sample-connection"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DisconnectMediatorError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+}
+
+func TestCommand_CheckHealth(t *testing.T) {
+	const pingResponse = `{"@id": "%s", "@type": "%s", "~thread" : {"thid": "%s"}}`
+
+	const pingResponseV2 = `{"id": "%s", "type": "%s", "thid": "%s", "body": {}}`
+
+	for _, test := range []struct {
+		name         string
+		version      service.Version
+		response     string
+		responseType string
+	}{
+		{name: "test DIDComm V1 router", version: service.V1, response: pingResponse, responseType: trustPingResponseMsgType},
+		{
+			name: "test DIDComm V2 router", version: service.V2,
+			response: pingResponseV2, responseType: trustPingV2ResponseMsgType,
+		},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			prov := newMockProvider(map[string]interface{}{
+				mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{Connections: []string{"sample-connection"}},
+				didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+				outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+				outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+			})
+			prov.StoreProvider = newRouterConnectionStore(t, &connection.Record{
+				ConnectionID: "sample-connection", MyDID: "myDID", TheirDID: "theirDID", DIDCommVersion: test.version,
+			})
+
+			registrar := mockmsghandler.NewMockMsgServiceProvider()
+			messenger := sdkmockprotocol.NewMockMessenger()
+			prov.CustomMessenger = messenger
+
+			go func() {
+				for {
+					if len(registrar.Services()) > 0 && messenger.GetLastID() != "" { //nolint: gocritic
+						replyMsg, e := service.ParseDIDCommMsgMap([]byte(
+							fmt.Sprintf(test.response, "response-id", test.responseType, messenger.GetLastID())))
+						require.NoError(t, e)
+
+						_, e = registrar.Services()[0].HandleInbound(replyMsg, &sdkmockprotocol.MockDIDCommContext{
+							MyDIDValue:    "myDID",
+							TheirDIDValue: "theirDID",
+						})
+						require.NoError(t, e)
+
+						break
+					}
+				}
+			}()
+
+			c, err := New(prov, registrar, mocks.NewMockNotifier())
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+			require.NoError(t, c.CheckHealth(&b, bytes.NewBufferString(`{}`)))
+
+			var response CheckHealthResponse
+			require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+			require.Len(t, response.Results, 1)
+			require.Equal(t, "sample-connection", response.Results[0].ConnectionID)
+			require.True(t, response.Results[0].Healthy, response.Results[0].Error)
+			require.Empty(t, response.Results[0].Error)
+		})
+	}
+
+	t.Run("test routers are checked concurrently", func(t *testing.T) {
+		connections := []string{"connection-1", "connection-2", "connection-3"}
+
+		prov := newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{Connections: connections},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		})
+
+		records := make([]*connection.Record, len(connections))
+		for i, connID := range connections {
+			records[i] = &connection.Record{ConnectionID: connID, DIDCommVersion: service.V2}
+		}
+
+		prov.StoreProvider = newRouterConnectionStore(t, records...)
+
+		// every router replies once all the pings are sent, so that all of them wait at the same time.
+		var pings sync.WaitGroup
+
+		pings.Add(len(connections))
+
+		router := &pickupRouter{
+			MockMessenger: sdkmockprotocol.NewMockMessenger(),
+			registrar:     mockmsghandler.NewMockMsgServiceProvider(),
+			reply: func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+				pings.Done()
+				pings.Wait()
+
+				return newPickupReply(msg, trustPingV2ResponseMsgType, map[string]interface{}{})
+			},
+		}
+		prov.CustomMessenger = router
+
+		c, err := New(prov, router.registrar, mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.CheckHealth(&b, bytes.NewBufferString(`{}`)))
+
+		var response CheckHealthResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Len(t, response.Results, len(connections))
+
+		for _, result := range response.Results {
+			require.True(t, result.Healthy, result.Error)
+		}
+	})
+
+	t.Run("test unknown router connection", func(t *testing.T) {
+		c, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.CheckHealth(&b, bytes.NewBufferString(`{"connectionID":"unknown"}`)))
+
+		var response CheckHealthResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Len(t, response.Results, 1)
+		require.False(t, response.Results[0].Healthy)
+		require.Contains(t, response.Results[0].Error, "failed to get router connection")
+	})
+
+	t.Run("test failure while getting connections", func(t *testing.T) {
+		c, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf(sampleErr)},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.CheckHealth(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, CheckHealthError, cmdErr.Code())
+	})
+
+	t.Run("test invalid request", func(t *testing.T) {
+		c, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := c.CheckHealth(&b, bytes.NewBufferString("---"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+}
+
+func newRouterConnectionStore(t *testing.T, records ...*connection.Record) *mockstorage.MockStoreProvider {
+	t.Helper()
+
+	store := &mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)}
+
+	for _, record := range records {
+		if record.State == "" {
+			record.State = "completed"
+		}
+
+		recordBytes, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, store.Put("conn_"+record.ConnectionID, recordBytes))
+	}
+
+	return mockstorage.NewCustomMockStoreProvider(store)
+}
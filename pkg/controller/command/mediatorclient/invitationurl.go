@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	oobv2 "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// invitation URL query parameters of out-of-band V1 and V2 invitations.
+	oobURLParam   = "oob"
+	oobV2URLParam = "_oob"
+
+	// out-of-band V1 attachments, aries decodes `request~attach` whereas RFC 0434 names them `requests~attach`.
+	requestAttachField  = "request~attach"
+	requestsAttachField = "requests~attach"
+)
+
+// ParseInvitationURL decodes out-of-band (V1 or V2) invitation from invitation URL,
+// decoded invitation can be used as invitation of connection request.
+func (c *Command) ParseInvitationURL(rw io.Writer, req io.Reader) command.Error {
+	var request ParseInvitationURLRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ParseInvitationURL, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	invitation, err := decodeInvitationURL(request.URL)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ParseInvitationURL, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ParseInvitationURLResponse{Invitation: invitation}, logger)
+
+	logutil.LogDebug(logger, CommandName, ParseInvitationURL, successString)
+
+	return nil
+}
+
+func validateInvitationRequest(request *CreateInvitationRequest) error {
+	if request.From != "" && len(request.Attachments) > 0 {
+		return fmt.Errorf("attachments can't be used in DIDComm V2 invitation, use attachments-v2 instead")
+	}
+
+	if request.From == "" && len(request.AttachmentsV2) > 0 {
+		return fmt.Errorf("attachments-v2 can't be used in DIDComm V1 invitation, use attachments instead")
+	}
+
+	if request.BaseURL != "" {
+		if _, err := parseBaseURL(request.BaseURL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseBaseURL(baseURL string) (*url.URL, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL : %w", err)
+	}
+
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("invalid base URL : scheme missing in %s", baseURL)
+	}
+
+	return u, nil
+}
+
+// encodeInvitationURL encodes invitation as base64url query parameter of given base URL.
+func encodeInvitationURL(baseURL, param string, invitation interface{}) (string, error) {
+	u, err := parseBaseURL(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	invitationBytes, err := json.Marshal(invitation)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal invitation : %w", err)
+	}
+
+	query := u.Query()
+	query.Set(param, base64.RawURLEncoding.EncodeToString(invitationBytes))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// decodeInvitationURL decodes out-of-band invitation from `oob` or `_oob` query parameter of invitation URL.
+func decodeInvitationURL(invitationURL string) (*service.DIDCommMsgMap, error) {
+	if invitationURL == "" {
+		return nil, fmt.Errorf("invitation URL missing in request")
+	}
+
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation URL : %w", err)
+	}
+
+	query := u.Query()
+
+	encoded := query.Get(oobV2URLParam)
+	if encoded == "" {
+		encoded = query.Get(oobURLParam)
+	}
+
+	if encoded == "" {
+		return nil, fmt.Errorf("invitation missing in URL, expected '%s' or '%s' query parameter",
+			oobURLParam, oobV2URLParam)
+	}
+
+	invitationBytes, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode invitation from URL : %w", err)
+	}
+
+	invitation, err := service.ParseDIDCommMsgMap(invitationBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invitation from URL : %w", err)
+	}
+
+	switch invitation.Type() {
+	case outofbandsvc.InvitationMsgType:
+		if attachments, ok := invitation[requestsAttachField]; ok && invitation[requestAttachField] == nil {
+			invitation[requestAttachField] = attachments
+			delete(invitation, requestsAttachField)
+		}
+	case oobv2.InvitationMsgType:
+	default:
+		return nil, fmt.Errorf("unsupported invitation type '%s' in URL", invitation.Type())
+	}
+
+	return &invitation, nil
+}
+
+// decodeBase64 decodes both base64url and standard base64 with or without padding, since invitation URLs are
+// produced by different agents. Unescaped '+' of standard base64 turns into space in URL query.
+func decodeBase64(encoded string) ([]byte, error) {
+	normalized := strings.NewReplacer(" ", "-", "+", "-", "/", "_").Replace(strings.TrimRight(encoded, "="))
+
+	return base64.RawURLEncoding.DecodeString(normalized)
+}
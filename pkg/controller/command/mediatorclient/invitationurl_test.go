@@ -0,0 +1,294 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+)
+
+const (
+	presentationRequestAttachment = `{
+		"@id": "request-0",
+		"mime-type": "application/json",
+		"data": {"json": {"@type": "https://didcomm.org/present-proof/2.0/request-presentation"}}
+	}`
+	presentationRequestAttachmentV2 = `{
+		"id": "request-0",
+		"media_type": "application/json",
+		"data": {"json": {"type": "https://didcomm.org/present-proof/3.0/request-presentation"}}
+	}`
+)
+
+func TestCommand_CreateInvitationURL(t *testing.T) {
+	t.Run("invitation with attachments encoded as URL", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		var b bytes.Buffer
+		cmdErr := c.CreateInvitation(&b, bytes.NewBufferString(fmt.Sprintf(
+			`{"label":"alice","attachments":[%s],"baseURL":"https://example.com/invite?lang=en"}`,
+			presentationRequestAttachment)))
+		require.NoError(t, cmdErr)
+
+		var response CreateInvitationResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.NotNil(t, response.Invitation)
+		require.Len(t, response.Invitation.Requests, 1)
+		require.Equal(t, "request-0", response.Invitation.Requests[0].ID)
+
+		u, err := url.Parse(response.InvitationURL)
+		require.NoError(t, err)
+		require.Equal(t, "https", u.Scheme)
+		require.Equal(t, "example.com", u.Host)
+		require.Equal(t, "/invite", u.Path)
+		require.Equal(t, "en", u.Query().Get("lang"))
+		require.NotEmpty(t, u.Query().Get("oob"))
+		require.Empty(t, u.Query().Get("_oob"))
+
+		parsed := parseInvitationURL(t, c, response.InvitationURL)
+		require.Equal(t, outofbandsvc.InvitationMsgType, parsed.Invitation.Type())
+		require.Equal(t, response.Invitation.ID, parsed.Invitation.ID())
+
+		requests, ok := (*parsed.Invitation)["request~attach"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("DIDComm V2 invitation with attachments encoded as URL", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		var b bytes.Buffer
+		cmdErr := c.CreateInvitation(&b, bytes.NewBufferString(fmt.Sprintf(
+			`{"label":"alice","from":"did:example:alice","attachments-v2":[%s],"baseURL":"didcomm://invite"}`,
+			presentationRequestAttachmentV2)))
+		require.NoError(t, cmdErr)
+
+		var response CreateInvitationResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.NotNil(t, response.InvitationV2)
+		require.Len(t, response.InvitationV2.Requests, 1)
+		require.Equal(t, "request-0", response.InvitationV2.Requests[0].ID)
+		require.True(t, strings.HasPrefix(response.InvitationURL, "didcomm://invite?_oob="))
+
+		parsed := parseInvitationURL(t, c, response.InvitationURL)
+		require.Equal(t, outofbandv2svc.InvitationMsgType, parsed.Invitation.Type())
+		require.Equal(t, response.InvitationV2.ID, parsed.Invitation.ID())
+
+		attachments, ok := (*parsed.Invitation)["attachments"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, attachments, 1)
+	})
+
+	t.Run("invitation without base URL isn't encoded", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		var b bytes.Buffer
+		cmdErr := c.CreateInvitation(&b, bytes.NewBufferString(`{"label":"alice"}`))
+		require.NoError(t, cmdErr)
+
+		var response CreateInvitationResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.NotNil(t, response.Invitation)
+		require.Empty(t, response.InvitationURL)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		tests := []struct {
+			name    string
+			request string
+			err     string
+		}{
+			{
+				name:    "V1 attachments in V2 invitation",
+				request: fmt.Sprintf(`{"from":"did:example:alice","attachments":[%s]}`, presentationRequestAttachment),
+				err:     "attachments can't be used in DIDComm V2 invitation",
+			},
+			{
+				name:    "V2 attachments in V1 invitation",
+				request: fmt.Sprintf(`{"attachments-v2":[%s]}`, presentationRequestAttachmentV2),
+				err:     "attachments-v2 can't be used in DIDComm V1 invitation",
+			},
+			{
+				name:    "base URL without scheme",
+				request: `{"baseURL":"example.com/invite"}`,
+				err:     "scheme missing",
+			},
+			{
+				name:    "malformed base URL",
+				request: `{"baseURL":"https://example.com/%zz"}`,
+				err:     "invalid base URL",
+			},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+			cmdErr := c.CreateInvitation(&b, bytes.NewBufferString(tc.request))
+			require.Error(t, cmdErr, tc.name)
+			require.Contains(t, cmdErr.Error(), tc.err, tc.name)
+		}
+	})
+}
+
+func TestCommand_ParseInvitationURL(t *testing.T) {
+	const invitation = `{"@id":"inv-1","@type":"https://didcomm.org/out-of-band/1.0/invitation","label":"bob"}`
+
+	t.Run("standard base64 invitation with padding", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		// padded invitation, and unescaped invitation whose '+' turns into space in URL query.
+		encoded := base64.StdEncoding.EncodeToString([]byte(invitation + "   "))
+		require.True(t, strings.HasSuffix(encoded, "="))
+
+		parsed := parseInvitationURL(t, c, "https://example.com?c=1&oob="+url.QueryEscape(encoded))
+		require.Equal(t, "inv-1", parsed.Invitation.ID())
+
+		parsed = parseInvitationURL(t, c, "https://example.com?oob="+strings.ReplaceAll(encoded, "=", ""))
+		require.Equal(t, "inv-1", parsed.Invitation.ID())
+	})
+
+	t.Run("parsed invitation can be used in connection request", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(invitation))
+
+		var b bytes.Buffer
+		cmdErr := c.ParseInvitationURL(&b, bytes.NewBufferString(
+			fmt.Sprintf(`{"url":"https://example.com?oob=%s"}`, encoded)))
+		require.NoError(t, cmdErr)
+
+		var request ConnectionRequest
+		require.NoError(t, json.Unmarshal(b.Bytes(), &request))
+		require.NotNil(t, request.Invitation)
+		require.Equal(t, "inv-1", request.Invitation.ID())
+	})
+
+	t.Run("RFC 0434 attachments of invitation are kept", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(
+			`{"@id":"inv-1","@type":"https://didcomm.org/out-of-band/1.0/invitation","requests~attach":[%s]}`,
+			presentationRequestAttachment)))
+
+		parsed := parseInvitationURL(t, c, "https://example.com?oob="+encoded)
+		require.NotContains(t, *parsed.Invitation, "requests~attach")
+
+		inv := &outofbandsvc.Invitation{}
+		require.NoError(t, parsed.Invitation.Decode(inv))
+		require.Len(t, inv.Requests, 1)
+		require.Equal(t, "request-0", inv.Requests[0].ID)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		c := newInvitationCommand(t)
+
+		encode := func(msg string) string {
+			return base64.RawURLEncoding.EncodeToString([]byte(msg))
+		}
+
+		tests := []struct {
+			name    string
+			request string
+			err     string
+		}{
+			{
+				name:    "invalid request",
+				request: `{`,
+				err:     "unexpected EOF",
+			},
+			{
+				name:    "URL missing",
+				request: `{}`,
+				err:     "invitation URL missing in request",
+			},
+			{
+				name:    "malformed URL",
+				request: `{"url":"https://example.com/%zz"}`,
+				err:     "invalid invitation URL",
+			},
+			{
+				name:    "invitation missing",
+				request: `{"url":"https://example.com?c_i=abc"}`,
+				err:     "invitation missing in URL",
+			},
+			{
+				name:    "invalid base64",
+				request: `{"url":"https://example.com?oob=a"}`,
+				err:     "failed to decode invitation from URL",
+			},
+			{
+				name:    "invalid invitation",
+				request: fmt.Sprintf(`{"url":"https://example.com?_oob=%s"}`, encode("[]")),
+				err:     "failed to parse invitation from URL",
+			},
+			{
+				name: "not an invitation",
+				request: fmt.Sprintf(`{"url":"https://example.com?oob=%s"}`,
+					encode(`{"@type":"https://didcomm.org/trust_ping/1.0/ping"}`)),
+				err: "unsupported invitation type 'https://didcomm.org/trust_ping/1.0/ping'",
+			},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+			cmdErr := c.ParseInvitationURL(&b, bytes.NewBufferString(tc.request))
+			require.Error(t, cmdErr, tc.name)
+			require.Contains(t, cmdErr.Error(), tc.err, tc.name)
+		}
+	})
+}
+
+func newInvitationCommand(t *testing.T) *Command {
+	t.Helper()
+
+	prov := newMockProvider(map[string]interface{}{
+		mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+			Connections: []string{"sample-connection"},
+		},
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+		outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+		outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+	})
+
+	c, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	return c
+}
+
+func parseInvitationURL(t *testing.T, c *Command, invitationURL string) *ParseInvitationURLResponse {
+	t.Helper()
+
+	request, err := json.Marshal(&ParseInvitationURLRequest{URL: invitationURL})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	cmdErr := c.ParseInvitationURL(&b, bytes.NewBuffer(request))
+	require.NoError(t, cmdErr)
+
+	var response ParseInvitationURLResponse
+	require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+	require.NotNil(t, response.Invitation)
+
+	return &response
+}
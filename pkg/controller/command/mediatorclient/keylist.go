@@ -0,0 +1,359 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangeSvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	// number of keys queried from the router at once.
+	defaultKeylistPageSize = 100
+
+	// keylist query message types, mediator service doesn't support keylist query.
+	keylistQueryMsgType = mediatorservice.CoordinationSpec + "keylist_query"
+	keylistMsgType      = mediatorservice.CoordinationSpec + "keylist"
+)
+
+// keylist is route coordination keylist message.
+type keylist struct {
+	Keys []struct {
+		RecipientKey string `json:"recipient_key"`
+	} `json:"keys"`
+	Pagination *struct {
+		Count     int `json:"count"`
+		Offset    int `json:"offset"`
+		Remaining int `json:"remaining"`
+	} `json:"pagination,omitempty"`
+}
+
+// GetKeylist queries a page of recipient keys registered with the router.
+func (c *Command) GetKeylist(rw io.Writer, req io.Reader) command.Error {
+	var request GetKeylistRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetKeylist, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	_, err = c.pickupConnection(request.ConnectionID)
+	if err == nil && (request.Limit < 0 || request.Offset < 0) {
+		err = fmt.Errorf("limit and offset can't be negative")
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetKeylist, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultKeylistPageSize
+	}
+
+	resp, err := c.queryKeylist(request.ConnectionID, limit, request.Offset)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetKeylist, err.Error())
+
+		return command.NewExecuteError(GetKeylistError, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, GetKeylist, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// ReconcileKeylist compares keylist of the router with the keys of agent DIDs routed through the router, it adds
+// missing keys to the router and removes stale keys from the router unless it is a dry run.
+// Keys of agent DIDs are the keys registered with the router by the agent, including keys of out-of-band
+// invitations, along with recipient and key agreement keys of agent DIDs of completed and pending connections whose
+// DIDComm service is routed through the router. If keys of a pending connection can't be determined, only the keys
+// which the agent removed from the router are removed again.
+func (c *Command) ReconcileKeylist(rw io.Writer, req io.Reader) command.Error {
+	var request ReconcileKeylistRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ReconcileKeylist, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	_, err = c.pickupConnection(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ReconcileKeylist, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	resp, err := c.reconcileKeylist(request.ConnectionID, request.DryRun)
+	if err != nil {
+		logutil.LogError(logger, CommandName, ReconcileKeylist, err.Error())
+
+		return command.NewExecuteError(ReconcileKeylistError, err)
+	}
+
+	command.WriteNillableResponse(rw, resp, logger)
+
+	logutil.LogDebug(logger, CommandName, ReconcileKeylist,
+		fmt.Sprintf("%s for %s, added %d and removed %d keys", successString, request.ConnectionID,
+			len(resp.Added), len(resp.Removed)))
+
+	return nil
+}
+
+func (c *Command) reconcileKeylist(connID string, dryRun bool) (*ReconcileKeylistResponse, error) {
+	expected, complete, err := c.expectedRouterKeys(connID)
+	if err != nil {
+		return nil, err
+	}
+
+	removable := func(string) bool { return true }
+
+	if !complete {
+		dropped, e := routeutil.DroppedKeys(c.routeProvider, connID)
+		if e != nil {
+			return nil, e
+		}
+
+		droppedKeys := make(map[string]bool, len(dropped))
+		for _, key := range dropped {
+			droppedKeys[key] = true
+		}
+
+		removable = func(key string) bool { return droppedKeys[key] }
+	}
+
+	routerKeys, err := c.routerKeylist(connID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ReconcileKeylistResponse{DryRun: dryRun}
+
+	for key := range expected {
+		if !routerKeys[key] {
+			resp.Added = append(resp.Added, key)
+		}
+	}
+
+	for key := range routerKeys {
+		if !expected[key] && removable(key) {
+			resp.Removed = append(resp.Removed, key)
+		}
+	}
+
+	sort.Strings(resp.Added)
+	sort.Strings(resp.Removed)
+
+	if dryRun {
+		return resp, nil
+	}
+
+	err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, connID, resp.Added...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add keys to router : %w", err)
+	}
+
+	err = routeutil.RemoveKeyFromRouter(c.routeProvider, connID, resp.Removed...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove keys from router : %w", err)
+	}
+
+	return resp, nil
+}
+
+// expectedRouterKeys returns keys which are supposed to be registered with the router, and whether keys of all
+// pending connections could be determined.
+func (c *Command) expectedRouterKeys(connID string) (map[string]bool, bool, error) {
+	config, err := c.mediator.GetConfig(connID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get router config for connection %s : %w", connID, err)
+	}
+
+	registered, err := routeutil.RegisteredKeys(c.routeProvider, connID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	keys := make(map[string]bool)
+
+	for _, key := range registered {
+		keys[key] = true
+	}
+
+	records, err := c.connLookup.QueryConnectionRecords()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query connections : %w", err)
+	}
+
+	// connections are resolved in the same order every time, so that a failure is reported consistently.
+	sort.Slice(records, func(i, j int) bool { return records[i].ConnectionID < records[j].ConnectionID })
+
+	resolved := make(map[string]bool)
+	complete := true
+
+	for _, record := range records {
+		if record.State == didexchangeSvc.StateIDAbandoned || record.MyDID == "" || resolved[record.MyDID] {
+			continue
+		}
+
+		resolved[record.MyDID] = true
+
+		docResolution, err := c.vdRegistry.Resolve(record.MyDID)
+		if err == nil && docResolution.DIDDocument == nil {
+			err = fmt.Errorf("DID document is missing")
+		}
+
+		if err != nil && record.State != connection.StateNameCompleted {
+			logger.Warnf("failed to resolve DID %s of pending connection %s : %s", record.MyDID,
+				record.ConnectionID, err)
+
+			complete = false
+
+			continue
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to resolve DID %s of connection %s : %w", record.MyDID,
+				record.ConnectionID, err)
+		}
+
+		for i := range docResolution.DIDDocument.Service {
+			didSvc := &docResolution.DIDDocument.Service[i]
+
+			if !routedThrough(didSvc, config) {
+				continue
+			}
+
+			for _, key := range routeutil.DIDRouterKeys(docResolution.DIDDocument, didSvc) {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys, complete, nil
+}
+
+// recordInvitationKeys records recipient keys of out-of-band invitation services routed through the router, so that
+// they're kept on the router until the agent removes them.
+func recordInvitationKeys(p routeutil.Provider, connID string, inv *outofband.Invitation) error {
+	var keys []string
+
+	for _, s := range inv.Services {
+		if didSvc, ok := s.(*did.Service); ok {
+			keys = append(keys, didSvc.RecipientKeys...)
+		}
+	}
+
+	return routeutil.RecordKeys(p, connID, keys...)
+}
+
+// routerKeylist queries all pages of the router keylist.
+func (c *Command) routerKeylist(connID string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	offset := 0
+
+	for {
+		page, err := c.queryKeylist(connID, defaultKeylistPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range page.Keys {
+			keys[key] = true
+		}
+
+		if page.Remaining <= 0 || len(page.Keys) == 0 {
+			return keys, nil
+		}
+
+		offset += len(page.Keys)
+	}
+}
+
+func (c *Command) queryKeylist(connID string, limit, offset int) (*GetKeylistResponse, error) {
+	query := service.DIDCommMsgMap{
+		"@id":   uuid.New().String(),
+		"@type": keylistQueryMsgType,
+		"paginate": map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keylistTimeOut)
+	defer cancel()
+
+	reply, err := c.sendAndWaitForReply(ctx, connID, query, keylistMsgType)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &keylist{}
+
+	err = reply.Decode(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode keylist message : %w", err)
+	}
+
+	resp := &GetKeylistResponse{Keys: make([]string, len(list.Keys)), Count: len(list.Keys), Offset: offset}
+
+	for i, key := range list.Keys {
+		resp.Keys[i] = key.RecipientKey
+	}
+
+	// routers which don't page return all the keys.
+	if list.Pagination != nil {
+		resp.Count = list.Pagination.Count
+		resp.Offset = list.Pagination.Offset
+		resp.Remaining = list.Pagination.Remaining
+	}
+
+	return resp, nil
+}
+
+// routedThrough tells whether DIDComm service is routed through the router of given config.
+func routedThrough(didSvc *did.Service, config *mediatorservice.Config) bool {
+	routingKeys := append([]string{}, didSvc.RoutingKeys...)
+
+	if keys, err := didSvc.ServiceEndpoint.RoutingKeys(); err == nil {
+		routingKeys = append(routingKeys, keys...)
+	}
+
+	for _, key := range routingKeys {
+		for _, routerKey := range config.Keys() {
+			if key == routerKey {
+				return true
+			}
+		}
+	}
+
+	uri, err := didSvc.ServiceEndpoint.URI()
+
+	return err == nil && uri != "" && uri == config.Endpoint()
+}
@@ -0,0 +1,401 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	routerEndpoint   = "https://router.example.com"
+	routerRoutingKey = "did:key:router"
+
+	keylistRouterPageSize = 2
+)
+
+func TestCommand_GetKeylist(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		k := newKeylistCommand(t, "key-1", "key-2", "key-3")
+
+		var b bytes.Buffer
+
+		cmdErr := k.cmd.GetKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1","limit":2,"offset":1}`))
+		require.NoError(t, cmdErr)
+
+		var resp GetKeylistResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Equal(t, GetKeylistResponse{Keys: []string{"key-2", "key-3"}, Count: 2, Offset: 1}, resp)
+
+		require.Len(t, k.router.sent, 1)
+		require.Equal(t, keylistQueryMsgType, k.router.sent[0].Type())
+		require.Equal(t, map[string]interface{}{"limit": float64(2), "offset": float64(1)},
+			k.router.sent[0]["paginate"])
+	})
+
+	t.Run("test router without paging", func(t *testing.T) {
+		k := newKeylistCommand(t, "key-1")
+		k.noPaging = true
+
+		var b bytes.Buffer
+
+		cmdErr := k.cmd.GetKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.NoError(t, cmdErr)
+
+		var resp GetKeylistResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Equal(t, GetKeylistResponse{Keys: []string{"key-1"}, Count: 1}, resp)
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		k := newKeylistCommand(t)
+
+		for _, request := range []string{
+			"---", `{}`, `{"connectionID":"unknown"}`, `{"connectionID":"router-v1","limit":-1}`,
+		} {
+			var b bytes.Buffer
+			cmdErr := k.cmd.GetKeylist(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr, request)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+
+	t.Run("test query error", func(t *testing.T) {
+		k := newKeylistCommand(t)
+		k.router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			return service.DIDCommMsgMap{"@id": "reply", "@type": "https://example.com/unknown"}
+		}
+
+		var b bytes.Buffer
+
+		cmdErr := k.cmd.GetKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, GetKeylistError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "no message service found")
+	})
+}
+
+func TestCommand_ReconcileKeylist(t *testing.T) {
+	t.Run("test dry run", func(t *testing.T) {
+		k := newKeylistCommand(t, "did:example:orb#key-agreement", "did:key:stale", "did:key:alice")
+
+		var b bytes.Buffer
+
+		cmdErr := k.cmd.ReconcileKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1","dryRun":true}`))
+		require.NoError(t, cmdErr)
+
+		var resp ReconcileKeylistResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Equal(t, ReconcileKeylistResponse{
+			Added:   []string{"did:example:alice#key-agreement"},
+			Removed: []string{"did:key:stale"},
+			DryRun:  true,
+		}, resp)
+
+		// keylist is queried page by page and nothing is updated.
+		require.Len(t, k.router.sent, 2)
+		require.Empty(t, k.added)
+	})
+
+	t.Run("test success", func(t *testing.T) {
+		k := newKeylistCommand(t, "did:example:orb#key-agreement", "did:key:stale")
+
+		var b bytes.Buffer
+
+		cmdErr := k.cmd.ReconcileKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.NoError(t, cmdErr)
+
+		var resp ReconcileKeylistResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Equal(t, []string{"did:example:alice#key-agreement", "did:key:alice"}, resp.Added)
+		require.Equal(t, []string{"did:key:stale"}, resp.Removed)
+		require.False(t, resp.DryRun)
+
+		require.Equal(t, resp.Added, k.added)
+
+		update := k.router.sent[len(k.router.sent)-1]
+		require.Equal(t, mediatorsvc.KeylistUpdateMsgType, update.Type())
+		require.Equal(t, "did:key:stale", update["updates"].([]interface{})[0].(map[string]interface{})["recipient_key"])
+
+		keys, err := routeutil.RegisteredKeys(k.cmd.routeProvider, routerV1ConnID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:example:alice#key-agreement", "did:example:orb#key-agreement", "did:key:alice"},
+			keys)
+	})
+
+	t.Run("test pending connection", func(t *testing.T) {
+		k := newKeylistCommand(t, "did:example:orb#key-agreement", "did:key:alice", "did:example:alice#key-agreement",
+			"did:key:carol", "did:example:carol#key-agreement", "did:key:stale")
+		k.docs["did:example:carol"] = newKeylistDIDDoc("did:example:carol", routerEndpoint, routerRoutingKey)
+
+		resp, err := k.cmd.reconcileKeylist(routerV1ConnID, true)
+		require.NoError(t, err)
+		require.Empty(t, resp.Added)
+		require.Equal(t, []string{"did:key:stale"}, resp.Removed)
+	})
+
+	t.Run("test pending connection with unknown keys", func(t *testing.T) {
+		k := newKeylistCommand(t, "did:example:orb#key-agreement", "did:key:alice", "did:example:alice#key-agreement",
+			"did:key:carol", "did:key:dropped", "did:key:stale")
+		delete(k.docs, "did:example:carol")
+
+		require.NoError(t, routeutil.AddKeyToRouter(k.cmd.routeProvider, &mockroute.MockMediatorSvc{}, routerV1ConnID,
+			"did:key:dropped"))
+		require.NoError(t, routeutil.RemoveKeyFromRouter(k.cmd.routeProvider, routerV1ConnID, "did:key:dropped"))
+
+		// only the key which the agent removed from the router is removed again.
+		resp, err := k.cmd.reconcileKeylist(routerV1ConnID, true)
+		require.NoError(t, err)
+		require.Empty(t, resp.Added)
+		require.Equal(t, []string{"did:key:dropped"}, resp.Removed)
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		k := newKeylistCommand(t)
+
+		for _, request := range []string{"---", `{"dryRun":true}`, `{"connectionID":"unknown"}`} {
+			var b bytes.Buffer
+			cmdErr := k.cmd.ReconcileKeylist(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr, request)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name  string
+			setup func(k *keylistCommand)
+			err   string
+		}{
+			{
+				name:  "router config error",
+				setup: func(k *keylistCommand) { k.routeSvc.ConfigErr = fmt.Errorf("config error") },
+				err:   "config error",
+			},
+			{
+				name: "resolve error",
+				setup: func(k *keylistCommand) {
+					k.vdr.ResolveFunc = func(string, ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+						return nil, fmt.Errorf("resolve error")
+					}
+				},
+				err: "failed to resolve DID did:example:alice of connection conn-alice",
+			},
+			{
+				name: "query error",
+				setup: func(k *keylistCommand) {
+					k.router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+						return service.DIDCommMsgMap{"@id": "reply", "@type": "https://example.com/unknown"}
+					}
+				},
+				err: "no message service found",
+			},
+			{
+				name:  "add key error",
+				setup: func(k *keylistCommand) { k.routeSvc.AddKeyErr = fmt.Errorf("add key error") },
+				err:   "add key error",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				k := newKeylistCommand(t)
+				tc.setup(k)
+
+				var b bytes.Buffer
+
+				cmdErr := k.cmd.ReconcileKeylist(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+				require.Error(t, cmdErr)
+				require.Equal(t, ReconcileKeylistError, cmdErr.Code())
+				require.Contains(t, cmdErr.Error(), tc.err)
+			})
+		}
+	})
+}
+
+// keylistCommand is a command connected to a router holding given keys, which returns two keys per keylist page.
+// Agent has connections routed through the router and through another router, a pending connection routed through
+// another router, and a DID registered with the router.
+type keylistCommand struct {
+	cmd      *Command
+	router   *pickupRouter
+	routeSvc *mockroute.MockMediatorSvc
+	vdr      *mockvdr.MockVDRegistry
+	docs     map[string]*did.Doc
+	keys     []string
+	noPaging bool
+	added    []string
+}
+
+func newKeylistCommand(t *testing.T, keys ...string) *keylistCommand {
+	t.Helper()
+
+	k := &keylistCommand{keys: keys}
+
+	k.routeSvc = &mockroute.MockMediatorSvc{
+		RouterEndpoint: routerEndpoint,
+		RoutingKeys:    []string{routerRoutingKey},
+		AddKeyFunc: func(key string) error {
+			k.added = append(k.added, key)
+
+			return nil
+		},
+	}
+
+	k.router = &pickupRouter{
+		MockMessenger: sdkmockprotocol.NewMockMessenger(),
+		registrar:     mockmsghandler.NewMockMsgServiceProvider(),
+		reply:         k.reply,
+	}
+
+	k.docs = map[string]*did.Doc{
+		"did:example:alice": newKeylistDIDDoc("did:example:alice", routerEndpoint, routerRoutingKey),
+		"did:example:bob":   newKeylistDIDDoc("did:example:bob", "https://other.example.com", "did:key:other"),
+		"did:example:carol": newKeylistDIDDoc("did:example:carol", "https://other.example.com", "did:key:other"),
+	}
+
+	k.vdr = &mockvdr.MockVDRegistry{
+		ResolveFunc: func(didID string, _ ...vdrapi.DIDMethodOption) (*did.DocResolution, error) {
+			doc, ok := k.docs[didID]
+			if !ok {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &did.DocResolution{DIDDocument: doc}, nil
+		},
+	}
+
+	prov := newMockProvider(map[string]interface{}{
+		mediatorsvc.Coordination:   k.routeSvc,
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+		outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+		outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+	})
+	prov.CustomMessenger = k.router
+	prov.CustomVDR = k.vdr
+	prov.StoreProvider = mockstorage.NewMockStoreProvider()
+	prov.ProtocolStateStoreProvider = mockstorage.NewMockStoreProvider()
+
+	recorder, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	for _, record := range []*connection.Record{
+		{ConnectionID: routerV1ConnID, DIDCommVersion: service.V1},
+		{ConnectionID: "conn-alice", MyDID: "did:example:alice"},
+		{ConnectionID: "conn-alice-2", MyDID: "did:example:alice"},
+		{ConnectionID: "conn-bob", MyDID: "did:example:bob"},
+		{ConnectionID: "conn-carol", MyDID: "did:example:carol", State: "requested"},
+	} {
+		if record.State == "" {
+			record.State = connection.StateNameCompleted
+		}
+
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+	}
+
+	c, err := New(prov, k.router.registrar, mocks.NewMockNotifier(), WithRouterSupervision(-1, 0, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, routeutil.AddKeyToRouter(c.routeProvider, &mockroute.MockMediatorSvc{}, routerV1ConnID,
+		"did:example:orb#key-agreement"))
+
+	k.cmd = c
+
+	return k
+}
+
+func (k *keylistCommand) reply(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+	if msg.Type() != keylistQueryMsgType {
+		return nil
+	}
+
+	var query struct {
+		Paginate struct {
+			Limit  int `json:"limit"`
+			Offset int `json:"offset"`
+		} `json:"paginate"`
+	}
+
+	if err := msg.Decode(&query); err != nil {
+		panic(err)
+	}
+
+	reply := service.DIDCommMsgMap{
+		"@id":     "reply-" + msg.ID(),
+		"@type":   keylistMsgType,
+		"~thread": map[string]interface{}{"thid": msg.ID()},
+	}
+
+	page := k.keys
+	if !k.noPaging {
+		// the router returns two keys per page at most.
+		start, end := query.Paginate.Offset, query.Paginate.Offset+query.Paginate.Limit
+		if end > start+keylistRouterPageSize {
+			end = start + keylistRouterPageSize
+		}
+
+		if end > len(page) {
+			end = len(page)
+		}
+
+		if start > end {
+			start = end
+		}
+
+		page = page[start:end]
+	}
+
+	keys := make([]map[string]interface{}, len(page))
+	for i, key := range page {
+		keys[i] = map[string]interface{}{"recipient_key": key}
+	}
+
+	reply["keys"] = keys
+
+	if !k.noPaging {
+		reply["pagination"] = map[string]interface{}{
+			"count":     len(page),
+			"offset":    query.Paginate.Offset,
+			"remaining": len(k.keys) - query.Paginate.Offset - len(page),
+		}
+	}
+
+	return reply
+}
+
+func newKeylistDIDDoc(didID, endpoint, routingKey string) *did.Doc {
+	kaVM := did.VerificationMethod{ID: "#key-agreement", Type: "X25519KeyAgreementKey2019", Value: []byte("key")}
+
+	return &did.Doc{
+		ID: didID,
+		Service: []did.Service{{
+			Type:            "did-communication",
+			RecipientKeys:   []string{"did:key:" + didID[len("did:example:"):]},
+			RoutingKeys:     []string{routingKey},
+			ServiceEndpoint: model.NewDIDCommV1Endpoint(endpoint),
+		}},
+		KeyAgreement: []did.Verification{*did.NewReferencedVerification(&kaVM, did.KeyAgreement)},
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
 )
 
@@ -31,6 +32,14 @@ type ConnectionRequest struct {
 	// If not provided, then this agent will go ahead with mediator registration once did exchange state is
 	// completed at invitee.
 	StateCompleteMessageType string `json:"stateCompleteMessageType,omitempty"`
+
+	// Timeout (in milliseconds) waiting for the connection with the router to be completed.
+	// Optional: if missing, default timeout of 120 seconds is used.
+	Timeout int64 `json:"timeout,omitempty"`
+
+	// RequestID is client supplied ID of this request, which can be used to cancel pending request.
+	// Optional: if missing, request can't be cancelled.
+	RequestID string `json:"requestID,omitempty"`
 }
 
 // ConnectionResponse contains response.
@@ -49,6 +58,21 @@ type CreateInvitationRequest struct {
 	Service   []interface{} `json:"service"`
 	Protocols []string      `json:"protocols"`
 	From      string        `json:"from"`
+
+	// RouterConnectionID is ID of the router connection to be used for routing of out-of-band (V1) invitation.
+	// Optional: if missing, router is picked by router selection strategy of the agent.
+	RouterConnectionID string `json:"routerConnectionID,omitempty"`
+
+	// Attachments are requests attached to out-of-band (V1) invitation as `request~attach`,
+	// for example request-presentation or offer-credential message.
+	Attachments []*decorator.Attachment `json:"attachments,omitempty"`
+
+	// AttachmentsV2 are requests attached to out-of-band (V2) invitation as `attachments`.
+	AttachmentsV2 []*decorator.AttachmentV2 `json:"attachments-v2,omitempty"`
+
+	// BaseURL is base URL of invitation URL, for example https://example.com/path or didcomm://invite.
+	// Optional: if provided, invitation is also returned as URL with `oob` (V1) or `_oob` (V2) query parameter.
+	BaseURL string `json:"baseURL,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -60,6 +84,12 @@ type CreateInvitationResponse struct {
 	Invitation *outofband.Invitation `json:"invitation"`
 
 	InvitationV2 *outofbandv2.Invitation `json:"invitation-v2"`
+
+	// RouterConnectionID is ID of the router connection used for routing of out-of-band (V1) invitation.
+	RouterConnectionID string `json:"routerConnectionID,omitempty"`
+
+	// InvitationURL is invitation encoded as URL, returned only if base URL is provided in request.
+	InvitationURL string `json:"invitationURL,omitempty"`
 }
 
 // CreateConnectionRequest model
@@ -68,6 +98,18 @@ type CreateInvitationResponse struct {
 //
 type CreateConnectionRequest struct {
 	DIDDocument json.RawMessage `json:"didDoc"`
+
+	// RouterConnectionID is ID of the router connection to send create connection request to.
+	// Optional: if missing, router is picked by router selection strategy of the agent.
+	RouterConnectionID string `json:"routerConnectionID,omitempty"`
+
+	// Timeout (in milliseconds) waiting for create connection response from the router.
+	// Optional: if missing, default timeout of 120 seconds is used.
+	Timeout int64 `json:"timeout,omitempty"`
+
+	// RequestID is client supplied ID of this request, which can be used to cancel pending request.
+	// Optional: if missing, request can't be cancelled.
+	RequestID string `json:"requestID,omitempty"`
 }
 
 // CreateConnectionResponse model
@@ -76,4 +118,285 @@ type CreateConnectionRequest struct {
 //
 type CreateConnectionResponse struct {
 	Payload json.RawMessage `json:"payload"`
+
+	// RouterConnectionID is ID of the router connection create connection request was sent to.
+	RouterConnectionID string `json:"routerConnectionID,omitempty"`
+}
+
+// GetConnectionsResponse model
+//
+// Response for listing router connections of the agent.
+//
+type GetConnectionsResponse struct {
+	Connections []*RouterConnection `json:"connections"`
+}
+
+// RouterConnection contains details of a router connection.
+type RouterConnection struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// Label is label of the router.
+	Label string `json:"label,omitempty"`
+
+	// Endpoint is router endpoint to be used in DID services.
+	Endpoint string `json:"endpoint"`
+
+	// RoutingKeys are routing keys of the router.
+	RoutingKeys []string `json:"routingKeys"`
+
+	// DIDCommVersion is DIDComm version of the connection.
+	DIDCommVersion string `json:"didCommVersion,omitempty"`
+}
+
+// DisconnectRequest model
+//
+// This is used for disconnecting from a router.
+//
+type DisconnectRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// RecipientKeys are keys to be removed from keylist of the router before unregistering.
+	// Optional: if missing, keys registered with the router by the agent are removed.
+	RecipientKeys []string `json:"recipientKeys,omitempty"`
+}
+
+// CheckHealthRequest model
+//
+// This is used for checking health of routers.
+//
+type CheckHealthRequest struct {
+	// ConnectionID is ID of the connection with the router to be checked.
+	// Optional: if missing, all routers of the agent will be checked.
+	ConnectionID string `json:"connectionID,omitempty"`
+}
+
+// CheckHealthResponse model
+//
+// Response for checking health of routers.
+//
+type CheckHealthResponse struct {
+	Results []*HealthCheckResult `json:"results"`
+}
+
+// HealthCheckResult contains result of trust ping sent to a router.
+type HealthCheckResult struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// Healthy is true if router responded to trust ping.
+	Healthy bool `json:"healthy"`
+
+	// LatencyMS is round-trip time of trust ping in milliseconds.
+	LatencyMS int64 `json:"latencyMS,omitempty"`
+
+	// Error is reason why router is not healthy.
+	Error string `json:"error,omitempty"`
+}
+
+// PickupStatusRequest model
+//
+// This is used for requesting status of messages waiting at a router.
+//
+type PickupStatusRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// RecipientKey is key to get status of messages for.
+	// Optional: if missing, status of all messages is requested. Applicable to DIDComm V2 routers only.
+	RecipientKey string `json:"recipientKey,omitempty"`
+}
+
+// PickupStatusResponse model
+//
+// Response for message pickup status request.
+//
+type PickupStatusResponse struct {
+	// MessageCount is number of messages waiting at the router.
+	MessageCount int `json:"messageCount"`
+
+	// RecipientKey is key the status is reported for.
+	RecipientKey string `json:"recipientKey,omitempty"`
+
+	// LongestWaitedSeconds is how long the oldest message has been waiting at the router.
+	LongestWaitedSeconds int `json:"longestWaitedSeconds,omitempty"`
+
+	// TotalBytes is total size of messages waiting at the router.
+	TotalBytes int `json:"totalBytes,omitempty"`
+
+	// LiveDelivery is true if router delivers messages in live mode.
+	LiveDelivery bool `json:"liveDelivery,omitempty"`
+}
+
+// BatchPickupRequest model
+//
+// This is used for picking up a batch of messages from DIDComm V1 router.
+//
+type BatchPickupRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// BatchSize is maximum number of messages to be picked up, defaults to 10.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// BatchPickupResponse model
+//
+// Response for batch pickup.
+//
+type BatchPickupResponse struct {
+	// MessageCount is number of messages picked up.
+	MessageCount int `json:"messageCount"`
+}
+
+// PickupDeliveryRequest model
+//
+// This is used for requesting delivery of messages from DIDComm V2 router.
+//
+type PickupDeliveryRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// Limit is maximum number of messages to be delivered, defaults to 10.
+	Limit int `json:"limit,omitempty"`
+
+	// RecipientKey is key to get messages for.
+	// Optional: if missing, messages for all keys are delivered.
+	RecipientKey string `json:"recipientKey,omitempty"`
+}
+
+// PickupDeliveryResponse model
+//
+// Response for message pickup delivery request.
+//
+type PickupDeliveryResponse struct {
+	// MessageIDs are IDs of delivered messages handed over to the agent, to be acknowledged using MessagesReceived.
+	MessageIDs []string `json:"messageIDs,omitempty"`
+
+	// FailedMessageIDs are IDs of delivered messages the agent failed to handle.
+	FailedMessageIDs []string `json:"failedMessageIDs,omitempty"`
+
+	// MessageCount is number of messages waiting at the router, reported when no messages were delivered.
+	MessageCount int `json:"messageCount,omitempty"`
+}
+
+// MessagesReceivedRequest model
+//
+// This is used for acknowledging receipt of messages delivered by DIDComm V2 router.
+//
+type MessagesReceivedRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// MessageIDs are IDs of received messages.
+	MessageIDs []string `json:"messageIDs"`
+}
+
+// LiveModeRequest model
+//
+// This is used for turning live delivery of messages by DIDComm V2 router on or off.
+//
+type LiveModeRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// Enabled turns live delivery on if true, off otherwise.
+	Enabled bool `json:"enabled"`
+}
+
+// GetKeylistRequest model
+//
+// This is used for querying recipient keys registered with the router.
+//
+type GetKeylistRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// Limit is maximum number of keys to be returned, defaults to 100.
+	Limit int `json:"limit,omitempty"`
+
+	// Offset is number of keys to be skipped.
+	Offset int `json:"offset,omitempty"`
+}
+
+// GetKeylistResponse model
+//
+// Response for router keylist query.
+//
+type GetKeylistResponse struct {
+	// Keys are recipient keys registered with the router.
+	Keys []string `json:"keys"`
+
+	// Count is number of returned keys.
+	Count int `json:"count"`
+
+	// Offset is number of skipped keys.
+	Offset int `json:"offset"`
+
+	// Remaining is number of keys after the returned ones.
+	Remaining int `json:"remaining"`
+}
+
+// ReconcileKeylistRequest model
+//
+// This is used for reconciling keylist of the router with the keys of agent DIDs routed through the router.
+//
+type ReconcileKeylistRequest struct {
+	// ConnectionID is ID of the connection with the router.
+	ConnectionID string `json:"connectionID"`
+
+	// DryRun only reports keys to be added and removed, the router keylist isn't updated.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ReconcileKeylistResponse model
+//
+// Response for router keylist reconciliation.
+//
+type ReconcileKeylistResponse struct {
+	// Added are keys missing in the router keylist, which were added unless it was a dry run.
+	Added []string `json:"added,omitempty"`
+
+	// Removed are stale keys in the router keylist, which were removed unless it was a dry run.
+	Removed []string `json:"removed,omitempty"`
+
+	// DryRun tells whether the router keylist was left as it was.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ParseInvitationURLRequest model
+//
+// This is used for decoding out-of-band invitation from invitation URL.
+//
+type ParseInvitationURLRequest struct {
+	// URL is invitation URL with `oob` (V1) or `_oob` (V2) query parameter.
+	URL string `json:"url"`
+}
+
+// ParseInvitationURLResponse model
+//
+// Response of decoding invitation URL, it can be used as connection request.
+//
+type ParseInvitationURLResponse struct {
+	// Invitation is out-of-band (V1 or V2) invitation decoded from invitation URL.
+	Invitation *service.DIDCommMsgMap `json:"invitation"`
+}
+
+// CancelPendingRequest model
+//
+// This is used for cancelling pending connect or create connection request.
+//
+type CancelPendingRequest struct {
+	// RequestID is client supplied ID of the pending request.
+	RequestID string `json:"requestID"`
+}
+
+// CancelPendingResponse model
+//
+// Response of cancelling pending request.
+//
+type CancelPendingResponse struct {
+	// Cancelled tells whether the request was pending, requests which are already done can't be cancelled.
+	Cancelled bool `json:"cancelled"`
 }
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// errors.
+	errMissingRequestID = "request ID missing in request"
+	errRequestCancelled = "request cancelled"
+)
+
+// pendingRequests keeps cancel functions of requests by client supplied request ID, so that a client giving up
+// on a request stops the agent waiting for it too.
+type pendingRequests struct {
+	mutex   sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newPendingRequests() *pendingRequests {
+	return &pendingRequests{cancels: make(map[string]context.CancelFunc)}
+}
+
+// start returns context of the request which is done after given timeout or once the request is cancelled.
+// Returned cancel function has to be called once the request is done.
+func (p *pendingRequests) start(requestID string, timeout time.Duration) (context.Context, context.CancelFunc, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	if requestID == "" {
+		return ctx, cancel, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.cancels[requestID]; ok {
+		cancel()
+
+		return nil, nil, fmt.Errorf("request %s is already pending", requestID)
+	}
+
+	p.cancels[requestID] = cancel
+
+	return ctx, func() {
+		p.mutex.Lock()
+		delete(p.cancels, requestID)
+		p.mutex.Unlock()
+
+		cancel()
+	}, nil
+}
+
+// cancel cancels pending request, it returns false if the request isn't pending.
+func (p *pendingRequests) cancel(requestID string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cancel, ok := p.cancels[requestID]
+	if ok {
+		delete(p.cancels, requestID)
+		cancel()
+	}
+
+	return ok
+}
+
+// CancelPending cancels pending connect or create connection request with given client supplied request ID,
+// the agent stops waiting for the request and releases everything it was waiting with.
+func (c *Command) CancelPending(rw io.Writer, req io.Reader) command.Error {
+	var request CancelPendingRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, CancelPending, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if request.RequestID == "" {
+		logutil.LogError(logger, CommandName, CancelPending, errMissingRequestID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errMissingRequestID))
+	}
+
+	cancelled := c.pending.cancel(request.RequestID)
+
+	command.WriteNillableResponse(rw, &CancelPendingResponse{Cancelled: cancelled}, logger)
+
+	logutil.LogDebug(logger, CommandName, CancelPending,
+		fmt.Sprintf("%s for %s, cancelled %t", successString, request.RequestID, cancelled))
+
+	return nil
+}
+
+// requestTimeout returns timeout of the request given in milliseconds, or default timeout if it isn't given.
+func requestTimeout(timeoutMS int64, defaultTimeout time.Duration) (time.Duration, error) {
+	if timeoutMS < 0 {
+		return 0, fmt.Errorf("timeout can't be negative")
+	}
+
+	if timeoutMS == 0 {
+		return defaultTimeout, nil
+	}
+
+	return time.Duration(timeoutMS) * time.Millisecond, nil
+}
+
+// waitError returns error of waiting stopped by done context, timeoutErr is returned unless the request was
+// cancelled.
+func waitError(ctx context.Context, timeoutErr string) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return errors.New(errRequestCancelled)
+	}
+
+	return errors.New(timeoutErr)
+}
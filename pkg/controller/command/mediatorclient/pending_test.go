@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+)
+
+const (
+	pendingConnID     = "pending-conn-id"
+	pendingInvitation = `{
+		"@id": "3ae3d2cb-83bf-429f-93ea-0802f92ecf42",
+		"@type": "https://didcomm.org/out-of-band/1.0/invitation",
+		"label": "hub-router",
+		"service": ["did:example:router"],
+		"protocols": ["https://didcomm.org/didexchange/1.0"]
+	}`
+)
+
+func TestCommand_CancelPending(t *testing.T) {
+	t.Run("connect waiting for state complete notification is cancelled", func(t *testing.T) {
+		registrar := mockmsghandler.NewMockMsgServiceProvider()
+		c := newPendingCommand(t, registrar)
+
+		result := connectAsync(t, c, &ConnectionRequest{
+			StateCompleteMessageType: "https://example.com/router/1.0/state-complete",
+			RequestID:                "request-1",
+		})
+
+		require.Eventually(t, func() bool { return len(registrar.Services()) == 1 }, time.Second, time.Millisecond)
+
+		require.True(t, cancelPending(t, c, "request-1"))
+
+		cmdErr := waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errRequestCancelled)
+		require.Equal(t, ConnectMediatorError, cmdErr.Code())
+		require.Empty(t, registrar.Services())
+
+		// request is done, so it isn't pending anymore.
+		require.False(t, cancelPending(t, c, "request-1"))
+	})
+
+	t.Run("connect waiting for did exchange is cancelled", func(t *testing.T) {
+		c := newPendingCommand(t, mockmsghandler.NewMockMsgServiceProvider())
+
+		result := connectAsync(t, c, &ConnectionRequest{RequestID: "request-1"})
+
+		require.Eventually(t, func() bool { return cancelPending(t, c, "request-1") }, time.Second, time.Millisecond)
+
+		cmdErr := waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errRequestCancelled)
+
+		// cancelled connect isn't supervised.
+		regs, err := c.supervisor.list()
+		require.NoError(t, err)
+		require.Empty(t, regs)
+	})
+
+	t.Run("create connection request is cancelled", func(t *testing.T) {
+		registrar := mockmsghandler.NewMockMsgServiceProvider()
+
+		prov := newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+				Connections: []string{"sample-connection"},
+			},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		})
+
+		connBytes, err := json.Marshal(&connection.Record{
+			ConnectionID: "sample-connection",
+			State:        "completed", MyDID: "mydid", TheirDID: "theirDID-001",
+		})
+		require.NoError(t, err)
+
+		mockStore := &mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)}
+		require.NoError(t, mockStore.Put("conn_sample-connection", connBytes))
+
+		prov.StoreProvider = mockstorage.NewCustomMockStoreProvider(mockStore)
+		prov.CustomMessenger = sdkmockprotocol.NewMockMessenger()
+
+		c, err := New(prov, registrar, mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		request, err := json.Marshal(&CreateConnectionRequest{
+			DIDDocument: json.RawMessage(sampleDIDDoc),
+			RequestID:   "request-1",
+		})
+		require.NoError(t, err)
+
+		result := make(chan command.Error, 1)
+
+		go func() {
+			var b bytes.Buffer
+			result <- c.SendCreateConnectionRequest(&b, bytes.NewBuffer(request))
+		}()
+
+		require.Eventually(t, func() bool { return len(registrar.Services()) == 1 }, time.Second, time.Millisecond)
+
+		require.True(t, cancelPending(t, c, "request-1"))
+
+		cmdErr := waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errRequestCancelled)
+		require.Equal(t, SendCreateConnectionRequestError, cmdErr.Code())
+		require.Empty(t, registrar.Services())
+	})
+
+	t.Run("connect times out with timeout of request", func(t *testing.T) {
+		registrar := mockmsghandler.NewMockMsgServiceProvider()
+		c := newPendingCommand(t, registrar)
+
+		start := time.Now()
+
+		result := connectAsync(t, c, &ConnectionRequest{
+			StateCompleteMessageType: "https://example.com/router/1.0/state-complete",
+			Timeout:                  50,
+		})
+
+		cmdErr := waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "timeout waiting for state completed message from mediator")
+		require.Less(t, time.Since(start), c.didExchTimeout)
+		require.Empty(t, registrar.Services())
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		c := newPendingCommand(t, mockmsghandler.NewMockMsgServiceProvider())
+
+		_, cancel, err := c.pending.start("request-1", time.Minute)
+		require.NoError(t, err)
+
+		defer cancel()
+
+		result := connectAsync(t, c, &ConnectionRequest{RequestID: "request-1"})
+
+		cmdErr := waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "request request-1 is already pending")
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		result = connectAsync(t, c, &ConnectionRequest{Timeout: -1})
+
+		cmdErr = waitForResult(t, result)
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "timeout can't be negative")
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		var b bytes.Buffer
+
+		cmdErr = c.CancelPending(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errMissingRequestID)
+
+		cmdErr = c.CancelPending(&b, bytes.NewBufferString(`{`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		require.False(t, cancelPending(t, c, "request-2"))
+	})
+}
+
+func newPendingCommand(t *testing.T, registrar *mockmsghandler.MockMsgSvcProvider) *Command {
+	t.Helper()
+
+	prov := newMockProvider(map[string]interface{}{
+		mediatorsvc.Coordination: &mockroute.MockMediatorSvc{},
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{
+			ConnID:             pendingConnID,
+			State:              didexchangesvc.StateIDRequested,
+			MockDIDExchangeSvc: &mockdidexchange.MockDIDExchangeSvc{},
+		},
+		outofbandsvc.Name: &sdkmockprotocol.MockOobService{
+			AcceptInvitationHandle: func(*outofbandsvc.Invitation, outofbandsvc.Options) (string, error) {
+				return pendingConnID, nil
+			},
+		},
+		outofbandv2svc.Name: &sdkmockprotocol.MockOobServiceV2{},
+	})
+
+	c, err := New(prov, registrar, mocks.NewMockNotifier(), WithRouterSupervision(-1, 0, 0))
+	require.NoError(t, err)
+
+	return c
+}
+
+func connectAsync(t *testing.T, c *Command, request *ConnectionRequest) chan command.Error {
+	t.Helper()
+
+	invitation, err := service.ParseDIDCommMsgMap([]byte(pendingInvitation))
+	require.NoError(t, err)
+
+	request.Invitation = &invitation
+
+	requestBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	result := make(chan command.Error, 1)
+
+	go func() {
+		var b bytes.Buffer
+		result <- c.Connect(&b, bytes.NewBuffer(requestBytes))
+	}()
+
+	return result
+}
+
+func cancelPending(t *testing.T, c *Command, requestID string) bool {
+	t.Helper()
+
+	var b bytes.Buffer
+	cmdErr := c.CancelPending(&b, bytes.NewBufferString(`{"requestID":"`+requestID+`"}`))
+	require.NoError(t, cmdErr)
+
+	var response CancelPendingResponse
+	require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+
+	return response.Cancelled
+}
+
+func waitForResult(t *testing.T, result chan command.Error) command.Error {
+	t.Helper()
+
+	select {
+	case cmdErr := <-result:
+		return cmdErr
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "request wasn't done in time")
+
+		return nil
+	}
+}
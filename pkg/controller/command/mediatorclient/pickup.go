@@ -0,0 +1,420 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/client/messaging"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/logutil"
+)
+
+const (
+	// errors.
+	errMessagePickupUnavailable = "message pickup service is not available"
+
+	// default number of messages to be picked up at once.
+	defaultPickupBatchSize = 10
+
+	// pickup 2.0 message types.
+	pickupStatusRequestMsgType      = "https://didcomm.org/messagepickup/2.0/status-request"
+	pickupStatusMsgType             = "https://didcomm.org/messagepickup/2.0/status"
+	pickupDeliveryRequestMsgType    = "https://didcomm.org/messagepickup/2.0/delivery-request"
+	pickupDeliveryMsgType           = "https://didcomm.org/messagepickup/2.0/delivery"
+	pickupMessagesReceivedMsgType   = "https://didcomm.org/messagepickup/2.0/messages-received"
+	pickupLiveDeliveryChangeMsgType = "https://didcomm.org/messagepickup/2.0/live-delivery-change"
+)
+
+// pickupStatus is body of pickup 2.0 status message.
+type pickupStatus struct {
+	MessageCount         int    `json:"message_count"`
+	RecipientKey         string `json:"recipient_key,omitempty"`
+	LongestWaitedSeconds int    `json:"longest_waited_seconds,omitempty"`
+	TotalBytes           int    `json:"total_bytes,omitempty"`
+	LiveDelivery         bool   `json:"live_delivery,omitempty"`
+}
+
+// StatusRequest requests status of messages waiting for the agent at the router.
+// Pickup 2.0 is used for DIDComm V2 routers and message pickup 1.0 for DIDComm V1 routers.
+func (c *Command) StatusRequest(rw io.Writer, req io.Reader) command.Error {
+	var request PickupStatusRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, StatusRequest, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	conn, err := c.pickupConnection(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, StatusRequest, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	var status *PickupStatusResponse
+
+	if conn.DIDCommVersion == service.V2 {
+		status, err = c.pickupStatus(conn.ConnectionID, request.RecipientKey)
+	} else {
+		status, err = c.batchPickupStatus(conn.ConnectionID)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, StatusRequest, err.Error())
+
+		return command.NewExecuteError(StatusRequestError, err)
+	}
+
+	command.WriteNillableResponse(rw, status, logger)
+
+	logutil.LogDebug(logger, CommandName, StatusRequest, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// BatchPickup picks up a batch of messages from DIDComm V1 router using message pickup 1.0,
+// picked up messages are handed over to the inbound message handler of the agent.
+func (c *Command) BatchPickup(rw io.Writer, req io.Reader) command.Error {
+	var request BatchPickupRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, BatchPickup, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	conn, err := c.pickupConnection(request.ConnectionID)
+	if err == nil && conn.DIDCommVersion == service.V2 {
+		err = fmt.Errorf("batch pickup isn't supported by DIDComm V2 router %s, use delivery request", conn.ConnectionID)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, BatchPickup, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if c.messagePickup == nil {
+		logutil.LogError(logger, CommandName, BatchPickup, errMessagePickupUnavailable)
+
+		return command.NewExecuteError(BatchPickupError, fmt.Errorf(errMessagePickupUnavailable))
+	}
+
+	batchSize := request.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPickupBatchSize
+	}
+
+	count, err := c.messagePickup.BatchPickup(request.ConnectionID, batchSize)
+	if err != nil {
+		logutil.LogError(logger, CommandName, BatchPickup, err.Error())
+
+		return command.NewExecuteError(BatchPickupError, err)
+	}
+
+	command.WriteNillableResponse(rw, &BatchPickupResponse{MessageCount: count}, logger)
+
+	logutil.LogDebug(logger, CommandName, BatchPickup, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// DeliveryRequest requests delivery of messages from DIDComm V2 router using pickup 2.0,
+// delivered messages are handed over to the inbound message handler of the agent.
+// Router keeps delivered messages until their receipt is acknowledged using MessagesReceived.
+func (c *Command) DeliveryRequest(rw io.Writer, req io.Reader) command.Error {
+	var request PickupDeliveryRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeliveryRequest, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = c.validatePickupV2(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeliveryRequest, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultPickupBatchSize
+	}
+
+	body := map[string]interface{}{"limit": limit}
+	if request.RecipientKey != "" {
+		body["recipient_key"] = request.RecipientKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.pickupTimeout)
+	defer cancel()
+
+	// router replies with status message instead of delivery when there are no messages waiting.
+	reply, err := c.sendAndWaitForReply(ctx, request.ConnectionID,
+		newPickupMessage(pickupDeliveryRequestMsgType, body), pickupDeliveryMsgType, pickupStatusMsgType)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeliveryRequest, err.Error())
+
+		return command.NewExecuteError(DeliveryRequestError, err)
+	}
+
+	response, err := c.handleDelivery(reply)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeliveryRequest, err.Error())
+
+		return command.NewExecuteError(DeliveryRequestError, err)
+	}
+
+	command.WriteNillableResponse(rw, response, logger)
+
+	logutil.LogDebug(logger, CommandName, DeliveryRequest, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// MessagesReceived acknowledges receipt of given messages to DIDComm V2 router using pickup 2.0,
+// so that the router can remove them from the queue.
+func (c *Command) MessagesReceived(rw io.Writer, req io.Reader) command.Error {
+	var request MessagesReceivedRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, MessagesReceived, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = c.validatePickupV2(request.ConnectionID)
+	if err == nil && len(request.MessageIDs) == 0 {
+		err = fmt.Errorf("message IDs missing in request")
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, MessagesReceived, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = c.sendPickupMessage(request.ConnectionID, newPickupMessage(pickupMessagesReceivedMsgType,
+		map[string]interface{}{"message_id_list": request.MessageIDs}))
+	if err != nil {
+		logutil.LogError(logger, CommandName, MessagesReceived, err.Error())
+
+		return command.NewExecuteError(MessagesReceivedError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, MessagesReceived, fmt.Sprintf("%s for %s", successString, request.ConnectionID))
+
+	return nil
+}
+
+// LiveMode turns live delivery of DIDComm V2 router on or off using pickup 2.0. In live mode router sends messages
+// to the agent as soon as they arrive, over the connection kept open by duplex transport like websocket.
+func (c *Command) LiveMode(rw io.Writer, req io.Reader) command.Error {
+	var request LiveModeRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogError(logger, CommandName, LiveMode, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = c.validatePickupV2(request.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, LiveMode, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	err = c.sendPickupMessage(request.ConnectionID, newPickupMessage(pickupLiveDeliveryChangeMsgType,
+		map[string]interface{}{"live_delivery": request.Enabled}))
+	if err != nil {
+		logutil.LogError(logger, CommandName, LiveMode, err.Error())
+
+		return command.NewExecuteError(LiveModeError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, LiveMode,
+		fmt.Sprintf("%s for %s, live delivery=%t", successString, request.ConnectionID, request.Enabled))
+
+	return nil
+}
+
+// pickupConnection returns router connection to pick up messages from.
+func (c *Command) pickupConnection(connID string) (*connection.Record, error) {
+	if connID == "" {
+		return nil, fmt.Errorf(errMissingConnectionID)
+	}
+
+	conn, err := c.connLookup.GetConnectionRecord(connID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router connection %s : %w", connID, err)
+	}
+
+	return conn, nil
+}
+
+func (c *Command) validatePickupV2(connID string) error {
+	conn, err := c.pickupConnection(connID)
+	if err != nil {
+		return err
+	}
+
+	if conn.DIDCommVersion != service.V2 {
+		return fmt.Errorf("pickup 2.0 isn't supported by DIDComm V1 router %s, use batch pickup", connID)
+	}
+
+	return nil
+}
+
+func (c *Command) batchPickupStatus(connID string) (*PickupStatusResponse, error) {
+	if c.messagePickup == nil {
+		return nil, fmt.Errorf(errMessagePickupUnavailable)
+	}
+
+	status, err := c.messagePickup.StatusRequest(connID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PickupStatusResponse{
+		MessageCount:         status.MessageCount,
+		LongestWaitedSeconds: status.DurationWaited,
+		TotalBytes:           status.TotalSize,
+	}, nil
+}
+
+func (c *Command) pickupStatus(connID, recipientKey string) (*PickupStatusResponse, error) {
+	body := map[string]interface{}{}
+	if recipientKey != "" {
+		body["recipient_key"] = recipientKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.pickupTimeout)
+	defer cancel()
+
+	reply, err := c.sendAndWaitForReply(ctx, connID, newPickupMessage(pickupStatusRequestMsgType, body),
+		pickupStatusMsgType)
+	if err != nil {
+		return nil, err
+	}
+
+	return readPickupStatus(reply)
+}
+
+func (c *Command) sendPickupMessage(connID string, msg service.DIDCommMsgMap) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message : %w", err)
+	}
+
+	_, err = c.messenger.Send(msgBytes, messaging.SendByConnectionID(connID))
+
+	return err
+}
+
+// handleDelivery hands messages delivered by the router over to the inbound message handler of the agent.
+func (c *Command) handleDelivery(reply service.DIDCommMsgMap) (*PickupDeliveryResponse, error) {
+	response := &PickupDeliveryResponse{}
+
+	if reply.Type() == pickupStatusMsgType {
+		status, err := readPickupStatus(reply)
+		if err != nil {
+			return nil, err
+		}
+
+		response.MessageCount = status.MessageCount
+
+		return response, nil
+	}
+
+	var delivery struct {
+		Attachments []decorator.AttachmentV2 `json:"attachments"`
+	}
+
+	err := reply.Decode(&delivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode delivery message : %w", err)
+	}
+
+	for i := range delivery.Attachments {
+		attachment := &delivery.Attachments[i]
+
+		err = c.handleDeliveredMessage(attachment)
+		if err != nil {
+			logger.Warnf("failed to handle delivered message %s : %s", attachment.ID, err)
+
+			response.FailedMessageIDs = append(response.FailedMessageIDs, attachment.ID)
+
+			continue
+		}
+
+		response.MessageIDs = append(response.MessageIDs, attachment.ID)
+	}
+
+	return response, nil
+}
+
+func (c *Command) handleDeliveredMessage(attachment *decorator.AttachmentV2) error {
+	packed, err := attachment.Data.Fetch()
+	if err != nil {
+		return err
+	}
+
+	envelope, err := c.packager.UnpackMessage(packed)
+	if err != nil {
+		return fmt.Errorf("failed to unpack message : %w", err)
+	}
+
+	return c.inboundHandler(envelope)
+}
+
+func readPickupStatus(msg service.DIDCommMsgMap) (*PickupStatusResponse, error) {
+	var status struct {
+		Body pickupStatus `json:"body"`
+	}
+
+	err := msg.Decode(&status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode status message : %w", err)
+	}
+
+	return &PickupStatusResponse{
+		MessageCount:         status.Body.MessageCount,
+		RecipientKey:         status.Body.RecipientKey,
+		LongestWaitedSeconds: status.Body.LongestWaitedSeconds,
+		TotalBytes:           status.Body.TotalBytes,
+		LiveDelivery:         status.Body.LiveDelivery,
+	}, nil
+}
+
+func newPickupMessage(msgType string, body map[string]interface{}) service.DIDCommMsgMap {
+	return service.DIDCommMsgMap{
+		"id":   uuid.New().String(),
+		"type": msgType,
+		"body": body,
+	}
+}
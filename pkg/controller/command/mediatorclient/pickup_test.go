@@ -0,0 +1,414 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	messagepickupsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockpickup "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/messagepickup"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+)
+
+const (
+	routerV1ConnID = "router-v1"
+	routerV2ConnID = "router-v2"
+)
+
+func TestCommand_StatusRequest(t *testing.T) {
+	t.Run("test DIDComm V2 router", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+		router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			require.Equal(t, pickupStatusRequestMsgType, msg.Type())
+			require.Equal(t, map[string]interface{}{"recipient_key": "key-1"}, msg["body"])
+
+			return newPickupReply(msg, pickupStatusMsgType, map[string]interface{}{
+				"message_count": 3, "recipient_key": "key-1", "longest_waited_seconds": 60,
+				"total_bytes": 1024, "live_delivery": true,
+			})
+		}
+
+		var b bytes.Buffer
+		require.NoError(t, c.StatusRequest(&b,
+			bytes.NewBufferString(`{"connectionID":"router-v2","recipientKey":"key-1"}`)))
+
+		var response PickupStatusResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Equal(t, PickupStatusResponse{
+			MessageCount: 3, RecipientKey: "key-1", LongestWaitedSeconds: 60, TotalBytes: 1024, LiveDelivery: true,
+		}, response)
+	})
+
+	t.Run("test DIDComm V1 router", func(t *testing.T) {
+		c, _ := newPickupCommand(t, &mockpickup.MockMessagePickupSvc{
+			StatusRequestFunc: func(connID string) (*messagepickupsvc.Status, error) {
+				require.Equal(t, routerV1ConnID, connID)
+
+				return &messagepickupsvc.Status{MessageCount: 2, DurationWaited: 10, TotalSize: 512}, nil
+			},
+		})
+
+		var b bytes.Buffer
+		require.NoError(t, c.StatusRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`)))
+
+		var response PickupStatusResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Equal(t, PickupStatusResponse{MessageCount: 2, LongestWaitedSeconds: 10, TotalBytes: 512}, response)
+	})
+
+	t.Run("test DIDComm V1 router failure", func(t *testing.T) {
+		c, _ := newPickupCommand(t, &mockpickup.MockMessagePickupSvc{StatusRequestErr: fmt.Errorf(sampleErr)})
+
+		var b bytes.Buffer
+		cmdErr := c.StatusRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, StatusRequestError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+
+	t.Run("test message pickup service not available", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		var b bytes.Buffer
+		cmdErr := c.StatusRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, StatusRequestError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errMessagePickupUnavailable)
+	})
+
+	t.Run("test timeout", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		c.pickupTimeout = 10 * time.Millisecond
+
+		var b bytes.Buffer
+		cmdErr := c.StatusRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v2"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, StatusRequestError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "timeout waiting for reply from router")
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		for _, request := range []string{"---", `{}`, `{"connectionID":"unknown"}`} {
+			var b bytes.Buffer
+			cmdErr := c.StatusRequest(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+func TestCommand_BatchPickup(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		c, _ := newPickupCommand(t, &mockpickup.MockMessagePickupSvc{
+			BatchPickupFunc: func(connID string, size int) (int, error) {
+				require.Equal(t, routerV1ConnID, connID)
+				require.Equal(t, defaultPickupBatchSize, size)
+
+				return 4, nil
+			},
+		})
+
+		var b bytes.Buffer
+		require.NoError(t, c.BatchPickup(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`)))
+
+		var response BatchPickupResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Equal(t, 4, response.MessageCount)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		c, _ := newPickupCommand(t, &mockpickup.MockMessagePickupSvc{BatchPickupErr: fmt.Errorf(sampleErr)})
+
+		var b bytes.Buffer
+		cmdErr := c.BatchPickup(&b, bytes.NewBufferString(`{"connectionID":"router-v1","batchSize":5}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, BatchPickupError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+
+	t.Run("test message pickup service not available", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		var b bytes.Buffer
+		cmdErr := c.BatchPickup(&b, bytes.NewBufferString(`{"connectionID":"router-v1"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, BatchPickupError, cmdErr.Code())
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, _ := newPickupCommand(t, &mockpickup.MockMessagePickupSvc{})
+
+		for _, request := range []string{"---", `{}`, `{"connectionID":"router-v2"}`} {
+			var b bytes.Buffer
+			cmdErr := c.BatchPickup(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+func TestCommand_DeliveryRequest(t *testing.T) {
+	t.Run("test delivered messages are handed over to inbound handler", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+		router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			require.Equal(t, pickupDeliveryRequestMsgType, msg.Type())
+			require.Equal(t, map[string]interface{}{"limit": float64(5)}, msg["body"])
+
+			reply := newPickupReply(msg, pickupDeliveryMsgType, map[string]interface{}{})
+			reply["attachments"] = []interface{}{
+				map[string]interface{}{"id": "msg-1", "data": map[string]interface{}{
+					"base64": base64.StdEncoding.EncodeToString([]byte(`{"packed":"msg-1"}`)),
+				}},
+				map[string]interface{}{"id": "msg-2", "data": map[string]interface{}{
+					"json": map[string]interface{}{"packed": "msg-2"},
+				}},
+				map[string]interface{}{"id": "msg-3", "data": map[string]interface{}{
+					"base64": base64.StdEncoding.EncodeToString([]byte("invalid")),
+				}},
+			}
+
+			return reply
+		}
+
+		var b bytes.Buffer
+		require.NoError(t, c.DeliveryRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v2","limit":5}`)))
+
+		var response PickupDeliveryResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Equal(t, []string{"msg-1", "msg-2"}, response.MessageIDs)
+		require.Equal(t, []string{"msg-3"}, response.FailedMessageIDs)
+		require.Equal(t, []string{`{"packed":"msg-1"}`, `{"packed":"msg-2"}`}, router.inbound)
+	})
+
+	t.Run("test no messages waiting", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+		router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			require.Equal(t, map[string]interface{}{
+				"limit": float64(defaultPickupBatchSize), "recipient_key": "key-1",
+			}, msg["body"])
+
+			return newPickupReply(msg, pickupStatusMsgType, map[string]interface{}{"message_count": 0})
+		}
+
+		var b bytes.Buffer
+		require.NoError(t, c.DeliveryRequest(&b,
+			bytes.NewBufferString(`{"connectionID":"router-v2","recipientKey":"key-1"}`)))
+
+		var response PickupDeliveryResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.Empty(t, response.MessageIDs)
+		require.Empty(t, router.inbound)
+	})
+
+	t.Run("test invalid delivery", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+		router.reply = func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			reply := newPickupReply(msg, pickupDeliveryMsgType, map[string]interface{}{})
+			reply["attachments"] = "invalid"
+
+			return reply
+		}
+
+		var b bytes.Buffer
+		cmdErr := c.DeliveryRequest(&b, bytes.NewBufferString(`{"connectionID":"router-v2"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DeliveryRequestError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "failed to decode delivery message")
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		for _, request := range []string{"---", `{}`, `{"connectionID":"router-v1"}`} {
+			var b bytes.Buffer
+			cmdErr := c.DeliveryRequest(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+func TestCommand_MessagesReceived(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+
+		var b bytes.Buffer
+		require.NoError(t, c.MessagesReceived(&b,
+			bytes.NewBufferString(`{"connectionID":"router-v2","messageIDs":["msg-1","msg-2"]}`)))
+		require.Len(t, router.sent, 1)
+		require.Equal(t, pickupMessagesReceivedMsgType, router.sent[0].Type())
+		require.Equal(t, map[string]interface{}{"message_id_list": []interface{}{"msg-1", "msg-2"}},
+			router.sent[0]["body"])
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		for _, request := range []string{
+			"---", `{"messageIDs":["msg-1"]}`, `{"connectionID":"router-v2"}`,
+			`{"connectionID":"router-v1","messageIDs":["msg-1"]}`,
+		} {
+			var b bytes.Buffer
+			cmdErr := c.MessagesReceived(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+func TestCommand_LiveMode(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		c, router := newPickupCommand(t, nil)
+
+		for _, enabled := range []bool{true, false} {
+			var b bytes.Buffer
+			require.NoError(t, c.LiveMode(&b,
+				bytes.NewBufferString(fmt.Sprintf(`{"connectionID":"router-v2","enabled":%t}`, enabled))))
+		}
+
+		require.Len(t, router.sent, 2)
+
+		for i, enabled := range []bool{true, false} {
+			require.Equal(t, pickupLiveDeliveryChangeMsgType, router.sent[i].Type())
+			require.Equal(t, map[string]interface{}{"live_delivery": enabled}, router.sent[i]["body"])
+		}
+	})
+
+	t.Run("test invalid requests", func(t *testing.T) {
+		c, _ := newPickupCommand(t, nil)
+
+		for _, request := range []string{"---", `{"enabled":true}`, `{"connectionID":"router-v1","enabled":true}`} {
+			var b bytes.Buffer
+			cmdErr := c.LiveMode(&b, bytes.NewBufferString(request))
+			require.Error(t, cmdErr)
+			require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		}
+	})
+}
+
+// pickupRouter is a messenger which replies to messages like a router, and an inbound message handler of the agent.
+type pickupRouter struct {
+	*sdkmockprotocol.MockMessenger
+	registrar *mockmsghandler.MockMsgSvcProvider
+	reply     func(msg service.DIDCommMsgMap) service.DIDCommMsgMap
+	mutex     sync.Mutex
+	sent      []service.DIDCommMsgMap
+	inbound   []string
+}
+
+func (r *pickupRouter) Send(msg service.DIDCommMsgMap, _, _ string, _ ...service.Opt) error {
+	r.mutex.Lock()
+	r.sent = append(r.sent, msg)
+	r.mutex.Unlock()
+
+	if r.reply == nil {
+		return nil
+	}
+
+	reply := r.reply(msg)
+	if reply == nil {
+		return nil
+	}
+
+	services := r.registrar.Services()
+
+	// like inbound message handler, reply is dispatched to the last service accepting it.
+	for i := len(services) - 1; i >= 0; i-- {
+		if services[i].Accept(reply.Type(), nil) {
+			_, err := services[i].HandleInbound(reply, &sdkmockprotocol.MockDIDCommContext{})
+
+			return err
+		}
+	}
+
+	return fmt.Errorf("no message service found for %s", reply.Type())
+}
+
+func (r *pickupRouter) UnpackMessage(encMessage []byte) (*transport.Envelope, error) {
+	if string(encMessage) == "invalid" {
+		return nil, fmt.Errorf("invalid message")
+	}
+
+	return &transport.Envelope{Message: encMessage}, nil
+}
+
+func (r *pickupRouter) PackMessage(*transport.Envelope) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *pickupRouter) handleInbound(envelope *transport.Envelope) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.inbound = append(r.inbound, string(envelope.Message))
+
+	return nil
+}
+
+// newPickupCommand returns command connected to DIDComm V1 and V2 routers.
+func newPickupCommand(t *testing.T, pickupSvc *mockpickup.MockMessagePickupSvc) (*Command, *pickupRouter) {
+	t.Helper()
+
+	serviceMap := map[string]interface{}{
+		mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{},
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+		outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+		outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+	}
+
+	if pickupSvc != nil {
+		serviceMap[messagepickupsvc.MessagePickup] = pickupSvc
+	}
+
+	router := &pickupRouter{
+		MockMessenger: sdkmockprotocol.NewMockMessenger(),
+		registrar:     mockmsghandler.NewMockMsgServiceProvider(),
+	}
+
+	prov := newMockProvider(serviceMap)
+	prov.CustomMessenger = router
+	prov.CustomPackager = router
+	prov.InboundMsgHandler = router.handleInbound
+	prov.StoreProvider = newRouterConnectionStore(t,
+		&connection.Record{ConnectionID: routerV1ConnID, DIDCommVersion: service.V1},
+		&connection.Record{ConnectionID: routerV2ConnID, DIDCommVersion: service.V2},
+	)
+
+	c, err := New(prov, router.registrar, mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	return c, router
+}
+
+func newPickupReply(msg service.DIDCommMsgMap, msgType string, body map[string]interface{}) service.DIDCommMsgMap {
+	return service.DIDCommMsgMap{
+		"id":   "reply-" + msg.ID(),
+		"type": msgType,
+		"thid": msg.ID(),
+		"body": body,
+	}
+}
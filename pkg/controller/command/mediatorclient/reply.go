@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/client/messaging"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/msghandler"
+)
+
+// replyNotifier hands replies from routers over to the requests waiting for them in the same thread.
+//
+// Every request registers its own message services for reply message types, but inbound message handler
+// dispatches a message to only one of the services accepting its type. So all the services share this notifier.
+type replyNotifier struct {
+	mutex   sync.Mutex
+	waiters map[string]chan service.DIDCommMsgMap
+}
+
+func newReplyNotifier() *replyNotifier {
+	return &replyNotifier{waiters: make(map[string]chan service.DIDCommMsgMap)}
+}
+
+// Notify hands reply over to the request waiting in the thread of the reply, replies nobody waits for are dropped.
+func (n *replyNotifier) Notify(_ string, message []byte) error {
+	var reply struct {
+		Message service.DIDCommMsgMap `json:"message"`
+	}
+
+	err := json.Unmarshal(message, &reply)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal reply notification : %w", err)
+	}
+
+	thID, err := reply.Message.ThreadID()
+	if err != nil {
+		return fmt.Errorf("failed to read reply thread ID : %w", err)
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if waiter, ok := n.waiters[thID]; ok {
+		select {
+		case waiter <- reply.Message:
+		default:
+			logger.Debugf("dropped duplicate reply in thread %s", thID)
+		}
+	}
+
+	return nil
+}
+
+func (n *replyNotifier) add(thID string) chan service.DIDCommMsgMap {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	waiter := make(chan service.DIDCommMsgMap, 1)
+	n.waiters[thID] = waiter
+
+	return waiter
+}
+
+func (n *replyNotifier) remove(thID string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.waiters, thID)
+}
+
+// sendAndWaitForReply sends message to the router and waits for a reply of any of given types in the message thread.
+func (c *Command) sendAndWaitForReply(ctx context.Context, connID string, msg service.DIDCommMsgMap,
+	replyTypes ...string) (service.DIDCommMsgMap, error) {
+	waiter := c.replies.add(msg.ID())
+	defer c.replies.remove(msg.ID())
+
+	for _, replyType := range replyTypes {
+		topic := fmt.Sprintf("%s-%s", replyTopic, uuid.New().String())
+
+		err := c.msgHandler.Register(msghandler.NewMessageService(topic, replyType, nil, c.replies))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register reply notifier : %w", err)
+		}
+
+		defer func() {
+			e := c.msgHandler.Unregister(topic)
+			if e != nil {
+				logger.Warnf("Failed to unregister reply notifier: %w", e)
+			}
+		}()
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message : %w", err)
+	}
+
+	_, err = c.messenger.Send(msgBytes, messaging.SendByConnectionID(connID))
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-waiter:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timeout waiting for reply from router")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// RouterSelectionParams contains parameters for selecting router connection.
+type RouterSelectionParams struct {
+	// Connections are IDs of router connections to select from, never empty.
+	Connections []string
+
+	// Label is label of the invitation being created, empty for connection requests.
+	Label string
+
+	// Health contains latest health check results of routers by connection ID.
+	Health map[string]*HealthCheckResult
+}
+
+// RouterSelector selects router connection to be used for creating invitations and sending connection requests.
+type RouterSelector interface {
+	Select(params *RouterSelectionParams) (string, error)
+}
+
+// RouterSelectorFunc is a function adapter for RouterSelector.
+type RouterSelectorFunc func(params *RouterSelectionParams) (string, error)
+
+// Select selects router connection.
+func (f RouterSelectorFunc) Select(params *RouterSelectionParams) (string, error) {
+	return f(params)
+}
+
+// NewRandomRouterSelector returns router selector picking random router connection.
+func NewRandomRouterSelector() RouterSelector {
+	return RouterSelectorFunc(func(params *RouterSelectionParams) (string, error) {
+		return params.Connections[rand.Intn(len(params.Connections))], nil //nolint: gosec
+	})
+}
+
+// NewRoundRobinRouterSelector returns router selector picking router connections in turn.
+func NewRoundRobinRouterSelector() RouterSelector {
+	var (
+		mutex sync.Mutex
+		next  int
+	)
+
+	return RouterSelectorFunc(func(params *RouterSelectionParams) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		connID := params.Connections[next%len(params.Connections)]
+		next++
+
+		return connID, nil
+	})
+}
+
+// NewStickyRouterSelector returns router selector picking the same router connection for the same label,
+// as long as the connection is available. Router for a new label is picked in turn.
+func NewStickyRouterSelector() RouterSelector {
+	var (
+		mutex   sync.Mutex
+		routers = make(map[string]string)
+	)
+
+	roundRobin := NewRoundRobinRouterSelector()
+
+	return RouterSelectorFunc(func(params *RouterSelectionParams) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if connID, ok := routers[params.Label]; ok && contains(params.Connections, connID) {
+			return connID, nil
+		}
+
+		connID, err := roundRobin.Select(params)
+		if err != nil {
+			return "", err
+		}
+
+		routers[params.Label] = connID
+
+		return connID, nil
+	})
+}
+
+// NewLowestLatencyRouterSelector returns router selector picking healthy router connection with the lowest latency
+// reported by the latest health check. Routers which weren't checked yet come after healthy routers and routers
+// which failed health check come last.
+func NewLowestLatencyRouterSelector() RouterSelector {
+	return RouterSelectorFunc(func(params *RouterSelectionParams) (string, error) {
+		selected := params.Connections[0]
+
+		for _, connID := range params.Connections[1:] {
+			if lowerLatency(params.Health[connID], params.Health[selected]) {
+				selected = connID
+			}
+		}
+
+		return selected, nil
+	})
+}
+
+// lowerLatency returns true if router with health a is to be preferred over router with health b.
+func lowerLatency(a, b *HealthCheckResult) bool {
+	rank := func(h *HealthCheckResult) int {
+		switch {
+		case h == nil:
+			return 1
+		case h.Healthy:
+			return 0
+		default:
+			return 2 //nolint: gomnd
+		}
+	}
+
+	if rank(a) != rank(b) {
+		return rank(a) < rank(b)
+	}
+
+	return a != nil && a.Healthy && a.LatencyMS < b.LatencyMS
+}
+
+// selectRouter returns router connection given in request or the one picked by router selector.
+func (c *Command) selectRouter(connections []string, connID, label string) (string, error) {
+	if connID != "" {
+		if !contains(connections, connID) {
+			return "", fmt.Errorf("router connection %s not found", connID)
+		}
+
+		return connID, nil
+	}
+
+	connID, err := c.routerSelector.Select(&RouterSelectionParams{
+		Connections: connections,
+		Label:       label,
+		Health:      c.routerHealth.get(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to select router : %w", err)
+	}
+
+	return connID, nil
+}
+
+// routerHealth keeps latest health check results of routers.
+type routerHealth struct {
+	mutex   sync.RWMutex
+	results map[string]*HealthCheckResult
+}
+
+func (h *routerHealth) set(result *HealthCheckResult) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.results == nil {
+		h.results = make(map[string]*HealthCheckResult)
+	}
+
+	h.results[result.ConnectionID] = result
+}
+
+func (h *routerHealth) get() map[string]*HealthCheckResult {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	results := make(map[string]*HealthCheckResult, len(h.results))
+	for connID, result := range h.results {
+		results[connID] = result
+	}
+
+	return results
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
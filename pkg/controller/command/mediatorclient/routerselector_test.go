@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/command"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+)
+
+func TestRouterSelectors(t *testing.T) {
+	connections := []string{"conn-1", "conn-2", "conn-3"}
+
+	selectRouter := func(t *testing.T, selector RouterSelector, params *RouterSelectionParams) string {
+		t.Helper()
+
+		connID, err := selector.Select(params)
+		require.NoError(t, err)
+
+		return connID
+	}
+
+	t.Run("test random", func(t *testing.T) {
+		selector := NewRandomRouterSelector()
+
+		for i := 0; i < 10; i++ {
+			require.Contains(t, connections, selectRouter(t, selector, &RouterSelectionParams{Connections: connections}))
+		}
+	})
+
+	t.Run("test round-robin", func(t *testing.T) {
+		selector := NewRoundRobinRouterSelector()
+
+		for i := 0; i < 6; i++ {
+			require.Equal(t, connections[i%3], selectRouter(t, selector, &RouterSelectionParams{Connections: connections}))
+		}
+	})
+
+	t.Run("test sticky per label", func(t *testing.T) {
+		selector := NewStickyRouterSelector()
+
+		alice := selectRouter(t, selector, &RouterSelectionParams{Connections: connections, Label: "alice"})
+		bob := selectRouter(t, selector, &RouterSelectionParams{Connections: connections, Label: "bob"})
+		require.NotEqual(t, alice, bob)
+
+		for i := 0; i < 3; i++ {
+			require.Equal(t, alice, selectRouter(t, selector, &RouterSelectionParams{Connections: connections, Label: "alice"}))
+			require.Equal(t, bob, selectRouter(t, selector, &RouterSelectionParams{Connections: connections, Label: "bob"}))
+		}
+
+		// router of alice is gone.
+		remaining := []string{bob}
+		require.Equal(t, bob, selectRouter(t, selector, &RouterSelectionParams{Connections: remaining, Label: "alice"}))
+	})
+
+	t.Run("test lowest latency", func(t *testing.T) {
+		selector := NewLowestLatencyRouterSelector()
+
+		require.Equal(t, "conn-1", selectRouter(t, selector, &RouterSelectionParams{Connections: connections}))
+
+		health := map[string]*HealthCheckResult{
+			"conn-1": {ConnectionID: "conn-1", Healthy: false, Error: "timeout"},
+			"conn-2": {ConnectionID: "conn-2", Healthy: true, LatencyMS: 30},
+			"conn-3": {ConnectionID: "conn-3", Healthy: true, LatencyMS: 10},
+		}
+		require.Equal(t, "conn-3", selectRouter(t, selector, &RouterSelectionParams{
+			Connections: connections, Health: health,
+		}))
+
+		// unchecked router comes before unhealthy one.
+		require.Equal(t, "conn-4", selectRouter(t, selector, &RouterSelectionParams{
+			Connections: []string{"conn-1", "conn-4"}, Health: health,
+		}))
+	})
+}
+
+func TestCommand_SelectRouter(t *testing.T) {
+	newProvider := func() *sdkmockprotocol.MockProvider {
+		return newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+				Connections:    []string{"conn-1", "conn-2"},
+				RouterEndpoint: "http://router.example.com",
+				RoutingKeys:    []string{"key-1"},
+			},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		})
+	}
+
+	createInvitation := func(t *testing.T, c *Command, request *CreateInvitationRequest) (string, command.Error) {
+		t.Helper()
+
+		requestBytes, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := c.CreateInvitation(&b, bytes.NewBuffer(requestBytes))
+		if cmdErr != nil {
+			return "", cmdErr
+		}
+
+		var response CreateInvitationResponse
+		require.NoError(t, json.Unmarshal(b.Bytes(), &response))
+		require.NotNil(t, response.Invitation)
+
+		return response.RouterConnectionID, nil
+	}
+
+	t.Run("test router selector is used", func(t *testing.T) {
+		c, err := New(newProvider(), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier(),
+			WithRouterSelector(NewRoundRobinRouterSelector()))
+		require.NoError(t, err)
+
+		for _, expected := range []string{"conn-1", "conn-2", "conn-1"} {
+			connID, cmdErr := createInvitation(t, c, &CreateInvitationRequest{Label: "label"})
+			require.NoError(t, cmdErr)
+			require.Equal(t, expected, connID)
+		}
+	})
+
+	t.Run("test router connection ID in request", func(t *testing.T) {
+		c, err := New(newProvider(), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier(),
+			WithRouterSelector(RouterSelectorFunc(func(*RouterSelectionParams) (string, error) {
+				return "", fmt.Errorf("selector must not be used")
+			})))
+		require.NoError(t, err)
+
+		connID, cmdErr := createInvitation(t, c, &CreateInvitationRequest{RouterConnectionID: "conn-2"})
+		require.NoError(t, cmdErr)
+		require.Equal(t, "conn-2", connID)
+
+		_, cmdErr = createInvitation(t, c, &CreateInvitationRequest{RouterConnectionID: "conn-3"})
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "router connection conn-3 not found")
+
+		var b bytes.Buffer
+		cmdErr = c.SendCreateConnectionRequest(&b, bytes.NewBufferString(`{"routerConnectionID":"conn-3"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, SendCreateConnectionRequestError, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "router connection conn-3 not found")
+	})
+
+	t.Run("test router selector failure", func(t *testing.T) {
+		c, err := New(newProvider(), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier(),
+			WithRouterSelector(RouterSelectorFunc(func(*RouterSelectionParams) (string, error) {
+				return "", fmt.Errorf(sampleErr)
+			})))
+		require.NoError(t, err)
+
+		_, cmdErr := createInvitation(t, c, &CreateInvitationRequest{})
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), sampleErr)
+	})
+
+	t.Run("test health check results are passed to router selector", func(t *testing.T) {
+		var health map[string]*HealthCheckResult
+
+		c, err := New(newProvider(), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier(),
+			WithRouterSelector(RouterSelectorFunc(func(params *RouterSelectionParams) (string, error) {
+				health = params.Health
+
+				return params.Connections[0], nil
+			})))
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, c.CheckHealth(&b, bytes.NewBufferString(`{"connectionID":"conn-2"}`)))
+
+		_, cmdErr := createInvitation(t, c, &CreateInvitationRequest{})
+		require.NoError(t, cmdErr)
+		require.Len(t, health, 1)
+		require.False(t, health["conn-2"].Healthy)
+	})
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// stateCompleteNotifier correlates state complete notifications from routers with connections being established.
+//
+// Every connect registers its own message service for state complete message type, but inbound message handler
+// dispatches a message to only one of the services accepting its type. So all the services share this notifier
+// which hands the message over to the connect it belongs to.
+type stateCompleteNotifier struct {
+	connLookup *connection.Lookup
+	retention  time.Duration
+	mutex      sync.Mutex
+	waiters    map[*stateCompleteWaiter]struct{}
+	unmatched  []*stateCompleteMsg
+}
+
+// stateCompleteWaiter is a connect waiting for state complete notification.
+type stateCompleteWaiter struct {
+	invitationID string
+	connectionID string
+	done         chan struct{}
+}
+
+// stateCompleteMsg is state complete notification sent by message service.
+type stateCompleteMsg struct {
+	Message  service.DIDCommMsgMap `json:"message"`
+	MyDID    string                `json:"mydid"`
+	TheirDID string                `json:"theirdid"`
+	received time.Time
+}
+
+func newStateCompleteNotifier(connLookup *connection.Lookup, retention time.Duration) *stateCompleteNotifier {
+	return &stateCompleteNotifier{
+		connLookup: connLookup,
+		retention:  retention,
+		waiters:    make(map[*stateCompleteWaiter]struct{}),
+	}
+}
+
+// Notify hands state complete notification over to the connect it belongs to. Notifications which don't match any
+// connect are kept until connection ID of the connect they belong to is known.
+func (n *stateCompleteNotifier) Notify(_ string, message []byte) error {
+	msg := &stateCompleteMsg{received: time.Now()}
+
+	err := json.Unmarshal(message, msg)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal state complete notification : %w", err)
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for waiter := range n.waiters {
+		if n.matches(waiter, msg) {
+			n.complete(waiter)
+
+			return nil
+		}
+	}
+
+	n.unmatched = append(n.pruneUnmatched(), msg)
+
+	return nil
+}
+
+// add adds connect accepting invitation with given ID.
+func (n *stateCompleteNotifier) add(invitationID string) *stateCompleteWaiter {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	waiter := &stateCompleteWaiter{invitationID: invitationID, done: make(chan struct{})}
+	n.waiters[waiter] = struct{}{}
+
+	return waiter
+}
+
+// setConnectionID sets ID of the connection being established by the connect and checks notifications received
+// before the connection ID was known.
+func (n *stateCompleteNotifier) setConnectionID(waiter *stateCompleteWaiter, connID string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if _, ok := n.waiters[waiter]; !ok {
+		return
+	}
+
+	waiter.connectionID = connID
+
+	unmatched := n.pruneUnmatched()
+
+	for i, msg := range unmatched {
+		if n.matches(waiter, msg) {
+			n.complete(waiter)
+			n.unmatched = append(unmatched[:i], unmatched[i+1:]...)
+
+			return
+		}
+	}
+
+	n.unmatched = unmatched
+}
+
+// remove removes the connect once it's done waiting.
+func (n *stateCompleteNotifier) remove(waiter *stateCompleteWaiter) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.waiters, waiter)
+}
+
+func (n *stateCompleteNotifier) complete(waiter *stateCompleteWaiter) {
+	logger.Debugf("Received state complete notification for invitationID=%s connectionID=%s",
+		waiter.invitationID, waiter.connectionID)
+
+	close(waiter.done)
+	delete(n.waiters, waiter)
+}
+
+// matches returns true if notification belongs to the connect, which is when notification is sent in the thread of
+// the invitation or the did exchange, or over the connection being established.
+func (n *stateCompleteNotifier) matches(waiter *stateCompleteWaiter, msg *stateCompleteMsg) bool {
+	if pthID := msg.Message.ParentThreadID(); pthID != "" && pthID == waiter.invitationID {
+		return true
+	}
+
+	if waiter.connectionID == "" {
+		return false
+	}
+
+	record, err := n.connLookup.GetConnectionRecord(waiter.connectionID)
+	if err != nil {
+		logger.Debugf("failed to get connection %s : %s", waiter.connectionID, err)
+
+		return false
+	}
+
+	if thID, e := msg.Message.ThreadID(); e == nil && record.ThreadID != "" && thID == record.ThreadID {
+		return true
+	}
+
+	return msg.TheirDID != "" && msg.TheirDID == record.TheirDID && msg.MyDID == record.MyDID
+}
+
+func (n *stateCompleteNotifier) pruneUnmatched() []*stateCompleteMsg {
+	unmatched := n.unmatched[:0]
+
+	for _, msg := range n.unmatched {
+		if time.Since(msg.received) < n.retention {
+			unmatched = append(unmatched, msg)
+		}
+	}
+
+	return unmatched
+}
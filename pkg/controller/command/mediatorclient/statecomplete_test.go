@@ -0,0 +1,160 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+)
+
+func TestCommand_ConnectParallel(t *testing.T) {
+	const (
+		count                    = 9
+		stateCompleteMsgType     = "https://trustbloc.dev/didexchange/1.0/state-complete"
+		connectionRequestPattern = `{
+			"invitation": {
+				"@id": "invitation-%d",
+				"@type": "https://didcomm.org/out-of-band/1.0/invitation",
+				"label": "hub-router",
+				"service": [{
+					"ID": "1d03b636-ab0d-4a4e-904b-cdc70265c6bc",
+					"Type": "did-communication",
+					"RecipientKeys": ["36umoSWgaY4pBpwGUX9UNXBmpo1iDSdLsiKDs4XPXK4Q"],
+					"ServiceEndpoint": "wss://hub.router.agent.example.com:10072"
+				}],
+				"protocols": ["https://didcomm.org/didexchange/1.0"]
+			},
+			"stateCompleteMessageType": "%s"
+		}`
+	)
+
+	records := make([]*connection.Record, count)
+	for i := range records {
+		records[i] = &connection.Record{
+			ConnectionID: fmt.Sprintf("connection-%d", i),
+			ThreadID:     fmt.Sprintf("thread-%d", i),
+			MyDID:        fmt.Sprintf("my-did-%d", i),
+			TheirDID:     fmt.Sprintf("their-did-%d", i),
+		}
+	}
+
+	registrar := mockmsghandler.NewMockMsgServiceProvider()
+
+	// dispatch delivers message to one of the services accepting it, like inbound message handler does.
+	dispatch := func(msg service.DIDCommMsgMap, ctx *sdkmockprotocol.MockDIDCommContext) {
+		services := registrar.Services()
+
+		for i := len(services) - 1; i >= 0; i-- {
+			if services[i].Accept(msg.Type(), nil) {
+				_, err := services[i].HandleInbound(msg, ctx)
+				require.NoError(t, err)
+
+				return
+			}
+		}
+
+		require.Fail(t, "no message service found for state complete message")
+	}
+
+	// routers of odd connections never send state complete message, routers of even connections send it
+	// in the thread of invitation, in the thread of did exchange or over the connection.
+	stateComplete := func(i int) {
+		time.Sleep(10 * time.Millisecond)
+
+		msg := service.DIDCommMsgMap{"@id": fmt.Sprintf("state-complete-%d", i), "@type": stateCompleteMsgType}
+		ctx := &sdkmockprotocol.MockDIDCommContext{}
+
+		switch i % 3 {
+		case 0:
+			msg["~thread"] = map[string]interface{}{"pthid": fmt.Sprintf("invitation-%d", i)}
+		case 1:
+			msg["~thread"] = map[string]interface{}{"thid": records[i].ThreadID}
+		default:
+			ctx.MyDIDValue, ctx.TheirDIDValue = records[i].MyDID, records[i].TheirDID
+		}
+
+		dispatch(msg, ctx)
+	}
+
+	prov := newMockProvider(map[string]interface{}{
+		mediatorsvc.Coordination: &mockroute.MockMediatorSvc{
+			RouterEndpoint: "http://router.example.com",
+			RoutingKeys:    []string{"key-1"},
+		},
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+		outofbandsvc.Name: &sdkmockprotocol.MockOobService{
+			AcceptInvitationHandle: func(inv *outofbandsvc.Invitation, _ outofbandsvc.Options) (string, error) {
+				var i int
+
+				_, err := fmt.Sscanf(inv.ID, "invitation-%d", &i)
+				require.NoError(t, err)
+
+				if i%2 == 0 {
+					go stateComplete(i)
+				}
+
+				return records[i].ConnectionID, nil
+			},
+		},
+		outofbandv2svc.Name: &sdkmockprotocol.MockOobServiceV2{},
+	})
+	prov.StoreProvider = newRouterConnectionStore(t, records...)
+
+	c, err := New(prov, registrar, mocks.NewMockNotifier())
+	require.NoError(t, err)
+
+	defer c.Close()
+
+	c.didExchTimeout = time.Second
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var b bytes.Buffer
+
+			cmdErr := c.Connect(&b, strings.NewReader(fmt.Sprintf(connectionRequestPattern, i, stateCompleteMsgType)))
+			if i%2 != 0 {
+				require.Error(t, cmdErr, "connect %d", i)
+				require.Contains(t, cmdErr.Error(), "timeout waiting for state completed message from mediator")
+
+				return
+			}
+
+			require.NoError(t, cmdErr, "connect %d", i)
+
+			var resp ConnectionResponse
+			require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+			require.Equal(t, records[i].ConnectionID, resp.ConnectionID)
+		}(i)
+	}
+
+	wg.Wait()
+
+	require.Empty(t, registrar.Services())
+}
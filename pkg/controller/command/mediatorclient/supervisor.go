@@ -0,0 +1,468 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	// MediatorConnectedTopic is the notifier topic on which supervised routers are reported reachable again.
+	MediatorConnectedTopic = "mediator-connected"
+	// MediatorDisconnectedTopic is the notifier topic on which supervised routers are reported unreachable.
+	MediatorDisconnectedTopic = "mediator-disconnected"
+
+	routerRegistrationKeyPrefix = "routerregistration_"
+	routerRegistrationTag       = "routerRegistration"
+
+	defaultSupervisionInterval     = time.Minute
+	defaultReconnectInitialBackoff = 5 * time.Second
+	defaultReconnectMaxBackoff     = 5 * time.Minute
+)
+
+var errRouterRegistrationNotFound = errors.New("router registration not found")
+
+// MediatorStatusEvent is published on MediatorConnectedTopic and MediatorDisconnectedTopic when reachability of
+// a supervised router changes.
+type MediatorStatusEvent struct {
+	ConnectionID string `json:"connectionID"`
+	// PreviousConnectionID is set when the router was dialed again through its invitation.
+	PreviousConnectionID string `json:"previousConnectionID,omitempty"`
+	Error                string `json:"error,omitempty"`
+}
+
+// routerRegistration is a router connected through connect command, kept in the store so that relationship with
+// the router can be re-established when the router becomes unreachable or the agent restarts. Keys registered
+// with the router are kept by routeutil.
+type routerRegistration struct {
+	ID           string             `json:"id"`
+	ConnectionID string             `json:"connectionID"`
+	Request      *ConnectionRequest `json:"request"`
+	CreatedAt    time.Time          `json:"createdAt"`
+}
+
+// routerSupervisor keeps router registrations in the store and checks health of the routers, it reconnects
+// unhealthy routers with exponential backoff.
+type routerSupervisor struct {
+	check          func(connID string) *HealthCheckResult
+	reconnect      func(ctx context.Context, reg *routerRegistration) (string, error)
+	leave          func(connID string)
+	store          storage.Store
+	notifier       ariescmd.Notifier
+	checkInterval  time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	mutex          sync.Mutex
+	workers        map[string]chan struct{}
+	ctx            context.Context
+	cancel         context.CancelFunc
+	running        sync.WaitGroup
+	closed         bool
+}
+
+func newRouterSupervisor(check func(string) *HealthCheckResult,
+	reconnect func(context.Context, *routerRegistration) (string, error), leave func(string),
+	store storage.Store, notifier ariescmd.Notifier) *routerSupervisor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &routerSupervisor{
+		check:          check,
+		reconnect:      reconnect,
+		leave:          leave,
+		store:          store,
+		notifier:       notifier,
+		checkInterval:  defaultSupervisionInterval,
+		initialBackoff: defaultReconnectInitialBackoff,
+		maxBackoff:     defaultReconnectMaxBackoff,
+		workers:        make(map[string]chan struct{}),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// resume restarts supervision of routers registered before the agent was stopped, they are checked right away.
+func (s *routerSupervisor) resume() error {
+	regs, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	for _, reg := range regs {
+		s.start(reg.ID, 0)
+	}
+
+	return nil
+}
+
+// add saves registration of router connected through given request and starts supervising it. The router has just
+// been connected, so it is first checked after check interval.
+func (s *routerSupervisor) add(connID string, request *ConnectionRequest) error {
+	reg := &routerRegistration{
+		ID:           uuid.New().String(),
+		ConnectionID: connID,
+		Request:      request,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	err := s.put(reg)
+	if err != nil {
+		return err
+	}
+
+	s.start(reg.ID, s.checkInterval)
+
+	return nil
+}
+
+// remove deletes registration of the router connection and stops supervising it. Routers which weren't connected
+// through connect command aren't supervised, so missing registration is not an error.
+func (s *routerSupervisor) remove(connID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	regs, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	for _, reg := range regs {
+		if reg.ConnectionID != connID {
+			continue
+		}
+
+		err = s.store.Delete(routerRegistrationKeyPrefix + reg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete router registration : %w", err)
+		}
+
+		if stop, ok := s.workers[reg.ID]; ok {
+			close(stop)
+			delete(s.workers, reg.ID)
+		}
+	}
+
+	return nil
+}
+
+// close stops supervising all the routers and waits for the supervision to stop. Registrations are kept, so that
+// supervision is resumed by the next supervisor on the same store.
+func (s *routerSupervisor) close() {
+	s.mutex.Lock()
+	s.closed = true
+	s.mutex.Unlock()
+
+	s.cancel()
+	s.running.Wait()
+}
+
+func (s *routerSupervisor) start(id string, delay time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// negative check interval disables supervision.
+	if _, ok := s.workers[id]; ok || s.checkInterval < 0 || s.closed {
+		return
+	}
+
+	stop := make(chan struct{})
+	s.workers[id] = stop
+
+	s.running.Add(1)
+
+	go func() {
+		defer s.running.Done()
+
+		s.run(id, stop, delay)
+	}()
+}
+
+func (s *routerSupervisor) run(id string, stop chan struct{}, delay time.Duration) {
+	var lastTopic string
+
+	backoff := s.initialBackoff
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		reg, err := s.get(id)
+		if err != nil {
+			logger.Warnf("stopped supervising router %s : %s", id, err)
+
+			return
+		}
+
+		result := s.check(reg.ConnectionID)
+		if result.Healthy {
+			lastTopic = s.notify(MediatorConnectedTopic, lastTopic, &MediatorStatusEvent{ConnectionID: reg.ConnectionID})
+			backoff = s.initialBackoff
+			delay = s.checkInterval
+
+			continue
+		}
+
+		lastTopic = s.notify(MediatorDisconnectedTopic, lastTopic, &MediatorStatusEvent{
+			ConnectionID: reg.ConnectionID,
+			Error:        result.Error,
+		})
+
+		connID, err := s.reconnect(s.ctx, reg)
+		if err != nil {
+			logger.Warnf("failed to reconnect router %s, retrying in %s : %s", reg.ConnectionID, backoff, err)
+
+			delay = backoff
+
+			backoff *= 2
+			if backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+
+			continue
+		}
+
+		event := &MediatorStatusEvent{ConnectionID: connID}
+
+		if connID != reg.ConnectionID {
+			event.PreviousConnectionID = reg.ConnectionID
+			reg.ConnectionID = connID
+
+			// registration was removed while the router was dialed, so the new connection isn't kept either.
+			if !s.update(reg, stop) {
+				s.leave(connID)
+
+				return
+			}
+		}
+
+		lastTopic = s.notify(MediatorConnectedTopic, lastTopic, event)
+		backoff = s.initialBackoff
+		delay = s.checkInterval
+	}
+}
+
+// update saves reconnected router registration unless it was removed meanwhile.
+func (s *routerSupervisor) update(reg *routerRegistration, stop chan struct{}) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cancelled(stop) {
+		return false
+	}
+
+	err := s.put(reg)
+	if err != nil {
+		logger.Warnf("failed to save router registration %s : %s", reg.ID, err)
+	}
+
+	return true
+}
+
+// notify publishes event on the topic unless it is the last published topic, it returns the last published topic.
+func (s *routerSupervisor) notify(topic, lastTopic string, event *MediatorStatusEvent) string {
+	if topic == lastTopic {
+		return lastTopic
+	}
+
+	msg, err := json.Marshal(event)
+	if err != nil {
+		logger.Warnf("failed to marshal mediator status event : %s", err)
+
+		return lastTopic
+	}
+
+	err = s.notifier.Notify(topic, msg)
+	if err != nil {
+		logger.Warnf("failed to notify status of router %s : %s", event.ConnectionID, err)
+	}
+
+	return topic
+}
+
+func (s *routerSupervisor) list() ([]*routerRegistration, error) {
+	iter, err := s.store.Query(routerRegistrationTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query router registrations : %w", err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("failed to close iterator : %s", e)
+		}
+	}()
+
+	var regs []*routerRegistration
+
+	for {
+		ok, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next router registration : %w", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		data, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get router registration : %w", err)
+		}
+
+		reg := &routerRegistration{}
+
+		err = json.Unmarshal(data, reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal router registration : %w", err)
+		}
+
+		regs = append(regs, reg)
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].CreatedAt.Before(regs[j].CreatedAt)
+	})
+
+	return regs, nil
+}
+
+func (s *routerSupervisor) get(id string) (*routerRegistration, error) {
+	data, err := s.store.Get(routerRegistrationKeyPrefix + id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("%w : %s", errRouterRegistrationNotFound, id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router registration : %w", err)
+	}
+
+	reg := &routerRegistration{}
+
+	err = json.Unmarshal(data, reg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal router registration : %w", err)
+	}
+
+	return reg, nil
+}
+
+func (s *routerSupervisor) put(reg *routerRegistration) error {
+	data, err := json.Marshal(reg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal router registration : %w", err)
+	}
+
+	err = s.store.Put(routerRegistrationKeyPrefix+reg.ID, data, storage.Tag{Name: routerRegistrationTag})
+	if err != nil {
+		return fmt.Errorf("failed to save router registration : %w", err)
+	}
+
+	return nil
+}
+
+func cancelled(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnectRouter re-establishes relationship with the router of given registration and returns ID of the router
+// connection. Agent registers with the router again over the existing connection, if the connection is gone or
+// the router still doesn't respond, the router is dialed again through its invitation and the registered keys are
+// moved over to the new connection.
+func (c *Command) reconnectRouter(ctx context.Context, reg *routerRegistration) (string, error) {
+	_, err := c.connLookup.GetConnectionRecord(reg.ConnectionID)
+	if err == nil {
+		err = c.registerAgain(reg.ConnectionID)
+		if err == nil && c.checkRouterHealth(reg.ConnectionID).Healthy {
+			return reg.ConnectionID, nil
+		}
+	}
+
+	if err != nil {
+		logger.Debugf("failed to register with router %s again, dialing it : %s", reg.ConnectionID, err)
+	}
+
+	if reg.Request == nil || reg.Request.Invitation == nil {
+		return "", fmt.Errorf("router %s can't be dialed without invitation", reg.ConnectionID)
+	}
+
+	// timeout of the request is meant for the client waiting for connect, so reconnects wait with default timeout.
+	ctx, cancel := context.WithTimeout(ctx, c.didExchTimeout)
+	defer cancel()
+
+	connID, err := c.connect(ctx, reg.Request)
+	if err != nil {
+		return "", fmt.Errorf("failed to dial router : %w", err)
+	}
+
+	keys, err := routeutil.RegisteredKeys(c.routeProvider, reg.ConnectionID)
+	if err == nil {
+		err = routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, connID, keys...)
+	}
+
+	if err != nil {
+		c.leaveRouter(connID)
+
+		return "", fmt.Errorf("failed to register keys with router : %w", err)
+	}
+
+	c.leaveRouter(reg.ConnectionID)
+
+	return connID, nil
+}
+
+// registerAgain registers agent with the router of the connection again and adds the registered keys.
+func (c *Command) registerAgain(connID string) error {
+	err := c.mediator.Unregister(connID)
+	if err != nil && !errors.Is(err, mediatorservice.ErrRouterNotRegistered) {
+		return err
+	}
+
+	err = c.mediator.Register(connID)
+	if err != nil {
+		return err
+	}
+
+	keys, err := routeutil.RegisteredKeys(c.routeProvider, connID)
+	if err != nil {
+		return err
+	}
+
+	return routeutil.AddKeyToRouter(c.routeProvider, c.mediatorSvc, connID, keys...)
+}
+
+// leaveRouter unregisters agent from the router of the connection and forgets keys registered with it.
+func (c *Command) leaveRouter(connID string) {
+	err := c.mediator.Unregister(connID)
+	if err != nil && !errors.Is(err, mediatorservice.ErrRouterNotRegistered) {
+		logger.Warnf("failed to unregister router %s : %s", connID, err)
+	}
+
+	err = routeutil.ForgetRouter(c.routeProvider, connID)
+	if err != nil {
+		logger.Warnf("failed to forget keys of router %s : %s", connID, err)
+	}
+}
@@ -0,0 +1,403 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediatorclient // nolint:testpackage // uses internal implementation details
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
+	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
+	sdkmockprotocol "github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks/protocol"
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+const (
+	routerGoneConnID   = "router-gone"
+	routerInvitationV2 = `{
+		"id": "invitation-v2",
+		"type": "https://didcomm.org/out-of-band/2.0/invitation",
+		"from": "did:example:router",
+		"body": {"accept": ["didcomm/v2"]}
+	}`
+)
+
+func TestCommand_RouterSupervision(t *testing.T) {
+	t.Run("test connected router is supervised until disconnected", func(t *testing.T) {
+		routeSvc := &mockroute.MockMediatorSvc{}
+		s := newSupervisedCommand(t, routeSvc, nil, WithRouterSupervision(10*time.Millisecond, 0, 0))
+		s.setHealthy(true)
+
+		var b bytes.Buffer
+
+		cmdErr := s.cmd.Connect(&b, bytes.NewBufferString(`{"invitation":`+routerInvitationV2+`,"mylabel":"alice"}`))
+		require.NoError(t, cmdErr)
+
+		regs, err := s.cmd.supervisor.list()
+		require.NoError(t, err)
+		require.Len(t, regs, 1)
+		require.Equal(t, routerV2ConnID, regs[0].ConnectionID)
+		require.Equal(t, "alice", regs[0].Request.MyLabel)
+		require.Equal(t, "invitation-v2", regs[0].Request.Invitation.ID())
+
+		event := s.nextEvent(t, MediatorConnectedTopic)
+		require.Equal(t, routerV2ConnID, event.ConnectionID)
+
+		// router is reported only when its status changes.
+		time.Sleep(50 * time.Millisecond)
+		s.noEvent(t)
+
+		cmdErr = s.cmd.Disconnect(&b, bytes.NewBufferString(`{"connectionID":"router-v2"}`))
+		require.NoError(t, cmdErr)
+
+		regs, err = s.cmd.supervisor.list()
+		require.NoError(t, err)
+		require.Empty(t, regs)
+
+		pings := s.pings()
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, pings, s.pings())
+	})
+
+	t.Run("test agent registers with router again", func(t *testing.T) {
+		var added []string
+
+		routeSvc := &mockroute.MockMediatorSvc{
+			AddKeyFunc: func(key string) error {
+				added = append(added, key)
+
+				return nil
+			},
+		}
+
+		s := newSupervisedCommand(t, routeSvc, func(c *Command) {
+			require.NoError(t, routeutil.AddKeyToRouter(c.routeProvider, routeSvc, routerV2ConnID, "key-1"))
+			require.NoError(t, c.supervisor.put(&routerRegistration{ID: "reg-1", ConnectionID: routerV2ConnID}))
+		}, WithRouterSupervision(time.Hour, 0, 0))
+
+		routeSvc.RegisterFunc = func(connID string, _ ...mediatorsvc.ClientOption) error {
+			require.Equal(t, routerV2ConnID, connID)
+			s.setHealthy(true)
+
+			return nil
+		}
+
+		// router is checked right away after restart.
+		event := s.nextEvent(t, MediatorDisconnectedTopic)
+		require.Equal(t, routerV2ConnID, event.ConnectionID)
+		require.Contains(t, event.Error, "no message service found")
+
+		event = s.nextEvent(t, MediatorConnectedTopic)
+		require.Equal(t, routerV2ConnID, event.ConnectionID)
+		require.Empty(t, event.PreviousConnectionID)
+		require.Equal(t, []string{"key-1", "key-1"}, added)
+	})
+
+	t.Run("test agent dials router again", func(t *testing.T) {
+		routeSvc := &mockroute.MockMediatorSvc{}
+
+		s := newSupervisedCommand(t, routeSvc, func(c *Command) {
+			require.NoError(t, routeutil.AddKeyToRouter(c.routeProvider, routeSvc, routerGoneConnID, "key-1"))
+
+			request := &ConnectionRequest{}
+			require.NoError(t, json.Unmarshal([]byte(`{"invitation":`+routerInvitationV2+`}`), request))
+			require.NoError(t, c.supervisor.put(&routerRegistration{
+				ID:           "reg-1",
+				ConnectionID: routerGoneConnID,
+				Request:      request,
+			}))
+		}, WithRouterSupervision(time.Hour, 0, 0))
+		s.setHealthy(true)
+
+		event := s.nextEvent(t, MediatorDisconnectedTopic)
+		require.Equal(t, routerGoneConnID, event.ConnectionID)
+		require.Contains(t, event.Error, "failed to get router connection")
+
+		event = s.nextEvent(t, MediatorConnectedTopic)
+		require.Equal(t, routerV2ConnID, event.ConnectionID)
+		require.Equal(t, routerGoneConnID, event.PreviousConnectionID)
+
+		reg, err := s.cmd.supervisor.get("reg-1")
+		require.NoError(t, err)
+		require.Equal(t, routerV2ConnID, reg.ConnectionID)
+
+		keys, err := routeutil.RegisteredKeys(s.cmd.routeProvider, routerV2ConnID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key-1"}, keys)
+
+		keys, err = routeutil.RegisteredKeys(s.cmd.routeProvider, routerGoneConnID)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("test reconnect is retried with backoff", func(t *testing.T) {
+		var (
+			mutex    sync.Mutex
+			attempts int
+		)
+
+		routeSvc := &mockroute.MockMediatorSvc{RegisterFunc: func(string, ...mediatorsvc.ClientOption) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			attempts++
+
+			return fmt.Errorf("register error")
+		}}
+
+		s := newSupervisedCommand(t, routeSvc, func(c *Command) {
+			require.NoError(t, c.supervisor.put(&routerRegistration{ID: "reg-1", ConnectionID: routerV2ConnID,
+				Request: &ConnectionRequest{}}))
+		}, WithRouterSupervision(time.Hour, 5*time.Millisecond, 10*time.Millisecond))
+
+		s.nextEvent(t, MediatorDisconnectedTopic)
+
+		require.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return attempts > 3
+		}, time.Second, 5*time.Millisecond)
+
+		s.noEvent(t)
+	})
+
+	t.Run("test closed command stops supervision", func(t *testing.T) {
+		s := newSupervisedCommand(t, &mockroute.MockMediatorSvc{}, nil, WithRouterSupervision(10*time.Millisecond, 0, 0))
+		s.setHealthy(true)
+
+		var b bytes.Buffer
+
+		cmdErr := s.cmd.Connect(&b, bytes.NewBufferString(`{"invitation":`+routerInvitationV2+`}`))
+		require.NoError(t, cmdErr)
+
+		s.nextEvent(t, MediatorConnectedTopic)
+
+		s.cmd.Close()
+
+		pings := s.pings()
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, pings, s.pings())
+
+		// registration is kept for the next command.
+		regs, err := s.cmd.supervisor.list()
+		require.NoError(t, err)
+		require.Len(t, regs, 1)
+	})
+
+	t.Run("test router dialed after registration is removed is left", func(t *testing.T) {
+		var (
+			reconnecting = make(chan struct{})
+			release      = make(chan struct{})
+			left         = make(chan string, 1)
+		)
+
+		supervisor := newRouterSupervisor(
+			func(connID string) *HealthCheckResult {
+				return &HealthCheckResult{ConnectionID: connID, Error: "router unreachable"}
+			},
+			func(context.Context, *routerRegistration) (string, error) {
+				close(reconnecting)
+				<-release
+
+				return routerV2ConnID, nil
+			},
+			func(connID string) { left <- connID },
+			&mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)},
+			mocks.NewMockNotifier(),
+		)
+		supervisor.checkInterval = time.Hour
+
+		defer supervisor.close()
+
+		require.NoError(t, supervisor.put(&routerRegistration{ID: "reg-1", ConnectionID: routerGoneConnID}))
+		supervisor.start("reg-1", 0)
+
+		select {
+		case <-reconnecting:
+		case <-time.After(time.Second):
+			require.Fail(t, "router wasn't reconnected")
+		}
+
+		require.NoError(t, supervisor.remove(routerGoneConnID))
+		close(release)
+
+		select {
+		case connID := <-left:
+			require.Equal(t, routerV2ConnID, connID)
+		case <-time.After(time.Second):
+			require.Fail(t, "dialed router wasn't left")
+		}
+	})
+
+	t.Run("test supervision disabled", func(t *testing.T) {
+		s := newSupervisedCommand(t, &mockroute.MockMediatorSvc{}, func(c *Command) {
+			require.NoError(t, c.supervisor.put(&routerRegistration{ID: "reg-1", ConnectionID: routerV2ConnID}))
+		}, WithRouterSupervision(-1, 0, 0))
+
+		time.Sleep(50 * time.Millisecond)
+		s.noEvent(t)
+		require.Zero(t, s.pings())
+	})
+
+	t.Run("test registration store errors", func(t *testing.T) {
+		prov := newMockProvider(nil)
+		prov.StoreProvider = &mockstorage.MockStoreProvider{
+			Store:         &mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)},
+			FailNamespace: CommandName,
+		}
+
+		_, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open mediator client store")
+
+		prov.StoreProvider = mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:    make(map[string]mockstorage.DBEntry),
+			ErrQuery: fmt.Errorf("query error"),
+		})
+
+		_, err = New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to resume router supervision")
+	})
+}
+
+// supervisedCommand is a command connected to a DIDComm V2 router which responds to pings when it is healthy.
+type supervisedCommand struct {
+	cmd     *Command
+	router  *pickupRouter
+	events  chan *topicEvent
+	mutex   sync.Mutex
+	healthy bool
+	pinged  int
+}
+
+type topicEvent struct {
+	topic string
+	event *MediatorStatusEvent
+}
+
+// newSupervisedCommand creates command, setup is called with the command before it is created again on the same
+// store, like on agent restart.
+func newSupervisedCommand(t *testing.T, routeSvc *mockroute.MockMediatorSvc, setup func(c *Command),
+	opts ...Option) *supervisedCommand {
+	t.Helper()
+
+	s := &supervisedCommand{events: make(chan *topicEvent, 10)}
+
+	s.router = &pickupRouter{
+		MockMessenger: sdkmockprotocol.NewMockMessenger(),
+		registrar:     mockmsghandler.NewMockMsgServiceProvider(),
+		reply: func(msg service.DIDCommMsgMap) service.DIDCommMsgMap {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			s.pinged++
+
+			if !s.healthy {
+				return service.DIDCommMsgMap{"id": "unknown", "type": "https://example.com/unknown"}
+			}
+
+			return newPickupReply(msg, trustPingV2ResponseMsgType, nil)
+		},
+	}
+
+	prov := newMockProvider(map[string]interface{}{
+		mediatorsvc.Coordination:   routeSvc,
+		didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+		outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+		outofbandv2svc.Name: &sdkmockprotocol.MockOobServiceV2{
+			AcceptInvitationHandle: func(*outofbandv2svc.Invitation) (string, error) {
+				return routerV2ConnID, nil
+			},
+		},
+	})
+	prov.CustomMessenger = s.router
+	prov.StoreProvider = newRouterConnectionStore(t,
+		&connection.Record{ConnectionID: routerV2ConnID, DIDCommVersion: service.V2},
+	)
+
+	notifier := mocks.NewMockNotifier()
+	notifier.NotifyFunc = func(topic string, message []byte) error {
+		event := &MediatorStatusEvent{}
+		require.NoError(t, json.Unmarshal(message, event))
+
+		s.events <- &topicEvent{topic: topic, event: event}
+
+		return nil
+	}
+
+	if setup != nil {
+		c, err := New(prov, s.router.registrar, mocks.NewMockNotifier(), WithRouterSupervision(-1, 0, 0))
+		require.NoError(t, err)
+
+		setup(c)
+		c.Close()
+	}
+
+	c, err := New(prov, s.router.registrar, notifier, opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(c.Close)
+
+	s.cmd = c
+
+	return s
+}
+
+func (s *supervisedCommand) setHealthy(healthy bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.healthy = healthy
+}
+
+func (s *supervisedCommand) pings() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.pinged
+}
+
+func (s *supervisedCommand) nextEvent(t *testing.T, topic string) *MediatorStatusEvent {
+	t.Helper()
+
+	select {
+	case e := <-s.events:
+		require.Equal(t, topic, e.topic)
+
+		return e.event
+	case <-time.After(time.Second):
+		require.Fail(t, "timeout waiting for "+topic)
+	}
+
+	return nil
+}
+
+func (s *supervisedCommand) noEvent(t *testing.T) {
+	t.Helper()
+
+	select {
+	case e := <-s.events:
+		require.Fail(t, "unexpected event on "+e.topic)
+	default:
+	}
+}
@@ -9,6 +9,7 @@ package controller
 
 import (
 	"fmt"
+	"time"
 
 	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
@@ -35,6 +36,8 @@ type allOpts struct {
 	msgHandler               ariescmd.MessageHandler
 	notifier                 ariescmd.Notifier
 	webhookURLs              []string
+	didClientOpts            []didclientcmd.Option
+	mediatorClientOpts       []mediatorclientcmd.Option
 }
 
 // Opt represents a controller option.
@@ -68,6 +71,42 @@ func WithUnanchoredDIDMaxLifeTime(seconds int) Opt {
 	}
 }
 
+// WithDIDResolutionCacheTTL is an option for how long, in seconds, resolutions of anchored and unanchored DIDs
+// are cached. Zero keeps the default TTL and caching is disabled for negative values.
+func WithDIDResolutionCacheTTL(anchoredSeconds, unanchoredSeconds int) Opt {
+	return func(opts *allOpts) {
+		opts.didClientOpts = append(opts.didClientOpts, didclientcmd.WithResolutionCacheTTL(
+			time.Duration(anchoredSeconds)*time.Second, time.Duration(unanchoredSeconds)*time.Second))
+	}
+}
+
+// WithOrbDomains is an option for Orb domains which DID operations fail over to when the trustbloc domain is not
+// reachable.
+func WithOrbDomains(domains ...didclientcmd.OrbDomain) Opt {
+	return func(opts *allOpts) {
+		opts.didClientOpts = append(opts.didClientOpts, didclientcmd.WithOrbDomains(domains...))
+	}
+}
+
+// WithRouterSelector is an option for strategy of selecting router connection for invitations and connection
+// requests created through mediator client.
+func WithRouterSelector(selector mediatorclientcmd.RouterSelector) Opt {
+	return func(opts *allOpts) {
+		opts.mediatorClientOpts = append(opts.mediatorClientOpts, mediatorclientcmd.WithRouterSelector(selector))
+	}
+}
+
+// WithRouterSupervision is an option for how often, in seconds, routers connected through mediator client are
+// checked and for backoff of reconnecting unreachable routers. Zero keeps the defaults and negative check interval
+// disables supervision.
+func WithRouterSupervision(checkIntervalSeconds, initialBackoffSeconds, maxBackoffSeconds int) Opt {
+	return func(opts *allOpts) {
+		opts.mediatorClientOpts = append(opts.mediatorClientOpts, mediatorclientcmd.WithRouterSupervision(
+			time.Duration(checkIntervalSeconds)*time.Second, time.Duration(initialBackoffSeconds)*time.Second,
+			time.Duration(maxBackoffSeconds)*time.Second))
+	}
+}
+
 // WithMessageHandler is an option allowing for the message handler to be set.
 func WithMessageHandler(handler ariescmd.MessageHandler) Opt {
 	return func(opts *allOpts) {
@@ -104,13 +143,13 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 
 	// did client command operation.
 	didClientCmd, err := didclientcmd.New(cmdOpts.blocDomain, cmdOpts.didAnchorOrigin, cmdOpts.sidetreeToken,
-		cmdOpts.unanchoredDIDMaxLifeTime, ctx)
+		cmdOpts.unanchoredDIDMaxLifeTime, ctx, notifier, cmdOpts.didClientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize DID client: %w", err)
 	}
 
 	// mediator client command operation,
-	mediatorClientCmd, err := mediatorclientcmd.New(ctx, cmdOpts.msgHandler, notifier)
+	mediatorClientCmd, err := mediatorclientcmd.New(ctx, cmdOpts.msgHandler, notifier, cmdOpts.mediatorClientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -151,13 +190,13 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 
 	// DID Client REST operation.
 	didClientOp, err := didclient.New(ctx, restOpts.blocDomain, restOpts.didAnchorOrigin, restOpts.sidetreeToken,
-		restOpts.unanchoredDIDMaxLifeTime)
+		restOpts.unanchoredDIDMaxLifeTime, notifier, restOpts.didClientOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	// mediator client REST operation.
-	mediatorClientOp, err := mediatorclient.New(ctx, restOpts.msgHandler, notifier)
+	mediatorClientOp, err := mediatorclient.New(ctx, restOpts.msgHandler, notifier, restOpts.mediatorClientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/agent-sdk/pkg/controller"
+	"github.com/trustbloc/agent-sdk/pkg/controller/command/mediatorclient"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/mocks"
 )
 
@@ -60,7 +61,9 @@ func TestGetCommandHandlers(t *testing.T) {
 
 		handlers, err = controller.GetCommandHandlers(ctx, controller.WithBlocDomain("domain"), controller.WithMessageHandler(
 			mockmsghandler.NewMockMsgServiceProvider()), controller.WithNotifier(mocks.NewMockNotifier()),
-			controller.WithWebhookURLs("sample-wh-url"))
+			controller.WithWebhookURLs("sample-wh-url"),
+			controller.WithRouterSelector(mediatorclient.NewRoundRobinRouterSelector()),
+			controller.WithRouterSupervision(30, 1, 60))
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
 	})
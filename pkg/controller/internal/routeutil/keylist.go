@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package routeutil provides router keylist utilities.
+package routeutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	removeAction = "remove"
+
+	// routerKeysStore keeps recipient keys added to routers, mediator service doesn't keep them.
+	routerKeysStore = "routerkeys"
+
+	// droppedKeysPrefix prefixes records of recipient keys removed from routers.
+	droppedKeysPrefix = "dropped_"
+
+	didCommV2ServiceType = "DIDCommMessaging"
+)
+
+// keysMutex serializes updates of recorded router keys.
+var keysMutex sync.Mutex // nolint:gochecknoglobals
+
+// Provider describes dependencies for sending keylist updates to the router.
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+// AddKeyToRouter adds the recipient keys to the router of given connection and records them, so that they can be
+// added again if the agent has to register with the router again. Keys are not added if connection is not a router
+// connection.
+func AddKeyToRouter(p Provider, routeSvc mediatorservice.ProtocolService, connID string, recKeys ...string) error {
+	added := make([]string, 0, len(recKeys))
+
+	for _, key := range recKeys {
+		err := routeSvc.AddKey(connID, key)
+		if errors.Is(err, mediatorservice.ErrRouterNotRegistered) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("addKey: %w", err)
+		}
+
+		added = append(added, key)
+	}
+
+	return RecordKeys(p, connID, added...)
+}
+
+// RecordKeys records the recipient keys added to the router of given connection by other means, like keys of
+// out-of-band invitations added by the out-of-band client.
+func RecordKeys(p Provider, connID string, recKeys ...string) error {
+	return updateRouterKeys(p, connID, func(registered, dropped map[string]bool) {
+		for _, key := range recKeys {
+			registered[key] = true
+			delete(dropped, key)
+		}
+	})
+}
+
+// RemoveKeyFromRouter sends keylist update to the router of given connection for removing the recipient keys.
+// Mediator service doesn't track responses of remove requests, so the update is sent without waiting for response.
+func RemoveKeyFromRouter(p Provider, connID string, recKeys ...string) error {
+	err := sendKeylistUpdate(p, connID, removeAction, recKeys...)
+	if err != nil {
+		return err
+	}
+
+	return updateRouterKeys(p, connID, func(registered, dropped map[string]bool) {
+		for _, key := range recKeys {
+			delete(registered, key)
+			dropped[key] = true
+		}
+	})
+}
+
+// DIDRouterKeys returns keys of the DID document to be registered with the router of the DIDComm service:
+// recipient keys of DIDComm V1 service along with key agreement key IDs.
+func DIDRouterKeys(didDoc *did.Doc, didSvc *did.Service) []string {
+	var keys []string
+
+	if didSvc.Type != didCommV2ServiceType {
+		keys = append(keys, didSvc.RecipientKeys...)
+	}
+
+	for _, ka := range didDoc.KeyAgreement {
+		kaID := ka.VerificationMethod.ID
+		if strings.HasPrefix(kaID, "#") {
+			kaID = didDoc.ID + kaID
+		}
+
+		keys = append(keys, kaID)
+	}
+
+	return keys
+}
+
+// RegisteredKeys returns recipient keys added to the router of given connection.
+func RegisteredKeys(p Provider, connID string) ([]string, error) {
+	keysMutex.Lock()
+	defer keysMutex.Unlock()
+
+	store, err := p.StorageProvider().OpenStore(routerKeysStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open router keys store : %w", err)
+	}
+
+	return getRegisteredKeys(store, connID)
+}
+
+// DroppedKeys returns recipient keys removed from the router of given connection and not added again since.
+func DroppedKeys(p Provider, connID string) ([]string, error) {
+	keysMutex.Lock()
+	defer keysMutex.Unlock()
+
+	store, err := p.StorageProvider().OpenStore(routerKeysStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open router keys store : %w", err)
+	}
+
+	return getRegisteredKeys(store, droppedKeysPrefix+connID)
+}
+
+// ForgetRouter removes records of recipient keys added to and removed from the router of given connection.
+func ForgetRouter(p Provider, connID string) error {
+	keysMutex.Lock()
+	defer keysMutex.Unlock()
+
+	store, err := p.StorageProvider().OpenStore(routerKeysStore)
+	if err != nil {
+		return fmt.Errorf("failed to open router keys store : %w", err)
+	}
+
+	for _, key := range []string{connID, droppedKeysPrefix + connID} {
+		err = store.Delete(key)
+		if err != nil {
+			return fmt.Errorf("failed to delete router keys of connection %s : %w", connID, err)
+		}
+	}
+
+	return nil
+}
+
+func updateRouterKeys(p Provider, connID string, update func(registered, dropped map[string]bool)) error {
+	keysMutex.Lock()
+	defer keysMutex.Unlock()
+
+	store, err := p.StorageProvider().OpenStore(routerKeysStore)
+	if err != nil {
+		return fmt.Errorf("failed to open router keys store : %w", err)
+	}
+
+	records := []string{connID, droppedKeysPrefix + connID}
+	keys := make([]map[string]bool, len(records))
+
+	for i, record := range records {
+		recorded, e := getRegisteredKeys(store, record)
+		if e != nil {
+			return e
+		}
+
+		keys[i] = make(map[string]bool, len(recorded))
+		for _, key := range recorded {
+			keys[i][key] = true
+		}
+	}
+
+	update(keys[0], keys[1])
+
+	for i, record := range records {
+		err = putRegisteredKeys(store, record, keys[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func putRegisteredKeys(store storage.Store, record string, keys map[string]bool) error {
+	registered := make([]string, 0, len(keys))
+	for key := range keys {
+		registered = append(registered, key)
+	}
+
+	sort.Strings(registered)
+
+	data, err := json.Marshal(registered)
+	if err != nil {
+		return fmt.Errorf("failed to marshal router keys : %w", err)
+	}
+
+	err = store.Put(record, data)
+	if err != nil {
+		return fmt.Errorf("failed to save router keys of connection %s : %w", strings.TrimPrefix(record,
+			droppedKeysPrefix), err)
+	}
+
+	return nil
+}
+
+func getRegisteredKeys(store storage.Store, connID string) ([]string, error) {
+	data, err := store.Get(connID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router keys of connection %s : %w", connID, err)
+	}
+
+	var keys []string
+
+	err = json.Unmarshal(data, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal router keys : %w", err)
+	}
+
+	return keys, nil
+}
+
+func sendKeylistUpdate(p Provider, connID, action string, recKeys ...string) error {
+	if len(recKeys) == 0 {
+		return nil
+	}
+
+	lookup, err := connection.NewLookup(p)
+	if err != nil {
+		return fmt.Errorf("failed to create connection lookup : %w", err)
+	}
+
+	conn, err := lookup.GetConnectionRecord(connID)
+	if err != nil {
+		return fmt.Errorf("failed to get router connection %s : %w", connID, err)
+	}
+
+	updates := make([]mediatorservice.Update, len(recKeys))
+	for i, key := range recKeys {
+		updates[i] = mediatorservice.Update{RecipientKey: key, Action: action}
+	}
+
+	keyUpdate := &mediatorservice.KeylistUpdate{
+		ID:      uuid.New().String(),
+		Type:    mediatorservice.KeylistUpdateMsgType,
+		Updates: updates,
+	}
+
+	err = p.Messenger().Send(service.NewDIDCommMsgMap(keyUpdate), conn.MyDID, conn.TheirDID)
+	if err != nil {
+		return fmt.Errorf("failed to send keylist update to router %s : %w", connID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package routeutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mediatorservice "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	mockprotocol "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockservice "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/service"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/agent-sdk/pkg/controller/internal/routeutil"
+)
+
+func TestAddKeyToRouter(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		var added []string
+
+		p := newProvider(t, &recordingMessenger{})
+		routeSvc := &mockroute.MockMediatorSvc{AddKeyFunc: func(key string) error {
+			added = append(added, key)
+
+			return nil
+		}}
+
+		require.NoError(t, routeutil.AddKeyToRouter(p, routeSvc, "conn1", "key2", "key1"))
+		require.NoError(t, routeutil.AddKeyToRouter(p, routeSvc, "conn1", "key1"))
+		require.Equal(t, []string{"key2", "key1", "key1"}, added)
+
+		keys, err := routeutil.RegisteredKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1", "key2"}, keys)
+
+		keys, err = routeutil.RegisteredKeys(p, "conn2")
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("test router not registered", func(t *testing.T) {
+		p := newProvider(t, &recordingMessenger{})
+		routeSvc := &mockroute.MockMediatorSvc{AddKeyErr: mediatorservice.ErrRouterNotRegistered}
+
+		require.NoError(t, routeutil.AddKeyToRouter(p, routeSvc, "conn1", "key1"))
+
+		keys, err := routeutil.RegisteredKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("test add key error", func(t *testing.T) {
+		p := newProvider(t, &recordingMessenger{})
+		routeSvc := &mockroute.MockMediatorSvc{AddKeyErr: fmt.Errorf("add key error")}
+
+		err := routeutil.AddKeyToRouter(p, routeSvc, "conn1", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "add key error")
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		p := &provider{MockProvider: mockprotocol.MockProvider{
+			StoreProvider: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: fmt.Errorf("store error")},
+		}}
+
+		err := routeutil.AddKeyToRouter(p, &mockroute.MockMediatorSvc{}, "conn1", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store error")
+
+		_, err = routeutil.RegisteredKeys(p, "conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store error")
+
+		err = routeutil.ForgetRouter(p, "conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store error")
+	})
+}
+
+func TestForgetRouter(t *testing.T) {
+	p := newProvider(t, &recordingMessenger{})
+
+	require.NoError(t, routeutil.AddKeyToRouter(p, &mockroute.MockMediatorSvc{}, "conn1", "key1", "key2"))
+	require.NoError(t, routeutil.RemoveKeyFromRouter(p, "conn1", "key2"))
+	require.NoError(t, routeutil.ForgetRouter(p, "conn1"))
+
+	keys, err := routeutil.RegisteredKeys(p, "conn1")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	keys, err = routeutil.DroppedKeys(p, "conn1")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestRemoveKeyFromRouter(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		messenger := &recordingMessenger{}
+		p := newProvider(t, messenger)
+
+		require.NoError(t, routeutil.AddKeyToRouter(p, &mockroute.MockMediatorSvc{}, "conn1", "key1", "key3"))
+		require.NoError(t, routeutil.RemoveKeyFromRouter(p, "conn1", "key1", "key2"))
+		require.Len(t, messenger.sent, 1)
+		require.Equal(t, "did:example:me", messenger.myDID)
+		require.Equal(t, "did:example:router", messenger.theirDID)
+
+		updates, ok := messenger.sent[0]["updates"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, updates, 2)
+		require.Equal(t, "remove", updates[0].(map[string]interface{})["action"])
+
+		keys, err := routeutil.RegisteredKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key3"}, keys)
+
+		keys, err = routeutil.DroppedKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1", "key2"}, keys)
+
+		// keys recorded again aren't dropped anymore.
+		require.NoError(t, routeutil.RecordKeys(p, "conn1", "key2"))
+
+		keys, err = routeutil.DroppedKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key1"}, keys)
+
+		keys, err = routeutil.RegisteredKeys(p, "conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key2", "key3"}, keys)
+	})
+
+	t.Run("test no keys", func(t *testing.T) {
+		messenger := &recordingMessenger{}
+
+		require.NoError(t, routeutil.RemoveKeyFromRouter(newProvider(t, messenger), "conn1"))
+		require.Empty(t, messenger.sent)
+	})
+
+	t.Run("test connection not found", func(t *testing.T) {
+		err := routeutil.RemoveKeyFromRouter(newProvider(t, &recordingMessenger{}), "conn2", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get router connection conn2")
+	})
+
+	t.Run("test send error", func(t *testing.T) {
+		messenger := &recordingMessenger{MockMessenger: mockservice.MockMessenger{ErrSend: fmt.Errorf("send error")}}
+
+		err := routeutil.RemoveKeyFromRouter(newProvider(t, messenger), "conn1", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send error")
+	})
+
+	t.Run("test store error", func(t *testing.T) {
+		p := &provider{MockProvider: mockprotocol.MockProvider{
+			StoreProvider: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: fmt.Errorf("store error")},
+		}}
+
+		err := routeutil.RemoveKeyFromRouter(p, "conn1", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store error")
+	})
+}
+
+func newProvider(t *testing.T, messenger *recordingMessenger) *provider {
+	t.Helper()
+
+	p := &provider{
+		MockProvider: mockprotocol.MockProvider{
+			StoreProvider:              mockstorage.NewMockStoreProvider(),
+			ProtocolStateStoreProvider: mockstorage.NewMockStoreProvider(),
+		},
+		messenger: messenger,
+	}
+
+	recorder, err := connection.NewRecorder(p)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "conn1",
+		State:        connection.StateNameCompleted,
+		MyDID:        "did:example:me",
+		TheirDID:     "did:example:router",
+	}))
+
+	return p
+}
+
+type provider struct {
+	mockprotocol.MockProvider
+	messenger *recordingMessenger
+}
+
+func (p *provider) Messenger() service.Messenger {
+	return p.messenger
+}
+
+type recordingMessenger struct {
+	mockservice.MockMessenger
+	sent     []service.DIDCommMsgMap
+	myDID    string
+	theirDID string
+}
+
+func (m *recordingMessenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string, _ ...service.Opt) error {
+	if m.ErrSend != nil {
+		return m.ErrSend
+	}
+
+	m.sent = append(m.sent, msg)
+	m.myDID = myDID
+	m.theirDID = theirDID
+
+	return nil
+}
@@ -31,7 +31,7 @@ type createOrbDIDRequest struct { // nolint: unused,deadcode
 //
 // swagger:parameters resolveOrbDID
 type resolveOrbDIDRequest struct { // nolint: unused,deadcode
-	// Params for resolving Orb DID.
+	// Params for resolving Orb DID, optionally at a version or a point in time.
 	//
 	// in: body
 	// required: true
@@ -51,6 +51,19 @@ type createPeerDIDRequest struct { // nolint: unused,deadcode
 	Request didclient.CreatePeerDIDRequest
 }
 
+// updateOrbDIDRequest model
+//
+// Request to update an orb DID.
+//
+// swagger:parameters updateOrbDID
+type updateOrbDIDRequest struct { // nolint: unused,deadcode
+	// Params for updating Orb DID.
+	//
+	// in: body
+	// required: true
+	Request didclient.UpdateOrbDIDRequest
+}
+
 // createDIDResp model
 //
 // This is used as the response model for create TrustBloc/ DID operations.
@@ -70,3 +83,478 @@ type resolveDIDResp struct { // nolint: unused,deadcode
 	// in: body
 	Response *did.DocResolution
 }
+
+// updateDIDResp model
+//
+// This is used as the response model for update Orb DID operations.
+//
+// swagger:response updateDIDResp
+type updateDIDResp struct{} // nolint: unused,deadcode
+
+// recoverOrbDIDRequest model
+//
+// Request to recover an orb DID.
+//
+// swagger:parameters recoverOrbDID
+type recoverOrbDIDRequest struct { // nolint: unused,deadcode
+	// Params for recovering Orb DID.
+	//
+	// in: body
+	// required: true
+	Request didclient.RecoverOrbDIDRequest
+}
+
+// recoverDIDResp model
+//
+// This is used as the response model for recoverOrbDID operation.
+//
+// swagger:response recoverDIDResp
+type recoverDIDResp struct{} // nolint: unused,deadcode
+
+// deactivateOrbDIDRequest model
+//
+// Request to deactivate an orb DID.
+//
+// swagger:parameters deactivateOrbDID
+type deactivateOrbDIDRequest struct { // nolint: unused,deadcode
+	// Params for deactivating Orb DID.
+	//
+	// in: body
+	// required: true
+	Request didclient.DeactivateOrbDIDRequest
+}
+
+// deactivateDIDResp model
+//
+// This is used as the response model for deactivateOrbDID operation.
+//
+// swagger:response deactivateDIDResp
+type deactivateDIDResp struct{} // nolint: unused,deadcode
+
+// resolveDIDRequest model
+//
+// Request to resolve DID of any supported method.
+//
+// swagger:parameters resolveDID
+type resolveDIDRequest struct { // nolint: unused,deadcode
+	// Params for resolving DID.
+	//
+	// in: body
+	// required: true
+	Request didclient.ResolveDIDRequest
+}
+
+// resolveDIDResultResp model
+//
+// This is used as the response model for resolveDID operation.
+//
+// swagger:response resolveDIDResultResp
+type resolveDIDResultResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.ResolveDIDResponse
+}
+
+// getOrbDIDStatusRequest model
+//
+// Request to get publication status of orb DID.
+//
+// swagger:parameters getOrbDIDStatus
+type getOrbDIDStatusRequest struct { // nolint: unused,deadcode
+	// Params for getting orb DID status.
+	//
+	// in: body
+	// required: true
+	Request didclient.GetOrbDIDStatusRequest
+}
+
+// orbDIDStatusResp model
+//
+// This is used as the response model for getOrbDIDStatus operation.
+//
+// swagger:response orbDIDStatusResp
+type orbDIDStatusResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.OrbDIDStatus
+}
+
+// createDIDRequest model
+//
+// Params for creating DID.
+//
+// swagger:parameters createDID
+type createDIDRequest struct { // nolint: unused,deadcode
+	// Params for creating DID of key, jwk or web method with keys created in the agent KMS.
+	//
+	// in: body
+	// required: true
+	Request didclient.CreateDIDRequest
+}
+
+// createDIDResultResp model
+//
+// This is used as the response model for createDID operation.
+//
+// swagger:response createDIDResultResp
+type createDIDResultResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.CreateDIDResponse
+}
+
+// invalidateDIDCacheRequest model
+//
+// Params for invalidating DID resolution cache.
+//
+// swagger:parameters invalidateDIDCache
+type invalidateDIDCacheRequest struct { // nolint: unused,deadcode
+	// Params for removing cached resolutions of DID, the whole cache is cleared if DID is empty.
+	//
+	// in: body
+	// required: true
+	Request didclient.InvalidateDIDCacheRequest
+}
+
+// invalidateDIDCacheResp model
+//
+// This is used as the response model for invalidateDIDCache operation.
+//
+// swagger:response invalidateDIDCacheResp
+type invalidateDIDCacheResp struct{} // nolint: unused,deadcode
+
+// listCachedDIDsResp model
+//
+// This is used as the response model for listCachedDIDs operation.
+//
+// swagger:response listCachedDIDsResp
+type listCachedDIDsResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.ListCachedDIDsResponse
+}
+
+// rotatePeerDIDKeysRequest model
+//
+// Params for rotating peer DID keys.
+//
+// swagger:parameters rotatePeerDIDKeys
+type rotatePeerDIDKeysRequest struct { // nolint: unused,deadcode
+	// Params for rotating keys of peer DID, key and service types are the same as for creating peer DID.
+	//
+	// in: body
+	// required: true
+	Request didclient.RotatePeerDIDKeysRequest
+}
+
+// rotatePeerDIDKeysResultResp model
+//
+// This is used as the response model for rotatePeerDIDKeys operation.
+//
+// swagger:response rotatePeerDIDKeysResultResp
+type rotatePeerDIDKeysResultResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.RotatePeerDIDKeysResponse
+}
+
+// createDIDConfigurationRequest model
+//
+// Params for creating DID configuration.
+//
+// swagger:parameters createDIDConfiguration
+type createDIDConfigurationRequest struct { // nolint: unused,deadcode
+	// Params for creating DID configuration (/.well-known/did-configuration.json) linking DID to origin.
+	//
+	// in: body
+	// required: true
+	Request didclient.CreateDIDConfigurationRequest
+}
+
+// createDIDConfigurationResultResp model
+//
+// This is used as the response model for createDIDConfiguration operation.
+//
+// swagger:response createDIDConfigurationResultResp
+type createDIDConfigurationResultResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.CreateDIDConfigurationResponse
+}
+
+// verifyDIDConfigurationRequest model
+//
+// Params for verifying DID configuration.
+//
+// swagger:parameters verifyDIDConfiguration
+type verifyDIDConfigurationRequest struct { // nolint: unused,deadcode
+	// Params for verifying DID configuration, it is fetched from origin if not given.
+	//
+	// in: body
+	// required: true
+	Request didclient.VerifyDIDConfigurationRequest
+}
+
+// verifyDIDConfigurationResultResp model
+//
+// This is used as the response model for verifyDIDConfiguration operation.
+//
+// swagger:response verifyDIDConfigurationResultResp
+type verifyDIDConfigurationResultResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.VerifyDIDConfigurationResponse
+}
+
+// getOrbDIDHistoryRequest model
+//
+// Request to get operation history of orb DID.
+//
+// swagger:parameters getOrbDIDHistory
+type getOrbDIDHistoryRequest struct { // nolint: unused,deadcode
+	// Params for getting orb DID history.
+	//
+	// in: body
+	// required: true
+	Request didclient.GetOrbDIDHistoryRequest
+}
+
+// orbDIDHistoryResp model
+//
+// This is used as the response model for getOrbDIDHistory operation.
+//
+// swagger:response orbDIDHistoryResp
+type orbDIDHistoryResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.GetOrbDIDHistoryResponse
+}
+
+// listPendingDIDOperationsResp model
+//
+// This is used as the response model for listPendingDIDOperations operation.
+//
+// swagger:response listPendingDIDOperationsResp
+type listPendingDIDOperationsResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.ListPendingDIDOperationsResponse
+}
+
+// cancelDIDOperationRequest model
+//
+// Params for cancelling pending orb DID operation.
+//
+// swagger:parameters cancelDIDOperation
+type cancelDIDOperationRequest struct { // nolint: unused,deadcode
+	// The ID of pending operation
+	//
+	// in: body
+	// required: true
+	Request didclient.CancelPendingDIDOperationRequest
+}
+
+// cancelPendingDIDOperationResp model
+//
+// This is used as the response model for cancelDIDOperation operation.
+//
+// swagger:response cancelPendingDIDOperationResp
+type cancelPendingDIDOperationResp struct{} // nolint: unused,deadcode
+
+// createDIDBatchRequest model
+//
+// Params for creating DIDs in batch.
+//
+// swagger:parameters createDIDBatch
+type createDIDBatchRequest struct { // nolint: unused,deadcode
+	// The create requests of DIDs and batch options
+	//
+	// in: body
+	// required: true
+	Request didclient.CreateDIDBatchRequest
+}
+
+// createDIDBatchResp model
+//
+// This is used as the response model for createDIDBatch operation.
+//
+// swagger:response createDIDBatchResp
+type createDIDBatchResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.CreateDIDBatchResponse
+}
+
+// validateOrbDIDRequest model
+//
+// Params for validating orb DID create request.
+//
+// swagger:parameters validateOrbDIDRequest
+type validateOrbDIDRequest struct { // nolint: unused,deadcode
+	// The create request of orb DID
+	//
+	// in: body
+	// required: true
+	Request didclient.CreateOrbDIDRequest
+}
+
+// validateOrbDIDResp model
+//
+// This is used as the response model for validateOrbDIDRequest operation.
+//
+// swagger:response validateOrbDIDResp
+type validateOrbDIDResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.ValidateOrbDIDResponse
+}
+
+// signJWTRequest model
+//
+// Params for signing JWT.
+//
+// swagger:parameters signJWT
+type signJWTRequest struct { // nolint: unused,deadcode
+	// The sign JWT request
+	//
+	// in: body
+	// required: true
+	Request didclient.SignJWTRequest
+}
+
+// signJWTResp model
+//
+// This is used as the response model for signJWT operation.
+//
+// swagger:response signJWTResp
+type signJWTResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.SignJWTResponse
+}
+
+// verifyJWTRequest model
+//
+// Params for verifying JWT.
+//
+// swagger:parameters verifyJWT
+type verifyJWTRequest struct { // nolint: unused,deadcode
+	// The verify JWT request
+	//
+	// in: body
+	// required: true
+	Request didclient.VerifyJWTRequest
+}
+
+// verifyJWTResp model
+//
+// This is used as the response model for verifyJWT operation.
+//
+// swagger:response verifyJWTResp
+type verifyJWTResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.VerifyJWTResponse
+}
+
+// signJWSRequest model
+//
+// Params for signing JWS.
+//
+// swagger:parameters signJWS
+type signJWSRequest struct { // nolint: unused,deadcode
+	// The sign JWS request
+	//
+	// in: body
+	// required: true
+	Request didclient.SignJWSRequest
+}
+
+// signJWSResp model
+//
+// This is used as the response model for signJWS operation.
+//
+// swagger:response signJWSResp
+type signJWSResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.SignJWSResponse
+}
+
+// verifyJWSRequest model
+//
+// Params for verifying JWS.
+//
+// swagger:parameters verifyJWS
+type verifyJWSRequest struct { // nolint: unused,deadcode
+	// The verify JWS request
+	//
+	// in: body
+	// required: true
+	Request didclient.VerifyJWSRequest
+}
+
+// verifyJWSResp model
+//
+// This is used as the response model for verifyJWS operation.
+//
+// swagger:response verifyJWSResp
+type verifyJWSResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.VerifyJWSResponse
+}
+
+// createDIDAuthChallengeRequest model
+//
+// Params for creating DID Auth challenge.
+//
+// swagger:parameters createDIDAuthChallenge
+type createDIDAuthChallengeRequest struct { // nolint: unused,deadcode
+	// The create DID Auth challenge request
+	//
+	// in: body
+	// required: true
+	Request didclient.CreateDIDAuthChallengeRequest
+}
+
+// createDIDAuthChallengeResp model
+//
+// This is used as the response model for createDIDAuthChallenge operation.
+//
+// swagger:response createDIDAuthChallengeResp
+type createDIDAuthChallengeResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.CreateDIDAuthChallengeResponse
+}
+
+// respondDIDAuthChallengeRequest model
+//
+// Params for answering DID Auth challenge.
+//
+// swagger:parameters respondDIDAuthChallenge
+type respondDIDAuthChallengeRequest struct { // nolint: unused,deadcode
+	// The respond DID Auth challenge request
+	//
+	// in: body
+	// required: true
+	Request didclient.RespondDIDAuthChallengeRequest
+}
+
+// respondDIDAuthChallengeResp model
+//
+// This is used as the response model for respondDIDAuthChallenge operation.
+//
+// swagger:response respondDIDAuthChallengeResp
+type respondDIDAuthChallengeResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.RespondDIDAuthChallengeResponse
+}
+
+// verifyDIDAuthResponseRequest model
+//
+// Params for verifying DID Auth response.
+//
+// swagger:parameters verifyDIDAuthResponse
+type verifyDIDAuthResponseRequest struct { // nolint: unused,deadcode
+	// The verify DID Auth response request
+	//
+	// in: body
+	// required: true
+	Request didclient.VerifyDIDAuthResponseRequest
+}
+
+// verifyDIDAuthResponseResp model
+//
+// This is used as the response model for verifyDIDAuthResponse operation.
+//
+// swagger:response verifyDIDAuthResponseResp
+type verifyDIDAuthResponseResp struct { // nolint: unused,deadcode
+	// in: body
+	Response *didclient.VerifyDIDAuthResponseResponse
+}
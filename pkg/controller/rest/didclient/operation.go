@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"net/http"
 
+	ariescmd "github.com/hyperledger/aries-framework-go/pkg/controller/command"
+
 	"github.com/trustbloc/agent-sdk/pkg/controller/command/didclient"
 	"github.com/trustbloc/agent-sdk/pkg/controller/internal/cmdutil"
 	"github.com/trustbloc/agent-sdk/pkg/controller/rest"
@@ -18,10 +20,33 @@ import (
 
 // constants for endpoints of DIDClient.
 const (
-	OperationID       = "/didclient"
-	CreateOrbDIDPath  = OperationID + "/create-orb-did"
-	CreatePeerDIDPath = OperationID + "/create-peer-did"
-	ResolveOrbDIDPath = OperationID + "/resolve-orb-did"
+	OperationID                   = "/didclient"
+	CreateOrbDIDPath              = OperationID + "/create-orb-did"
+	CreatePeerDIDPath             = OperationID + "/create-peer-did"
+	ResolveOrbDIDPath             = OperationID + "/resolve-orb-did"
+	UpdateOrbDIDPath              = OperationID + "/update-orb-did"
+	RecoverOrbDIDPath             = OperationID + "/recover-orb-did"
+	DeactivateOrbDIDPath          = OperationID + "/deactivate-orb-did"
+	ResolveDIDPath                = OperationID + "/resolve-did"
+	GetOrbDIDStatusPath           = OperationID + "/get-orb-did-status"
+	CreateDIDPath                 = OperationID + "/create-did"
+	InvalidateDIDCachePath        = OperationID + "/invalidate-did-cache"
+	ListCachedDIDsPath            = OperationID + "/list-cached-dids"
+	RotatePeerDIDKeysPath         = OperationID + "/rotate-peer-did-keys"
+	CreateDIDConfigurationPath    = OperationID + "/create-did-configuration"
+	VerifyDIDConfigurationPath    = OperationID + "/verify-did-configuration"
+	GetOrbDIDHistoryPath          = OperationID + "/get-orb-did-history"
+	ListPendingDIDOperationsPath  = OperationID + "/list-pending-did-operations"
+	CancelPendingDIDOperationPath = OperationID + "/cancel-pending-did-operation"
+	CreateDIDBatchPath            = OperationID + "/create-did-batch"
+	ValidateOrbDIDRequestPath     = OperationID + "/validate-orb-did-request"
+	SignJWTPath                   = OperationID + "/sign-jwt"
+	VerifyJWTPath                 = OperationID + "/verify-jwt"
+	SignJWSPath                   = OperationID + "/sign-jws"
+	VerifyJWSPath                 = OperationID + "/verify-jws"
+	CreateDIDAuthChallengePath    = OperationID + "/create-did-auth-challenge"
+	RespondDIDAuthChallengePath   = OperationID + "/respond-did-auth-challenge"
+	VerifyDIDAuthResponsePath     = OperationID + "/verify-did-auth-response"
 )
 
 // Operation is controller REST service controller for DID Client.
@@ -32,8 +57,8 @@ type Operation struct {
 
 // New returns new DID client rest instance.
 func New(ctx didclient.Provider, domain, didAnchorOrigin, token string,
-	unanchoredDIDMaxLifeTime int) (*Operation, error) {
-	client, err := didclient.New(domain, didAnchorOrigin, token, unanchoredDIDMaxLifeTime, ctx)
+	unanchoredDIDMaxLifeTime int, notifier ariescmd.Notifier, opts ...didclient.Option) (*Operation, error) {
+	client, err := didclient.New(domain, didAnchorOrigin, token, unanchoredDIDMaxLifeTime, ctx, notifier, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize did-client command: %w", err)
 	}
@@ -49,6 +74,11 @@ func (c *Operation) GetRESTHandlers() []rest.Handler {
 	return c.handlers
 }
 
+// Close stops tracking publication of orb DIDs.
+func (c *Operation) Close() {
+	c.command.Close()
+}
+
 // registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
 func (c *Operation) registerHandler() {
 	// Add more protocol endpoints here to expose them as controller API endpoints
@@ -56,6 +86,29 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(CreateOrbDIDPath, http.MethodPost, c.CreateOrbDID),
 		cmdutil.NewHTTPHandler(CreatePeerDIDPath, http.MethodPost, c.CreatePeerDID),
 		cmdutil.NewHTTPHandler(ResolveOrbDIDPath, http.MethodPost, c.ResolveOrbDID),
+		cmdutil.NewHTTPHandler(UpdateOrbDIDPath, http.MethodPost, c.UpdateOrbDID),
+		cmdutil.NewHTTPHandler(RecoverOrbDIDPath, http.MethodPost, c.RecoverOrbDID),
+		cmdutil.NewHTTPHandler(DeactivateOrbDIDPath, http.MethodPost, c.DeactivateOrbDID),
+		cmdutil.NewHTTPHandler(ResolveDIDPath, http.MethodPost, c.ResolveDID),
+		cmdutil.NewHTTPHandler(GetOrbDIDStatusPath, http.MethodPost, c.GetOrbDIDStatus),
+		cmdutil.NewHTTPHandler(CreateDIDPath, http.MethodPost, c.CreateDID),
+		cmdutil.NewHTTPHandler(InvalidateDIDCachePath, http.MethodPost, c.InvalidateDIDCache),
+		cmdutil.NewHTTPHandler(ListCachedDIDsPath, http.MethodPost, c.ListCachedDIDs),
+		cmdutil.NewHTTPHandler(RotatePeerDIDKeysPath, http.MethodPost, c.RotatePeerDIDKeys),
+		cmdutil.NewHTTPHandler(CreateDIDConfigurationPath, http.MethodPost, c.CreateDIDConfiguration),
+		cmdutil.NewHTTPHandler(VerifyDIDConfigurationPath, http.MethodPost, c.VerifyDIDConfiguration),
+		cmdutil.NewHTTPHandler(GetOrbDIDHistoryPath, http.MethodPost, c.GetOrbDIDHistory),
+		cmdutil.NewHTTPHandler(ListPendingDIDOperationsPath, http.MethodPost, c.ListPendingDIDOperations),
+		cmdutil.NewHTTPHandler(CancelPendingDIDOperationPath, http.MethodPost, c.CancelPendingDIDOperation),
+		cmdutil.NewHTTPHandler(CreateDIDBatchPath, http.MethodPost, c.CreateDIDBatch),
+		cmdutil.NewHTTPHandler(ValidateOrbDIDRequestPath, http.MethodPost, c.ValidateOrbDIDRequest),
+		cmdutil.NewHTTPHandler(SignJWTPath, http.MethodPost, c.SignJWT),
+		cmdutil.NewHTTPHandler(VerifyJWTPath, http.MethodPost, c.VerifyJWT),
+		cmdutil.NewHTTPHandler(SignJWSPath, http.MethodPost, c.SignJWS),
+		cmdutil.NewHTTPHandler(VerifyJWSPath, http.MethodPost, c.VerifyJWS),
+		cmdutil.NewHTTPHandler(CreateDIDAuthChallengePath, http.MethodPost, c.CreateDIDAuthChallenge),
+		cmdutil.NewHTTPHandler(RespondDIDAuthChallengePath, http.MethodPost, c.RespondDIDAuthChallenge),
+		cmdutil.NewHTTPHandler(VerifyDIDAuthResponsePath, http.MethodPost, c.VerifyDIDAuthResponse),
 	}
 }
 
@@ -91,3 +144,256 @@ func (c *Operation) ResolveOrbDID(rw http.ResponseWriter, req *http.Request) {
 func (c *Operation) CreatePeerDID(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(c.command.CreatePeerDID, rw, req.Body)
 }
+
+// UpdateOrbDID swagger:route POST /didclient/update-orb-did didclient updateOrbDID
+//
+// Updates keys and services of an orb DID.
+//
+// Responses:
+//    default: genericError
+//    200: updateDIDResp
+func (c *Operation) UpdateOrbDID(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.UpdateOrbDID, rw, req.Body)
+}
+
+// RecoverOrbDID swagger:route POST /didclient/recover-orb-did didclient recoverOrbDID
+//
+// Recovers an orb DID using its recovery key.
+//
+// Responses:
+//    default: genericError
+//    200: recoverDIDResp
+func (c *Operation) RecoverOrbDID(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RecoverOrbDID, rw, req.Body)
+}
+
+// DeactivateOrbDID swagger:route POST /didclient/deactivate-orb-did didclient deactivateOrbDID
+//
+// Deactivates an orb DID.
+//
+// Responses:
+//    default: genericError
+//    200: deactivateDIDResp
+func (c *Operation) DeactivateOrbDID(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeactivateOrbDID, rw, req.Body)
+}
+
+// ResolveDID swagger:route POST /didclient/resolve-did didclient resolveDID
+//
+// Resolves DID of any method supported by the agent.
+//
+// Responses:
+//    default: genericError
+//    200: resolveDIDResultResp
+func (c *Operation) ResolveDID(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ResolveDID, rw, req.Body)
+}
+
+// GetOrbDIDStatus swagger:route POST /didclient/get-orb-did-status didclient getOrbDIDStatus
+//
+// Returns publication status of orb DID along with its canonical and equivalent IDs once anchored.
+//
+// Responses:
+//    default: genericError
+//    200: orbDIDStatusResp
+func (c *Operation) GetOrbDIDStatus(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetOrbDIDStatus, rw, req.Body)
+}
+
+// CreateDID swagger:route POST /didclient/create-did didclient createDID
+//
+// Creates a new did:key, did:jwk or did:web DID.
+//
+// Responses:
+//    default: genericError
+//    200: createDIDResultResp
+func (c *Operation) CreateDID(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CreateDID, rw, req.Body)
+}
+
+// InvalidateDIDCache swagger:route POST /didclient/invalidate-did-cache didclient invalidateDIDCache
+//
+// Removes cached resolutions of DID or the whole DID resolution cache.
+//
+// Responses:
+//    default: genericError
+//    200: invalidateDIDCacheResp
+func (c *Operation) InvalidateDIDCache(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.InvalidateDIDCache, rw, req.Body)
+}
+
+// ListCachedDIDs swagger:route POST /didclient/list-cached-dids didclient listCachedDIDs
+//
+// Lists DIDs whose resolutions are cached.
+//
+// Responses:
+//    default: genericError
+//    200: listCachedDIDsResp
+func (c *Operation) ListCachedDIDs(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ListCachedDIDs, rw, req.Body)
+}
+
+// RotatePeerDIDKeys swagger:route POST /didclient/rotate-peer-did-keys didclient rotatePeerDIDKeys
+//
+// Rotates keys of peer DID by replacing it with a new peer DID and moving its router registrations and connections.
+//
+// Responses:
+//    default: genericError
+//    200: rotatePeerDIDKeysResultResp
+func (c *Operation) RotatePeerDIDKeys(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RotatePeerDIDKeys, rw, req.Body)
+}
+
+// CreateDIDConfiguration swagger:route POST /didclient/create-did-configuration didclient createDIDConfiguration
+//
+// Creates domain linkage credential and DID configuration of DID for origin.
+//
+// Responses:
+//    default: genericError
+//    200: createDIDConfigurationResultResp
+func (c *Operation) CreateDIDConfiguration(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CreateDIDConfiguration, rw, req.Body)
+}
+
+// VerifyDIDConfiguration swagger:route POST /didclient/verify-did-configuration didclient verifyDIDConfiguration
+//
+// Verifies DID configuration of origin against DID and its LinkedDomains service.
+//
+// Responses:
+//    default: genericError
+//    200: verifyDIDConfigurationResultResp
+func (c *Operation) VerifyDIDConfiguration(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.VerifyDIDConfiguration, rw, req.Body)
+}
+
+// GetOrbDIDHistory swagger:route POST /didclient/get-orb-did-history didclient getOrbDIDHistory
+//
+// Returns Sidetree operations of orb DID in the order they were applied, along with the document after each of them.
+//
+// Responses:
+//    default: genericError
+//    200: orbDIDHistoryResp
+func (c *Operation) GetOrbDIDHistory(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetOrbDIDHistory, rw, req.Body)
+}
+
+// ListPendingDIDOperations swagger:route POST /didclient/list-pending-did-operations didclient listPendingDIDOperations
+//
+// Lists orb DID operations waiting to be submitted.
+//
+// Responses:
+//    default: genericError
+//    200: listPendingDIDOperationsResp
+func (c *Operation) ListPendingDIDOperations(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ListPendingDIDOperations, rw, req.Body)
+}
+
+// CancelPendingDIDOperation swagger:route POST /didclient/cancel-pending-did-operation didclient cancelDIDOperation
+//
+// Removes orb DID operation from the queue of operations waiting to be submitted.
+//
+// Responses:
+//    default: genericError
+//    200: cancelPendingDIDOperationResp
+func (c *Operation) CancelPendingDIDOperation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CancelPendingDIDOperation, rw, req.Body)
+}
+
+// CreateDIDBatch swagger:route POST /didclient/create-did-batch didclient createDIDBatch
+//
+// Creates orb and peer DIDs in batch and reports the result of each of them.
+//
+// Responses:
+//    default: genericError
+//    200: createDIDBatchResp
+func (c *Operation) CreateDIDBatch(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CreateDIDBatch, rw, req.Body)
+}
+
+// ValidateOrbDIDRequest swagger:route POST /didclient/validate-orb-did-request didclient validateOrbDIDRequest
+//
+// Validates orb DID create request without creating the DID.
+//
+// Responses:
+//    default: genericError
+//    200: validateOrbDIDResp
+func (c *Operation) ValidateOrbDIDRequest(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ValidateOrbDIDRequest, rw, req.Body)
+}
+
+// SignJWT swagger:route POST /didclient/sign-jwt didclient signJWT
+//
+// Signs JWT claims with a key of DID.
+//
+// Responses:
+//    default: genericError
+//    200: signJWTResp
+func (c *Operation) SignJWT(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SignJWT, rw, req.Body)
+}
+
+// VerifyJWT swagger:route POST /didclient/verify-jwt didclient verifyJWT
+//
+// Verifies JWT signed by a key of DID.
+//
+// Responses:
+//    default: genericError
+//    200: verifyJWTResp
+func (c *Operation) VerifyJWT(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.VerifyJWT, rw, req.Body)
+}
+
+// SignJWS swagger:route POST /didclient/sign-jws didclient signJWS
+//
+// Signs payload with a key of DID into compact JWS.
+//
+// Responses:
+//    default: genericError
+//    200: signJWSResp
+func (c *Operation) SignJWS(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SignJWS, rw, req.Body)
+}
+
+// VerifyJWS swagger:route POST /didclient/verify-jws didclient verifyJWS
+//
+// Verifies compact JWS signed by a key of DID.
+//
+// Responses:
+//    default: genericError
+//    200: verifyJWSResp
+func (c *Operation) VerifyJWS(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.VerifyJWS, rw, req.Body)
+}
+
+// CreateDIDAuthChallenge swagger:route POST /didclient/create-did-auth-challenge didclient createDIDAuthChallenge
+//
+// Creates DID Auth challenge for the domain of relying party.
+//
+// Responses:
+//    default: genericError
+//    200: createDIDAuthChallengeResp
+func (c *Operation) CreateDIDAuthChallenge(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CreateDIDAuthChallenge, rw, req.Body)
+}
+
+// RespondDIDAuthChallenge swagger:route POST /didclient/respond-did-auth-challenge didclient respondDIDAuthChallenge
+//
+// Answers DID Auth challenge with Verifiable Presentation signed by an authentication key of DID.
+//
+// Responses:
+//    default: genericError
+//    200: respondDIDAuthChallengeResp
+func (c *Operation) RespondDIDAuthChallenge(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RespondDIDAuthChallenge, rw, req.Body)
+}
+
+// VerifyDIDAuthResponse swagger:route POST /didclient/verify-did-auth-response didclient verifyDIDAuthResponse
+//
+// Verifies DID Auth response to a challenge created by the agent.
+//
+// Responses:
+//    default: genericError
+//    200: verifyDIDAuthResponseResp
+func (c *Operation) VerifyDIDAuthResponse(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.VerifyDIDAuthResponse, rw, req.Body)
+}
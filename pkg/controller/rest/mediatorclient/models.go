@@ -78,3 +78,236 @@ type createConnectionResponse struct { // nolint: unused,deadcode
 	// in: body
 	Response mediatorclient.CreateConnectionResponse
 }
+
+// getConnectionsResponse model
+//
+// Response of listing router connections of the agent.
+//
+// swagger:response getConnectionsResponse
+type getConnectionsResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.GetConnectionsResponse
+}
+
+// disconnectRequest model
+//
+// Request for disconnecting agent from a router.
+//
+// swagger:parameters disconnectMediator
+type disconnectRequest struct { // nolint: unused,deadcode
+	// Params for disconnecting from router.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.DisconnectRequest
+}
+
+// checkHealthRequest model
+//
+// Request for checking health of routers.
+//
+// swagger:parameters checkMediatorHealth
+type checkHealthRequest struct { // nolint: unused,deadcode
+	// Params for checking health of routers.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.CheckHealthRequest
+}
+
+// checkHealthResponse model
+//
+// Response of checking health of routers.
+//
+// swagger:response checkHealthResponse
+type checkHealthResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.CheckHealthResponse
+}
+
+// pickupStatusRequest model
+//
+// Request for status of messages waiting at router.
+//
+// swagger:parameters pickupStatusRequest
+type pickupStatusRequest struct { // nolint: unused,deadcode
+	// Params for status of messages waiting at router.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.PickupStatusRequest
+}
+
+// pickupStatusResponse model
+//
+// Response of message pickup status request.
+//
+// swagger:response pickupStatusResponse
+type pickupStatusResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.PickupStatusResponse
+}
+
+// batchPickupRequest model
+//
+// Request for picking up a batch of messages from router.
+//
+// swagger:parameters batchPickup
+type batchPickupRequest struct { // nolint: unused,deadcode
+	// Params for picking up a batch of messages from router.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.BatchPickupRequest
+}
+
+// batchPickupResponse model
+//
+// Response of batch pickup.
+//
+// swagger:response batchPickupResponse
+type batchPickupResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.BatchPickupResponse
+}
+
+// pickupDeliveryRequest model
+//
+// Request for delivery of messages from router.
+//
+// swagger:parameters pickupDeliveryRequest
+type pickupDeliveryRequest struct { // nolint: unused,deadcode
+	// Params for delivery of messages from router.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.PickupDeliveryRequest
+}
+
+// pickupDeliveryResponse model
+//
+// Response of message pickup delivery request.
+//
+// swagger:response pickupDeliveryResponse
+type pickupDeliveryResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.PickupDeliveryResponse
+}
+
+// messagesReceivedRequest model
+//
+// Request for acknowledging receipt of messages delivered by router.
+//
+// swagger:parameters pickupMessagesReceived
+type messagesReceivedRequest struct { // nolint: unused,deadcode
+	// Params for acknowledging receipt of messages delivered by router.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.MessagesReceivedRequest
+}
+
+// liveModeRequest model
+//
+// Request for turning live delivery of messages by router on or off.
+//
+// swagger:parameters pickupLiveMode
+type liveModeRequest struct { // nolint: unused,deadcode
+	// Params for turning live delivery of messages by router on or off.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.LiveModeRequest
+}
+
+// getKeylistRequest model
+//
+// Request for querying recipient keys registered with router.
+//
+// swagger:parameters getKeylist
+type getKeylistRequest struct { // nolint: unused,deadcode
+	// Params for querying router keylist.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.GetKeylistRequest
+}
+
+// getKeylistResponse model
+//
+// Response of router keylist query.
+//
+// swagger:response getKeylistResponse
+type getKeylistResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.GetKeylistResponse
+}
+
+// reconcileKeylistRequest model
+//
+// Request for reconciling router keylist with keys of agent DIDs.
+//
+// swagger:parameters reconcileKeylist
+type reconcileKeylistRequest struct { // nolint: unused,deadcode
+	// Params for reconciling router keylist.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.ReconcileKeylistRequest
+}
+
+// reconcileKeylistResponse model
+//
+// Response of router keylist reconciliation.
+//
+// swagger:response reconcileKeylistResponse
+type reconcileKeylistResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.ReconcileKeylistResponse
+}
+
+// parseInvitationURLRequest model
+//
+// Request for decoding out-of-band invitation from invitation URL.
+//
+// swagger:parameters parseInvitationURL
+type parseInvitationURLRequest struct { // nolint: unused,deadcode
+	// Params for decoding invitation URL.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.ParseInvitationURLRequest
+}
+
+// parseInvitationURLResponse model
+//
+// Response of decoding out-of-band invitation from invitation URL.
+//
+// swagger:response parseInvitationURLResponse
+type parseInvitationURLResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.ParseInvitationURLResponse
+}
+
+// cancelPendingRequest model
+//
+// Request for cancelling pending connect or create connection request.
+//
+// swagger:parameters cancelPending
+type cancelPendingRequest struct { // nolint: unused,deadcode
+	// Params for cancelling pending request.
+	//
+	// in: body
+	// required: true
+	Request mediatorclient.CancelPendingRequest
+}
+
+// cancelPendingResponse model
+//
+// Response of cancelling pending request.
+//
+// swagger:response cancelPendingResponse
+type cancelPendingResponse struct { // nolint: unused,deadcode
+	// in: body
+	Response mediatorclient.CancelPendingResponse
+}
@@ -24,6 +24,18 @@ const (
 	ConnectPath                 = OperationID + "/connect"
 	CreateInvitationPath        = OperationID + "/create-invitation"
 	SendCreateConnectionRequest = OperationID + "/send-connection-request"
+	GetConnectionsPath          = OperationID + "/connections"
+	DisconnectPath              = OperationID + "/disconnect"
+	CheckHealthPath             = OperationID + "/check-health"
+	StatusRequestPath           = OperationID + "/pickup/status-request"
+	BatchPickupPath             = OperationID + "/pickup/batch-pickup"
+	DeliveryRequestPath         = OperationID + "/pickup/delivery-request"
+	MessagesReceivedPath        = OperationID + "/pickup/messages-received"
+	LiveModePath                = OperationID + "/pickup/live-mode"
+	GetKeylistPath              = OperationID + "/keylist"
+	ReconcileKeylistPath        = OperationID + "/keylist/reconcile"
+	ParseInvitationURLPath      = OperationID + "/parse-invitation-url"
+	CancelPendingPath           = OperationID + "/cancel-pending"
 )
 
 // Operation is controller REST service controller for mediator Client.
@@ -34,8 +46,8 @@ type Operation struct {
 
 // New returns new mediator client rest instance.
 func New(ctx mediatorclient.Provider, msgHandler ariescmd.MessageHandler,
-	notifier ariescmd.Notifier) (*Operation, error) {
-	client, err := mediatorclient.New(ctx, msgHandler, notifier)
+	notifier ariescmd.Notifier, opts ...mediatorclient.Option) (*Operation, error) {
+	client, err := mediatorclient.New(ctx, msgHandler, notifier, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize mediator-client command: %w", err)
 	}
@@ -51,6 +63,11 @@ func (c *Operation) GetRESTHandlers() []rest.Handler {
 	return c.handlers
 }
 
+// Close stops supervision of the routers connected through connect operation.
+func (c *Operation) Close() {
+	c.command.Close()
+}
+
 // registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
 func (c *Operation) registerHandler() {
 	// Add more protocol endpoints here to expose them as controller API endpoints
@@ -58,6 +75,18 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(ConnectPath, http.MethodPost, c.Connect),
 		cmdutil.NewHTTPHandler(CreateInvitationPath, http.MethodPost, c.CreateInvitation),
 		cmdutil.NewHTTPHandler(SendCreateConnectionRequest, http.MethodPost, c.SendCreateConnectionRequest),
+		cmdutil.NewHTTPHandler(GetConnectionsPath, http.MethodPost, c.GetConnections),
+		cmdutil.NewHTTPHandler(DisconnectPath, http.MethodPost, c.Disconnect),
+		cmdutil.NewHTTPHandler(CheckHealthPath, http.MethodPost, c.CheckHealth),
+		cmdutil.NewHTTPHandler(StatusRequestPath, http.MethodPost, c.StatusRequest),
+		cmdutil.NewHTTPHandler(BatchPickupPath, http.MethodPost, c.BatchPickup),
+		cmdutil.NewHTTPHandler(DeliveryRequestPath, http.MethodPost, c.DeliveryRequest),
+		cmdutil.NewHTTPHandler(MessagesReceivedPath, http.MethodPost, c.MessagesReceived),
+		cmdutil.NewHTTPHandler(LiveModePath, http.MethodPost, c.LiveMode),
+		cmdutil.NewHTTPHandler(GetKeylistPath, http.MethodPost, c.GetKeylist),
+		cmdutil.NewHTTPHandler(ReconcileKeylistPath, http.MethodPost, c.ReconcileKeylist),
+		cmdutil.NewHTTPHandler(ParseInvitationURLPath, http.MethodPost, c.ParseInvitationURL),
+		cmdutil.NewHTTPHandler(CancelPendingPath, http.MethodPost, c.CancelPending),
 	}
 }
 
@@ -95,3 +124,133 @@ func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request)
 func (c *Operation) SendCreateConnectionRequest(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(c.command.SendCreateConnectionRequest, rw, req.Body)
 }
+
+// GetConnections swagger:route POST /mediatorclient/connections mediatorclient getMediatorConnections
+//
+// Returns router connections of the agent along with their endpoints and routing keys.
+//
+// Responses:
+//    default: genericError
+//    200: getConnectionsResponse
+func (c *Operation) GetConnections(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetConnections, rw, req.Body)
+}
+
+// Disconnect swagger:route POST /mediatorclient/disconnect mediatorclient disconnectMediator
+//
+// Removes recipient keys from router and unregisters the agent from the router.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) Disconnect(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.Disconnect, rw, req.Body)
+}
+
+// CheckHealth swagger:route POST /mediatorclient/check-health mediatorclient checkMediatorHealth
+//
+// Sends trust ping to routers and reports whether they responded along with round-trip latency.
+//
+// Responses:
+//    default: genericError
+//    200: checkHealthResponse
+func (c *Operation) CheckHealth(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CheckHealth, rw, req.Body)
+}
+
+// StatusRequest swagger:route POST /mediatorclient/pickup/status-request mediatorclient pickupStatusRequest
+//
+// Requests status of messages waiting for the agent at the router.
+//
+// Responses:
+//    default: genericError
+//    200: pickupStatusResponse
+func (c *Operation) StatusRequest(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.StatusRequest, rw, req.Body)
+}
+
+// BatchPickup swagger:route POST /mediatorclient/pickup/batch-pickup mediatorclient batchPickup
+//
+// Picks up a batch of messages from DIDComm V1 router and hands them over to the agent.
+//
+// Responses:
+//    default: genericError
+//    200: batchPickupResponse
+func (c *Operation) BatchPickup(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.BatchPickup, rw, req.Body)
+}
+
+// DeliveryRequest swagger:route POST /mediatorclient/pickup/delivery-request mediatorclient pickupDeliveryRequest
+//
+// Requests delivery of messages from DIDComm V2 router and hands them over to the agent.
+//
+// Responses:
+//    default: genericError
+//    200: pickupDeliveryResponse
+func (c *Operation) DeliveryRequest(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DeliveryRequest, rw, req.Body)
+}
+
+// MessagesReceived swagger:route POST /mediatorclient/pickup/messages-received mediatorclient pickupMessagesReceived
+//
+// Acknowledges receipt of messages delivered by DIDComm V2 router.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) MessagesReceived(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.MessagesReceived, rw, req.Body)
+}
+
+// LiveMode swagger:route POST /mediatorclient/pickup/live-mode mediatorclient pickupLiveMode
+//
+// Turns live delivery of messages by DIDComm V2 router on or off.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) LiveMode(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.LiveMode, rw, req.Body)
+}
+
+// GetKeylist swagger:route POST /mediatorclient/keylist mediatorclient getKeylist
+//
+// Queries a page of recipient keys registered with the router.
+//
+// Responses:
+//    default: genericError
+//    200: getKeylistResponse
+func (c *Operation) GetKeylist(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.GetKeylist, rw, req.Body)
+}
+
+// ReconcileKeylist swagger:route POST /mediatorclient/keylist/reconcile mediatorclient reconcileKeylist
+//
+// Adds missing keys of agent DIDs to the router keylist and removes stale keys from it, or only reports them on
+// dry run.
+//
+// Responses:
+//    default: genericError
+//    200: reconcileKeylistResponse
+func (c *Operation) ReconcileKeylist(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ReconcileKeylist, rw, req.Body)
+}
+
+// ParseInvitationURL swagger:route POST /mediatorclient/parse-invitation-url mediatorclient parseInvitationURL
+//
+// Decodes out-of-band invitation from invitation URL with 'oob' or '_oob' query parameter.
+//
+// Responses:
+//    default: genericError
+//    200: parseInvitationURLResponse
+func (c *Operation) ParseInvitationURL(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.ParseInvitationURL, rw, req.Body)
+}
+
+// CancelPending swagger:route POST /mediatorclient/cancel-pending mediatorclient cancelPending
+//
+// Cancels pending connect or create connection request with given request ID.
+//
+// Responses:
+//    default: genericError
+//    200: cancelPendingResponse
+func (c *Operation) CancelPending(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.CancelPending, rw, req.Body)
+}
@@ -16,11 +16,13 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	didexchangesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	mediatorsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	messagepickupsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	outofbandsvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	outofbandv2svc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofbandv2"
 	mockmsghandler "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockpickup "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/messagepickup"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
@@ -89,7 +91,7 @@ func TestNew(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, c)
 		require.NotEmpty(t, c.GetRESTHandlers())
-		require.Len(t, c.GetRESTHandlers(), 3)
+		require.Len(t, c.GetRESTHandlers(), 15)
 	})
 
 	t.Run("test failure while creating mediator client", func(t *testing.T) {
@@ -319,6 +321,254 @@ func TestOperation_SendCreateConnectionRequest(t *testing.T) {
 	})
 }
 
+func TestOperation_GetConnections(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, GetConnectionsPath)
+
+		_, err = testutil.GetSuccessResponseFromHandler(handler, bytes.NewBufferString(""), handler.Path())
+		require.NoError(t, err)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{GetConnectionsErr: fmt.Errorf(sampleErr)},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+		}), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, GetConnectionsPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString(""), handler.Path())
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusInternalServerError, code)
+		testutil.VerifyError(t, mediatorclient.GetConnectionsError, sampleErr, buf.Bytes())
+	})
+}
+
+func TestOperation_Disconnect(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, DisconnectPath)
+
+		_, err = testutil.GetSuccessResponseFromHandler(handler,
+			bytes.NewBufferString(`{"connectionID":"sample-connection"}`), handler.Path())
+		require.NoError(t, err)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, DisconnectPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "connection ID missing", buf.Bytes())
+	})
+}
+
+func TestOperation_CheckHealth(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, CheckHealthPath)
+
+		_, err = testutil.GetSuccessResponseFromHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+		require.NoError(t, err)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		handler := testutil.LookupHandler(t, cmd, CheckHealthPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString("---"), handler.Path())
+		require.NoError(t, err)
+		require.NotEmpty(t, buf)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "invalid character", buf.Bytes())
+	})
+}
+
+func TestOperation_MessagePickup(t *testing.T) {
+	newPickupOperation := func(t *testing.T) *Operation {
+		t.Helper()
+
+		prov := newMockProvider(map[string]interface{}{
+			mediatorsvc.Coordination:   &mockroute.MockMediatorSvc{},
+			didexchangesvc.DIDExchange: &sdkmockprotocol.MockDIDExchangeSvc{},
+			outofbandsvc.Name:          &sdkmockprotocol.MockOobService{},
+			outofbandv2svc.Name:        &sdkmockprotocol.MockOobServiceV2{},
+			messagepickupsvc.MessagePickup: &mockpickup.MockMessagePickupSvc{
+				StatusRequestFunc: func(string) (*messagepickupsvc.Status, error) {
+					return &messagepickupsvc.Status{MessageCount: 2}, nil
+				},
+				BatchPickupFunc: func(string, int) (int, error) {
+					return 2, nil
+				},
+			},
+		})
+
+		mockStore := &mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)}
+
+		for _, record := range []*connection.Record{
+			{ConnectionID: "router-v1", State: "completed", DIDCommVersion: service.V1},
+			{ConnectionID: "router-v2", State: "completed", DIDCommVersion: service.V2},
+		} {
+			connBytes, err := json.Marshal(record)
+			require.NoError(t, err)
+			require.NoError(t, mockStore.Put("conn_"+record.ConnectionID, connBytes))
+		}
+
+		prov.StoreProvider = mockstorage.NewCustomMockStoreProvider(mockStore)
+		prov.CustomMessenger = sdkmockprotocol.NewMockMessenger()
+
+		cmd, err := New(prov, mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+
+		return cmd
+	}
+
+	t.Run("test success", func(t *testing.T) {
+		cmd := newPickupOperation(t)
+
+		for path, request := range map[string]string{
+			StatusRequestPath:    `{"connectionID":"router-v1"}`,
+			BatchPickupPath:      `{"connectionID":"router-v1"}`,
+			MessagesReceivedPath: `{"connectionID":"router-v2","messageIDs":["msg-1"]}`,
+			LiveModePath:         `{"connectionID":"router-v2","enabled":true}`,
+		} {
+			handler := testutil.LookupHandler(t, cmd, path)
+
+			_, err := testutil.GetSuccessResponseFromHandler(handler, bytes.NewBufferString(request), handler.Path())
+			require.NoError(t, err, path)
+		}
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd := newPickupOperation(t)
+
+		for _, path := range []string{
+			StatusRequestPath, BatchPickupPath, DeliveryRequestPath, MessagesReceivedPath, LiveModePath,
+		} {
+			handler := testutil.LookupHandler(t, cmd, path)
+
+			buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+			require.NoError(t, err)
+			require.NotEmpty(t, buf)
+
+			require.Equal(t, http.StatusBadRequest, code)
+			testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "connection ID missing", buf.Bytes())
+		}
+	})
+}
+
+func TestOperation_Keylist(t *testing.T) {
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		for _, path := range []string{GetKeylistPath, ReconcileKeylistPath} {
+			handler := testutil.LookupHandler(t, cmd, path)
+
+			buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+			require.NoError(t, err)
+			require.NotEmpty(t, buf)
+
+			require.Equal(t, http.StatusBadRequest, code)
+			testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "connection ID missing", buf.Bytes())
+		}
+	})
+}
+
+func TestOperation_ParseInvitationURL(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		handler := testutil.LookupHandler(t, cmd, ParseInvitationURLPath)
+
+		// {"@id":"inv-1","@type":"https://didcomm.org/out-of-band/1.0/invitation"}
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString(`{"url":"https://example.com?oob=`+
+			`eyJAaWQiOiJpbnYtMSIsIkB0eXBlIjoiaHR0cHM6Ly9kaWRjb21tLm9yZy9vdXQtb2YtYmFuZC8xLjAvaW52aXRhdGlvbiJ9"}`),
+			handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		var response mediatorclient.ParseInvitationURLResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.NotNil(t, response.Invitation)
+		require.Equal(t, "inv-1", response.Invitation.ID())
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		handler := testutil.LookupHandler(t, cmd, ParseInvitationURLPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString(`{"url":"https://example.com"}`),
+			handler.Path())
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "invitation missing in URL", buf.Bytes())
+	})
+}
+
+func TestOperation_CancelPending(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		handler := testutil.LookupHandler(t, cmd, CancelPendingPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString(`{"requestID":"request-1"}`),
+			handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		var response mediatorclient.CancelPendingResponse
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.False(t, response.Cancelled)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		cmd, err := New(newMockProvider(nil), mockmsghandler.NewMockMsgServiceProvider(), mocks.NewMockNotifier())
+		require.NoError(t, err)
+
+		handler := testutil.LookupHandler(t, cmd, CancelPendingPath)
+
+		buf, code, err := testutil.SendRequestToHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		testutil.VerifyError(t, mediatorclient.InvalidRequestErrorCode, "request ID missing", buf.Bytes())
+	})
+}
+
 func newMockProvider(serviceMap map[string]interface{}) *sdkmockprotocol.MockProvider {
 	if serviceMap == nil {
 		serviceMap = map[string]interface{}{